@@ -42,6 +42,7 @@ type RawBtcConfig struct {
 	BlockInterval            int64         `mapstructure:"blockInterval" default:"5"`
 	BlockRetryInterval       uint64        `mapstructure:"blockRetryInterval" default:"5"`
 	BlockConfirmations       int64         `mapstructure:"blockConfirmations" default:"10"`
+	ReorgDepth               int64         `mapstructure:"reorgDepth" default:"10"`
 	Network                  string        `mapstructure:"network" default:"mainnet"`
 	MempoolUrl               string        `mapstructure:"mempoolUrl"`
 }
@@ -55,6 +56,10 @@ func (c *RawBtcConfig) Validate() error {
 		return fmt.Errorf("blockConfirmations has to be >=1")
 	}
 
+	if c.ReorgDepth < 1 {
+		return fmt.Errorf("reorgDepth has to be >=1")
+	}
+
 	if c.Username == "" {
 		return fmt.Errorf("required field chain.Username empty for chain %v", *c.Id)
 	}
@@ -75,6 +80,7 @@ type BtcConfig struct {
 	BlockInterval      *big.Int
 	BlockRetryInterval time.Duration
 	BlockConfirmations *big.Int
+	ReorgDepth         *big.Int
 	Tweak              string
 	Script             []byte
 	MempoolUrl         string
@@ -144,6 +150,7 @@ func NewBtcConfig(chainConfig map[string]interface{}) (*BtcConfig, error) {
 		GeneralChainConfig: c.GeneralChainConfig,
 		StartBlock:         big.NewInt(c.StartBlock),
 		BlockConfirmations: big.NewInt(c.BlockConfirmations),
+		ReorgDepth:         big.NewInt(c.ReorgDepth),
 		BlockInterval:      big.NewInt(c.BlockInterval),
 		BlockRetryInterval: time.Duration(c.BlockRetryInterval) * time.Second,
 		Username:           c.Username,
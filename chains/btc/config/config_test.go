@@ -62,6 +62,18 @@ func (s *NewBtcConfigTestSuite) Test_InvalidBlockConfirmation() {
 	s.Equal(err.Error(), "blockConfirmations has to be >=1")
 }
 
+func (s *NewBtcConfigTestSuite) Test_InvalidReorgDepth() {
+	_, err := config.NewBtcConfig(map[string]interface{}{
+		"id":         1,
+		"endpoint":   "ws://domain.com",
+		"name":       "btc1",
+		"reorgDepth": -1,
+	})
+
+	s.NotNil(err)
+	s.Equal(err.Error(), "reorgDepth has to be >=1")
+}
+
 func (s *NewBtcConfigTestSuite) Test_InvalidUsername() {
 	_, err := config.NewBtcConfig(map[string]interface{}{
 		"id":       1,
@@ -130,6 +142,7 @@ func (s *NewBtcConfigTestSuite) Test_ValidConfig() {
 		Password:           "pass123",
 		StartBlock:         big.NewInt(0),
 		BlockConfirmations: big.NewInt(10),
+		ReorgDepth:         big.NewInt(10),
 		BlockInterval:      big.NewInt(5),
 		BlockRetryInterval: time.Duration(5) * time.Second,
 		Network:            chaincfg.TestNet3Params,
@@ -42,9 +42,9 @@ type MempoolAPI interface {
 }
 
 type Executor struct {
-	coordinator *tss.Coordinator
-	host        host.Host
-	comm        comm.Communication
+	scheduler *tss.Scheduler
+	host      host.Host
+	comm      comm.Communication
 
 	conn      *connection.Connection
 	resources map[[32]byte]config.Resource
@@ -63,7 +63,7 @@ func NewExecutor(
 	propStorer PropStorer,
 	host host.Host,
 	comm comm.Communication,
-	coordinator *tss.Coordinator,
+	scheduler *tss.Scheduler,
 	fetcher signing.SaveDataFetcher,
 	conn *connection.Connection,
 	mempool MempoolAPI,
@@ -73,17 +73,17 @@ func NewExecutor(
 	uploader uploader.Uploader,
 ) *Executor {
 	return &Executor{
-		propStorer:  propStorer,
-		host:        host,
-		comm:        comm,
-		coordinator: coordinator,
-		exitLock:    exitLock,
-		fetcher:     fetcher,
-		conn:        conn,
-		resources:   resources,
-		mempool:     mempool,
-		chainCfg:    chainCfg,
-		uploader:    uploader,
+		propStorer: propStorer,
+		host:       host,
+		comm:       comm,
+		scheduler:  scheduler,
+		exitLock:   exitLock,
+		fetcher:    fetcher,
+		conn:       conn,
+		resources:  resources,
+		mempool:    mempool,
+		chainCfg:   chainCfg,
+		uploader:   uploader,
 	}
 }
 
@@ -180,7 +180,7 @@ func (e *Executor) executeResourceProps(props []*BtcTransferProposal, resource c
 		tssProcesses[i] = signing
 	}
 	p.Go(func() error {
-		return e.coordinator.Execute(executionContext, tssProcesses, sigChn)
+		return e.scheduler.Execute(executionContext, tss.SigningPriority(isRetried(props)), latestDepositNonce(props), tssProcesses, sigChn)
 	})
 	return p.Wait()
 }
@@ -378,6 +378,16 @@ func (e *Executor) proposalsForExecution(proposals []*proposal.Proposal, message
 	e.propMutex.Lock()
 	props := make([]*BtcTransferProposal, 0)
 	for _, prop := range proposals {
+		orphaned, err := e.isOrphaned(prop)
+		if err != nil {
+			return props, err
+		}
+
+		if orphaned {
+			log.Warn().Str("messageID", messageID).Msgf("Skipping proposal %s from orphaned block", fmt.Sprintf("%d-%d-%d", prop.Source, prop.Destination, prop.Data.(BtcTransferProposalData).DepositNonce))
+			continue
+		}
+
 		executed, err := e.isExecuted(prop)
 		if err != nil {
 			return props, err
@@ -408,12 +418,21 @@ func (e *Executor) isExecuted(prop *proposal.Proposal) (bool, error) {
 		return true, err
 	}
 
-	if status == store.MissingProp || status == store.FailedProp {
+	if status == store.MissingProp || status == store.FailedProp || status == store.InvalidProp {
 		return false, nil
 	}
 	return true, err
 }
 
+// isOrphaned returns true if the proposal deposit is from a source block orphaned by a chain reorganization
+func (e *Executor) isOrphaned(prop *proposal.Proposal) (bool, error) {
+	data := prop.Data.(BtcTransferProposalData)
+	if data.BlockHash == "" {
+		return false, nil
+	}
+	return e.propStorer.IsPropInvalid(prop.Source, prop.Destination, data.DepositNonce, data.BlockHash)
+}
+
 func (e *Executor) storeProposalsStatus(props []*BtcTransferProposal, status store.PropStatus) {
 	e.propMutex.Lock()
 	for _, prop := range props {
@@ -428,3 +447,24 @@ func (e *Executor) storeProposalsStatus(props []*BtcTransferProposal, status sto
 	}
 	e.propMutex.Unlock()
 }
+
+// latestDepositNonce returns the highest deposit nonce of the proposals
+func latestDepositNonce(props []*BtcTransferProposal) uint64 {
+	var nonce uint64
+	for _, prop := range props {
+		if prop.Data.DepositNonce > nonce {
+			nonce = prop.Data.DepositNonce
+		}
+	}
+	return nonce
+}
+
+// isRetried returns true if any of the proposals was created from a retried deposit
+func isRetried(props []*BtcTransferProposal) bool {
+	for _, prop := range props {
+		if prop.Data.Retried {
+			return true
+		}
+	}
+	return false
+}
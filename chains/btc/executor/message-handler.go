@@ -23,6 +23,10 @@ type BtcTransferProposalData struct {
 	Recipient    string
 	DepositNonce uint64
 	ResourceId   [32]byte
+	// BlockHash is the hash of the source block that contains the deposit
+	BlockHash string
+	// Retried is set for retried deposits so their signing is prioritized over new deposits
+	Retried bool
 }
 
 type BtcTransferProposal struct {
@@ -73,6 +77,8 @@ func ERC20MessageHandler(msg *transfer.TransferMessage) (*proposal.Proposal, err
 		Recipient:    string(recipient),
 		DepositNonce: msg.Data.DepositNonce,
 		ResourceId:   msg.Data.ResourceId,
+		BlockHash:    msg.Data.BlockHash,
+		Retried:      msg.Data.Retried,
 	}, msg.ID, transfer.TransferProposalType), nil
 }
 
@@ -84,6 +90,7 @@ type BlockFetcher interface {
 type PropStorer interface {
 	StorePropStatus(source, destination uint8, depositNonce uint64, status store.PropStatus) error
 	PropStatus(source, destination uint8, depositNonce uint64) (store.PropStatus, error)
+	IsPropInvalid(source, destination uint8, depositNonce uint64, blockHash string) (bool, error)
 }
 
 type DepositProcessor interface {
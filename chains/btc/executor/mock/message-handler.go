@@ -91,6 +91,21 @@ func (m *MockPropStorer) EXPECT() *MockPropStorerMockRecorder {
 	return m.recorder
 }
 
+// IsPropInvalid mocks base method.
+func (m *MockPropStorer) IsPropInvalid(source, destination uint8, depositNonce uint64, blockHash string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsPropInvalid", source, destination, depositNonce, blockHash)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsPropInvalid indicates an expected call of IsPropInvalid.
+func (mr *MockPropStorerMockRecorder) IsPropInvalid(source, destination, depositNonce, blockHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsPropInvalid", reflect.TypeOf((*MockPropStorer)(nil).IsPropInvalid), source, destination, depositNonce, blockHash)
+}
+
 // PropStatus mocks base method.
 func (m *MockPropStorer) PropStatus(source, destination uint8, depositNonce uint64) (store.PropStatus, error) {
 	m.ctrl.T.Helper()
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/ChainSafe/sygma-relayer/chains/btc/config"
+	"github.com/ChainSafe/sygma-relayer/relayer/transfer"
 	"github.com/btcsuite/btcd/btcjson"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/rs/zerolog"
@@ -39,6 +40,12 @@ type DepositHandler interface {
 	) (*message.Message, error)
 }
 
+type DepositTracker interface {
+	Track(block *big.Int, blockHash string, txHash string, destination uint8, nonce uint64)
+	Invalidate(forkBlock *big.Int) error
+	OrphanedNonce(txHash string) (uint64, bool)
+}
+
 type FungibleTransferEventHandler struct {
 	depositHandler DepositHandler
 	domainID       uint8
@@ -47,6 +54,7 @@ type FungibleTransferEventHandler struct {
 	conn           Connection
 	msgChan        chan []*message.Message
 	resources      map[[32]byte]config.Resource
+	depositTracker DepositTracker
 }
 
 func NewFungibleTransferEventHandler(
@@ -56,7 +64,8 @@ func NewFungibleTransferEventHandler(
 	msgChan chan []*message.Message,
 	conn Connection,
 	resources map[[32]byte]config.Resource,
-	feeAddress btcutil.Address) *FungibleTransferEventHandler {
+	feeAddress btcutil.Address,
+	depositTracker DepositTracker) *FungibleTransferEventHandler {
 	return &FungibleTransferEventHandler{
 		depositHandler: depositHandler,
 		domainID:       domainID,
@@ -65,6 +74,7 @@ func NewFungibleTransferEventHandler(
 		conn:           conn,
 		msgChan:        msgChan,
 		resources:      resources,
+		depositTracker: depositTracker,
 	}
 }
 
@@ -82,13 +92,21 @@ func (eh *FungibleTransferEventHandler) HandleEvents(blockNumber *big.Int) error
 	return nil
 }
 
+// HandleReorg invalidates deposits emitted from blocks after the fork block
+func (eh *FungibleTransferEventHandler) HandleReorg(forkBlock *big.Int) error {
+	if eh.depositTracker == nil {
+		return nil
+	}
+	return eh.depositTracker.Invalidate(forkBlock)
+}
+
 func (eh *FungibleTransferEventHandler) ProcessDeposits(blockNumber *big.Int) (map[uint8][]*message.Message, error) {
 	domainDeposits := make(map[uint8][]*message.Message)
-	evts, err := eh.FetchEvents(blockNumber)
+	block, err := eh.FetchBlock(blockNumber)
 	if err != nil {
 		return nil, err
 	}
-	for _, evt := range evts {
+	for _, evt := range block.Tx {
 		err := func(evt btcjson.TxRawResult) error {
 			defer func() {
 				if r := recover(); r != nil {
@@ -105,7 +123,7 @@ func (eh *FungibleTransferEventHandler) ProcessDeposits(blockNumber *big.Int) (m
 				if !isDeposit {
 					continue
 				}
-				nonce, err := eh.CalculateNonce(blockNumber, evt.Hash)
+				nonce, err := eh.depositNonce(blockNumber, evt.Hash)
 				if err != nil {
 					return err
 				}
@@ -114,6 +132,13 @@ func (eh *FungibleTransferEventHandler) ProcessDeposits(blockNumber *big.Int) (m
 				if err != nil {
 					return err
 				}
+				if data, ok := m.Data.(transfer.TransferMessageData); ok {
+					data.BlockHash = block.Hash
+					m.Data = data
+				}
+				if eh.depositTracker != nil {
+					eh.depositTracker.Track(blockNumber, block.Hash, evt.Hash, m.Destination, nonce)
+				}
 
 				log.Debug().Str("messageID", m.ID).Msgf("Resolved message %+v in block: %s", m, blockNumber.String())
 				domainDeposits[m.Destination] = append(domainDeposits[m.Destination], m)
@@ -128,18 +153,26 @@ func (eh *FungibleTransferEventHandler) ProcessDeposits(blockNumber *big.Int) (m
 	return domainDeposits, nil
 }
 
-func (eh *FungibleTransferEventHandler) FetchEvents(startBlock *big.Int) ([]btcjson.TxRawResult, error) {
+func (eh *FungibleTransferEventHandler) FetchBlock(startBlock *big.Int) (*btcjson.GetBlockVerboseTxResult, error) {
 	blockHash, err := eh.conn.GetBlockHash(startBlock.Int64())
 	if err != nil {
 		return nil, err
 	}
 
 	// Fetch block details in verbose mode
-	block, err := eh.conn.GetBlockVerboseTx(blockHash)
-	if err != nil {
-		return nil, err
+	return eh.conn.GetBlockVerboseTx(blockHash)
+}
+
+// depositNonce returns the nonce the deposit had before its block was orphaned so the deposit
+// keeps its identity when the transaction is included in a different block
+func (eh *FungibleTransferEventHandler) depositNonce(blockNumber *big.Int, transactionHash string) (uint64, error) {
+	if eh.depositTracker != nil {
+		nonce, ok := eh.depositTracker.OrphanedNonce(transactionHash)
+		if ok {
+			return nonce, nil
+		}
 	}
-	return block.Tx, nil
+	return eh.CalculateNonce(blockNumber, transactionHash)
 }
 
 func (eh *FungibleTransferEventHandler) CalculateNonce(blockNumber *big.Int, transactionHash string) (uint64, error) {
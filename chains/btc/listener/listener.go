@@ -4,6 +4,7 @@
 package listener
 
 import (
+	"bytes"
 	"context"
 	"math/big"
 	"time"
@@ -18,13 +19,24 @@ import (
 type EventHandler interface {
 	HandleEvents(startBlock *big.Int) error
 }
+
+// ReorgHandler is implemented by event handlers that need to revert events
+// emitted from blocks orphaned by a chain reorganization
+type ReorgHandler interface {
+	HandleReorg(forkBlock *big.Int) error
+}
 type BlockStorer interface {
 	StoreBlock(block *big.Int, domainID uint8) error
 }
+type BlockHashStorer interface {
+	StoreBlockHash(domainID uint8, block *big.Int, hash []byte) error
+	BlockHash(domainID uint8, block *big.Int) ([]byte, error)
+}
 type Connection interface {
 	GetRawTransactionVerbose(*chainhash.Hash) (*btcjson.TxRawResult, error)
 	GetBlockHash(int64) (*chainhash.Hash, error)
 	GetBlockVerboseTx(*chainhash.Hash) (*btcjson.GetBlockVerboseTxResult, error)
+	GetBlockHeaderVerbose(*chainhash.Hash) (*btcjson.GetBlockHeaderVerboseResult, error)
 	GetBestBlockHash() (*chainhash.Hash, error)
 }
 type BtcListener struct {
@@ -33,15 +45,18 @@ type BtcListener struct {
 	eventHandlers      []EventHandler
 	blockRetryInterval time.Duration
 	blockConfirmations *big.Int
+	reorgDepth         *big.Int
 	blockstore         BlockStorer
+	blockHashStore     BlockHashStorer
 
 	log      zerolog.Logger
 	domainID uint8
 }
 
 // NewBtcListener creates an BtcListener that listens to deposit events on chain
-// and calls event handler when one occurs
-func NewBtcListener(connection Connection, eventHandlers []EventHandler, config *config.BtcConfig, blockstore BlockStorer,
+// and calls event handler when one occurs. The listener stores the hash of every processed
+// block and rewinds to the fork block when the chain reorganizes.
+func NewBtcListener(connection Connection, eventHandlers []EventHandler, config *config.BtcConfig, blockstore BlockStorer, blockHashStore BlockHashStorer,
 ) *BtcListener {
 	return &BtcListener{
 		log:                log.With().Uint8("domainID", *config.GeneralChainConfig.Id).Logger(),
@@ -49,7 +64,9 @@ func NewBtcListener(connection Connection, eventHandlers []EventHandler, config
 		eventHandlers:      eventHandlers,
 		blockRetryInterval: config.BlockRetryInterval,
 		blockConfirmations: config.BlockConfirmations,
+		reorgDepth:         config.ReorgDepth,
 		blockstore:         blockstore,
+		blockHashStore:     blockHashStore,
 		domainID:           *config.GeneralChainConfig.Id,
 	}
 }
@@ -90,6 +107,32 @@ loop:
 				continue
 			}
 
+			forkBlock, err := l.forkBlock(startBlock)
+			if err != nil {
+				l.log.Warn().Err(err).Msg("Unable to check chain reorganization")
+				time.Sleep(l.blockRetryInterval)
+				continue
+			}
+			if forkBlock != nil {
+				err = l.handleReorg(forkBlock)
+				if err != nil {
+					l.log.Warn().Err(err).Msg("Unable to handle chain reorganization")
+					time.Sleep(l.blockRetryInterval)
+					continue
+				}
+
+				l.log.Warn().Msgf("Chain reorganization detected, rewinding from block %s to block %s", startBlock, forkBlock)
+				startBlock.Add(forkBlock, big.NewInt(1))
+				continue
+			}
+
+			blockHash, err := l.conn.GetBlockHash(startBlock.Int64())
+			if err != nil {
+				l.log.Warn().Err(err).Msgf("Unable to get hash of block %s", startBlock)
+				time.Sleep(l.blockRetryInterval)
+				continue
+			}
+
 			log.Debug().Msgf("Fetching btc events for block %d", startBlock)
 
 			for _, handler := range l.eventHandlers {
@@ -100,6 +143,13 @@ loop:
 				}
 			}
 
+			err = l.blockHashStore.StoreBlockHash(l.domainID, startBlock, blockHash[:])
+			if err != nil {
+				l.log.Warn().Err(err).Msgf("Unable to store hash of block %s", startBlock)
+				time.Sleep(l.blockRetryInterval)
+				continue
+			}
+
 			//Write to block store. Not a critical operation, no need to retry
 			err = l.blockstore.StoreBlock(startBlock, l.domainID)
 			if err != nil {
@@ -109,3 +159,79 @@ loop:
 		}
 	}
 }
+
+// forkBlock returns the last processed block that is still part of the canonical chain if
+// the previous hash of the start block does not match the stored hash of the last processed block.
+// Returns nil if the chain was not reorganized.
+func (l *BtcListener) forkBlock(startBlock *big.Int) (*big.Int, error) {
+	block := new(big.Int).Sub(startBlock, big.NewInt(1))
+	processedHash, err := l.blockHashStore.BlockHash(l.domainID, block)
+	if err != nil || processedHash == nil {
+		return nil, err
+	}
+
+	hash, err := l.conn.GetBlockHash(startBlock.Int64())
+	if err != nil {
+		return nil, err
+	}
+	header, err := l.conn.GetBlockHeaderVerbose(hash)
+	if err != nil {
+		return nil, err
+	}
+	previousHash, err := chainhash.NewHashFromStr(header.PreviousHash)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(previousHash[:], processedHash) {
+		return nil, nil
+	}
+	l.log.Warn().Msgf("Processed block %s hash does not match previous hash %s of the next block", block, previousHash)
+
+	oldestBlock := new(big.Int).Sub(block, l.reorgDepth)
+	for block.Cmp(oldestBlock) > 0 {
+		block.Sub(block, big.NewInt(1))
+		canonical, err := l.isCanonical(block)
+		if err != nil {
+			return nil, err
+		}
+		if canonical {
+			return block, nil
+		}
+	}
+
+	l.log.Error().Msgf("Chain reorganization deeper than %s blocks, rewinding to block %s", l.reorgDepth, block)
+	return block, nil
+}
+
+// isCanonical compares the stored hash of the processed block with the hash of the block at the same height.
+// Blocks without a stored hash are considered canonical as they were not processed.
+func (l *BtcListener) isCanonical(block *big.Int) (bool, error) {
+	processedHash, err := l.blockHashStore.BlockHash(l.domainID, block)
+	if err != nil {
+		return false, err
+	}
+	if processedHash == nil {
+		return true, nil
+	}
+
+	hash, err := l.conn.GetBlockHash(block.Int64())
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(hash[:], processedHash), nil
+}
+
+func (l *BtcListener) handleReorg(forkBlock *big.Int) error {
+	for _, handler := range l.eventHandlers {
+		reorgHandler, ok := handler.(ReorgHandler)
+		if !ok {
+			continue
+		}
+
+		err := reorgHandler.HandleReorg(forkBlock)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -23,9 +23,16 @@ type ListenerTestSuite struct {
 	mockConn         *mock_listener.MockConnection
 	mockEventHandler *mock_listener.MockEventHandler
 	mockBlockStorer  *mock_listener.MockBlockStorer
+	mockHashStorer   *mock_listener.MockBlockHashStorer
+	mockReorgHandler *mock_listener.MockReorgHandler
 	domainID         uint8
 }
 
+type reorgEventHandler struct {
+	*mock_listener.MockEventHandler
+	*mock_listener.MockReorgHandler
+}
+
 func TestRunTestSuite(t *testing.T) {
 	suite.Run(t, new(ListenerTestSuite))
 }
@@ -38,19 +45,23 @@ func (s *ListenerTestSuite) SetupTest() {
 		},
 		BlockRetryInterval: time.Millisecond * 75,
 		BlockConfirmations: big.NewInt(5),
+		ReorgDepth:         big.NewInt(3),
 	}
 
 	ctrl := gomock.NewController(s.T())
 	s.mockBlockStorer = mock_listener.NewMockBlockStorer(ctrl)
+	s.mockHashStorer = mock_listener.NewMockBlockHashStorer(ctrl)
+	s.mockReorgHandler = mock_listener.NewMockReorgHandler(ctrl)
 
 	s.mockConn = mock_listener.NewMockConnection(ctrl)
 	s.mockEventHandler = mock_listener.NewMockEventHandler(ctrl)
 
 	s.listener = listener.NewBtcListener(
 		s.mockConn,
-		[]listener.EventHandler{s.mockEventHandler, s.mockEventHandler},
+		[]listener.EventHandler{s.mockEventHandler, reorgEventHandler{s.mockEventHandler, s.mockReorgHandler}},
 		&btcConfig,
 		s.mockBlockStorer,
+		s.mockHashStorer,
 	)
 }
 
@@ -97,13 +108,18 @@ func (s *ListenerTestSuite) Test_ListenToEvents_RetriesInCaseOfHandlerFailure()
 	hash, _ := chainhash.NewHashFromStr("00000000000000000008bba5a6ff31fdb9bb1d4147905b5b3c47a07a07235bfc")
 	s.mockConn.EXPECT().GetBestBlockHash().Return(hash, nil)
 	s.mockConn.EXPECT().GetBlockVerboseTx(hash).Return(&btcjson.GetBlockVerboseTxResult{Height: head}, nil)
+	s.mockHashStorer.EXPECT().BlockHash(s.domainID, big.NewInt(104)).Return(nil, nil)
+	s.mockConn.EXPECT().GetBlockHash(int64(105)).Return(hash, nil)
 	s.mockEventHandler.EXPECT().HandleEvents(startBlock).Return(fmt.Errorf("error"))
 	// Second pass
 	s.mockConn.EXPECT().GetBestBlockHash().Return(hash, nil)
 	s.mockConn.EXPECT().GetBlockVerboseTx(hash).Return(&btcjson.GetBlockVerboseTxResult{Height: head}, nil)
+	s.mockHashStorer.EXPECT().BlockHash(s.domainID, big.NewInt(104)).Return(nil, nil)
+	s.mockConn.EXPECT().GetBlockHash(int64(105)).Return(hash, nil)
 	s.mockEventHandler.EXPECT().HandleEvents(startBlock).Return(nil)
 	s.mockEventHandler.EXPECT().HandleEvents(startBlock).Return(nil)
 
+	s.mockHashStorer.EXPECT().StoreBlockHash(s.domainID, startBlock, hash[:]).Return(nil)
 	s.mockBlockStorer.EXPECT().StoreBlock(startBlock, s.domainID).Return(nil)
 	// third pass
 	s.mockConn.EXPECT().GetBestBlockHash().Return(hash, nil)
@@ -131,9 +147,12 @@ func (s *ListenerTestSuite) Test_ListenToEvents_UsesHeadAsStartBlockIfNilPassed(
 	s.mockConn.EXPECT().GetBestBlockHash().Return(hash, nil)
 	s.mockConn.EXPECT().GetBlockVerboseTx(hash).Return(&btcjson.GetBlockVerboseTxResult{Height: int64(50)}, nil)
 
+	s.mockHashStorer.EXPECT().BlockHash(s.domainID, big.NewInt(99)).Return(nil, nil)
+	s.mockConn.EXPECT().GetBlockHash(int64(100)).Return(hash, nil)
 	s.mockEventHandler.EXPECT().HandleEvents(startBlock).Return(nil)
 	s.mockEventHandler.EXPECT().HandleEvents(startBlock).Return(nil)
 
+	s.mockHashStorer.EXPECT().StoreBlockHash(s.domainID, startBlock, hash[:]).Return(nil)
 	s.mockBlockStorer.EXPECT().StoreBlock(startBlock, s.domainID).Return(nil)
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -143,3 +162,37 @@ func (s *ListenerTestSuite) Test_ListenToEvents_UsesHeadAsStartBlockIfNilPassed(
 	time.Sleep(time.Millisecond * 100)
 	cancel()
 }
+
+func (s *ListenerTestSuite) Test_ListenToEvents_RewindsToForkBlockOnReorg() {
+	bestHash, _ := chainhash.NewHashFromStr("00000000000000000008bba5a6ff31fdb9bb1d4147905b5b3c47a07a07235bfc")
+	processedHash, _ := chainhash.NewHashFromStr("00000000000000000001")
+	orphanedHash, _ := chainhash.NewHashFromStr("00000000000000000002")
+	canonicalHash, _ := chainhash.NewHashFromStr("00000000000000000003")
+	nextHash, _ := chainhash.NewHashFromStr("00000000000000000004")
+	s.mockConn.EXPECT().GetBestBlockHash().Return(bestHash, nil).AnyTimes()
+	s.mockConn.EXPECT().GetBlockVerboseTx(bestHash).Return(&btcjson.GetBlockVerboseTxResult{Height: 110}, nil).Times(2)
+	s.mockConn.EXPECT().GetBlockVerboseTx(bestHash).Return(&btcjson.GetBlockVerboseTxResult{Height: 106}, nil).AnyTimes()
+
+	// parent of block 105 does not match processed block 104, block 103 is still canonical
+	s.mockHashStorer.EXPECT().BlockHash(s.domainID, big.NewInt(104)).Return(orphanedHash[:], nil)
+	s.mockConn.EXPECT().GetBlockHash(int64(105)).Return(nextHash, nil)
+	s.mockConn.EXPECT().GetBlockHeaderVerbose(nextHash).Return(&btcjson.GetBlockHeaderVerboseResult{PreviousHash: canonicalHash.String()}, nil)
+	s.mockHashStorer.EXPECT().BlockHash(s.domainID, big.NewInt(103)).Return(processedHash[:], nil)
+	s.mockConn.EXPECT().GetBlockHash(int64(103)).Return(processedHash, nil)
+	s.mockReorgHandler.EXPECT().HandleReorg(big.NewInt(103)).Return(nil)
+
+	// block 104 is processed again
+	s.mockHashStorer.EXPECT().BlockHash(s.domainID, big.NewInt(103)).Return(processedHash[:], nil)
+	s.mockConn.EXPECT().GetBlockHash(int64(104)).Return(canonicalHash, nil).Times(2)
+	s.mockConn.EXPECT().GetBlockHeaderVerbose(canonicalHash).Return(&btcjson.GetBlockHeaderVerboseResult{PreviousHash: processedHash.String()}, nil)
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(104)).Return(nil).Times(2)
+	s.mockHashStorer.EXPECT().StoreBlockHash(s.domainID, big.NewInt(104), canonicalHash[:]).Return(nil)
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(104), s.domainID).Return(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go s.listener.ListenToEvents(ctx, big.NewInt(105))
+
+	time.Sleep(time.Millisecond * 50)
+	cancel()
+}
@@ -50,6 +50,43 @@ func (mr *MockEventHandlerMockRecorder) HandleEvents(startBlock interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleEvents", reflect.TypeOf((*MockEventHandler)(nil).HandleEvents), startBlock)
 }
 
+// MockReorgHandler is a mock of ReorgHandler interface.
+type MockReorgHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockReorgHandlerMockRecorder
+}
+
+// MockReorgHandlerMockRecorder is the mock recorder for MockReorgHandler.
+type MockReorgHandlerMockRecorder struct {
+	mock *MockReorgHandler
+}
+
+// NewMockReorgHandler creates a new mock instance.
+func NewMockReorgHandler(ctrl *gomock.Controller) *MockReorgHandler {
+	mock := &MockReorgHandler{ctrl: ctrl}
+	mock.recorder = &MockReorgHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReorgHandler) EXPECT() *MockReorgHandlerMockRecorder {
+	return m.recorder
+}
+
+// HandleReorg mocks base method.
+func (m *MockReorgHandler) HandleReorg(forkBlock *big.Int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleReorg", forkBlock)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleReorg indicates an expected call of HandleReorg.
+func (mr *MockReorgHandlerMockRecorder) HandleReorg(forkBlock interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleReorg", reflect.TypeOf((*MockReorgHandler)(nil).HandleReorg), forkBlock)
+}
+
 // MockBlockStorer is a mock of BlockStorer interface.
 type MockBlockStorer struct {
 	ctrl     *gomock.Controller
@@ -87,6 +124,58 @@ func (mr *MockBlockStorerMockRecorder) StoreBlock(block, domainID interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreBlock", reflect.TypeOf((*MockBlockStorer)(nil).StoreBlock), block, domainID)
 }
 
+// MockBlockHashStorer is a mock of BlockHashStorer interface.
+type MockBlockHashStorer struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlockHashStorerMockRecorder
+}
+
+// MockBlockHashStorerMockRecorder is the mock recorder for MockBlockHashStorer.
+type MockBlockHashStorerMockRecorder struct {
+	mock *MockBlockHashStorer
+}
+
+// NewMockBlockHashStorer creates a new mock instance.
+func NewMockBlockHashStorer(ctrl *gomock.Controller) *MockBlockHashStorer {
+	mock := &MockBlockHashStorer{ctrl: ctrl}
+	mock.recorder = &MockBlockHashStorerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlockHashStorer) EXPECT() *MockBlockHashStorerMockRecorder {
+	return m.recorder
+}
+
+// BlockHash mocks base method.
+func (m *MockBlockHashStorer) BlockHash(domainID uint8, block *big.Int) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockHash", domainID, block)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockHash indicates an expected call of BlockHash.
+func (mr *MockBlockHashStorerMockRecorder) BlockHash(domainID, block interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockHash", reflect.TypeOf((*MockBlockHashStorer)(nil).BlockHash), domainID, block)
+}
+
+// StoreBlockHash mocks base method.
+func (m *MockBlockHashStorer) StoreBlockHash(domainID uint8, block *big.Int, hash []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreBlockHash", domainID, block, hash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StoreBlockHash indicates an expected call of StoreBlockHash.
+func (mr *MockBlockHashStorerMockRecorder) StoreBlockHash(domainID, block, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreBlockHash", reflect.TypeOf((*MockBlockHashStorer)(nil).StoreBlockHash), domainID, block, hash)
+}
+
 // MockConnection is a mock of Connection interface.
 type MockConnection struct {
 	ctrl     *gomock.Controller
@@ -140,6 +229,21 @@ func (mr *MockConnectionMockRecorder) GetBlockHash(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockHash", reflect.TypeOf((*MockConnection)(nil).GetBlockHash), arg0)
 }
 
+// GetBlockHeaderVerbose mocks base method.
+func (m *MockConnection) GetBlockHeaderVerbose(arg0 *chainhash.Hash) (*btcjson.GetBlockHeaderVerboseResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockHeaderVerbose", arg0)
+	ret0, _ := ret[0].(*btcjson.GetBlockHeaderVerboseResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockHeaderVerbose indicates an expected call of GetBlockHeaderVerbose.
+func (mr *MockConnectionMockRecorder) GetBlockHeaderVerbose(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockHeaderVerbose", reflect.TypeOf((*MockConnection)(nil).GetBlockHeaderVerbose), arg0)
+}
+
 // GetBlockVerboseTx mocks base method.
 func (m *MockConnection) GetBlockVerboseTx(arg0 *chainhash.Hash) (*btcjson.GetBlockVerboseTxResult, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,78 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package consts
+
+import (
+	"fmt"
+
+	"golang.org/x/mod/semver"
+)
+
+// BridgeABIVersion is the bridge version of BridgeABI. Only the ABI of this version is available,
+// so bridges of other versions are supported only if the ABI is compatible with it.
+const BridgeABIVersion = "3.1.0"
+
+// BridgeABIForVersion returns the ABI of the bridge version. Bridges of the same major version that
+// are not older than BridgeABIVersion are expected to be compatible with BridgeABI.
+func BridgeABIForVersion(version string) (string, error) {
+	v := "v" + version
+	if !semver.IsValid(v) {
+		return "", fmt.Errorf("invalid bridge version %s", version)
+	}
+
+	abiVersion := "v" + BridgeABIVersion
+	if semver.Major(v) != semver.Major(abiVersion) || semver.Compare(v, abiVersion) < 0 {
+		return "", fmt.Errorf("bridge version %s not supported", version)
+	}
+	return BridgeABI, nil
+}
+
+// EIP5267ABI contains the EIP-5267 eip712Domain method
+const EIP5267ABI = `
+[
+	{
+		"inputs": [],
+		"name": "eip712Domain",
+		"outputs": [
+			{
+				"internalType": "bytes1",
+				"name": "fields",
+				"type": "bytes1"
+			},
+			{
+				"internalType": "string",
+				"name": "name",
+				"type": "string"
+			},
+			{
+				"internalType": "string",
+				"name": "version",
+				"type": "string"
+			},
+			{
+				"internalType": "uint256",
+				"name": "chainId",
+				"type": "uint256"
+			},
+			{
+				"internalType": "address",
+				"name": "verifyingContract",
+				"type": "address"
+			},
+			{
+				"internalType": "bytes32",
+				"name": "salt",
+				"type": "bytes32"
+			},
+			{
+				"internalType": "uint256[]",
+				"name": "extensions",
+				"type": "uint256[]"
+			}
+		],
+		"stateMutability": "view",
+		"type": "function"
+	}
+]
+`
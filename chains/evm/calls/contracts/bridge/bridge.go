@@ -4,17 +4,23 @@
 package bridge
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"math/big"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/ChainSafe/sygma-relayer/chains"
 	"github.com/ChainSafe/sygma-relayer/chains/evm/calls/consts"
 	"github.com/ChainSafe/sygma-relayer/relayer/transfer"
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/rs/zerolog/log"
 
 	"github.com/sygmaprotocol/sygma-core/chains/evm/client"
@@ -22,8 +28,6 @@ import (
 	"github.com/sygmaprotocol/sygma-core/chains/evm/transactor"
 )
 
-const bridgeVersion = "3.1.0"
-
 type BridgeProposal struct {
 	OriginDomainID uint8
 	ResourceID     [32]byte
@@ -31,26 +35,91 @@ type BridgeProposal struct {
 	Data           []byte
 }
 
+// revertSelector is the selector of the Error(string) revert
+const revertSelector = "0x08c379a0"
+
+// RevertError is returned when the proposal execution reverts in the simulation
+type RevertError struct {
+	Reason string
+}
+
+func (e *RevertError) Error() string {
+	return fmt.Sprintf("proposal execution reverted: %s", e.Reason)
+}
+
+// ProposalExecutionResult is the outcome of the proposal execution parsed from
+// bridge events of the execution transaction
+type ProposalExecutionResult struct {
+	OriginDomainID uint8
+	DepositNonce   uint64
+	Executed       bool
+	Reason         string
+}
+
 type ChainClient interface {
 	client.Client
 	ChainID(ctx context.Context) (*big.Int, error)
+	CallContext(ctx context.Context, target interface{}, rpcMethod string, args ...interface{}) error
+}
+
+// multicallCall is the Call3 struct of the Multicall3 aggregate3 method
+type multicallCall struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+// multicallResult is the Result struct of the Multicall3 aggregate3 method
+type multicallResult struct {
+	Success    bool
+	ReturnData []byte
 }
 
 type BridgeContract struct {
 	contracts.Contract
-	client ChainClient
+	client       ChainClient
+	handlerABI   abi.ABI
+	multicallABI abi.ABI
+	eip5267ABI   abi.ABI
+	version      string
+	domain       *chains.EIP712Domain
+
+	multicallLock sync.Mutex
+	multicallCode []byte
 }
 
+// NewBridgeContract creates the bridge contract with the ABI of the bridge version. The EIP-712 domain
+// of the bridge is read with EIP-5267 if the version is empty, so the version has to be set for bridges
+// without EIP-5267. Returns an error if the domain can not be resolved or the bridge version is not supported.
 func NewBridgeContract(
 	client ChainClient,
 	bridgeContractAddress common.Address,
 	transactor transactor.Transactor,
-) *BridgeContract {
-	a, _ := abi.JSON(strings.NewReader(consts.BridgeABI))
-	return &BridgeContract{
-		Contract: contracts.NewContract(bridgeContractAddress, a, nil, client, transactor),
-		client:   client,
+	version string,
+) (*BridgeContract, error) {
+	h, _ := abi.JSON(strings.NewReader(consts.HandlerABI))
+	m, _ := abi.JSON(strings.NewReader(consts.Multicall3ABI))
+	e, _ := abi.JSON(strings.NewReader(consts.EIP5267ABI))
+	c := &BridgeContract{
+		client:       client,
+		handlerABI:   h,
+		multicallABI: m,
+		eip5267ABI:   e,
+		version:      version,
+	}
+
+	domain, err := c.resolveEIP712Domain(bridgeContractAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed resolving EIP-712 domain of bridge %s: %w", bridgeContractAddress, err)
+	}
+	bridgeABI, err := consts.BridgeABIForVersion(domain.Version)
+	if err != nil {
+		return nil, err
 	}
+	a, _ := abi.JSON(strings.NewReader(bridgeABI))
+	c.Contract = contracts.NewContract(bridgeContractAddress, a, nil, client, transactor)
+	c.domain = domain
+	return c, nil
 }
 
 func (c *BridgeContract) ExecuteProposal(
@@ -92,12 +161,297 @@ func (c *BridgeContract) ExecuteProposals(
 	)
 }
 
+// SimulateProposals simulates handler executions of proposals at the block with eth_call sent from the bridge,
+// so proposals that would fail can be detected before signing. The returned slice contains *RevertError
+// for proposals whose execution reverts and nil for other proposals.
+//
+// Handlers are fetched and executions simulated with Multicall3 calls. Handlers only accept executions
+// from the bridge, so executions are simulated by replacing the bridge code with the Multicall3 code
+// with eth_call state override. Proposals are simulated with a call per proposal if Multicall3 is not
+// deployed or the endpoint does not support state overrides.
+func (c *BridgeContract) SimulateProposals(proposals []*transfer.TransferProposal, blockNumber *big.Int) ([]*RevertError, error) {
+	reverts := make([]*RevertError, len(proposals))
+	if len(proposals) == 0 {
+		return reverts, nil
+	}
+
+	handlers, err := c.handlerAddresses(proposals, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	calls := make([]multicallCall, 0, len(proposals))
+	indexes := make([]int, 0, len(proposals))
+	for i, p := range proposals {
+		if handlers[i] == (common.Address{}) {
+			reverts[i] = &RevertError{Reason: "ResourceIDNotMappedToHandler"}
+			continue
+		}
+
+		input, err := c.handlerABI.Pack("executeProposal", p.Data.ResourceId, p.Data.Data)
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, multicallCall{
+			Target:       handlers[i],
+			AllowFailure: true,
+			CallData:     input,
+		})
+		indexes = append(indexes, i)
+	}
+	if len(calls) == 0 {
+		return reverts, nil
+	}
+
+	results, err := c.simulateMulticall(calls, blockNumber)
+	if err != nil {
+		log.Debug().Err(err).Msgf("Unable to simulate proposals with multicall, simulating proposals individually")
+		results, err = c.simulateCalls(calls, blockNumber)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for i, result := range results {
+		if !result.Success {
+			reverts[indexes[i]] = &RevertError{Reason: c.revertReason(result.ReturnData)}
+		}
+	}
+	return reverts, nil
+}
+
+// handlerAddresses returns handlers of proposal resource IDs at the block
+func (c *BridgeContract) handlerAddresses(proposals []*transfer.TransferProposal, blockNumber *big.Int) ([]common.Address, error) {
+	calls := make([]multicallCall, len(proposals))
+	for i, p := range proposals {
+		input, err := c.ABI.Pack("_resourceIDToHandlerAddress", p.Data.ResourceId)
+		if err != nil {
+			return nil, err
+		}
+		calls[i] = multicallCall{
+			Target:       *c.ContractAddress(),
+			AllowFailure: false,
+			CallData:     input,
+		}
+	}
+
+	var results []multicallResult
+	var err error
+	if c.isMulticallDeployed() {
+		results, err = c.aggregate3(calls, blockNumber)
+	} else {
+		results, err = c.simulateCalls(calls, blockNumber)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	handlers := make([]common.Address, len(proposals))
+	for i, result := range results {
+		if !result.Success {
+			return nil, fmt.Errorf("failed fetching handler of resource %s", hexutil.Encode(proposals[i].Data.ResourceId[:]))
+		}
+
+		out, err := c.ABI.Unpack("_resourceIDToHandlerAddress", result.ReturnData)
+		if err != nil {
+			return nil, err
+		}
+		handlers[i] = *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+	}
+	return handlers, nil
+}
+
+// simulateMulticall sends calls from the bridge with Multicall3 aggregate3 by overriding the bridge
+// code with the Multicall3 code in the eth_call
+func (c *BridgeContract) simulateMulticall(calls []multicallCall, blockNumber *big.Int) ([]multicallResult, error) {
+	code := c.deployedMulticallCode()
+	if len(code) == 0 {
+		return nil, fmt.Errorf("multicall not deployed")
+	}
+	input, err := c.multicallABI.Pack("aggregate3", calls)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debug().Msgf("Simulating %d proposals with multicall", len(calls))
+	msg := ethereum.CallMsg{From: *c.ContractAddress(), To: c.ContractAddress(), Data: input}
+	overrides := map[common.Address]map[string]hexutil.Bytes{
+		*c.ContractAddress(): {"code": code},
+	}
+	var res hexutil.Bytes
+	err = c.client.CallContext(context.Background(), &res, "eth_call", client.ToCallArg(msg), blockNumberArg(blockNumber), overrides)
+	if err != nil {
+		return nil, err
+	}
+	return c.unpackMulticallResults(res, len(calls))
+}
+
+// simulateCalls sends calls from the bridge with a call per call
+func (c *BridgeContract) simulateCalls(calls []multicallCall, blockNumber *big.Int) ([]multicallResult, error) {
+	results := make([]multicallResult, len(calls))
+	for i, call := range calls {
+		target := call.Target
+		msg := ethereum.CallMsg{From: *c.ContractAddress(), To: &target, Data: call.CallData}
+		res, err := c.client.CallContract(context.Background(), client.ToCallArg(msg), blockNumber)
+		if err == nil {
+			results[i] = multicallResult{Success: true, ReturnData: res}
+			continue
+		}
+
+		revertData, ok := revertData(err)
+		if !ok {
+			return nil, err
+		}
+		results[i] = multicallResult{Success: false, ReturnData: revertData}
+	}
+	return results, nil
+}
+
+// revertData returns the revert data of the reverted call error. Reverts without
+// data are returned as Error(string) revert data with the error message.
+func revertData(err error) ([]byte, bool) {
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if data, ok := dataErr.ErrorData().(string); ok {
+			revertData, decodeErr := hexutil.Decode(data)
+			if decodeErr == nil {
+				return revertData, true
+			}
+		}
+	}
+	if !strings.Contains(err.Error(), "execution reverted") {
+		return nil, false
+	}
+
+	stringType, _ := abi.NewType("string", "", nil)
+	reason, packErr := abi.Arguments{{Type: stringType}}.Pack(err.Error())
+	if packErr != nil {
+		return nil, false
+	}
+	return append(common.FromHex(revertSelector), reason...), true
+}
+
+func blockNumberArg(blockNumber *big.Int) string {
+	if blockNumber == nil {
+		return "latest"
+	}
+	return hexutil.EncodeBig(blockNumber)
+}
+
+// ProposalExecutionResults waits for the execution transaction receipt and returns results of
+// proposals executed in the transaction parsed from ProposalExecution and FailedHandlerExecution events
+func (c *BridgeContract) ProposalExecutionResults(hash common.Hash) ([]*ProposalExecutionResult, error) {
+	receipt, err := c.client.WaitAndReturnTxReceipt(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*ProposalExecutionResult, 0)
+	for _, l := range receipt.Logs {
+		if l.Address != *c.ContractAddress() || len(l.Topics) == 0 {
+			continue
+		}
+
+		switch l.Topics[0] {
+		case c.ABI.Events["ProposalExecution"].ID:
+			{
+				out, err := c.ABI.Unpack("ProposalExecution", l.Data)
+				if err != nil {
+					return nil, err
+				}
+				results = append(results, &ProposalExecutionResult{
+					OriginDomainID: *abi.ConvertType(out[0], new(uint8)).(*uint8),
+					DepositNonce:   *abi.ConvertType(out[1], new(uint64)).(*uint64),
+					Executed:       true,
+				})
+			}
+		case c.ABI.Events["FailedHandlerExecution"].ID:
+			{
+				out, err := c.ABI.Unpack("FailedHandlerExecution", l.Data)
+				if err != nil {
+					return nil, err
+				}
+				results = append(results, &ProposalExecutionResult{
+					OriginDomainID: *abi.ConvertType(out[1], new(uint8)).(*uint8),
+					DepositNonce:   *abi.ConvertType(out[2], new(uint64)).(*uint64),
+					Executed:       false,
+					Reason:         c.revertReason(*abi.ConvertType(out[0], new([]byte)).(*[]byte)),
+				})
+			}
+		}
+	}
+	return results, nil
+}
+
+// revertReason decodes the revert reason from the revert string, panic code or bridge custom error
+func (c *BridgeContract) revertReason(data []byte) string {
+	reason, err := abi.UnpackRevert(data)
+	if err == nil {
+		return reason
+	}
+	if len(data) >= 4 {
+		for name, e := range c.ABI.Errors {
+			if bytes.Equal(e.ID[:4], data[:4]) {
+				return name
+			}
+		}
+	}
+	return hexutil.Encode(data)
+}
+
 func (c *BridgeContract) ProposalsHash(proposals []*transfer.TransferProposal) ([]byte, error) {
+	return chains.ProposalsHash(proposals, c.domain)
+}
+
+// EIP712Domain returns the EIP-712 domain proposals are signed for
+func (c *BridgeContract) EIP712Domain() *chains.EIP712Domain {
+	return c.domain
+}
+
+// resolveEIP712Domain reads the domain from the bridge with EIP-5267 eip712Domain()
+// unless the bridge version is configured
+func (c *BridgeContract) resolveEIP712Domain(bridgeAddress common.Address) (*chains.EIP712Domain, error) {
 	chainID, err := c.client.ChainID(context.Background())
 	if err != nil {
-		return []byte{}, err
+		return nil, err
+	}
+	domain := &chains.EIP712Domain{
+		Name:              "Bridge",
+		Version:           c.version,
+		ChainID:           chainID,
+		VerifyingContract: bridgeAddress.Hex(),
+	}
+	if c.version != "" {
+		return domain, nil
+	}
+
+	input, err := c.eip5267ABI.Pack("eip712Domain")
+	if err != nil {
+		return nil, err
+	}
+	msg := ethereum.CallMsg{From: bridgeAddress, To: &bridgeAddress, Data: input}
+	res, err := c.client.CallContract(context.Background(), client.ToCallArg(msg), nil)
+	if err != nil && !isRevert(err) {
+		return nil, err
+	}
+	out, unpackErr := c.eip5267ABI.Unpack("eip712Domain", res)
+	if err != nil || unpackErr != nil {
+		return nil, fmt.Errorf("bridge does not implement EIP-5267, bridge version has to be configured")
 	}
-	return chains.ProposalsHash(proposals, chainID.Int64(), c.ContractAddress().Hex(), bridgeVersion)
+
+	// proposals are signed for domains with name, version, chain ID and verifying contract fields
+	fields := *abi.ConvertType(out[0], new([1]byte)).(*[1]byte)
+	if fields[0] != 0x0f {
+		return nil, fmt.Errorf("unsupported EIP-712 domain fields %#x", fields[0])
+	}
+	domain.Name = *abi.ConvertType(out[1], new(string)).(*string)
+	domain.Version = *abi.ConvertType(out[2], new(string)).(*string)
+	domain.ChainID = *abi.ConvertType(out[3], new(*big.Int)).(**big.Int)
+	domain.VerifyingContract = (*abi.ConvertType(out[4], new(common.Address)).(*common.Address)).Hex()
+	return domain, nil
+}
+
+func isRevert(err error) bool {
+	var dataErr rpc.DataError
+	return errors.As(err, &dataErr) || strings.Contains(err.Error(), "execution reverted")
 }
 
 func (c *BridgeContract) IsProposalExecuted(p *transfer.TransferProposal) (bool, error) {
@@ -114,6 +468,120 @@ func (c *BridgeContract) IsProposalExecuted(p *transfer.TransferProposal) (bool,
 	return out, nil
 }
 
+// AreProposalsExecuted returns execution statuses of proposals fetched in a single Multicall3 call.
+// Statuses are fetched with a call per proposal if Multicall3 is not deployed on the chain.
+func (c *BridgeContract) AreProposalsExecuted(proposals []*transfer.TransferProposal) ([]bool, error) {
+	if len(proposals) == 0 {
+		return []bool{}, nil
+	}
+	if !c.isMulticallDeployed() {
+		return c.proposalStatuses(proposals)
+	}
+
+	calls := make([]multicallCall, len(proposals))
+	for i, p := range proposals {
+		input, err := c.ABI.Pack("isProposalExecuted", p.Source, new(big.Int).SetUint64(p.Data.DepositNonce))
+		if err != nil {
+			return nil, err
+		}
+		calls[i] = multicallCall{
+			Target:       *c.ContractAddress(),
+			AllowFailure: true,
+			CallData:     input,
+		}
+	}
+	log.Debug().Msgf("Getting execution statuses of %d proposals with multicall", len(proposals))
+	results, err := c.aggregate3(calls, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]bool, len(proposals))
+	for i, result := range results {
+		if !result.Success {
+			statuses[i], err = c.IsProposalExecuted(proposals[i])
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		out, err := c.ABI.Unpack("isProposalExecuted", result.ReturnData)
+		if err != nil {
+			return nil, err
+		}
+		statuses[i] = *abi.ConvertType(out[0], new(bool)).(*bool)
+	}
+	return statuses, nil
+}
+
+func (c *BridgeContract) proposalStatuses(proposals []*transfer.TransferProposal) ([]bool, error) {
+	statuses := make([]bool, len(proposals))
+	for i, p := range proposals {
+		isExecuted, err := c.IsProposalExecuted(p)
+		if err != nil {
+			return nil, err
+		}
+		statuses[i] = isExecuted
+	}
+	return statuses, nil
+}
+
+// aggregate3 sends calls with the Multicall3 aggregate3 call at the block
+func (c *BridgeContract) aggregate3(calls []multicallCall, blockNumber *big.Int) ([]multicallResult, error) {
+	input, err := c.multicallABI.Pack("aggregate3", calls)
+	if err != nil {
+		return nil, err
+	}
+
+	multicall := common.HexToAddress(consts.Multicall3Address)
+	msg := ethereum.CallMsg{From: *c.ContractAddress(), To: &multicall, Data: input}
+	res, err := c.client.CallContract(context.Background(), client.ToCallArg(msg), blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	return c.unpackMulticallResults(res, len(calls))
+}
+
+func (c *BridgeContract) unpackMulticallResults(res []byte, calls int) ([]multicallResult, error) {
+	var results []multicallResult
+	err := c.multicallABI.UnpackIntoInterface(&results, "aggregate3", res)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) != calls {
+		return nil, fmt.Errorf("multicall returned %d results for %d calls", len(results), calls)
+	}
+	return results, nil
+}
+
+func (c *BridgeContract) isMulticallDeployed() bool {
+	return len(c.deployedMulticallCode()) > 0
+}
+
+// deployedMulticallCode fetches the Multicall3 code once and caches it.
+// Multicall3 is considered not deployed while the check fails.
+func (c *BridgeContract) deployedMulticallCode() []byte {
+	c.multicallLock.Lock()
+	defer c.multicallLock.Unlock()
+
+	if c.multicallCode != nil {
+		return c.multicallCode
+	}
+
+	code, err := c.client.CodeAt(context.Background(), common.HexToAddress(consts.Multicall3Address), nil)
+	if err != nil {
+		log.Warn().Err(err).Msg("Unable to check if multicall is deployed")
+		return nil
+	}
+	if len(code) == 0 {
+		log.Info().Msgf("Multicall not deployed at %s, checking proposals individually", consts.Multicall3Address)
+		code = []byte{}
+	}
+	c.multicallCode = code
+	return code
+}
+
 func (c *BridgeContract) GetHandlerAddressForResourceID(
 	resourceID [32]byte,
 ) (common.Address, error) {
@@ -0,0 +1,326 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package bridge_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ChainSafe/sygma-relayer/chains"
+	"github.com/ChainSafe/sygma-relayer/chains/evm/calls/consts"
+	"github.com/ChainSafe/sygma-relayer/chains/evm/calls/contracts/bridge"
+	"github.com/ChainSafe/sygma-relayer/relayer/transfer"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/suite"
+	"github.com/sygmaprotocol/sygma-core/mock"
+	"go.uber.org/mock/gomock"
+)
+
+type testClient struct {
+	*mock.MockClient
+	callContext func(ctx context.Context, target interface{}, rpcMethod string, args ...interface{}) error
+}
+
+func (c *testClient) CallContext(ctx context.Context, target interface{}, rpcMethod string, args ...interface{}) error {
+	return c.callContext(ctx, target, rpcMethod, args...)
+}
+
+func (c *testClient) ChainID(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(1), nil
+}
+
+type revertError struct {
+	data string
+}
+
+func (e *revertError) Error() string {
+	return "execution reverted"
+}
+
+func (e *revertError) ErrorData() interface{} {
+	return e.data
+}
+
+type multicallResult struct {
+	Success    bool
+	ReturnData []byte
+}
+
+type BridgeTestSuite struct {
+	suite.Suite
+	client        *mock.MockClient
+	testClient    *testClient
+	bridge        *bridge.BridgeContract
+	bridgeAddress common.Address
+	bridgeABI     abi.ABI
+	proposal      *transfer.TransferProposal
+}
+
+func TestRunBridgeTestSuite(t *testing.T) {
+	suite.Run(t, new(BridgeTestSuite))
+}
+
+func (s *BridgeTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.client = mock.NewMockClient(ctrl)
+	s.bridgeAddress = common.HexToAddress("0x6CdE2Cd82a4F8B74693Ff5e194c19CA08c2d1c68")
+	s.testClient = &testClient{MockClient: s.client}
+	s.bridge, _ = bridge.NewBridgeContract(s.testClient, s.bridgeAddress, nil, consts.BridgeABIVersion)
+	s.bridgeABI, _ = abi.JSON(strings.NewReader(consts.BridgeABI))
+	s.proposal = &transfer.TransferProposal{
+		Source: 1,
+		Data: transfer.TransferProposalData{
+			DepositNonce: 5,
+			ResourceId:   [32]byte{1},
+			Data:         []byte{2},
+		},
+	}
+}
+
+func (s *BridgeTestSuite) handlerOutput(handler common.Address) []byte {
+	output, _ := s.bridgeABI.Methods["_resourceIDToHandlerAddress"].Outputs.Pack(handler)
+	return output
+}
+
+func (s *BridgeTestSuite) revertOutput(reason string) []byte {
+	output, _ := (abi.Arguments{{Type: abi.Type{T: abi.StringTy}}}).Pack(reason)
+	return append(common.FromHex("0x08c379a0"), output...)
+}
+
+func (s *BridgeTestSuite) Test_SimulateProposals_Multicall() {
+	handler := common.HexToAddress("0x02091EefF969b33A5CE8A729DaE325879bf76f90")
+	multicall := common.HexToAddress(consts.Multicall3Address)
+	s.client.EXPECT().CodeAt(gomock.Any(), multicall, gomock.Any()).Return([]byte{1}, nil)
+	s.client.EXPECT().CallContract(gomock.Any(), gomock.Any(), big.NewInt(100)).DoAndReturn(
+		func(ctx context.Context, callArgs map[string]interface{}, blockNumber *big.Int) ([]byte, error) {
+			s.Equal(&multicall, callArgs["to"])
+			return s.multicallOutput(
+				multicallResult{Success: true, ReturnData: s.handlerOutput(handler)},
+				multicallResult{Success: true, ReturnData: s.handlerOutput(handler)},
+			), nil
+		})
+	s.testClient.callContext = func(ctx context.Context, target interface{}, rpcMethod string, args ...interface{}) error {
+		s.Equal("eth_call", rpcMethod)
+		s.Equal(&s.bridgeAddress, args[0].(map[string]interface{})["to"])
+		s.Equal("0x64", args[1])
+		s.Equal(map[common.Address]map[string]hexutil.Bytes{s.bridgeAddress: {"code": []byte{1}}}, args[2])
+		*target.(*hexutil.Bytes) = s.multicallOutput(
+			multicallResult{Success: true, ReturnData: []byte{}},
+			multicallResult{Success: false, ReturnData: s.revertOutput("ERC20: insufficient balance")},
+		)
+		return nil
+	}
+
+	reverts, err := s.bridge.SimulateProposals([]*transfer.TransferProposal{s.proposal, s.proposal}, big.NewInt(100))
+
+	s.Nil(err)
+	s.Nil(reverts[0])
+	s.Equal("ERC20: insufficient balance", reverts[1].Reason)
+}
+
+func (s *BridgeTestSuite) Test_SimulateProposals_UnmappedResourceID() {
+	s.client.EXPECT().CodeAt(gomock.Any(), gomock.Any(), gomock.Any()).Return([]byte{1}, nil)
+	s.client.EXPECT().CallContract(gomock.Any(), gomock.Any(), gomock.Any()).Return(s.multicallOutput(
+		multicallResult{Success: true, ReturnData: s.handlerOutput(common.Address{})},
+	), nil)
+
+	reverts, err := s.bridge.SimulateProposals([]*transfer.TransferProposal{s.proposal}, big.NewInt(100))
+
+	s.Nil(err)
+	s.Equal("ResourceIDNotMappedToHandler", reverts[0].Reason)
+}
+
+func (s *BridgeTestSuite) Test_SimulateProposals_OverrideNotSupported() {
+	handler := common.HexToAddress("0x02091EefF969b33A5CE8A729DaE325879bf76f90")
+	s.client.EXPECT().CodeAt(gomock.Any(), gomock.Any(), gomock.Any()).Return([]byte{1}, nil)
+	s.client.EXPECT().CallContract(gomock.Any(), gomock.Any(), gomock.Any()).Return(s.multicallOutput(
+		multicallResult{Success: true, ReturnData: s.handlerOutput(handler)},
+	), nil)
+	s.testClient.callContext = func(ctx context.Context, target interface{}, rpcMethod string, args ...interface{}) error {
+		return errors.New("too many arguments")
+	}
+	s.client.EXPECT().CallContract(gomock.Any(), gomock.Any(), big.NewInt(100)).DoAndReturn(
+		func(ctx context.Context, callArgs map[string]interface{}, blockNumber *big.Int) ([]byte, error) {
+			s.Equal(s.bridgeAddress, callArgs["from"])
+			s.Equal(&handler, callArgs["to"])
+			return nil, &revertError{data: hexutil.Encode(s.revertOutput("ERC20: insufficient balance"))}
+		})
+
+	reverts, err := s.bridge.SimulateProposals([]*transfer.TransferProposal{s.proposal}, big.NewInt(100))
+
+	s.Nil(err)
+	s.Equal("ERC20: insufficient balance", reverts[0].Reason)
+}
+
+func (s *BridgeTestSuite) Test_SimulateProposals_CallFails() {
+	s.client.EXPECT().CodeAt(gomock.Any(), gomock.Any(), gomock.Any()).Return([]byte{}, nil)
+	s.client.EXPECT().CallContract(gomock.Any(), gomock.Any(), gomock.Any()).Return(s.handlerOutput(
+		common.HexToAddress("0x02091EefF969b33A5CE8A729DaE325879bf76f90"),
+	), nil)
+	s.client.EXPECT().CallContract(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("connection refused"))
+
+	_, err := s.bridge.SimulateProposals([]*transfer.TransferProposal{s.proposal}, big.NewInt(100))
+
+	s.NotNil(err)
+}
+
+func (s *BridgeTestSuite) Test_ProposalExecutionResults_ParsesEvents() {
+	executed, _ := s.bridgeABI.Events["ProposalExecution"].Inputs.Pack(uint8(1), uint64(5), [32]byte{}, []byte{})
+	customError := s.bridgeABI.Errors["InvalidProposalSigner"].ID.Bytes()[:4]
+	failed, _ := s.bridgeABI.Events["FailedHandlerExecution"].Inputs.Pack(customError, uint8(1), uint64(6))
+	s.client.EXPECT().WaitAndReturnTxReceipt(common.Hash{1}).Return(&types.Receipt{
+		Logs: []*types.Log{
+			{Address: s.bridgeAddress, Topics: []common.Hash{s.bridgeABI.Events["ProposalExecution"].ID}, Data: executed},
+			{Address: common.Address{}, Topics: []common.Hash{s.bridgeABI.Events["ProposalExecution"].ID}, Data: executed},
+			{Address: s.bridgeAddress, Topics: []common.Hash{s.bridgeABI.Events["FailedHandlerExecution"].ID}, Data: failed},
+		},
+	}, nil)
+
+	results, err := s.bridge.ProposalExecutionResults(common.Hash{1})
+
+	s.Nil(err)
+	s.Equal([]*bridge.ProposalExecutionResult{
+		{OriginDomainID: 1, DepositNonce: 5, Executed: true},
+		{OriginDomainID: 1, DepositNonce: 6, Executed: false, Reason: "InvalidProposalSigner"},
+	}, results)
+}
+
+func (s *BridgeTestSuite) multicallOutput(results ...multicallResult) []byte {
+	multicallABI, _ := abi.JSON(strings.NewReader(consts.Multicall3ABI))
+	output, _ := multicallABI.Methods["aggregate3"].Outputs.Pack(results)
+	return output
+}
+
+func (s *BridgeTestSuite) executedOutput(executed bool) []byte {
+	output, _ := s.bridgeABI.Methods["isProposalExecuted"].Outputs.Pack(executed)
+	return output
+}
+
+func (s *BridgeTestSuite) Test_AreProposalsExecuted_Multicall() {
+	multicall := common.HexToAddress(consts.Multicall3Address)
+	s.client.EXPECT().CodeAt(gomock.Any(), multicall, gomock.Any()).Return([]byte{1}, nil)
+	s.client.EXPECT().CallContract(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, callArgs map[string]interface{}, blockNumber *big.Int) ([]byte, error) {
+			s.Equal(&multicall, callArgs["to"])
+			return s.multicallOutput(
+				multicallResult{Success: true, ReturnData: s.executedOutput(true)},
+				multicallResult{Success: true, ReturnData: s.executedOutput(false)},
+			), nil
+		}).Times(2)
+
+	executed, err := s.bridge.AreProposalsExecuted([]*transfer.TransferProposal{s.proposal, s.proposal})
+
+	s.Nil(err)
+	s.Equal([]bool{true, false}, executed)
+
+	_, err = s.bridge.AreProposalsExecuted([]*transfer.TransferProposal{s.proposal, s.proposal})
+
+	s.Nil(err)
+}
+
+func (s *BridgeTestSuite) Test_AreProposalsExecuted_FailedMulticallCallRetriedIndividually() {
+	s.client.EXPECT().CodeAt(gomock.Any(), gomock.Any(), gomock.Any()).Return([]byte{1}, nil)
+	s.client.EXPECT().CallContract(gomock.Any(), gomock.Any(), gomock.Any()).Return(s.multicallOutput(
+		multicallResult{Success: false, ReturnData: []byte{}},
+	), nil)
+	s.client.EXPECT().From().Return(common.Address{})
+	s.client.EXPECT().CallContract(gomock.Any(), gomock.Any(), gomock.Any()).Return(s.executedOutput(true), nil)
+
+	executed, err := s.bridge.AreProposalsExecuted([]*transfer.TransferProposal{s.proposal})
+
+	s.Nil(err)
+	s.Equal([]bool{true}, executed)
+}
+
+func (s *BridgeTestSuite) Test_AreProposalsExecuted_MulticallNotDeployed() {
+	s.client.EXPECT().CodeAt(gomock.Any(), gomock.Any(), gomock.Any()).Return([]byte{}, nil)
+	s.client.EXPECT().From().Return(common.Address{}).Times(2)
+	s.client.EXPECT().CallContract(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, callArgs map[string]interface{}, blockNumber *big.Int) ([]byte, error) {
+			s.Equal(&s.bridgeAddress, callArgs["to"])
+			return s.executedOutput(true), nil
+		}).Times(2)
+
+	executed, err := s.bridge.AreProposalsExecuted([]*transfer.TransferProposal{s.proposal, s.proposal})
+
+	s.Nil(err)
+	s.Equal([]bool{true, true}, executed)
+}
+
+func (s *BridgeTestSuite) eip712DomainOutput(fields byte, name string, version string) []byte {
+	eip5267ABI, _ := abi.JSON(strings.NewReader(consts.EIP5267ABI))
+	output, _ := eip5267ABI.Methods["eip712Domain"].Outputs.Pack(
+		[1]byte{fields}, name, version, big.NewInt(1), s.bridgeAddress, [32]byte{}, []*big.Int{},
+	)
+	return output
+}
+
+func (s *BridgeTestSuite) Test_EIP712Domain_ConfiguredVersion() {
+	b, err := bridge.NewBridgeContract(s.testClient, s.bridgeAddress, nil, "3.1.0")
+	s.Nil(err)
+
+	s.Equal(&chains.EIP712Domain{
+		Name:              "Bridge",
+		Version:           "3.1.0",
+		ChainID:           big.NewInt(1),
+		VerifyingContract: s.bridgeAddress.Hex(),
+	}, b.EIP712Domain())
+}
+
+func (s *BridgeTestSuite) Test_EIP712Domain_ReadWithEIP5267() {
+	s.client.EXPECT().CallContract(gomock.Any(), gomock.Any(), gomock.Any()).Return(s.eip712DomainOutput(0x0f, "Bridge", "3.2.0"), nil)
+
+	b, err := bridge.NewBridgeContract(s.testClient, s.bridgeAddress, nil, "")
+	s.Nil(err)
+
+	domain := b.EIP712Domain()
+	s.Equal("Bridge", domain.Name)
+	s.Equal("3.2.0", domain.Version)
+	s.Equal(0, domain.ChainID.Cmp(big.NewInt(1)))
+	s.Equal(s.bridgeAddress.Hex(), domain.VerifyingContract)
+}
+
+func (s *BridgeTestSuite) Test_EIP712Domain_UnsupportedFields() {
+	s.client.EXPECT().CallContract(gomock.Any(), gomock.Any(), gomock.Any()).Return(s.eip712DomainOutput(0x1f, "Bridge", "3.2.0"), nil)
+
+	_, err := bridge.NewBridgeContract(s.testClient, s.bridgeAddress, nil, "")
+
+	s.NotNil(err)
+}
+
+func (s *BridgeTestSuite) Test_EIP712Domain_EIP5267NotImplemented() {
+	s.client.EXPECT().CallContract(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, &revertError{data: "0x"})
+
+	_, err := bridge.NewBridgeContract(s.testClient, s.bridgeAddress, nil, "")
+
+	s.NotNil(err)
+}
+
+func (s *BridgeTestSuite) Test_EIP712Domain_CallFails() {
+	s.client.EXPECT().CallContract(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("connection refused"))
+
+	_, err := bridge.NewBridgeContract(s.testClient, s.bridgeAddress, nil, "")
+
+	s.NotNil(err)
+}
+
+func (s *BridgeTestSuite) Test_EIP712Domain_UnsupportedReadVersion() {
+	s.client.EXPECT().CallContract(gomock.Any(), gomock.Any(), gomock.Any()).Return(s.eip712DomainOutput(0x0f, "Bridge", "4.0.0"), nil)
+
+	_, err := bridge.NewBridgeContract(s.testClient, s.bridgeAddress, nil, "")
+
+	s.NotNil(err)
+}
+
+func (s *BridgeTestSuite) Test_EIP712Domain_UnsupportedConfiguredVersion() {
+	_, err := bridge.NewBridgeContract(s.testClient, s.bridgeAddress, nil, "3.0.0")
+
+	s.NotNil(err)
+}
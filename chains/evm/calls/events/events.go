@@ -33,6 +33,8 @@ const (
 type Refresh struct {
 	// SHA1 hash of topology file
 	Hash string
+	// Block number of the refresh event
+	BlockNumber uint64
 }
 
 type RetryV1Event struct {
@@ -63,4 +65,10 @@ type Deposit struct {
 	HandlerResponse []byte
 	// Timestamp is the timestamp of the block that the deposit event is in
 	Timestamp time.Time
+	// BlockNumber is the number of the block that the deposit event is in
+	BlockNumber *big.Int
+	// BlockHash is the hash of the block that the deposit event is in
+	BlockHash common.Hash
+	// TxHash is the hash of the deposit transaction
+	TxHash common.Hash
 }
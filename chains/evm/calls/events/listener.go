@@ -19,41 +19,68 @@ import (
 )
 
 type ChainClient interface {
-	FetchEventLogs(ctx context.Context, contractAddress common.Address, event string, startBlock *big.Int, endBlock *big.Int) ([]ethTypes.Log, error)
 	WaitAndReturnTxReceipt(h common.Hash) (*ethTypes.Receipt, error)
 	LatestBlock() (*big.Int, error)
 	BlockByNumber(ctx context.Context, number *big.Int) (*ethTypes.Block, error)
 }
 
+type LogFetcher interface {
+	FetchEventLogs(ctx context.Context, contractAddress common.Address, event string, startBlock *big.Int, endBlock *big.Int) ([]ethTypes.Log, error)
+}
+
 type Listener struct {
-	client   ChainClient
-	abi      abi.ABI
-	retryAbi abi.ABI
+	client     ChainClient
+	logFetcher LogFetcher
+	verifier   LogVerifier
+	abi        abi.ABI
+	retryAbi   abi.ABI
 }
 
-func NewListener(client ChainClient) *Listener {
+// NewListener creates a listener that fetches event logs with the log fetcher. Deposits are verified
+// with the verifier before they are returned if the verifier is provided.
+func NewListener(client ChainClient, logFetcher LogFetcher, verifier LogVerifier) *Listener {
 	retryAbi, _ := abi.JSON(strings.NewReader(consts.RetryABI))
 	abi, _ := abi.JSON(strings.NewReader(consts.BridgeABI))
 	return &Listener{
-		client:   client,
-		abi:      abi,
-		retryAbi: retryAbi,
+		client:     client,
+		logFetcher: logFetcher,
+		verifier:   verifier,
+		abi:        abi,
+		retryAbi:   retryAbi,
 	}
 }
 
 func (l *Listener) FetchDeposits(ctx context.Context, contractAddress common.Address, startBlock *big.Int, endBlock *big.Int) ([]*Deposit, error) {
-	logs, err := l.client.FetchEventLogs(ctx, contractAddress, string(DepositSig), startBlock, endBlock)
+	logs, err := l.logFetcher.FetchEventLogs(ctx, contractAddress, string(DepositSig), startBlock, endBlock)
 	if err != nil {
 		return nil, err
 	}
+	return l.ParseDeposits(ctx, logs)
+}
+
+// ParseDeposits verifies deposit logs if the verifier is provided and unpacks them into deposits.
+// Logs that can not be unpacked are skipped.
+func (l *Listener) ParseDeposits(ctx context.Context, logs []ethTypes.Log) ([]*Deposit, error) {
+	var err error
+	if l.verifier != nil {
+		logs, err = l.verifier.VerifyLogs(ctx, logs)
+		if err != nil {
+			return nil, err
+		}
+	}
 	deposits := make([]*Deposit, 0)
 
 	for _, dl := range logs {
-		d, err := l.parseDeposit(ctx, dl)
+		d, err := l.parseDeposit(dl)
 		if err != nil {
 			log.Error().Msgf("failed unpacking deposit event log: %v", err)
 			continue
 		}
+		// deposits are aggregated by the block timestamp so it can't be replaced by local time
+		d.Timestamp, err = l.blockTimestamp(ctx, dl.BlockNumber)
+		if err != nil {
+			return nil, err
+		}
 
 		log.Debug().Msgf("Found deposit log in block: %d, TxHash: %s, contractAddress: %s, sender: %s", dl.BlockNumber, dl.TxHash, dl.Address, d.SenderAddress)
 		deposits = append(deposits, d)
@@ -62,7 +89,7 @@ func (l *Listener) FetchDeposits(ctx context.Context, contractAddress common.Add
 	return deposits, nil
 }
 
-func (l *Listener) parseDeposit(ctx context.Context, dl ethTypes.Log) (*Deposit, error) {
+func (l *Listener) parseDeposit(dl ethTypes.Log) (*Deposit, error) {
 	var d Deposit
 	err := l.abi.UnpackIntoInterface(&d, "Deposit", dl.Data)
 	if err != nil {
@@ -70,17 +97,20 @@ func (l *Listener) parseDeposit(ctx context.Context, dl ethTypes.Log) (*Deposit,
 	}
 
 	d.SenderAddress = common.BytesToAddress(dl.Topics[1].Bytes())
-	block, err := l.client.BlockByNumber(ctx, new(big.Int).SetUint64(dl.BlockNumber))
-	if err == nil {
-		d.Timestamp = time.Unix(int64(block.Time()), 0)
-	} else {
-		log.Warn().Msgf("Failed fetching block with number %d because of: %+v", dl.BlockNumber, err)
-		d.Timestamp = time.Now()
-	}
-
+	d.BlockNumber = new(big.Int).SetUint64(dl.BlockNumber)
+	d.BlockHash = dl.BlockHash
+	d.TxHash = dl.TxHash
 	return &d, nil
 }
 
+func (l *Listener) blockTimestamp(ctx context.Context, blockNumber uint64) (time.Time, error) {
+	block, err := l.client.BlockByNumber(ctx, new(big.Int).SetUint64(blockNumber))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed fetching block with number %d because of: %w", blockNumber, err)
+	}
+	return time.Unix(int64(block.Time()), 0), nil
+}
+
 func (l *Listener) FetchRetryDepositEvents(event RetryV1Event, bridgeAddress common.Address, blockConfirmations *big.Int) ([]Deposit, error) {
 	depositEvents := make([]Deposit, 0)
 	retryDepositTxHash := common.HexToHash(event.TxHash)
@@ -102,16 +132,31 @@ func (l *Listener) FetchRetryDepositEvents(event RetryV1Event, bridgeAddress com
 		)
 	}
 
+	logs := make([]ethTypes.Log, 0)
 	for _, lg := range receipt.Logs {
 		if lg.Address != bridgeAddress {
 			continue
 		}
+		logs = append(logs, *lg)
+	}
+	if l.verifier != nil {
+		logs, err = l.verifier.VerifyLogs(context.Background(), logs)
+		if err != nil {
+			return depositEvents, err
+		}
+	}
 
-		d, err := l.parseDeposit(context.Background(), *lg)
+	for _, lg := range logs {
+		d, err := l.parseDeposit(lg)
 		if err != nil {
 			log.Error().Msgf("failed unpacking deposit event log: %v", err)
 			continue
 		}
+		// retried deposits are not aggregated so they are executed without timestamp if the block can't be fetched
+		d.Timestamp, err = l.blockTimestamp(context.Background(), lg.BlockNumber)
+		if err != nil {
+			log.Warn().Err(err).Msgf("Failed fetching timestamp of retried deposit")
+		}
 		depositEvents = append(depositEvents, *d)
 	}
 
@@ -119,7 +164,7 @@ func (l *Listener) FetchRetryDepositEvents(event RetryV1Event, bridgeAddress com
 }
 
 func (l *Listener) FetchRetryV1Events(ctx context.Context, contractAddress common.Address, startBlock *big.Int, endBlock *big.Int) ([]RetryV1Event, error) {
-	logs, err := l.client.FetchEventLogs(ctx, contractAddress, string(RetryV1Sig), startBlock, endBlock)
+	logs, err := l.logFetcher.FetchEventLogs(ctx, contractAddress, string(RetryV1Sig), startBlock, endBlock)
 	if err != nil {
 		return nil, err
 	}
@@ -141,7 +186,7 @@ func (l *Listener) FetchRetryV1Events(ctx context.Context, contractAddress commo
 }
 
 func (l *Listener) FetchRetryV2Events(ctx context.Context, contractAddress common.Address, startBlock *big.Int, endBlock *big.Int) ([]RetryV2Event, error) {
-	logs, err := l.client.FetchEventLogs(ctx, contractAddress, string(RetryV2Sig), startBlock, endBlock)
+	logs, err := l.logFetcher.FetchEventLogs(ctx, contractAddress, string(RetryV2Sig), startBlock, endBlock)
 	if err != nil {
 		return nil, err
 	}
@@ -163,7 +208,7 @@ func (l *Listener) FetchRetryV2Events(ctx context.Context, contractAddress commo
 }
 
 func (l *Listener) FetchKeygenEvents(ctx context.Context, contractAddress common.Address, startBlock *big.Int, endBlock *big.Int) ([]ethTypes.Log, error) {
-	logs, err := l.client.FetchEventLogs(ctx, contractAddress, string(StartKeygenSig), startBlock, endBlock)
+	logs, err := l.logFetcher.FetchEventLogs(ctx, contractAddress, string(StartKeygenSig), startBlock, endBlock)
 	if err != nil {
 		return nil, err
 	}
@@ -172,7 +217,7 @@ func (l *Listener) FetchKeygenEvents(ctx context.Context, contractAddress common
 }
 
 func (l *Listener) FetchFrostKeygenEvents(ctx context.Context, contractAddress common.Address, startBlock *big.Int, endBlock *big.Int) ([]ethTypes.Log, error) {
-	logs, err := l.client.FetchEventLogs(ctx, contractAddress, string(StartFrostKeygenSig), startBlock, endBlock)
+	logs, err := l.logFetcher.FetchEventLogs(ctx, contractAddress, string(StartFrostKeygenSig), startBlock, endBlock)
 	if err != nil {
 		return nil, err
 	}
@@ -181,7 +226,7 @@ func (l *Listener) FetchFrostKeygenEvents(ctx context.Context, contractAddress c
 }
 
 func (l *Listener) FetchRefreshEvents(ctx context.Context, contractAddress common.Address, startBlock *big.Int, endBlock *big.Int) ([]*Refresh, error) {
-	logs, err := l.client.FetchEventLogs(ctx, contractAddress, string(KeyRefreshSig), startBlock, endBlock)
+	logs, err := l.logFetcher.FetchEventLogs(ctx, contractAddress, string(KeyRefreshSig), startBlock, endBlock)
 	if err != nil {
 		return nil, err
 	}
@@ -193,6 +238,7 @@ func (l *Listener) FetchRefreshEvents(ctx context.Context, contractAddress commo
 			log.Err(err).Msgf("failed unpacking refresh event log")
 			continue
 		}
+		r.BlockNumber = re.BlockNumber
 
 		refreshEvents = append(refreshEvents, r)
 	}
@@ -4,9 +4,11 @@
 package events_test
 
 import (
+	"context"
 	"fmt"
 	"math/big"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -19,8 +21,9 @@ import (
 
 type ListenerTestSuite struct {
 	suite.Suite
-	mockClient *mock_listener.MockChainClient
-	listener   *events.Listener
+	mockClient     *mock_listener.MockChainClient
+	mockLogFetcher *mock_listener.MockLogFetcher
+	listener       *events.Listener
 }
 
 func TestRunListenerTestSuite(t *testing.T) {
@@ -30,7 +33,8 @@ func TestRunListenerTestSuite(t *testing.T) {
 func (s *ListenerTestSuite) SetupTest() {
 	ctrl := gomock.NewController(s.T())
 	s.mockClient = mock_listener.NewMockChainClient(ctrl)
-	s.listener = events.NewListener(s.mockClient)
+	s.mockLogFetcher = mock_listener.NewMockLogFetcher(ctrl)
+	s.listener = events.NewListener(s.mockClient, s.mockLogFetcher, nil)
 }
 
 func (s *ListenerTestSuite) Test_FetchRetryDepositEvents_FetchingTxFails() {
@@ -135,3 +139,30 @@ func (s *ListenerTestSuite) Test_FetchRetryDepositEvents_ValidEvent() {
 	s.Nil(err)
 	s.Equal(deposits[0].DestinationDomainID, uint8(2))
 }
+
+func (s *ListenerTestSuite) Test_ParseDeposits_FetchingBlockTimestampFails() {
+	depositEvent := common.Hex2Bytes("00000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000300000000000000000000000000000000000000000000000000000000000000001d00000000000000000000000000000000000000000000000000000000000000a00000000000000000000000000000000000000000000000000000000000000120000000000000000000000000000000000000000000000000000000000000005600000000000000000000000000000000000000000000000000000000000f424000000000000000000000000000000000000000000000000000000000000000148e0a907331554af72563bd8d43051c2e64be5d350102000000000000000000000000000000000000000000000000000000000000000000000000000000000000")
+	s.mockClient.EXPECT().BlockByNumber(gomock.Any(), big.NewInt(14)).Return(nil, fmt.Errorf("error"))
+
+	_, err := s.listener.ParseDeposits(context.Background(), []types.Log{{
+		Data:        depositEvent,
+		Topics:      []common.Hash{{}, {}},
+		BlockNumber: 14,
+	}})
+
+	s.NotNil(err)
+}
+
+func (s *ListenerTestSuite) Test_ParseDeposits_UsesBlockTimestamp() {
+	depositEvent := common.Hex2Bytes("00000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000300000000000000000000000000000000000000000000000000000000000000001d00000000000000000000000000000000000000000000000000000000000000a00000000000000000000000000000000000000000000000000000000000000120000000000000000000000000000000000000000000000000000000000000005600000000000000000000000000000000000000000000000000000000000f424000000000000000000000000000000000000000000000000000000000000000148e0a907331554af72563bd8d43051c2e64be5d350102000000000000000000000000000000000000000000000000000000000000000000000000000000000000")
+	s.mockClient.EXPECT().BlockByNumber(gomock.Any(), big.NewInt(14)).Return(types.NewBlockWithHeader(&types.Header{Time: 1000}), nil)
+
+	deposits, err := s.listener.ParseDeposits(context.Background(), []types.Log{{
+		Data:        depositEvent,
+		Topics:      []common.Hash{{}, {}},
+		BlockNumber: 14,
+	}})
+
+	s.Nil(err)
+	s.Equal(time.Unix(1000, 0), deposits[0].Timestamp)
+}
@@ -52,21 +52,6 @@ func (mr *MockChainClientMockRecorder) BlockByNumber(ctx, number interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockByNumber", reflect.TypeOf((*MockChainClient)(nil).BlockByNumber), ctx, number)
 }
 
-// FetchEventLogs mocks base method.
-func (m *MockChainClient) FetchEventLogs(ctx context.Context, contractAddress common.Address, event string, startBlock, endBlock *big.Int) ([]types.Log, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FetchEventLogs", ctx, contractAddress, event, startBlock, endBlock)
-	ret0, _ := ret[0].([]types.Log)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
-
-// FetchEventLogs indicates an expected call of FetchEventLogs.
-func (mr *MockChainClientMockRecorder) FetchEventLogs(ctx, contractAddress, event, startBlock, endBlock interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchEventLogs", reflect.TypeOf((*MockChainClient)(nil).FetchEventLogs), ctx, contractAddress, event, startBlock, endBlock)
-}
-
 // LatestBlock mocks base method.
 func (m *MockChainClient) LatestBlock() (*big.Int, error) {
 	m.ctrl.T.Helper()
@@ -96,3 +81,41 @@ func (mr *MockChainClientMockRecorder) WaitAndReturnTxReceipt(h interface{}) *go
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitAndReturnTxReceipt", reflect.TypeOf((*MockChainClient)(nil).WaitAndReturnTxReceipt), h)
 }
+
+// MockLogFetcher is a mock of LogFetcher interface.
+type MockLogFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockLogFetcherMockRecorder
+}
+
+// MockLogFetcherMockRecorder is the mock recorder for MockLogFetcher.
+type MockLogFetcherMockRecorder struct {
+	mock *MockLogFetcher
+}
+
+// NewMockLogFetcher creates a new mock instance.
+func NewMockLogFetcher(ctrl *gomock.Controller) *MockLogFetcher {
+	mock := &MockLogFetcher{ctrl: ctrl}
+	mock.recorder = &MockLogFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLogFetcher) EXPECT() *MockLogFetcherMockRecorder {
+	return m.recorder
+}
+
+// FetchEventLogs mocks base method.
+func (m *MockLogFetcher) FetchEventLogs(ctx context.Context, contractAddress common.Address, event string, startBlock, endBlock *big.Int) ([]types.Log, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchEventLogs", ctx, contractAddress, event, startBlock, endBlock)
+	ret0, _ := ret[0].([]types.Log)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchEventLogs indicates an expected call of FetchEventLogs.
+func (mr *MockLogFetcherMockRecorder) FetchEventLogs(ctx, contractAddress, event, startBlock, endBlock interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchEventLogs", reflect.TypeOf((*MockLogFetcher)(nil).FetchEventLogs), ctx, contractAddress, event, startBlock, endBlock)
+}
@@ -0,0 +1,175 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package events
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// LimitRecoveryInterval is the interval after which the discovered provider limit is doubled
+// so the limit recovers if the provider limited ranges only temporarily
+const LimitRecoveryInterval = 10 * time.Minute
+
+// rangeLimitErrorCodes are JSON-RPC error codes returned by providers for block ranges
+// that have too many results (-32005 limit exceeded)
+var rangeLimitErrorCodes = []int{-32005}
+
+// rangeLimitErrors are error messages returned by providers for block ranges
+// that are too large or have too many results
+var rangeLimitErrors = []string{
+	"query returned more than",
+	"exceed maximum block range",
+	"block range is too wide",
+	"log response size exceeded",
+	"eth_getlogs is limited to",
+}
+
+// RangeFetcher fetches event logs from a single endpoint in block ranges accepted by the provider.
+// Ranges are split into ranges of at most max range blocks and ranges failing because of the provider
+// range limit are bisected until they succeed. The range of a successful bisection is cached as
+// the provider limit for the following fetches and doubled on every recovery interval.
+type RangeFetcher struct {
+	client           LogFetcher
+	maxRange         *big.Int
+	recoveryInterval time.Duration
+
+	lock      sync.Mutex
+	limit     *big.Int
+	limitedAt time.Time
+
+	log zerolog.Logger
+}
+
+// NewRangeFetcher creates a range fetcher of the endpoint with a max range in blocks. Ranges are not
+// limited until the provider limit is discovered if the max range is zero.
+func NewRangeFetcher(client LogFetcher, domainID uint8, endpoint string, maxRange *big.Int, recoveryInterval time.Duration) *RangeFetcher {
+	var limit *big.Int
+	if maxRange != nil && maxRange.Sign() > 0 {
+		limit = new(big.Int).Set(maxRange)
+	}
+	return &RangeFetcher{
+		client:           client,
+		maxRange:         limit,
+		recoveryInterval: recoveryInterval,
+		limit:            limit,
+		log:              log.With().Uint8("domainID", domainID).Str("endpoint", endpoint).Logger(),
+	}
+}
+
+// FetchEventLogs fetches logs of the event in the inclusive block range
+func (f *RangeFetcher) FetchEventLogs(ctx context.Context, contractAddress common.Address, event string, startBlock *big.Int, endBlock *big.Int) ([]ethTypes.Log, error) {
+	logs := make([]ethTypes.Log, 0)
+	from := new(big.Int).Set(startBlock)
+	for from.Cmp(endBlock) <= 0 {
+		to := new(big.Int).Set(endBlock)
+		limit := f.Limit()
+		if limit != nil {
+			rangeEnd := new(big.Int).Add(from, limit)
+			rangeEnd.Sub(rangeEnd, big.NewInt(1))
+			if rangeEnd.Cmp(to) < 0 {
+				to = rangeEnd
+			}
+		}
+
+		rangeLogs, err := f.fetchRange(ctx, contractAddress, event, from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		logs = append(logs, rangeLogs...)
+		from = new(big.Int).Add(to, big.NewInt(1))
+	}
+	return logs, nil
+}
+
+// Limit returns the current range limit in blocks or nil if ranges are not limited.
+// The discovered provider limit is doubled up to the max range once the recovery interval passes.
+func (f *RangeFetcher) Limit() *big.Int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.limit == nil {
+		return nil
+	}
+	if !f.limitedAt.IsZero() && time.Since(f.limitedAt) >= f.recoveryInterval {
+		f.limit = new(big.Int).Mul(f.limit, big.NewInt(2))
+		f.limitedAt = time.Now()
+		if f.maxRange != nil && f.limit.Cmp(f.maxRange) >= 0 {
+			f.limit = new(big.Int).Set(f.maxRange)
+			f.limitedAt = time.Time{}
+		}
+		f.log.Debug().Msgf("Raising log fetching limit to ranges of %s blocks", f.limit)
+	}
+	return new(big.Int).Set(f.limit)
+}
+
+// fetchRange bisects the range if fetching it fails because of the provider range limit.
+// Ranges of a single block can not be bisected.
+func (f *RangeFetcher) fetchRange(ctx context.Context, contractAddress common.Address, event string, startBlock *big.Int, endBlock *big.Int) ([]ethTypes.Log, error) {
+	logs, err := f.client.FetchEventLogs(ctx, contractAddress, event, startBlock, endBlock)
+	if err == nil {
+		return logs, nil
+	}
+	if startBlock.Cmp(endBlock) == 0 || !isRangeLimitError(err) {
+		return nil, err
+	}
+
+	f.log.Debug().Err(err).Msgf("Fetching logs of block range %s-%s failed, bisecting range", startBlock, endBlock)
+	mid := new(big.Int).Add(startBlock, endBlock)
+	mid.Div(mid, big.NewInt(2))
+	firstLogs, err := f.fetchRange(ctx, contractAddress, event, startBlock, mid)
+	if err != nil {
+		return nil, err
+	}
+	f.lowerLimit(new(big.Int).Sub(new(big.Int).Add(mid, big.NewInt(1)), startBlock))
+
+	secondLogs, err := f.fetchRange(ctx, contractAddress, event, new(big.Int).Add(mid, big.NewInt(1)), endBlock)
+	if err != nil {
+		return nil, err
+	}
+	return append(firstLogs, secondLogs...), nil
+}
+
+// lowerLimit caches the range as the provider limit if it is lower than the current limit
+func (f *RangeFetcher) lowerLimit(blocks *big.Int) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.limit != nil && f.limit.Cmp(blocks) <= 0 {
+		return
+	}
+	f.log.Info().Msgf("Limiting log fetching to ranges of %s blocks", blocks)
+	f.limit = blocks
+	f.limitedAt = time.Now()
+}
+
+func isRangeLimitError(err error) bool {
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		for _, code := range rangeLimitErrorCodes {
+			if rpcErr.ErrorCode() == code {
+				return true
+			}
+		}
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, e := range rangeLimitErrors {
+		if strings.Contains(msg, e) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package events_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/ChainSafe/sygma-relayer/chains/evm/calls/events"
+	mock_listener "github.com/ChainSafe/sygma-relayer/chains/evm/calls/events/mock"
+)
+
+type testRPCError struct {
+	code int
+}
+
+func (e testRPCError) Error() string  { return "limit exceeded" }
+func (e testRPCError) ErrorCode() int { return e.code }
+
+type RangeFetcherTestSuite struct {
+	suite.Suite
+	mockLogFetcher *mock_listener.MockLogFetcher
+}
+
+func TestRunRangeFetcherTestSuite(t *testing.T) {
+	suite.Run(t, new(RangeFetcherTestSuite))
+}
+
+func (s *RangeFetcherTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.mockLogFetcher = mock_listener.NewMockLogFetcher(ctrl)
+}
+
+func (s *RangeFetcherTestSuite) Test_FetchEventLogs_SplitsRangeByMaxRange() {
+	fetcher := events.NewRangeFetcher(s.mockLogFetcher, 1, "endpoint", big.NewInt(4), time.Hour)
+	s.mockLogFetcher.EXPECT().FetchEventLogs(gomock.Any(), gomock.Any(), gomock.Any(), big.NewInt(0), big.NewInt(3)).Return([]types.Log{{BlockNumber: 1}}, nil)
+	s.mockLogFetcher.EXPECT().FetchEventLogs(gomock.Any(), gomock.Any(), gomock.Any(), big.NewInt(4), big.NewInt(7)).Return([]types.Log{{BlockNumber: 5}}, nil)
+	s.mockLogFetcher.EXPECT().FetchEventLogs(gomock.Any(), gomock.Any(), gomock.Any(), big.NewInt(8), big.NewInt(9)).Return([]types.Log{}, nil)
+
+	logs, err := fetcher.FetchEventLogs(context.Background(), common.Address{}, string(events.DepositSig), big.NewInt(0), big.NewInt(9))
+
+	s.Nil(err)
+	s.Equal([]types.Log{{BlockNumber: 1}, {BlockNumber: 5}}, logs)
+}
+
+func (s *RangeFetcherTestSuite) Test_FetchEventLogs_BisectsFailingRangeAndCachesLimit() {
+	fetcher := events.NewRangeFetcher(s.mockLogFetcher, 1, "endpoint", big.NewInt(0), time.Hour)
+	s.mockLogFetcher.EXPECT().FetchEventLogs(gomock.Any(), gomock.Any(), gomock.Any(), big.NewInt(0), big.NewInt(9)).Return(nil, fmt.Errorf("query returned more than 10000 results"))
+	s.mockLogFetcher.EXPECT().FetchEventLogs(gomock.Any(), gomock.Any(), gomock.Any(), big.NewInt(0), big.NewInt(4)).Return([]types.Log{{BlockNumber: 1}}, nil)
+	s.mockLogFetcher.EXPECT().FetchEventLogs(gomock.Any(), gomock.Any(), gomock.Any(), big.NewInt(5), big.NewInt(9)).Return([]types.Log{{BlockNumber: 6}}, nil)
+
+	logs, err := fetcher.FetchEventLogs(context.Background(), common.Address{}, string(events.DepositSig), big.NewInt(0), big.NewInt(9))
+
+	s.Nil(err)
+	s.Equal([]types.Log{{BlockNumber: 1}, {BlockNumber: 6}}, logs)
+	s.Equal(big.NewInt(5), fetcher.Limit())
+
+	s.mockLogFetcher.EXPECT().FetchEventLogs(gomock.Any(), gomock.Any(), gomock.Any(), big.NewInt(10), big.NewInt(14)).Return([]types.Log{}, nil)
+	s.mockLogFetcher.EXPECT().FetchEventLogs(gomock.Any(), gomock.Any(), gomock.Any(), big.NewInt(15), big.NewInt(16)).Return([]types.Log{}, nil)
+
+	_, err = fetcher.FetchEventLogs(context.Background(), common.Address{}, string(events.DepositSig), big.NewInt(10), big.NewInt(16))
+
+	s.Nil(err)
+}
+
+func (s *RangeFetcherTestSuite) Test_FetchEventLogs_SingleBlockFails() {
+	fetcher := events.NewRangeFetcher(s.mockLogFetcher, 1, "endpoint", big.NewInt(0), time.Hour)
+	s.mockLogFetcher.EXPECT().FetchEventLogs(gomock.Any(), gomock.Any(), gomock.Any(), big.NewInt(10), big.NewInt(11)).Return(nil, fmt.Errorf("block range is too wide"))
+	s.mockLogFetcher.EXPECT().FetchEventLogs(gomock.Any(), gomock.Any(), gomock.Any(), big.NewInt(10), big.NewInt(10)).Return(nil, fmt.Errorf("block range is too wide"))
+
+	_, err := fetcher.FetchEventLogs(context.Background(), common.Address{}, string(events.DepositSig), big.NewInt(10), big.NewInt(11))
+
+	s.NotNil(err)
+	s.Nil(fetcher.Limit())
+}
+
+func (s *RangeFetcherTestSuite) Test_FetchEventLogs_OtherErrorNotBisected() {
+	fetcher := events.NewRangeFetcher(s.mockLogFetcher, 1, "endpoint", big.NewInt(0), time.Hour)
+	s.mockLogFetcher.EXPECT().FetchEventLogs(gomock.Any(), gomock.Any(), gomock.Any(), big.NewInt(0), big.NewInt(9)).Return(nil, fmt.Errorf("connection refused"))
+
+	_, err := fetcher.FetchEventLogs(context.Background(), common.Address{}, string(events.DepositSig), big.NewInt(0), big.NewInt(9))
+
+	s.NotNil(err)
+	s.Nil(fetcher.Limit())
+}
+
+func (s *RangeFetcherTestSuite) Test_FetchEventLogs_LimitErrorCodeBisected() {
+	fetcher := events.NewRangeFetcher(s.mockLogFetcher, 1, "endpoint", big.NewInt(0), time.Hour)
+	s.mockLogFetcher.EXPECT().FetchEventLogs(gomock.Any(), gomock.Any(), gomock.Any(), big.NewInt(0), big.NewInt(9)).Return(nil, testRPCError{code: -32005})
+	s.mockLogFetcher.EXPECT().FetchEventLogs(gomock.Any(), gomock.Any(), gomock.Any(), big.NewInt(0), big.NewInt(4)).Return([]types.Log{}, nil)
+	s.mockLogFetcher.EXPECT().FetchEventLogs(gomock.Any(), gomock.Any(), gomock.Any(), big.NewInt(5), big.NewInt(9)).Return([]types.Log{}, nil)
+
+	_, err := fetcher.FetchEventLogs(context.Background(), common.Address{}, string(events.DepositSig), big.NewInt(0), big.NewInt(9))
+
+	s.Nil(err)
+	s.Equal(big.NewInt(5), fetcher.Limit())
+}
+
+func (s *RangeFetcherTestSuite) Test_FetchEventLogs_UnrelatedErrorsNotBisected() {
+	fetcher := events.NewRangeFetcher(s.mockLogFetcher, 1, "endpoint", big.NewInt(0), time.Hour)
+	s.mockLogFetcher.EXPECT().FetchEventLogs(gomock.Any(), gomock.Any(), gomock.Any(), big.NewInt(0), big.NewInt(9)).Return(nil, fmt.Errorf("invalid block range"))
+	s.mockLogFetcher.EXPECT().FetchEventLogs(gomock.Any(), gomock.Any(), gomock.Any(), big.NewInt(0), big.NewInt(9)).Return(nil, testRPCError{code: -32000})
+
+	_, err := fetcher.FetchEventLogs(context.Background(), common.Address{}, string(events.DepositSig), big.NewInt(0), big.NewInt(9))
+	s.NotNil(err)
+	_, err = fetcher.FetchEventLogs(context.Background(), common.Address{}, string(events.DepositSig), big.NewInt(0), big.NewInt(9))
+	s.NotNil(err)
+
+	s.Nil(fetcher.Limit())
+}
+
+func (s *RangeFetcherTestSuite) Test_Limit_RecoversToMaxRange() {
+	fetcher := events.NewRangeFetcher(s.mockLogFetcher, 1, "endpoint", big.NewInt(16), time.Millisecond*10)
+	s.mockLogFetcher.EXPECT().FetchEventLogs(gomock.Any(), gomock.Any(), gomock.Any(), big.NewInt(0), big.NewInt(15)).Return(nil, fmt.Errorf("query returned more than 10000 results"))
+	s.mockLogFetcher.EXPECT().FetchEventLogs(gomock.Any(), gomock.Any(), gomock.Any(), big.NewInt(0), big.NewInt(7)).Return(nil, fmt.Errorf("query returned more than 10000 results"))
+	s.mockLogFetcher.EXPECT().FetchEventLogs(gomock.Any(), gomock.Any(), gomock.Any(), big.NewInt(0), big.NewInt(3)).Return([]types.Log{}, nil)
+	s.mockLogFetcher.EXPECT().FetchEventLogs(gomock.Any(), gomock.Any(), gomock.Any(), big.NewInt(4), big.NewInt(7)).Return([]types.Log{}, nil)
+	s.mockLogFetcher.EXPECT().FetchEventLogs(gomock.Any(), gomock.Any(), gomock.Any(), big.NewInt(8), big.NewInt(15)).Return([]types.Log{}, nil)
+
+	_, err := fetcher.FetchEventLogs(context.Background(), common.Address{}, string(events.DepositSig), big.NewInt(0), big.NewInt(15))
+
+	s.Nil(err)
+	s.Equal(big.NewInt(4), fetcher.Limit())
+
+	time.Sleep(time.Millisecond * 15)
+	s.Equal(big.NewInt(8), fetcher.Limit())
+	time.Sleep(time.Millisecond * 15)
+	s.Equal(big.NewInt(16), fetcher.Limit())
+	time.Sleep(time.Millisecond * 15)
+	s.Equal(big.NewInt(16), fetcher.Limit())
+}
@@ -0,0 +1,202 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package events
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rs/zerolog/log"
+)
+
+// MaxNotFoundRetries is the number of times the block range is retried when quorum endpoints
+// do not find the transaction of a log before the log is dropped and reported as a mismatch
+var MaxNotFoundRetries = 5
+
+type LogVerifier interface {
+	VerifyLogs(ctx context.Context, logs []ethTypes.Log) ([]ethTypes.Log, error)
+}
+
+type ReceiptFetcher interface {
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*ethTypes.Receipt, error)
+}
+
+type QuorumMetrics interface {
+	TrackDepositMismatch(domainID uint8)
+}
+
+// QuorumVerifier verifies logs against transaction receipts returned by independent endpoints
+// so a single malicious or faulty endpoint can not fabricate logs
+type QuorumVerifier struct {
+	domainID uint8
+	clients  []ReceiptFetcher
+	quorum   int
+	metrics  QuorumMetrics
+
+	notFoundLock sync.Mutex
+	notFound     map[common.Hash]int
+}
+
+func NewQuorumVerifier(domainID uint8, clients []ReceiptFetcher, quorum int, metrics QuorumMetrics) (*QuorumVerifier, error) {
+	if quorum < 1 || quorum > len(clients) {
+		return nil, fmt.Errorf("quorum %d invalid for %d endpoints", quorum, len(clients))
+	}
+
+	return &QuorumVerifier{
+		domainID: domainID,
+		clients:  clients,
+		quorum:   quorum,
+		metrics:  metrics,
+		notFound: make(map[common.Hash]int),
+	}, nil
+}
+
+// VerifyLogs returns logs contained in the transaction receipt that at least quorum endpoints returned
+// byte-identical for the same block hash. Logs not contained in the agreed receipt are dropped and
+// reported as mismatches. Returns an error if quorum endpoints did not find the transaction of a log,
+// as endpoints can lag behind, so the block range is retried. The log is dropped and reported as a mismatch
+// only after MaxNotFoundRetries retries. Returns an error if endpoints did not reach the quorum.
+func (v *QuorumVerifier) VerifyLogs(ctx context.Context, logs []ethTypes.Log) ([]ethTypes.Log, error) {
+	receipts := make(map[common.Hash]*ethTypes.Receipt)
+	verifiedLogs := make([]ethTypes.Log, 0)
+	for _, l := range logs {
+		receipt, ok := receipts[l.TxHash]
+		if !ok {
+			var err error
+			receipt, err = v.quorumReceipt(ctx, l.TxHash)
+			if err != nil {
+				return nil, err
+			}
+			if receipt == nil {
+				if v.retryNotFound(l.TxHash) {
+					return nil, fmt.Errorf(
+						"transaction %s of log %d in block %s not found by %d endpoints", l.TxHash, l.Index, l.BlockHash, v.quorum)
+				}
+				log.Error().Uint8("domainID", v.domainID).Msgf(
+					"Transaction %s of log %d in block %s not found by %d endpoints after %d retries", l.TxHash, l.Index, l.BlockHash, v.quorum, MaxNotFoundRetries)
+				v.trackMismatch()
+			} else {
+				v.clearNotFound(l.TxHash)
+			}
+			receipts[l.TxHash] = receipt
+		}
+
+		if receipt == nil {
+			continue
+		}
+		if !containsLog(receipt, l) {
+			log.Error().Uint8("domainID", v.domainID).Msgf(
+				"Log %d of transaction %s in block %s not confirmed by %d endpoints", l.Index, l.TxHash, l.BlockHash, v.quorum)
+			v.trackMismatch()
+			continue
+		}
+		verifiedLogs = append(verifiedLogs, l)
+	}
+
+	return verifiedLogs, nil
+}
+
+// quorumReceipt returns the receipt that at least quorum endpoints agree on or nil
+// if at least quorum endpoints agree the transaction does not exist. Mismatch is reported
+// only if endpoints returned conflicting receipts.
+func (v *QuorumVerifier) quorumReceipt(ctx context.Context, txHash common.Hash) (*ethTypes.Receipt, error) {
+	votes := make(map[common.Hash]int)
+	receipts := make(map[common.Hash]*ethTypes.Receipt)
+	for _, c := range v.clients {
+		receipt, err := c.TransactionReceipt(ctx, txHash)
+		if errors.Is(err, ethereum.NotFound) {
+			votes[common.Hash{}]++
+			continue
+		}
+		if err != nil {
+			log.Warn().Uint8("domainID", v.domainID).Err(err).Msgf("Failed fetching receipt of transaction %s", txHash)
+			continue
+		}
+
+		key, err := receiptKey(receipt)
+		if err != nil {
+			return nil, err
+		}
+		votes[key]++
+		receipts[key] = receipt
+	}
+
+	if len(receipts) > 1 {
+		log.Error().Uint8("domainID", v.domainID).Msgf("Endpoints returned different receipts of transaction %s", txHash)
+		v.trackMismatch()
+	}
+	for key, count := range votes {
+		if count >= v.quorum {
+			return receipts[key], nil
+		}
+	}
+	return nil, fmt.Errorf("quorum of %d endpoints not reached for receipt of transaction %s", v.quorum, txHash)
+}
+
+// retryNotFound counts the attempt and returns true if the transaction
+// was not found less than MaxNotFoundRetries times before
+func (v *QuorumVerifier) retryNotFound(txHash common.Hash) bool {
+	v.notFoundLock.Lock()
+	defer v.notFoundLock.Unlock()
+
+	if v.notFound[txHash] < MaxNotFoundRetries {
+		v.notFound[txHash]++
+		return true
+	}
+	delete(v.notFound, txHash)
+	return false
+}
+
+func (v *QuorumVerifier) clearNotFound(txHash common.Hash) {
+	v.notFoundLock.Lock()
+	defer v.notFoundLock.Unlock()
+
+	delete(v.notFound, txHash)
+}
+
+func (v *QuorumVerifier) trackMismatch() {
+	if v.metrics != nil {
+		v.metrics.TrackDepositMismatch(v.domainID)
+	}
+}
+
+// receiptKey hashes the block hash and the consensus encoding of the receipt
+func receiptKey(receipt *ethTypes.Receipt) (common.Hash, error) {
+	data, err := receipt.MarshalBinary()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(receipt.BlockHash.Bytes(), data), nil
+}
+
+func containsLog(receipt *ethTypes.Receipt, l ethTypes.Log) bool {
+	if receipt == nil || receipt.BlockHash != l.BlockHash {
+		return false
+	}
+
+	for _, rl := range receipt.Logs {
+		if rl.Index != l.Index || rl.Address != l.Address || !bytes.Equal(rl.Data, l.Data) || len(rl.Topics) != len(l.Topics) {
+			continue
+		}
+
+		equal := true
+		for i := range rl.Topics {
+			if rl.Topics[i] != l.Topics[i] {
+				equal = false
+				break
+			}
+		}
+		if equal {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,188 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package events_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/ChainSafe/sygma-relayer/chains/evm/calls/events"
+)
+
+type testReceiptFetcher struct {
+	receipt *types.Receipt
+	err     error
+}
+
+func (f *testReceiptFetcher) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	return f.receipt, f.err
+}
+
+type testQuorumMetrics struct {
+	mismatches int
+}
+
+func (m *testQuorumMetrics) TrackDepositMismatch(domainID uint8) {
+	m.mismatches++
+}
+
+type QuorumVerifierTestSuite struct {
+	suite.Suite
+	metrics *testQuorumMetrics
+	log     types.Log
+	receipt *types.Receipt
+}
+
+func TestRunQuorumVerifierTestSuite(t *testing.T) {
+	suite.Run(t, new(QuorumVerifierTestSuite))
+}
+
+func (s *QuorumVerifierTestSuite) SetupTest() {
+	s.metrics = &testQuorumMetrics{}
+	s.log = types.Log{
+		Address:   common.HexToAddress("0x6CdE2Cd82a4F8B74693Ff5e194c19CA08c2d1c68"),
+		Topics:    []common.Hash{{1}, {2}},
+		Data:      []byte{1, 2, 3},
+		TxHash:    common.Hash{3},
+		BlockHash: common.Hash{4},
+		Index:     1,
+	}
+	l := s.log
+	s.receipt = &types.Receipt{
+		Status:    types.ReceiptStatusSuccessful,
+		BlockHash: common.Hash{4},
+		Logs:      []*types.Log{&l},
+	}
+}
+
+func (s *QuorumVerifierTestSuite) verifier(fetchers ...*testReceiptFetcher) *events.QuorumVerifier {
+	clients := make([]events.ReceiptFetcher, len(fetchers))
+	for i, f := range fetchers {
+		clients[i] = f
+	}
+	v, err := events.NewQuorumVerifier(1, clients, 2, s.metrics)
+	s.Nil(err)
+	return v
+}
+
+func (s *QuorumVerifierTestSuite) Test_InvalidQuorum() {
+	_, err := events.NewQuorumVerifier(1, []events.ReceiptFetcher{&testReceiptFetcher{}}, 2, s.metrics)
+
+	s.NotNil(err)
+}
+
+func (s *QuorumVerifierTestSuite) Test_EndpointsAgree() {
+	v := s.verifier(
+		&testReceiptFetcher{receipt: s.receipt},
+		&testReceiptFetcher{receipt: s.receipt},
+	)
+
+	logs, err := v.VerifyLogs(context.Background(), []types.Log{s.log})
+
+	s.Nil(err)
+	s.Equal([]types.Log{s.log}, logs)
+	s.Equal(0, s.metrics.mismatches)
+}
+
+func (s *QuorumVerifierTestSuite) Test_QuorumAgreesWithMismatchedEndpoint() {
+	forkedReceipt := *s.receipt
+	forkedReceipt.BlockHash = common.Hash{5}
+	v := s.verifier(
+		&testReceiptFetcher{receipt: s.receipt},
+		&testReceiptFetcher{receipt: &forkedReceipt},
+		&testReceiptFetcher{receipt: s.receipt},
+	)
+
+	logs, err := v.VerifyLogs(context.Background(), []types.Log{s.log})
+
+	s.Nil(err)
+	s.Equal([]types.Log{s.log}, logs)
+	s.Equal(1, s.metrics.mismatches)
+}
+
+func (s *QuorumVerifierTestSuite) Test_FabricatedLogDropped() {
+	fabricatedLog := s.log
+	fabricatedLog.Data = []byte{1, 2, 4}
+	v := s.verifier(
+		&testReceiptFetcher{receipt: s.receipt},
+		&testReceiptFetcher{receipt: s.receipt},
+	)
+
+	logs, err := v.VerifyLogs(context.Background(), []types.Log{fabricatedLog})
+
+	s.Nil(err)
+	s.Equal([]types.Log{}, logs)
+	s.Equal(1, s.metrics.mismatches)
+}
+
+func (s *QuorumVerifierTestSuite) Test_FabricatedTransactionDroppedAfterRetries() {
+	v := s.verifier(
+		&testReceiptFetcher{receipt: s.receipt},
+		&testReceiptFetcher{err: ethereum.NotFound},
+		&testReceiptFetcher{err: ethereum.NotFound},
+	)
+
+	for i := 0; i < events.MaxNotFoundRetries; i++ {
+		_, err := v.VerifyLogs(context.Background(), []types.Log{s.log, s.log})
+
+		s.NotNil(err)
+		s.Equal(0, s.metrics.mismatches)
+	}
+	logs, err := v.VerifyLogs(context.Background(), []types.Log{s.log, s.log})
+
+	s.Nil(err)
+	s.Equal([]types.Log{}, logs)
+	s.Equal(1, s.metrics.mismatches)
+}
+
+func (s *QuorumVerifierTestSuite) Test_LaggingQuorumRetried() {
+	lagging := &testReceiptFetcher{err: ethereum.NotFound}
+	v := s.verifier(
+		&testReceiptFetcher{receipt: s.receipt},
+		lagging,
+		&testReceiptFetcher{err: ethereum.NotFound},
+	)
+
+	_, err := v.VerifyLogs(context.Background(), []types.Log{s.log})
+	s.NotNil(err)
+
+	lagging.receipt = s.receipt
+	lagging.err = nil
+	logs, err := v.VerifyLogs(context.Background(), []types.Log{s.log})
+
+	s.Nil(err)
+	s.Equal([]types.Log{s.log}, logs)
+	s.Equal(0, s.metrics.mismatches)
+}
+
+func (s *QuorumVerifierTestSuite) Test_LaggingEndpointNotReportedAsMismatch() {
+	v := s.verifier(
+		&testReceiptFetcher{receipt: s.receipt},
+		&testReceiptFetcher{err: ethereum.NotFound},
+		&testReceiptFetcher{receipt: s.receipt},
+	)
+
+	logs, err := v.VerifyLogs(context.Background(), []types.Log{s.log})
+
+	s.Nil(err)
+	s.Equal([]types.Log{s.log}, logs)
+	s.Equal(0, s.metrics.mismatches)
+}
+
+func (s *QuorumVerifierTestSuite) Test_QuorumNotReached() {
+	v := s.verifier(
+		&testReceiptFetcher{receipt: s.receipt},
+		&testReceiptFetcher{err: fmt.Errorf("error")},
+	)
+
+	_, err := v.VerifyLogs(context.Background(), []types.Log{s.log})
+
+	s.NotNil(err)
+}
@@ -0,0 +1,530 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/rs/zerolog/log"
+	coreClient "github.com/sygmaprotocol/sygma-core/chains/evm/client"
+)
+
+var (
+	// maxFailures is the number of consecutive failures after which the endpoint is considered unhealthy
+	maxFailures = 3
+
+	receiptRetries       = 50
+	receiptRetryInterval = 5 * time.Second
+)
+
+// requestErrors are errors returned by healthy endpoints for invalid requests
+var requestErrors = []string{
+	"execution reverted",
+	"nonce too low",
+	"already known",
+	"replacement transaction underpriced",
+	"insufficient funds",
+}
+
+// Client is the EVM client of a single endpoint
+type Client interface {
+	coreClient.Client
+	ChainID(ctx context.Context) (*big.Int, error)
+	LatestBlock() (*big.Int, error)
+	FetchEventLogs(ctx context.Context, contractAddress common.Address, event string, startBlock *big.Int, endBlock *big.Int) ([]types.Log, error)
+	SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error)
+	BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	BaseFee() (*big.Int, error)
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	CallContext(ctx context.Context, target interface{}, rpcMethod string, args ...interface{}) error
+}
+
+type EndpointMetrics interface {
+	TrackRPCError(domainID uint8, endpoint string, method string)
+	TrackRPCFailover(domainID uint8, endpoint string)
+}
+
+// LogFetcher fetches event logs of a single endpoint
+type LogFetcher interface {
+	FetchEventLogs(ctx context.Context, contractAddress common.Address, event string, startBlock *big.Int, endBlock *big.Int) ([]types.Log, error)
+}
+
+type endpoint struct {
+	name       string
+	client     Client
+	logFetcher LogFetcher
+	failures   int
+	head       *big.Int
+}
+
+// MultiEndpointClient routes calls to the healthiest endpoint of the domain and fails over
+// to other endpoints when the endpoint returns an error or lags behind other endpoints.
+type MultiEndpointClient struct {
+	domainID   uint8
+	endpoints  []*endpoint
+	maxHeadLag *big.Int
+	metrics    EndpointMetrics
+
+	lock    sync.Mutex
+	current *endpoint
+
+	nonce     *big.Int
+	nonceLock sync.Mutex
+}
+
+// NewEVMClient dials every endpoint with the provided signer and creates a client
+// that fails over between endpoints in order of their health
+func NewEVMClient(domainID uint8, urls []string, signer coreClient.Signer, maxHeadLag *big.Int, metrics EndpointMetrics) (*MultiEndpointClient, error) {
+	clients := make(map[string]Client)
+	for _, url := range urls {
+		c, err := coreClient.NewEVMClient(url, signer)
+		if err != nil {
+			return nil, err
+		}
+		clients[url] = c
+	}
+	return NewMultiEndpointClient(domainID, urls, clients, maxHeadLag, metrics)
+}
+
+// NewMultiEndpointClient creates a client from endpoint clients where urls define
+// the endpoint priority if endpoints are equally healthy
+func NewMultiEndpointClient(domainID uint8, urls []string, clients map[string]Client, maxHeadLag *big.Int, metrics EndpointMetrics) (*MultiEndpointClient, error) {
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("no endpoints provided for domain %d", domainID)
+	}
+
+	endpoints := make([]*endpoint, len(urls))
+	for i, url := range urls {
+		c, ok := clients[url]
+		if !ok {
+			return nil, fmt.Errorf("missing client for endpoint %s", EndpointName(url))
+		}
+		endpoints[i] = &endpoint{
+			name:       EndpointName(url),
+			client:     c,
+			logFetcher: c,
+		}
+	}
+	return &MultiEndpointClient{
+		domainID:   domainID,
+		endpoints:  endpoints,
+		maxHeadLag: maxHeadLag,
+		metrics:    metrics,
+		current:    endpoints[0],
+	}, nil
+}
+
+// EndpointName strips the path and query of the endpoint url as they can contain API keys
+func EndpointName(endpointURL string) string {
+	u, err := url.Parse(endpointURL)
+	if err != nil || u.Host == "" {
+		return "invalid"
+	}
+	return fmt.Sprintf("%s://%s", u.Scheme, u.Host)
+}
+
+// EndpointClients returns clients of all endpoints in the order of their priority
+func (c *MultiEndpointClient) EndpointClients() []Client {
+	clients := make([]Client, len(c.endpoints))
+	for i, e := range c.endpoints {
+		clients[i] = e.client
+	}
+	return clients
+}
+
+// WrapLogFetchers replaces log fetchers of endpoints with wrapped fetchers so the state of
+// wrapped fetchers is kept per endpoint. It has to be called before logs are fetched.
+func (c *MultiEndpointClient) WrapLogFetchers(wrap func(endpoint string, fetcher LogFetcher) LogFetcher) {
+	for _, e := range c.endpoints {
+		e.logFetcher = wrap(e.name, e.logFetcher)
+	}
+}
+
+// Monitor periodically fetches heads of all endpoints to detect endpoints that are down or lag behind
+func (c *MultiEndpointClient) Monitor(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			{
+				c.checkEndpoints()
+			}
+		case <-ctx.Done():
+			{
+				return
+			}
+		}
+	}
+}
+
+func (c *MultiEndpointClient) checkEndpoints() {
+	for _, e := range c.endpoints {
+		head, err := e.client.LatestBlock()
+		if err != nil {
+			c.failure(e, "LatestBlock", err)
+			continue
+		}
+		c.success(e, head)
+	}
+}
+
+// call calls the function on endpoints ordered by their health until the call succeeds.
+// Errors caused by the request itself are returned without trying other endpoints.
+func (c *MultiEndpointClient) call(method string, f func(client Client) error) error {
+	_, err := c.callEndpoint(method, f)
+	return err
+}
+
+// callEndpoint calls the function as call does and returns the endpoint that served the call
+func (c *MultiEndpointClient) callEndpoint(method string, f func(client Client) error) (*endpoint, error) {
+	return c.callEndpoints(method, func(e *endpoint) error {
+		return f(e.client)
+	})
+}
+
+// callEndpoints calls the function with endpoints as callEndpoint does
+func (c *MultiEndpointClient) callEndpoints(method string, f func(e *endpoint) error) (*endpoint, error) {
+	var err error
+	for _, e := range c.rankedEndpoints() {
+		c.use(e)
+		err = f(e)
+		if err == nil || !isEndpointError(err) {
+			c.success(e, nil)
+			return e, err
+		}
+
+		c.failure(e, method, err)
+	}
+	return nil, err
+}
+
+// rankedEndpoints orders endpoints by consecutive failures and lag behind the highest known head
+func (c *MultiEndpointClient) rankedEndpoints() []*endpoint {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	bestHead := big.NewInt(0)
+	for _, e := range c.endpoints {
+		if e.head != nil && e.head.Cmp(bestHead) > 0 {
+			bestHead = e.head
+		}
+	}
+
+	ranked := make([]*endpoint, len(c.endpoints))
+	copy(ranked, c.endpoints)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		iHealthy := c.isHealthy(ranked[i], bestHead)
+		jHealthy := c.isHealthy(ranked[j], bestHead)
+		if iHealthy != jHealthy {
+			return iHealthy
+		}
+		return ranked[i].failures < ranked[j].failures
+	})
+	return ranked
+}
+
+func (c *MultiEndpointClient) isHealthy(e *endpoint, bestHead *big.Int) bool {
+	if e.failures >= maxFailures {
+		return false
+	}
+	if e.head == nil || c.maxHeadLag == nil {
+		return true
+	}
+	return new(big.Int).Sub(bestHead, e.head).Cmp(c.maxHeadLag) <= 0
+}
+
+func (c *MultiEndpointClient) use(e *endpoint) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.current == e {
+		return
+	}
+	log.Warn().Uint8("domainID", c.domainID).Msgf("Failing over from endpoint %s to endpoint %s", c.current.name, e.name)
+	c.current = e
+	if c.metrics != nil {
+		c.metrics.TrackRPCFailover(c.domainID, e.name)
+	}
+}
+
+func (c *MultiEndpointClient) success(e *endpoint, head *big.Int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	e.failures = 0
+	if head != nil {
+		e.head = head
+	}
+}
+
+func (c *MultiEndpointClient) failure(e *endpoint, method string, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	e.failures++
+	log.Warn().Uint8("domainID", c.domainID).Err(err).Msgf("Endpoint %s failed calling %s", e.name, method)
+	if c.metrics != nil {
+		c.metrics.TrackRPCError(c.domainID, e.name, method)
+	}
+}
+
+// isEndpointError returns true if the error is caused by the endpoint instead of the request itself
+func isEndpointError(err error) bool {
+	if errors.Is(err, ethereum.NotFound) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		return false
+	}
+
+	msg := err.Error()
+	for _, e := range requestErrors {
+		if strings.Contains(msg, e) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *MultiEndpointClient) LatestBlock() (*big.Int, error) {
+	var head *big.Int
+	e, err := c.callEndpoint("LatestBlock", func(client Client) error {
+		var err error
+		head, err = client.LatestBlock()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.success(e, head)
+	return head, nil
+}
+
+func (c *MultiEndpointClient) ChainID(ctx context.Context) (*big.Int, error) {
+	var chainID *big.Int
+	err := c.call("ChainID", func(client Client) error {
+		var err error
+		chainID, err = client.ChainID(ctx)
+		return err
+	})
+	return chainID, err
+}
+
+func (c *MultiEndpointClient) FetchEventLogs(ctx context.Context, contractAddress common.Address, event string, startBlock *big.Int, endBlock *big.Int) ([]types.Log, error) {
+	var logs []types.Log
+	_, err := c.callEndpoints("FetchEventLogs", func(e *endpoint) error {
+		var err error
+		logs, err = e.logFetcher.FetchEventLogs(ctx, contractAddress, event, startBlock, endBlock)
+		return err
+	})
+	return logs, err
+}
+
+// SubscribeFilterLogs subscribes to logs on the healthiest endpoint that supports subscriptions.
+// Endpoints without subscription support, like HTTP endpoints, are skipped without being penalized.
+func (c *MultiEndpointClient) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	err := fmt.Errorf("no endpoint of domain %d supports subscriptions", c.domainID)
+	for _, e := range c.rankedEndpoints() {
+		sub, subErr := e.client.SubscribeFilterLogs(ctx, q, ch)
+		if subErr == nil {
+			return sub, nil
+		}
+		if errors.Is(subErr, rpc.ErrNotificationsUnsupported) {
+			continue
+		}
+
+		c.failure(e, "SubscribeFilterLogs", subErr)
+		err = subErr
+	}
+	return nil, err
+}
+
+func (c *MultiEndpointClient) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	var block *types.Block
+	err := c.call("BlockByNumber", func(client Client) error {
+		var err error
+		block, err = client.BlockByNumber(ctx, number)
+		return err
+	})
+	return block, err
+}
+
+func (c *MultiEndpointClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	var header *types.Header
+	err := c.call("HeaderByNumber", func(client Client) error {
+		var err error
+		header, err = client.HeaderByNumber(ctx, number)
+		return err
+	})
+	return header, err
+}
+
+func (c *MultiEndpointClient) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	var gasPrice *big.Int
+	err := c.call("SuggestGasPrice", func(client Client) error {
+		var err error
+		gasPrice, err = client.SuggestGasPrice(ctx)
+		return err
+	})
+	return gasPrice, err
+}
+
+func (c *MultiEndpointClient) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	var gasTipCap *big.Int
+	err := c.call("SuggestGasTipCap", func(client Client) error {
+		var err error
+		gasTipCap, err = client.SuggestGasTipCap(ctx)
+		return err
+	})
+	return gasTipCap, err
+}
+
+func (c *MultiEndpointClient) BaseFee() (*big.Int, error) {
+	var baseFee *big.Int
+	err := c.call("BaseFee", func(client Client) error {
+		var err error
+		baseFee, err = client.BaseFee()
+		return err
+	})
+	return baseFee, err
+}
+
+func (c *MultiEndpointClient) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	var nonce uint64
+	err := c.call("PendingNonceAt", func(client Client) error {
+		var err error
+		nonce, err = client.PendingNonceAt(ctx, account)
+		return err
+	})
+	return nonce, err
+}
+
+func (c *MultiEndpointClient) CallContract(ctx context.Context, callArgs map[string]interface{}, blockNumber *big.Int) ([]byte, error) {
+	var out []byte
+	err := c.call("CallContract", func(client Client) error {
+		var err error
+		out, err = client.CallContract(ctx, callArgs, blockNumber)
+		return err
+	})
+	return out, err
+}
+
+// CallContext sends the raw RPC request, used for requests not supported by the client like eth_call with state overrides
+func (c *MultiEndpointClient) CallContext(ctx context.Context, target interface{}, rpcMethod string, args ...interface{}) error {
+	return c.call(rpcMethod, func(client Client) error {
+		return client.CallContext(ctx, target, rpcMethod, args...)
+	})
+}
+
+func (c *MultiEndpointClient) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	var code []byte
+	err := c.call("CodeAt", func(client Client) error {
+		var err error
+		code, err = client.CodeAt(ctx, contract, blockNumber)
+		return err
+	})
+	return code, err
+}
+
+func (c *MultiEndpointClient) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	var receipt *types.Receipt
+	err := c.call("TransactionReceipt", func(client Client) error {
+		var err error
+		receipt, err = client.TransactionReceipt(ctx, txHash)
+		return err
+	})
+	return receipt, err
+}
+
+func (c *MultiEndpointClient) WaitAndReturnTxReceipt(h common.Hash) (*types.Receipt, error) {
+	for retry := receiptRetries; retry > 0; retry-- {
+		receipt, err := c.TransactionReceipt(context.Background(), h)
+		if err != nil {
+			time.Sleep(receiptRetryInterval)
+			continue
+		}
+		if receipt.Status != 1 {
+			return receipt, fmt.Errorf("transaction failed on chain. Receipt status %v", receipt.Status)
+		}
+		return receipt, nil
+	}
+	return nil, errors.New("tx did not appear")
+}
+
+func (c *MultiEndpointClient) SignAndSendTransaction(ctx context.Context, tx coreClient.CommonTransaction) (common.Hash, error) {
+	var hash common.Hash
+	err := c.call("SignAndSendTransaction", func(client Client) error {
+		var err error
+		hash, err = client.SignAndSendTransaction(ctx, tx)
+		return err
+	})
+	return hash, err
+}
+
+func (c *MultiEndpointClient) GetTransactionByHash(h common.Hash) (*types.Transaction, bool, error) {
+	var tx *types.Transaction
+	var isPending bool
+	err := c.call("GetTransactionByHash", func(client Client) error {
+		var err error
+		tx, isPending, err = client.GetTransactionByHash(h)
+		return err
+	})
+	return tx, isPending, err
+}
+
+func (c *MultiEndpointClient) From() common.Address {
+	return c.endpoints[0].client.From()
+}
+
+// LockNonce locks the nonce shared by all endpoints
+func (c *MultiEndpointClient) LockNonce() {
+	c.nonceLock.Lock()
+}
+
+func (c *MultiEndpointClient) UnlockNonce() {
+	c.nonceLock.Unlock()
+}
+
+// UnsafeNonce returns the nonce of the next transaction. Endpoint clients nonces are not used
+// as they would diverge after failing over.
+func (c *MultiEndpointClient) UnsafeNonce() (*big.Int, error) {
+	if c.nonce != nil {
+		return c.nonce, nil
+	}
+
+	nonce, err := c.PendingNonceAt(context.Background(), c.From())
+	if err != nil {
+		return nil, err
+	}
+	c.nonce = new(big.Int).SetUint64(nonce)
+	return c.nonce, nil
+}
+
+func (c *MultiEndpointClient) UnsafeIncreaseNonce() error {
+	nonce, err := c.UnsafeNonce()
+	if err != nil {
+		return err
+	}
+	c.nonce = nonce.Add(nonce, big.NewInt(1))
+	return nil
+}
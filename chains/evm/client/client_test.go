@@ -0,0 +1,197 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package client_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/sygma-relayer/chains/evm/client"
+	mock_client "github.com/ChainSafe/sygma-relayer/chains/evm/client/mock"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+)
+
+type testMetrics struct {
+	errors    map[string]int
+	failovers map[string]int
+}
+
+func (m *testMetrics) TrackRPCError(domainID uint8, endpoint string, method string) {
+	m.errors[endpoint]++
+}
+
+func (m *testMetrics) TrackRPCFailover(domainID uint8, endpoint string) {
+	m.failovers[endpoint]++
+}
+
+type MultiEndpointClientTestSuite struct {
+	suite.Suite
+	primary  *mock_client.MockClient
+	fallback *mock_client.MockClient
+	metrics  *testMetrics
+	client   *client.MultiEndpointClient
+}
+
+func TestRunMultiEndpointClientTestSuite(t *testing.T) {
+	suite.Run(t, new(MultiEndpointClientTestSuite))
+}
+
+func (s *MultiEndpointClientTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.primary = mock_client.NewMockClient(ctrl)
+	s.fallback = mock_client.NewMockClient(ctrl)
+	s.metrics = &testMetrics{
+		errors:    make(map[string]int),
+		failovers: make(map[string]int),
+	}
+	s.client, _ = client.NewMultiEndpointClient(
+		1,
+		[]string{"https://primary.com/key", "https://fallback.com"},
+		map[string]client.Client{"https://primary.com/key": s.primary, "https://fallback.com": s.fallback},
+		big.NewInt(5),
+		s.metrics,
+	)
+}
+
+func (s *MultiEndpointClientTestSuite) Test_MissingEndpoints() {
+	_, err := client.NewMultiEndpointClient(1, []string{}, map[string]client.Client{}, big.NewInt(5), nil)
+
+	s.NotNil(err)
+}
+
+func (s *MultiEndpointClientTestSuite) Test_EndpointName_StripsPath() {
+	s.Equal("https://primary.com", client.EndpointName("https://primary.com/v3/key?token=secret"))
+}
+
+func (s *MultiEndpointClientTestSuite) Test_PrimaryEndpointUsed() {
+	s.primary.EXPECT().LatestBlock().Return(big.NewInt(100), nil)
+
+	head, err := s.client.LatestBlock()
+
+	s.Nil(err)
+	s.Equal(big.NewInt(100), head)
+	s.Equal(0, len(s.metrics.failovers))
+}
+
+func (s *MultiEndpointClientTestSuite) Test_FailsOverOnEndpointError() {
+	s.primary.EXPECT().ChainID(gomock.Any()).Return(nil, errors.New("connection refused"))
+	s.fallback.EXPECT().ChainID(gomock.Any()).Return(big.NewInt(1), nil)
+
+	chainID, err := s.client.ChainID(context.Background())
+
+	s.Nil(err)
+	s.Equal(big.NewInt(1), chainID)
+	s.Equal(1, s.metrics.errors["https://primary.com"])
+	s.Equal(1, s.metrics.failovers["https://fallback.com"])
+}
+
+func (s *MultiEndpointClientTestSuite) Test_RequestErrorReturnedWithoutFailover() {
+	s.primary.EXPECT().TransactionReceipt(gomock.Any(), common.Hash{}).Return(nil, ethereum.NotFound)
+
+	_, err := s.client.TransactionReceipt(context.Background(), common.Hash{})
+
+	s.Equal(ethereum.NotFound, err)
+	s.Equal(0, len(s.metrics.errors))
+}
+
+func (s *MultiEndpointClientTestSuite) Test_AllEndpointsFail() {
+	s.primary.EXPECT().BaseFee().Return(nil, errors.New("connection refused"))
+	s.fallback.EXPECT().BaseFee().Return(nil, errors.New("timeout"))
+
+	_, err := s.client.BaseFee()
+
+	s.NotNil(err)
+	s.Equal(1, s.metrics.errors["https://primary.com"])
+	s.Equal(1, s.metrics.errors["https://fallback.com"])
+}
+
+func (s *MultiEndpointClientTestSuite) Test_FailedEndpointRankedLast() {
+	s.primary.EXPECT().SuggestGasPrice(gomock.Any()).Return(nil, errors.New("connection refused"))
+	s.fallback.EXPECT().SuggestGasPrice(gomock.Any()).Return(big.NewInt(1), nil).Times(2)
+	_, _ = s.client.SuggestGasPrice(context.Background())
+
+	gasPrice, err := s.client.SuggestGasPrice(context.Background())
+
+	s.Nil(err)
+	s.Equal(big.NewInt(1), gasPrice)
+	s.Equal(1, s.metrics.errors["https://primary.com"])
+}
+
+func (s *MultiEndpointClientTestSuite) Test_StaleEndpointRankedLast() {
+	s.primary.EXPECT().LatestBlock().Return(big.NewInt(100), nil).AnyTimes()
+	s.fallback.EXPECT().LatestBlock().Return(big.NewInt(110), nil).AnyTimes()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	s.client.Monitor(ctx, 10*time.Millisecond)
+
+	s.fallback.EXPECT().ChainID(gomock.Any()).Return(big.NewInt(1), nil)
+	chainID, err := s.client.ChainID(context.Background())
+
+	s.Nil(err)
+	s.Equal(big.NewInt(1), chainID)
+	s.Equal(0, len(s.metrics.errors))
+}
+
+func (s *MultiEndpointClientTestSuite) Test_NonceSharedBetweenEndpoints() {
+	s.primary.EXPECT().From().Return(common.Address{1})
+	s.primary.EXPECT().PendingNonceAt(gomock.Any(), common.Address{1}).Return(uint64(5), nil)
+
+	s.client.LockNonce()
+	nonce, err := s.client.UnsafeNonce()
+	s.Nil(err)
+	s.Equal(big.NewInt(5), nonce)
+	err = s.client.UnsafeIncreaseNonce()
+	s.Nil(err)
+	nonce, err = s.client.UnsafeNonce()
+	s.client.UnlockNonce()
+
+	s.Nil(err)
+	s.Equal(big.NewInt(6), nonce)
+}
+
+func (s *MultiEndpointClientTestSuite) Test_SubscribeFilterLogs_SkipsEndpointsWithoutSubscriptions() {
+	s.primary.EXPECT().SubscribeFilterLogs(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, rpc.ErrNotificationsUnsupported)
+	s.fallback.EXPECT().SubscribeFilterLogs(gomock.Any(), gomock.Any(), gomock.Any()).Return(event.NewSubscription(func(<-chan struct{}) error { return nil }), nil)
+
+	sub, err := s.client.SubscribeFilterLogs(context.Background(), ethereum.FilterQuery{}, make(chan types.Log))
+
+	s.Nil(err)
+	s.NotNil(sub)
+	s.Equal(0, len(s.metrics.errors))
+}
+
+func (s *MultiEndpointClientTestSuite) Test_SubscribeFilterLogs_NoEndpointSupportsSubscriptions() {
+	s.primary.EXPECT().SubscribeFilterLogs(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, rpc.ErrNotificationsUnsupported)
+	s.fallback.EXPECT().SubscribeFilterLogs(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, rpc.ErrNotificationsUnsupported)
+
+	_, err := s.client.SubscribeFilterLogs(context.Background(), ethereum.FilterQuery{}, make(chan types.Log))
+
+	s.NotNil(err)
+}
+
+func (s *MultiEndpointClientTestSuite) Test_WrapLogFetchers_FetchesLogsWithEndpointFetchers() {
+	primaryFetcher := mock_client.NewMockLogFetcher(gomock.NewController(s.T()))
+	fallbackFetcher := mock_client.NewMockLogFetcher(gomock.NewController(s.T()))
+	fetchers := map[string]client.LogFetcher{"https://primary.com": primaryFetcher, "https://fallback.com": fallbackFetcher}
+	s.client.WrapLogFetchers(func(endpoint string, fetcher client.LogFetcher) client.LogFetcher {
+		return fetchers[endpoint]
+	})
+	primaryFetcher.EXPECT().FetchEventLogs(gomock.Any(), gomock.Any(), gomock.Any(), big.NewInt(1), big.NewInt(2)).Return(nil, errors.New("connection refused"))
+	fallbackFetcher.EXPECT().FetchEventLogs(gomock.Any(), gomock.Any(), gomock.Any(), big.NewInt(1), big.NewInt(2)).Return([]types.Log{{BlockNumber: 1}}, nil)
+
+	logs, err := s.client.FetchEventLogs(context.Background(), common.Address{}, "event", big.NewInt(1), big.NewInt(2))
+
+	s.Nil(err)
+	s.Equal([]types.Log{{BlockNumber: 1}}, logs)
+	s.Equal(1, s.metrics.errors["https://primary.com"])
+}
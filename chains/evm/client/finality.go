@@ -0,0 +1,72 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+type FinalityMode string
+
+const (
+	ConfirmationsFinality FinalityMode = "confirmations"
+	SafeFinality          FinalityMode = "safe"
+	FinalizedFinality     FinalityMode = "finalized"
+)
+
+type HeadFetcher interface {
+	LatestBlock() (*big.Int, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// Finality resolves the latest final block of the domain by the finality mode of the domain
+type Finality struct {
+	client             HeadFetcher
+	mode               FinalityMode
+	blockConfirmations *big.Int
+}
+
+func NewFinality(client HeadFetcher, mode FinalityMode, blockConfirmations *big.Int) (*Finality, error) {
+	switch mode {
+	case ConfirmationsFinality, SafeFinality, FinalizedFinality:
+	default:
+		return nil, fmt.Errorf("unknown finality mode %s", mode)
+	}
+
+	return &Finality{
+		client:             client,
+		mode:               mode,
+		blockConfirmations: blockConfirmations,
+	}, nil
+}
+
+// FinalizedBlock returns the latest block considered final. In the confirmations mode that is
+// the latest block minus block confirmations, otherwise the block with the safe or finalized tag.
+func (f *Finality) FinalizedBlock() (*big.Int, error) {
+	switch f.mode {
+	case SafeFinality:
+		return f.taggedBlock(rpc.SafeBlockNumber)
+	case FinalizedFinality:
+		return f.taggedBlock(rpc.FinalizedBlockNumber)
+	default:
+		head, err := f.client.LatestBlock()
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Int).Sub(head, f.blockConfirmations), nil
+	}
+}
+
+func (f *Finality) taggedBlock(tag rpc.BlockNumber) (*big.Int, error) {
+	header, err := f.client.HeaderByNumber(context.Background(), big.NewInt(tag.Int64()))
+	if err != nil {
+		return nil, err
+	}
+	return header.Number, nil
+}
@@ -0,0 +1,75 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package client_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/sygma-relayer/chains/evm/client"
+	mock_client "github.com/ChainSafe/sygma-relayer/chains/evm/client/mock"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+)
+
+type FinalityTestSuite struct {
+	suite.Suite
+	client *mock_client.MockClient
+}
+
+func TestRunFinalityTestSuite(t *testing.T) {
+	suite.Run(t, new(FinalityTestSuite))
+}
+
+func (s *FinalityTestSuite) SetupTest() {
+	s.client = mock_client.NewMockClient(gomock.NewController(s.T()))
+}
+
+func (s *FinalityTestSuite) Test_UnknownMode() {
+	_, err := client.NewFinality(s.client, client.FinalityMode("latest"), big.NewInt(5))
+
+	s.NotNil(err)
+}
+
+func (s *FinalityTestSuite) Test_ConfirmationsMode() {
+	finality, _ := client.NewFinality(s.client, client.ConfirmationsFinality, big.NewInt(5))
+	s.client.EXPECT().LatestBlock().Return(big.NewInt(105), nil)
+
+	block, err := finality.FinalizedBlock()
+
+	s.Nil(err)
+	s.Equal(big.NewInt(100), block)
+}
+
+func (s *FinalityTestSuite) Test_SafeMode() {
+	finality, _ := client.NewFinality(s.client, client.SafeFinality, big.NewInt(5))
+	s.client.EXPECT().HeaderByNumber(gomock.Any(), big.NewInt(rpc.SafeBlockNumber.Int64())).Return(&types.Header{Number: big.NewInt(90)}, nil)
+
+	block, err := finality.FinalizedBlock()
+
+	s.Nil(err)
+	s.Equal(big.NewInt(90), block)
+}
+
+func (s *FinalityTestSuite) Test_FinalizedMode() {
+	finality, _ := client.NewFinality(s.client, client.FinalizedFinality, big.NewInt(5))
+	s.client.EXPECT().HeaderByNumber(gomock.Any(), big.NewInt(rpc.FinalizedBlockNumber.Int64())).Return(&types.Header{Number: big.NewInt(80)}, nil)
+
+	block, err := finality.FinalizedBlock()
+
+	s.Nil(err)
+	s.Equal(big.NewInt(80), block)
+}
+
+func (s *FinalityTestSuite) Test_FinalizedMode_FetchFails() {
+	finality, _ := client.NewFinality(s.client, client.FinalizedFinality, big.NewInt(5))
+	s.client.EXPECT().HeaderByNumber(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+	_, err := finality.FinalizedBlock()
+
+	s.NotNil(err)
+}
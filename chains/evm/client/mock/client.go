@@ -0,0 +1,452 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./chains/evm/client/client.go
+
+// Package mock_client is a generated GoMock package.
+package mock_client
+
+import (
+	context "context"
+	big "math/big"
+	reflect "reflect"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	common "github.com/ethereum/go-ethereum/common"
+	types "github.com/ethereum/go-ethereum/core/types"
+	gomock "github.com/golang/mock/gomock"
+	client "github.com/sygmaprotocol/sygma-core/chains/evm/client"
+)
+
+// MockClient is a mock of Client interface.
+type MockClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockClientMockRecorder
+}
+
+// MockClientMockRecorder is the mock recorder for MockClient.
+type MockClientMockRecorder struct {
+	mock *MockClient
+}
+
+// NewMockClient creates a new mock instance.
+func NewMockClient(ctrl *gomock.Controller) *MockClient {
+	mock := &MockClient{ctrl: ctrl}
+	mock.recorder = &MockClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClient) EXPECT() *MockClientMockRecorder {
+	return m.recorder
+}
+
+// BaseFee mocks base method.
+func (m *MockClient) BaseFee() (*big.Int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BaseFee")
+	ret0, _ := ret[0].(*big.Int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BaseFee indicates an expected call of BaseFee.
+func (mr *MockClientMockRecorder) BaseFee() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseFee", reflect.TypeOf((*MockClient)(nil).BaseFee))
+}
+
+// BlockByNumber mocks base method.
+func (m *MockClient) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockByNumber", ctx, number)
+	ret0, _ := ret[0].(*types.Block)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockByNumber indicates an expected call of BlockByNumber.
+func (mr *MockClientMockRecorder) BlockByNumber(ctx, number interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockByNumber", reflect.TypeOf((*MockClient)(nil).BlockByNumber), ctx, number)
+}
+
+// CallContext mocks base method.
+func (m *MockClient) CallContext(ctx context.Context, target interface{}, rpcMethod string, args ...interface{}) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, target, rpcMethod}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CallContext", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CallContext indicates an expected call of CallContext.
+func (mr *MockClientMockRecorder) CallContext(ctx, target, rpcMethod interface{}, args ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, target, rpcMethod}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CallContext", reflect.TypeOf((*MockClient)(nil).CallContext), varargs...)
+}
+
+// CallContract mocks base method.
+func (m *MockClient) CallContract(ctx context.Context, callArgs map[string]interface{}, blockNumber *big.Int) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CallContract", ctx, callArgs, blockNumber)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CallContract indicates an expected call of CallContract.
+func (mr *MockClientMockRecorder) CallContract(ctx, callArgs, blockNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CallContract", reflect.TypeOf((*MockClient)(nil).CallContract), ctx, callArgs, blockNumber)
+}
+
+// ChainID mocks base method.
+func (m *MockClient) ChainID(ctx context.Context) (*big.Int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChainID", ctx)
+	ret0, _ := ret[0].(*big.Int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChainID indicates an expected call of ChainID.
+func (mr *MockClientMockRecorder) ChainID(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChainID", reflect.TypeOf((*MockClient)(nil).ChainID), ctx)
+}
+
+// CodeAt mocks base method.
+func (m *MockClient) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CodeAt", ctx, contract, blockNumber)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CodeAt indicates an expected call of CodeAt.
+func (mr *MockClientMockRecorder) CodeAt(ctx, contract, blockNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CodeAt", reflect.TypeOf((*MockClient)(nil).CodeAt), ctx, contract, blockNumber)
+}
+
+// FetchEventLogs mocks base method.
+func (m *MockClient) FetchEventLogs(ctx context.Context, contractAddress common.Address, event string, startBlock, endBlock *big.Int) ([]types.Log, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchEventLogs", ctx, contractAddress, event, startBlock, endBlock)
+	ret0, _ := ret[0].([]types.Log)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchEventLogs indicates an expected call of FetchEventLogs.
+func (mr *MockClientMockRecorder) FetchEventLogs(ctx, contractAddress, event, startBlock, endBlock interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchEventLogs", reflect.TypeOf((*MockClient)(nil).FetchEventLogs), ctx, contractAddress, event, startBlock, endBlock)
+}
+
+// From mocks base method.
+func (m *MockClient) From() common.Address {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "From")
+	ret0, _ := ret[0].(common.Address)
+	return ret0
+}
+
+// From indicates an expected call of From.
+func (mr *MockClientMockRecorder) From() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "From", reflect.TypeOf((*MockClient)(nil).From))
+}
+
+// GetTransactionByHash mocks base method.
+func (m *MockClient) GetTransactionByHash(h common.Hash) (*types.Transaction, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionByHash", h)
+	ret0, _ := ret[0].(*types.Transaction)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetTransactionByHash indicates an expected call of GetTransactionByHash.
+func (mr *MockClientMockRecorder) GetTransactionByHash(h interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionByHash", reflect.TypeOf((*MockClient)(nil).GetTransactionByHash), h)
+}
+
+// HeaderByNumber mocks base method.
+func (m *MockClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HeaderByNumber", ctx, number)
+	ret0, _ := ret[0].(*types.Header)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HeaderByNumber indicates an expected call of HeaderByNumber.
+func (mr *MockClientMockRecorder) HeaderByNumber(ctx, number interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HeaderByNumber", reflect.TypeOf((*MockClient)(nil).HeaderByNumber), ctx, number)
+}
+
+// LatestBlock mocks base method.
+func (m *MockClient) LatestBlock() (*big.Int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LatestBlock")
+	ret0, _ := ret[0].(*big.Int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LatestBlock indicates an expected call of LatestBlock.
+func (mr *MockClientMockRecorder) LatestBlock() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LatestBlock", reflect.TypeOf((*MockClient)(nil).LatestBlock))
+}
+
+// LockNonce mocks base method.
+func (m *MockClient) LockNonce() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "LockNonce")
+}
+
+// LockNonce indicates an expected call of LockNonce.
+func (mr *MockClientMockRecorder) LockNonce() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockNonce", reflect.TypeOf((*MockClient)(nil).LockNonce))
+}
+
+// PendingNonceAt mocks base method.
+func (m *MockClient) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PendingNonceAt", ctx, account)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PendingNonceAt indicates an expected call of PendingNonceAt.
+func (mr *MockClientMockRecorder) PendingNonceAt(ctx, account interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PendingNonceAt", reflect.TypeOf((*MockClient)(nil).PendingNonceAt), ctx, account)
+}
+
+// SignAndSendTransaction mocks base method.
+func (m *MockClient) SignAndSendTransaction(ctx context.Context, tx client.CommonTransaction) (common.Hash, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignAndSendTransaction", ctx, tx)
+	ret0, _ := ret[0].(common.Hash)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignAndSendTransaction indicates an expected call of SignAndSendTransaction.
+func (mr *MockClientMockRecorder) SignAndSendTransaction(ctx, tx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignAndSendTransaction", reflect.TypeOf((*MockClient)(nil).SignAndSendTransaction), ctx, tx)
+}
+
+// SubscribeFilterLogs mocks base method.
+func (m *MockClient) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeFilterLogs", ctx, q, ch)
+	ret0, _ := ret[0].(ethereum.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeFilterLogs indicates an expected call of SubscribeFilterLogs.
+func (mr *MockClientMockRecorder) SubscribeFilterLogs(ctx, q, ch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeFilterLogs", reflect.TypeOf((*MockClient)(nil).SubscribeFilterLogs), ctx, q, ch)
+}
+
+// SuggestGasPrice mocks base method.
+func (m *MockClient) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SuggestGasPrice", ctx)
+	ret0, _ := ret[0].(*big.Int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SuggestGasPrice indicates an expected call of SuggestGasPrice.
+func (mr *MockClientMockRecorder) SuggestGasPrice(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SuggestGasPrice", reflect.TypeOf((*MockClient)(nil).SuggestGasPrice), ctx)
+}
+
+// SuggestGasTipCap mocks base method.
+func (m *MockClient) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SuggestGasTipCap", ctx)
+	ret0, _ := ret[0].(*big.Int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SuggestGasTipCap indicates an expected call of SuggestGasTipCap.
+func (mr *MockClientMockRecorder) SuggestGasTipCap(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SuggestGasTipCap", reflect.TypeOf((*MockClient)(nil).SuggestGasTipCap), ctx)
+}
+
+// TransactionReceipt mocks base method.
+func (m *MockClient) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransactionReceipt", ctx, txHash)
+	ret0, _ := ret[0].(*types.Receipt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransactionReceipt indicates an expected call of TransactionReceipt.
+func (mr *MockClientMockRecorder) TransactionReceipt(ctx, txHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransactionReceipt", reflect.TypeOf((*MockClient)(nil).TransactionReceipt), ctx, txHash)
+}
+
+// UnlockNonce mocks base method.
+func (m *MockClient) UnlockNonce() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UnlockNonce")
+}
+
+// UnlockNonce indicates an expected call of UnlockNonce.
+func (mr *MockClientMockRecorder) UnlockNonce() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockNonce", reflect.TypeOf((*MockClient)(nil).UnlockNonce))
+}
+
+// UnsafeIncreaseNonce mocks base method.
+func (m *MockClient) UnsafeIncreaseNonce() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnsafeIncreaseNonce")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnsafeIncreaseNonce indicates an expected call of UnsafeIncreaseNonce.
+func (mr *MockClientMockRecorder) UnsafeIncreaseNonce() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnsafeIncreaseNonce", reflect.TypeOf((*MockClient)(nil).UnsafeIncreaseNonce))
+}
+
+// UnsafeNonce mocks base method.
+func (m *MockClient) UnsafeNonce() (*big.Int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnsafeNonce")
+	ret0, _ := ret[0].(*big.Int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnsafeNonce indicates an expected call of UnsafeNonce.
+func (mr *MockClientMockRecorder) UnsafeNonce() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnsafeNonce", reflect.TypeOf((*MockClient)(nil).UnsafeNonce))
+}
+
+// WaitAndReturnTxReceipt mocks base method.
+func (m *MockClient) WaitAndReturnTxReceipt(h common.Hash) (*types.Receipt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitAndReturnTxReceipt", h)
+	ret0, _ := ret[0].(*types.Receipt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WaitAndReturnTxReceipt indicates an expected call of WaitAndReturnTxReceipt.
+func (mr *MockClientMockRecorder) WaitAndReturnTxReceipt(h interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitAndReturnTxReceipt", reflect.TypeOf((*MockClient)(nil).WaitAndReturnTxReceipt), h)
+}
+
+// MockEndpointMetrics is a mock of EndpointMetrics interface.
+type MockEndpointMetrics struct {
+	ctrl     *gomock.Controller
+	recorder *MockEndpointMetricsMockRecorder
+}
+
+// MockEndpointMetricsMockRecorder is the mock recorder for MockEndpointMetrics.
+type MockEndpointMetricsMockRecorder struct {
+	mock *MockEndpointMetrics
+}
+
+// NewMockEndpointMetrics creates a new mock instance.
+func NewMockEndpointMetrics(ctrl *gomock.Controller) *MockEndpointMetrics {
+	mock := &MockEndpointMetrics{ctrl: ctrl}
+	mock.recorder = &MockEndpointMetricsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEndpointMetrics) EXPECT() *MockEndpointMetricsMockRecorder {
+	return m.recorder
+}
+
+// TrackRPCError mocks base method.
+func (m *MockEndpointMetrics) TrackRPCError(domainID uint8, endpoint, method string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "TrackRPCError", domainID, endpoint, method)
+}
+
+// TrackRPCError indicates an expected call of TrackRPCError.
+func (mr *MockEndpointMetricsMockRecorder) TrackRPCError(domainID, endpoint, method interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrackRPCError", reflect.TypeOf((*MockEndpointMetrics)(nil).TrackRPCError), domainID, endpoint, method)
+}
+
+// TrackRPCFailover mocks base method.
+func (m *MockEndpointMetrics) TrackRPCFailover(domainID uint8, endpoint string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "TrackRPCFailover", domainID, endpoint)
+}
+
+// TrackRPCFailover indicates an expected call of TrackRPCFailover.
+func (mr *MockEndpointMetricsMockRecorder) TrackRPCFailover(domainID, endpoint interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrackRPCFailover", reflect.TypeOf((*MockEndpointMetrics)(nil).TrackRPCFailover), domainID, endpoint)
+}
+
+// MockLogFetcher is a mock of LogFetcher interface.
+type MockLogFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockLogFetcherMockRecorder
+}
+
+// MockLogFetcherMockRecorder is the mock recorder for MockLogFetcher.
+type MockLogFetcherMockRecorder struct {
+	mock *MockLogFetcher
+}
+
+// NewMockLogFetcher creates a new mock instance.
+func NewMockLogFetcher(ctrl *gomock.Controller) *MockLogFetcher {
+	mock := &MockLogFetcher{ctrl: ctrl}
+	mock.recorder = &MockLogFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLogFetcher) EXPECT() *MockLogFetcherMockRecorder {
+	return m.recorder
+}
+
+// FetchEventLogs mocks base method.
+func (m *MockLogFetcher) FetchEventLogs(ctx context.Context, contractAddress common.Address, event string, startBlock, endBlock *big.Int) ([]types.Log, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchEventLogs", ctx, contractAddress, event, startBlock, endBlock)
+	ret0, _ := ret[0].([]types.Log)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchEventLogs indicates an expected call of FetchEventLogs.
+func (mr *MockLogFetcherMockRecorder) FetchEventLogs(ctx, contractAddress, event, startBlock, endBlock interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchEventLogs", reflect.TypeOf((*MockLogFetcher)(nil).FetchEventLogs), ctx, contractAddress, event, startBlock, endBlock)
+}
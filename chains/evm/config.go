@@ -12,6 +12,8 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/mitchellh/mapstructure"
 
+	"github.com/ChainSafe/sygma-relayer/chains/evm/calls/consts"
+	"github.com/ChainSafe/sygma-relayer/chains/evm/client"
 	"github.com/ChainSafe/sygma-relayer/config/chain"
 	"github.com/sygmaprotocol/sygma-core/crypto/secp256k1"
 )
@@ -22,8 +24,16 @@ type HandlerConfig struct {
 }
 
 type EVMConfig struct {
-	GeneralChainConfig    chain.GeneralChainConfig
-	Bridge                string
+	GeneralChainConfig  chain.GeneralChainConfig
+	Endpoints           []string
+	MaxHeadLag          *big.Int
+	HealthCheckInterval time.Duration
+	// DepositQuorum enables deposit verification by the number of endpoints if greater than zero
+	DepositQuorum int
+	Bridge        string
+	// BridgeVersion overrides the bridge version read from the bridge with EIP-5267 if set.
+	// It is required for bridges that do not implement EIP-5267.
+	BridgeVersion         string
 	Retry                 string
 	FrostKeygen           string
 	Handlers              []HandlerConfig
@@ -34,22 +44,44 @@ type EVMConfig struct {
 	GasIncreasePercentage *big.Int
 	StartBlock            *big.Int
 	BlockConfirmations    *big.Int
-	BlockInterval         *big.Int
-	BlockRetryInterval    time.Duration
+	// Finality determines if blocks are final after block confirmations or by the safe or finalized block tag
+	Finality           client.FinalityMode
+	BlockInterval      *big.Int
+	BlockRetryInterval time.Duration
+	// MaxLogRange is the maximum number of blocks fetched in a single logs request, unlimited if zero
+	MaxLogRange *big.Int
+	// DepositSubscription enables handling deposits from a websocket log subscription as soon as they are final.
+	// Subscribed deposits are resolved in the same block ranges as polled deposits.
+	DepositSubscription bool
+	// ReorgDepth is the maximum number of blocks the listener rewinds on a chain reorganization
+	ReorgDepth *big.Int
+	// AggregationWindow enables proposal aggregation if greater than zero
+	AggregationWindow time.Duration
+	// AggregationDelay is the time after the window end, measured by the destination block timestamp,
+	// after which windows without later proposals are executed
+	AggregationDelay        time.Duration
+	AggregationMaxProposals int
+	// SubmissionBackOff is the delay between fallback submissions of signed proposals
+	SubmissionBackOff time.Duration
 }
 
 func (c *EVMConfig) String() string {
 	privateKey, _ := crypto.HexToECDSA(c.GeneralChainConfig.Key)
 	kp := secp256k1.NewKeypair(*privateKey)
-	return fmt.Sprintf(`Name: '%s', Id: '%d', Type: '%s', BlockstorePath: '%s', FreshStart: '%t', LatestBlock: '%t', Key address: '%s', Bridge: '%s', Retry: '%s', Handlers: %+v, MaxGasPrice: '%s', GasMultiplier: '%s', GasLimit: '%s', TransferGas: '%d', StartBlock: '%s', BlockConfirmations: '%s', BlockInterval: '%s', BlockRetryInterval: '%s'`,
+	return fmt.Sprintf(`Name: '%s', Id: '%d', Type: '%s', Endpoints: '%d', MaxHeadLag: '%s', HealthCheckInterval: '%s', DepositQuorum: '%d', BlockstorePath: '%s', FreshStart: '%t', LatestBlock: '%t', Key address: '%s', Bridge: '%s', BridgeVersion: '%s', Retry: '%s', Handlers: %+v, MaxGasPrice: '%s', GasMultiplier: '%s', GasLimit: '%s', TransferGas: '%d', StartBlock: '%s', BlockConfirmations: '%s', Finality: '%s', BlockInterval: '%s', BlockRetryInterval: '%s', MaxLogRange: '%s', DepositSubscription: '%t', ReorgDepth: '%s', AggregationWindow: '%s', AggregationDelay: '%s', AggregationMaxProposals: '%d', SubmissionBackOff: '%s'`,
 		c.GeneralChainConfig.Name,
 		*c.GeneralChainConfig.Id,
 		c.GeneralChainConfig.Type,
+		len(c.Endpoints),
+		c.MaxHeadLag,
+		c.HealthCheckInterval,
+		c.DepositQuorum,
 		c.GeneralChainConfig.BlockstorePath,
 		c.GeneralChainConfig.FreshStart,
 		c.GeneralChainConfig.LatestBlock,
 		kp.Address(),
 		c.Bridge,
+		c.BridgeVersion,
 		c.Retry,
 		c.Handlers,
 		c.MaxGasPrice,
@@ -58,14 +90,27 @@ func (c *EVMConfig) String() string {
 		c.TransferGas,
 		c.StartBlock,
 		c.BlockConfirmations,
+		c.Finality,
 		c.BlockInterval,
 		c.BlockRetryInterval,
+		c.MaxLogRange,
+		c.DepositSubscription,
+		c.ReorgDepth,
+		c.AggregationWindow,
+		c.AggregationDelay,
+		c.AggregationMaxProposals,
+		c.SubmissionBackOff,
 	)
 }
 
 type RawEVMConfig struct {
 	chain.GeneralChainConfig `mapstructure:",squash"`
+	Endpoints                []string        `mapstructure:"endpoints"`
+	MaxHeadLag               int64           `mapstructure:"maxHeadLag" default:"10"`
+	HealthCheckInterval      uint64          `mapstructure:"healthCheckInterval" default:"30"`
+	DepositQuorum            int             `mapstructure:"depositQuorum"`
 	Bridge                   string          `mapstructure:"bridge"`
+	BridgeVersion            string          `mapstructure:"bridgeVersion"`
 	Retry                    string          `mapstructure:"retry"`
 	FrostKeygen              string          `mapstructure:"frostKeygen"`
 	Handlers                 []HandlerConfig `mapstrcture:"handlers"`
@@ -76,8 +121,16 @@ type RawEVMConfig struct {
 	TransferGas              uint64          `mapstructure:"transferGas" default:"250000"`
 	StartBlock               int64           `mapstructure:"startBlock"`
 	BlockConfirmations       int64           `mapstructure:"blockConfirmations" default:"10"`
+	Finality                 string          `mapstructure:"finality" default:"confirmations"`
 	BlockInterval            int64           `mapstructure:"blockInterval" default:"5"`
 	BlockRetryInterval       uint64          `mapstructure:"blockRetryInterval" default:"5"`
+	MaxLogRange              int64           `mapstructure:"maxLogRange"`
+	DepositSubscription      bool            `mapstructure:"depositSubscription"`
+	ReorgDepth               int64           `mapstructure:"reorgDepth" default:"128"`
+	AggregationWindow        uint64          `mapstructure:"aggregationWindow"`
+	AggregationDelay         uint64          `mapstructure:"aggregationDelay" default:"120"`
+	AggregationMaxProposals  uint64          `mapstructure:"aggregationMaxProposals" default:"100"`
+	SubmissionBackOff        uint64          `mapstructure:"submissionBackOff" default:"120"`
 }
 
 func (c *RawEVMConfig) Validate() error {
@@ -87,12 +140,45 @@ func (c *RawEVMConfig) Validate() error {
 	if c.Bridge == "" {
 		return fmt.Errorf("required field chain.Bridge empty for chain %v", *c.Id)
 	}
+	if c.BridgeVersion != "" {
+		if _, err := consts.BridgeABIForVersion(c.BridgeVersion); err != nil {
+			return err
+		}
+	}
 	if c.BlockConfirmations < 1 {
 		return fmt.Errorf("blockConfirmations has to be >=1")
 	}
+	switch client.FinalityMode(c.Finality) {
+	case client.ConfirmationsFinality, client.SafeFinality, client.FinalizedFinality:
+	default:
+		return fmt.Errorf("finality has to be one of confirmations, safe or finalized")
+	}
+	if c.MaxLogRange < 0 {
+		return fmt.Errorf("maxLogRange has to be >=0")
+	}
+	if c.ReorgDepth < 1 {
+		return fmt.Errorf("reorgDepth has to be >=1")
+	}
+	if c.AggregationWindow > 0 && c.AggregationMaxProposals < 1 {
+		return fmt.Errorf("aggregationMaxProposals has to be >=1")
+	}
+	if c.DepositQuorum > len(c.endpoints()) {
+		return fmt.Errorf("depositQuorum can not be bigger than the number of endpoints")
+	}
 	return nil
 }
 
+// endpoints returns the general chain endpoint followed by other endpoints of the chain
+func (c *RawEVMConfig) endpoints() []string {
+	endpoints := []string{c.GeneralChainConfig.Endpoint}
+	for _, endpoint := range c.Endpoints {
+		if endpoint != c.GeneralChainConfig.Endpoint {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	return endpoints
+}
+
 // NewEVMConfig decodes and validates an instance of an EVMConfig from
 // raw chain config
 func NewEVMConfig(chainConfig map[string]interface{}) (*EVMConfig, error) {
@@ -115,11 +201,18 @@ func NewEVMConfig(chainConfig map[string]interface{}) (*EVMConfig, error) {
 	c.GeneralChainConfig.ParseFlags()
 	config := &EVMConfig{
 		GeneralChainConfig:    c.GeneralChainConfig,
+		Endpoints:             c.endpoints(),
+		DepositQuorum:         c.DepositQuorum,
+		MaxHeadLag:            big.NewInt(c.MaxHeadLag),
+		HealthCheckInterval:   time.Duration(c.HealthCheckInterval) * time.Second,
 		Handlers:              c.Handlers,
 		Bridge:                c.Bridge,
+		BridgeVersion:         c.BridgeVersion,
 		Retry:                 c.Retry,
 		FrostKeygen:           c.FrostKeygen,
 		BlockRetryInterval:    time.Duration(c.BlockRetryInterval) * time.Second,
+		MaxLogRange:           big.NewInt(c.MaxLogRange),
+		DepositSubscription:   c.DepositSubscription,
 		GasLimit:              big.NewInt(c.GasLimit),
 		TransferGas:           c.TransferGas,
 		MaxGasPrice:           big.NewInt(c.MaxGasPrice),
@@ -127,7 +220,14 @@ func NewEVMConfig(chainConfig map[string]interface{}) (*EVMConfig, error) {
 		GasMultiplier:         big.NewFloat(c.GasMultiplier),
 		StartBlock:            big.NewInt(c.StartBlock),
 		BlockConfirmations:    big.NewInt(c.BlockConfirmations),
+		Finality:              client.FinalityMode(c.Finality),
 		BlockInterval:         big.NewInt(c.BlockInterval),
+		ReorgDepth:            big.NewInt(c.ReorgDepth),
+
+		AggregationWindow:       time.Duration(c.AggregationWindow) * time.Second,
+		AggregationDelay:        time.Duration(c.AggregationDelay) * time.Second,
+		AggregationMaxProposals: int(c.AggregationMaxProposals),
+		SubmissionBackOff:       time.Duration(c.SubmissionBackOff) * time.Second,
 	}
 
 	return config, nil
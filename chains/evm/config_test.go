@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/ChainSafe/sygma-relayer/chains/evm"
+	"github.com/ChainSafe/sygma-relayer/chains/evm/client"
 	"github.com/ChainSafe/sygma-relayer/config/chain"
 	"github.com/stretchr/testify/suite"
 )
@@ -60,6 +61,77 @@ func (s *NewEVMConfigTestSuite) Test_InvalidBlockConfirmation() {
 	s.Equal(err.Error(), "blockConfirmations has to be >=1")
 }
 
+func (s *NewEVMConfigTestSuite) Test_InvalidFinality() {
+	_, err := evm.NewEVMConfig(map[string]interface{}{
+		"id":       1,
+		"endpoint": "ws://domain.com",
+		"name":     "evm1",
+		"from":     "address",
+		"bridge":   "bridgeAddress",
+		"finality": "latest",
+	})
+
+	s.NotNil(err)
+	s.Equal(err.Error(), "finality has to be one of confirmations, safe or finalized")
+}
+
+func (s *NewEVMConfigTestSuite) Test_InvalidMaxLogRange() {
+	_, err := evm.NewEVMConfig(map[string]interface{}{
+		"id":          1,
+		"endpoint":    "ws://domain.com",
+		"name":        "evm1",
+		"from":        "address",
+		"bridge":      "bridgeAddress",
+		"maxLogRange": -1,
+	})
+
+	s.NotNil(err)
+	s.Equal(err.Error(), "maxLogRange has to be >=0")
+}
+
+func (s *NewEVMConfigTestSuite) Test_UnsupportedBridgeVersion() {
+	_, err := evm.NewEVMConfig(map[string]interface{}{
+		"id":            1,
+		"endpoint":      "ws://domain.com",
+		"name":          "evm1",
+		"from":          "address",
+		"bridge":        "bridgeAddress",
+		"bridgeVersion": "2.0.0",
+	})
+
+	s.NotNil(err)
+	s.Equal(err.Error(), "bridge version 2.0.0 not supported")
+}
+
+func (s *NewEVMConfigTestSuite) Test_InvalidReorgDepth() {
+	_, err := evm.NewEVMConfig(map[string]interface{}{
+		"id":         1,
+		"endpoint":   "ws://domain.com",
+		"name":       "evm1",
+		"from":       "address",
+		"bridge":     "bridgeAddress",
+		"reorgDepth": -1,
+	})
+
+	s.NotNil(err)
+	s.Equal(err.Error(), "reorgDepth has to be >=1")
+}
+
+func (s *NewEVMConfigTestSuite) Test_InvalidDepositQuorum() {
+	_, err := evm.NewEVMConfig(map[string]interface{}{
+		"id":            1,
+		"endpoint":      "ws://domain.com",
+		"endpoints":     []string{"ws://domain.com", "ws://fallback.com"},
+		"name":          "evm1",
+		"from":          "address",
+		"bridge":        "bridgeAddress",
+		"depositQuorum": 3,
+	})
+
+	s.NotNil(err)
+	s.Equal(err.Error(), "depositQuorum can not be bigger than the number of endpoints")
+}
+
 func (s *NewEVMConfigTestSuite) Test_ValidConfig() {
 	rawConfig := map[string]interface{}{
 		"id":          1,
@@ -81,6 +153,9 @@ func (s *NewEVMConfigTestSuite) Test_ValidConfig() {
 			Endpoint: "ws://domain.com",
 			Id:       id,
 		},
+		Endpoints:             []string{"ws://domain.com"},
+		MaxHeadLag:            big.NewInt(10),
+		HealthCheckInterval:   time.Duration(30) * time.Second,
 		Bridge:                "bridgeAddress",
 		FrostKeygen:           "frostKeygen",
 		GasLimit:              big.NewInt(15000000),
@@ -90,20 +165,28 @@ func (s *NewEVMConfigTestSuite) Test_ValidConfig() {
 		GasIncreasePercentage: big.NewInt(15),
 		StartBlock:            big.NewInt(0),
 		BlockConfirmations:    big.NewInt(10),
+		Finality:              client.ConfirmationsFinality,
 		BlockInterval:         big.NewInt(5),
 		BlockRetryInterval:    time.Duration(5) * time.Second,
+		MaxLogRange:           big.NewInt(0),
+		ReorgDepth:            big.NewInt(128),
+
+		AggregationDelay:        time.Duration(120) * time.Second,
+		AggregationMaxProposals: 100,
+		SubmissionBackOff:       time.Duration(120) * time.Second,
 	})
 }
 
 func (s *NewEVMConfigTestSuite) Test_ValidConfigWithCustomTxParams() {
 	rawConfig := map[string]interface{}{
-		"id":          1,
-		"endpoint":    "ws://domain.com",
-		"name":        "evm1",
-		"from":        "address",
-		"bridge":      "bridgeAddress",
-		"retry":       "retryAddress",
-		"frostKeygen": "frostKeygen",
+		"id":            1,
+		"endpoint":      "ws://domain.com",
+		"name":          "evm1",
+		"from":          "address",
+		"bridge":        "bridgeAddress",
+		"bridgeVersion": "3.1.0",
+		"retry":         "retryAddress",
+		"frostKeygen":   "frostKeygen",
 		"handlers": []evm.HandlerConfig{
 			{
 				Type:    "erc20",
@@ -114,15 +197,27 @@ func (s *NewEVMConfigTestSuite) Test_ValidConfigWithCustomTxParams() {
 				Address: "address2",
 			},
 		},
-		"maxGasPrice":           1000,
-		"gasMultiplier":         1000,
-		"gasIncreasePercentage": 20,
-		"gasLimit":              1000,
-		"transferGas":           300000,
-		"startBlock":            1000,
-		"blockConfirmations":    10,
-		"blockRetryInterval":    10,
-		"blockInterval":         2,
+		"maxGasPrice":             1000,
+		"gasMultiplier":           1000,
+		"gasIncreasePercentage":   20,
+		"gasLimit":                1000,
+		"transferGas":             300000,
+		"startBlock":              1000,
+		"blockConfirmations":      10,
+		"finality":                "finalized",
+		"blockRetryInterval":      10,
+		"maxLogRange":             1000,
+		"depositSubscription":     true,
+		"blockInterval":           2,
+		"reorgDepth":              64,
+		"aggregationWindow":       30,
+		"aggregationDelay":        60,
+		"aggregationMaxProposals": 50,
+		"submissionBackOff":       30,
+		"endpoints":               []string{"ws://domain.com", "ws://fallback.com"},
+		"maxHeadLag":              5,
+		"healthCheckInterval":     10,
+		"depositQuorum":           2,
 	}
 
 	actualConfig, err := evm.NewEVMConfig(rawConfig)
@@ -136,9 +231,14 @@ func (s *NewEVMConfigTestSuite) Test_ValidConfigWithCustomTxParams() {
 			Endpoint: "ws://domain.com",
 			Id:       id,
 		},
-		Bridge:      "bridgeAddress",
-		Retry:       "retryAddress",
-		FrostKeygen: "frostKeygen",
+		Endpoints:           []string{"ws://domain.com", "ws://fallback.com"},
+		MaxHeadLag:          big.NewInt(5),
+		HealthCheckInterval: time.Duration(10) * time.Second,
+		DepositQuorum:       2,
+		Bridge:              "bridgeAddress",
+		BridgeVersion:       "3.1.0",
+		Retry:               "retryAddress",
+		FrostKeygen:         "frostKeygen",
 		Handlers: []evm.HandlerConfig{
 			{
 				Type:    "erc20",
@@ -156,7 +256,16 @@ func (s *NewEVMConfigTestSuite) Test_ValidConfigWithCustomTxParams() {
 		GasIncreasePercentage: big.NewInt(20),
 		StartBlock:            big.NewInt(1000),
 		BlockConfirmations:    big.NewInt(10),
+		Finality:              client.FinalizedFinality,
 		BlockInterval:         big.NewInt(2),
 		BlockRetryInterval:    time.Duration(10) * time.Second,
+		MaxLogRange:           big.NewInt(1000),
+		DepositSubscription:   true,
+		ReorgDepth:            big.NewInt(64),
+
+		AggregationWindow:       time.Duration(30) * time.Second,
+		AggregationDelay:        time.Duration(60) * time.Second,
+		AggregationMaxProposals: 50,
+		SubmissionBackOff:       time.Duration(30) * time.Second,
 	})
 }
@@ -0,0 +1,232 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rs/zerolog/log"
+	"github.com/sourcegraph/conc/pool"
+	"github.com/sygmaprotocol/sygma-core/relayer/proposal"
+
+	"github.com/ChainSafe/sygma-relayer/relayer/transfer"
+)
+
+type batchExecutor interface {
+	Execute(proposals []*proposal.Proposal) error
+	execute(proposals []*proposal.Proposal, sessionID func(batch *Batch, index int, propHash []byte) string) error
+}
+
+type HeaderFetcher interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// headPollInterval is the interval in which the destination head is fetched while windows are pending
+var headPollInterval = time.Second * 5
+
+type windowKey struct {
+	source uint8
+	index  int64
+}
+
+type aggregationWindow struct {
+	proposals []*proposal.Proposal
+	done      chan struct{}
+	err       error
+}
+
+// ProposalAggregator buffers proposals from different messages so they are signed
+// with a single tss session. Proposals are grouped per source domain by the source block
+// timestamp of the deposit so all relayers aggregate the same proposals.
+//
+// Batch boundaries depend only on chain data. Deposits of a source are received in block order,
+// so a window is flushed as soon as a proposal from a later window of the same source is received.
+// Windows without later proposals are flushed once the timestamp of the destination chain head passes
+// the window end and the configured delay, which should cover the time needed for all relayers
+// to index deposits from the window. Retried proposals and proposals without a timestamp are not
+// aggregated.
+type ProposalAggregator struct {
+	executor     batchExecutor
+	headers      HeaderFetcher
+	window       time.Duration
+	delay        time.Duration
+	maxProposals int
+
+	lock    sync.Mutex
+	windows map[windowKey]*aggregationWindow
+	polling bool
+}
+
+func NewProposalAggregator(executor *Executor, headers HeaderFetcher, window time.Duration, delay time.Duration, maxProposals int) *ProposalAggregator {
+	return newProposalAggregator(executor, headers, window, delay, maxProposals)
+}
+
+func newProposalAggregator(executor batchExecutor, headers HeaderFetcher, window time.Duration, delay time.Duration, maxProposals int) *ProposalAggregator {
+	return &ProposalAggregator{
+		executor:     executor,
+		headers:      headers,
+		window:       window,
+		delay:        delay,
+		maxProposals: maxProposals,
+		windows:      make(map[windowKey]*aggregationWindow),
+	}
+}
+
+// Execute adds proposals to their aggregation windows and waits until windows are executed.
+// Proposals that are not aggregated are executed immediately.
+func (a *ProposalAggregator) Execute(proposals []*proposal.Proposal) error {
+	windows := make(map[*aggregationWindow]bool)
+	directProposals := make([]*proposal.Proposal, 0)
+
+	a.lock.Lock()
+	for _, prop := range proposals {
+		data := prop.Data.(transfer.TransferProposalData)
+		if data.Timestamp.IsZero() || data.Retried {
+			directProposals = append(directProposals, prop)
+			continue
+		}
+
+		key := windowKey{source: prop.Source, index: data.Timestamp.UnixNano() / int64(a.window)}
+		w, ok := a.windows[key]
+		if !ok {
+			w = &aggregationWindow{
+				proposals: make([]*proposal.Proposal, 0),
+				done:      make(chan struct{}),
+			}
+			a.windows[key] = w
+		}
+		w.proposals = append(w.proposals, prop)
+		windows[w] = true
+	}
+	a.flushPrecedingWindows()
+	if len(a.windows) > 0 && !a.polling {
+		a.polling = true
+		go a.pollHead()
+	}
+	a.lock.Unlock()
+
+	p := pool.New().WithErrors()
+	if len(directProposals) > 0 {
+		p.Go(func() error {
+			return a.executor.Execute(directProposals)
+		})
+	}
+	for w := range windows {
+		w := w
+		p.Go(func() error {
+			<-w.done
+			return w.err
+		})
+	}
+	return p.Wait()
+}
+
+// pollHead flushes windows ended before the destination chain head until all windows are flushed
+func (a *ProposalAggregator) pollHead() {
+	for {
+		time.Sleep(headPollInterval)
+
+		header, err := a.headers.HeaderByNumber(context.Background(), nil)
+		if err != nil {
+			log.Warn().Err(err).Msgf("Failed fetching destination head for aggregated proposals")
+			continue
+		}
+
+		a.lock.Lock()
+		a.flushEndedWindows(time.Unix(int64(header.Time), 0))
+		if len(a.windows) == 0 {
+			a.polling = false
+			a.lock.Unlock()
+			return
+		}
+		a.lock.Unlock()
+	}
+}
+
+// flushEndedWindows flushes windows that ended the delay before the provided head timestamp.
+// It expects the lock to be held.
+func (a *ProposalAggregator) flushEndedWindows(head time.Time) {
+	for key := range a.windows {
+		windowEnd := time.Unix(0, (key.index+1)*int64(a.window)).Add(a.delay)
+		if !head.Before(windowEnd) {
+			a.flush(key)
+		}
+	}
+}
+
+// flushPrecedingWindows flushes windows that precede a later window of the same source.
+// It expects the lock to be held.
+func (a *ProposalAggregator) flushPrecedingWindows() {
+	latest := make(map[uint8]int64)
+	for key := range a.windows {
+		if index, ok := latest[key.source]; !ok || key.index > index {
+			latest[key.source] = key.index
+		}
+	}
+	for key := range a.windows {
+		if key.index < latest[key.source] {
+			a.flush(key)
+		}
+	}
+}
+
+// flush removes the window and executes its proposals sorted by deposit nonce in chunks of max proposals.
+// It expects the lock to be held.
+func (a *ProposalAggregator) flush(key windowKey) {
+	w := a.windows[key]
+	delete(a.windows, key)
+
+	proposals := uniqueProposals(w.proposals)
+	sort.SliceStable(proposals, func(i, j int) bool {
+		return depositNonce(proposals[i]) < depositNonce(proposals[j])
+	})
+	log.Info().Msgf("Executing %d aggregated proposals from domain %d for window %d", len(proposals), key.source, key.index)
+
+	go func() {
+		p := pool.New().WithErrors()
+		for chunk := 0; chunk*a.maxProposals < len(proposals); chunk++ {
+			chunk := chunk
+			end := (chunk + 1) * a.maxProposals
+			if end > len(proposals) {
+				end = len(proposals)
+			}
+			chunkProposals := proposals[chunk*a.maxProposals : end]
+
+			p.Go(func() error {
+				return a.executor.execute(chunkProposals, func(batch *Batch, index int, propHash []byte) string {
+					return fmt.Sprintf(
+						"aggregate-%d-%d-%d-%d-%d-%x",
+						chunkProposals[0].Destination, key.source, key.index, chunk, index, propHash,
+					)
+				})
+			})
+		}
+		w.err = p.Wait()
+		close(w.done)
+	}()
+}
+
+func uniqueProposals(proposals []*proposal.Proposal) []*proposal.Proposal {
+	seen := make(map[uint64]bool)
+	unique := make([]*proposal.Proposal, 0, len(proposals))
+	for _, prop := range proposals {
+		nonce := depositNonce(prop)
+		if seen[nonce] {
+			continue
+		}
+		seen[nonce] = true
+		unique = append(unique, prop)
+	}
+	return unique
+}
+
+func depositNonce(prop *proposal.Proposal) uint64 {
+	return prop.Data.(transfer.TransferProposalData).DepositNonce
+}
@@ -0,0 +1,184 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package executor
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/suite"
+	"github.com/sygmaprotocol/sygma-core/relayer/proposal"
+
+	"github.com/ChainSafe/sygma-relayer/relayer/transfer"
+)
+
+type testBatchExecutor struct {
+	lock       sync.Mutex
+	batches    [][]*proposal.Proposal
+	sessionIDs []string
+	direct     [][]*proposal.Proposal
+}
+
+func (e *testBatchExecutor) Execute(proposals []*proposal.Proposal) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.direct = append(e.direct, proposals)
+	return nil
+}
+
+func (e *testBatchExecutor) execute(proposals []*proposal.Proposal, sessionID func(batch *Batch, index int, propHash []byte) string) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.batches = append(e.batches, proposals)
+	e.sessionIDs = append(e.sessionIDs, sessionID(&Batch{}, 0, []byte{1}))
+	return nil
+}
+
+type testHeaderFetcher struct {
+	lock sync.Mutex
+	head time.Time
+}
+
+func (f *testHeaderFetcher) setHead(head time.Time) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.head = head
+}
+
+func (f *testHeaderFetcher) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return &types.Header{Time: uint64(f.head.Unix())}, nil
+}
+
+type AggregatorTestSuite struct {
+	suite.Suite
+	executor   *testBatchExecutor
+	headers    *testHeaderFetcher
+	aggregator *ProposalAggregator
+	windowTime time.Time
+}
+
+func TestRunAggregatorTestSuite(t *testing.T) {
+	suite.Run(t, new(AggregatorTestSuite))
+}
+
+func (s *AggregatorTestSuite) SetupTest() {
+	headPollInterval = time.Millisecond * 10
+	s.executor = &testBatchExecutor{}
+	s.windowTime = time.Unix(600, 0)
+	s.headers = &testHeaderFetcher{head: s.windowTime.Add(time.Minute * 3)}
+	s.aggregator = newProposalAggregator(s.executor, s.headers, time.Minute, time.Minute, 2)
+}
+
+func (s *AggregatorTestSuite) proposal(source uint8, nonce uint64, timestamp time.Time) *proposal.Proposal {
+	return proposal.NewProposal(source, 2, transfer.TransferProposalData{
+		DepositNonce: nonce,
+		Timestamp:    timestamp,
+	}, "", transfer.TransferProposalType)
+}
+
+func (s *AggregatorTestSuite) execute(wg *sync.WaitGroup, proposals ...*proposal.Proposal) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.Nil(s.aggregator.Execute(proposals))
+	}()
+}
+
+func (s *AggregatorTestSuite) Test_Execute_LaterWindowFlushesWindow() {
+	s.headers.setHead(s.windowTime)
+	wg := &sync.WaitGroup{}
+	first := s.proposal(1, 2, s.windowTime)
+	second := s.proposal(1, 1, s.windowTime.Add(time.Second))
+	later := s.proposal(1, 3, s.windowTime.Add(time.Minute))
+
+	s.execute(wg, first)
+	s.execute(wg, second)
+	s.Eventually(func() bool {
+		s.aggregator.lock.Lock()
+		defer s.aggregator.lock.Unlock()
+		return len(s.aggregator.windows) == 1 && len(s.aggregator.windows[windowKey{source: 1, index: 10}].proposals) == 2
+	}, time.Second, time.Millisecond)
+	s.execute(wg, later)
+	s.Eventually(func() bool {
+		s.executor.lock.Lock()
+		defer s.executor.lock.Unlock()
+		return len(s.executor.batches) == 1
+	}, time.Second, time.Millisecond)
+	s.headers.setHead(s.windowTime.Add(time.Minute * 3))
+	wg.Wait()
+
+	s.Equal([][]*proposal.Proposal{{second, first}, {later}}, s.executor.batches)
+	s.Equal([]string{"aggregate-2-1-10-0-0-01", "aggregate-2-1-11-0-0-01"}, s.executor.sessionIDs)
+	s.Nil(s.executor.direct)
+}
+
+func (s *AggregatorTestSuite) Test_Execute_WindowFlushedAfterDestinationHead() {
+	s.headers.setHead(s.windowTime)
+	wg := &sync.WaitGroup{}
+	first := s.proposal(1, 1, s.windowTime)
+	second := s.proposal(1, 2, s.windowTime.Add(time.Second))
+
+	s.execute(wg, first, second)
+	s.headers.setHead(s.windowTime.Add(time.Minute*2 - time.Second))
+	time.Sleep(headPollInterval * 5)
+	s.executor.lock.Lock()
+	s.Nil(s.executor.batches)
+	s.executor.lock.Unlock()
+
+	s.headers.setHead(s.windowTime.Add(time.Minute * 2))
+	wg.Wait()
+
+	s.Equal([][]*proposal.Proposal{{first, second}}, s.executor.batches)
+}
+
+func (s *AggregatorTestSuite) Test_Execute_SourcesAggregatedSeparately() {
+	wg := &sync.WaitGroup{}
+	first := s.proposal(1, 1, s.windowTime)
+	second := s.proposal(3, 1, s.windowTime)
+
+	s.execute(wg, first, second)
+	wg.Wait()
+
+	s.ElementsMatch([][]*proposal.Proposal{{first}, {second}}, s.executor.batches)
+}
+
+func (s *AggregatorTestSuite) Test_Execute_RetriedAndUntimedProposalsNotAggregated() {
+	wg := &sync.WaitGroup{}
+	untimed := s.proposal(1, 1, time.Time{})
+	retried := proposal.NewProposal(1, 2, transfer.TransferProposalData{
+		DepositNonce: 2,
+		Timestamp:    s.windowTime,
+		Retried:      true,
+	}, "", transfer.TransferProposalType)
+
+	s.execute(wg, untimed, retried)
+	wg.Wait()
+
+	s.Equal([][]*proposal.Proposal{{untimed, retried}}, s.executor.direct)
+	s.Nil(s.executor.batches)
+}
+
+func (s *AggregatorTestSuite) Test_Execute_WindowChunkedByMaxProposals() {
+	wg := &sync.WaitGroup{}
+	first := s.proposal(1, 1, s.windowTime)
+	second := s.proposal(1, 2, s.windowTime)
+	third := s.proposal(1, 3, s.windowTime)
+	duplicate := s.proposal(1, 3, s.windowTime)
+
+	s.execute(wg, third, first, second, duplicate)
+	wg.Wait()
+
+	s.ElementsMatch([][]*proposal.Proposal{{first, second}, {third}}, s.executor.batches)
+	s.ElementsMatch([]string{"aggregate-2-1-10-0-0-01", "aggregate-2-1-10-1-0-01"}, s.executor.sessionIDs)
+}
@@ -14,11 +14,15 @@ import (
 	"github.com/sourcegraph/conc/pool"
 
 	ethCommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/libp2p/go-libp2p/core/host"
 	"github.com/rs/zerolog/log"
 
+	"github.com/ChainSafe/sygma-relayer/chains"
+	"github.com/ChainSafe/sygma-relayer/chains/evm/calls/contracts/bridge"
 	"github.com/ChainSafe/sygma-relayer/comm"
 	"github.com/ChainSafe/sygma-relayer/relayer/transfer"
+	"github.com/ChainSafe/sygma-relayer/store"
 	"github.com/ChainSafe/sygma-relayer/tss"
 	"github.com/ChainSafe/sygma-relayer/tss/ecdsa/signing"
 	"github.com/sygmaprotocol/sygma-core/chains/evm/transactor"
@@ -33,49 +37,77 @@ type Batch struct {
 var (
 	executionCheckPeriod = time.Minute
 	signingTimeout       = 30 * time.Minute
+	// simulationBlockTimeout is how long the executor waits for the destination head
+	// to pass the deposit timestamp before proposals are executed without the simulation
+	simulationBlockTimeout = time.Minute
 )
 
 type BridgeContract interface {
-	IsProposalExecuted(p *transfer.TransferProposal) (bool, error)
+	AreProposalsExecuted(proposals []*transfer.TransferProposal) ([]bool, error)
 	ExecuteProposals(proposals []*transfer.TransferProposal, signature []byte, opts transactor.TransactOptions) (*ethCommon.Hash, error)
 	ProposalsHash(proposals []*transfer.TransferProposal) ([]byte, error)
+	SimulateProposals(proposals []*transfer.TransferProposal, blockNumber *big.Int) ([]*bridge.RevertError, error)
+	ProposalExecutionResults(hash ethCommon.Hash) ([]*bridge.ProposalExecutionResult, error)
 }
 
 type Executor struct {
-	coordinator       *tss.Coordinator
+	domainID          uint8
+	scheduler         *tss.Scheduler
 	host              host.Host
 	comm              comm.Communication
 	fetcher           signing.SaveDataFetcher
 	bridge            BridgeContract
+	headers           HeaderFetcher
+	propStorer        PropStorer
 	exitLock          *sync.RWMutex
 	transactionMaxGas uint64
 	transferGasCost   uint64
+	submissionBackOff time.Duration
+	metrics           chains.SubmissionMetrics
 }
 
 func NewExecutor(
+	domainID uint8,
 	host host.Host,
 	comm comm.Communication,
-	coordinator *tss.Coordinator,
+	scheduler *tss.Scheduler,
 	bridgeContract BridgeContract,
+	headers HeaderFetcher,
+	propStorer PropStorer,
 	fetcher signing.SaveDataFetcher,
 	exitLock *sync.RWMutex,
 	transactionMaxGas uint64,
 	transferGasCost uint64,
+	submissionBackOff time.Duration,
+	metrics chains.SubmissionMetrics,
 ) *Executor {
 	return &Executor{
+		domainID:          domainID,
 		host:              host,
 		comm:              comm,
-		coordinator:       coordinator,
+		scheduler:         scheduler,
 		bridge:            bridgeContract,
+		headers:           headers,
+		propStorer:        propStorer,
 		fetcher:           fetcher,
 		exitLock:          exitLock,
 		transactionMaxGas: transactionMaxGas,
 		transferGasCost:   transferGasCost,
+		submissionBackOff: submissionBackOff,
+		metrics:           metrics,
 	}
 }
 
 // Execute starts a signing process and executes proposals when signature is generated
 func (e *Executor) Execute(proposals []*proposal.Proposal) error {
+	return e.execute(proposals, func(batch *Batch, index int, propHash []byte) string {
+		return fmt.Sprintf("%s-%d", batch.proposals[0].MessageID, index)
+	})
+}
+
+// execute signs and executes proposals in batches limited by the transaction max gas
+// where each batch is signed in a session with the ID returned by sessionID
+func (e *Executor) execute(proposals []*proposal.Proposal, sessionID func(batch *Batch, index int, propHash []byte) string) error {
 	e.exitLock.RLock()
 	defer e.exitLock.RUnlock()
 	batches, err := e.proposalBatches(proposals)
@@ -90,6 +122,7 @@ func (e *Executor) Execute(proposals []*proposal.Proposal) error {
 		}
 		messageID := batch.proposals[0].MessageID
 
+		i := i
 		b := batch
 		p.Go(func() error {
 			propHash, err := e.bridge.ProposalsHash(b.proposals)
@@ -97,7 +130,7 @@ func (e *Executor) Execute(proposals []*proposal.Proposal) error {
 				return err
 			}
 
-			sessionID := fmt.Sprintf("%s-%d", messageID, i)
+			sessionID := sessionID(b, i, propHash)
 			log.Info().Str("messageID", batch.proposals[0].MessageID).Msgf("Starting session with ID: %s", sessionID)
 
 			msg := big.NewInt(0)
@@ -118,14 +151,16 @@ func (e *Executor) Execute(proposals []*proposal.Proposal) error {
 			watchContext, cancelWatch := context.WithCancel(context.Background())
 			ep := pool.New().WithErrors()
 			ep.Go(func() error {
-				err := e.coordinator.Execute(executionContext, []tss.TssProcess{signing}, sigChn)
+				err := e.scheduler.Execute(executionContext, tss.SigningPriority(transfer.IsRetried(b.proposals)), transfer.LatestDepositNonce(b.proposals), []tss.TssProcess{signing}, sigChn)
 				if err != nil {
 					cancelWatch()
 				}
 
 				return err
 			})
-			ep.Go(func() error { return e.watchExecution(watchContext, cancelExecution, b, sigChn, sessionID, messageID) })
+			ep.Go(func() error {
+				return e.watchExecution(watchContext, cancelExecution, b, sigChn, signing, sessionID, messageID)
+			})
 			return ep.Wait()
 		})
 	}
@@ -137,6 +172,7 @@ func (e *Executor) watchExecution(
 	cancelExecution context.CancelFunc,
 	batch *Batch,
 	sigChn chan interface{},
+	signing *signing.Signing,
 	sessionID string,
 	messageID string) error {
 	ticker := time.NewTicker(executionCheckPeriod)
@@ -145,6 +181,8 @@ func (e *Executor) watchExecution(
 	defer timeout.Stop()
 	defer cancelExecution()
 
+	var signatureData *common.SignatureData
+	var submissionTimer <-chan time.Time
 	for {
 		select {
 		case sigResult := <-sigChn:
@@ -154,14 +192,33 @@ func (e *Executor) watchExecution(
 					continue
 				}
 
-				signatureData := sigResult.(*common.SignatureData)
-				hash, err := e.executeBatch(batch, signatureData)
+				signatureData = sigResult.(*common.SignatureData)
+				delay := chains.SubmissionDelay(e.host.ID(), signing.Peers, sessionID, e.submissionBackOff)
+				if delay > 0 {
+					log.Info().Str("messageID", messageID).Msgf("Submitting proposals execution if not executed in %s", delay)
+					submissionTimer = time.After(delay)
+					continue
+				}
+
+				err := e.submitBatch(batch, signatureData, sessionID, messageID, false)
 				if err != nil {
-					_ = e.comm.Broadcast(e.host.Peerstore().Peers(), []byte{}, comm.TssFailMsg, sessionID)
 					return err
 				}
+			}
+		case <-submissionTimer:
+			{
+				if e.areProposalsExecuted(batch.proposals) {
+					if e.metrics != nil {
+						e.metrics.TrackSkippedProposalSubmission(e.domainID)
+					}
+					log.Info().Str("messageID", messageID).Msgf("Proposals executed by designated submitter")
+					return nil
+				}
 
-				log.Info().Str("messageID", messageID).Msgf("Sent proposals execution with hash: %s", hash)
+				err := e.submitBatch(batch, signatureData, sessionID, messageID, true)
+				if err != nil {
+					return err
+				}
 			}
 		case <-ticker.C:
 			{
@@ -192,6 +249,7 @@ func (e *Executor) proposalBatches(proposals []*proposal.Proposal) ([]*Batch, er
 	}
 	batches[0] = currentBatch
 
+	transferProposals := make([]*transfer.TransferProposal, 0)
 	for _, prop := range proposals {
 		transferProposal := &transfer.TransferProposal{
 			Source:      prop.Source,
@@ -201,15 +259,45 @@ func (e *Executor) proposalBatches(proposals []*proposal.Proposal) ([]*Batch, er
 			MessageID:   prop.MessageID,
 		}
 
-		isExecuted, err := e.bridge.IsProposalExecuted(transferProposal)
+		isInvalid, err := e.isOrphaned(transferProposal)
 		if err != nil {
 			return nil, err
 		}
-		if isExecuted {
+		if isInvalid {
+			log.Warn().Str("messageID", transferProposal.MessageID).Msgf("Skipping proposal %+v from orphaned block", transferProposal)
+			continue
+		}
+
+		transferProposals = append(transferProposals, transferProposal)
+	}
+
+	executed, err := e.bridge.AreProposalsExecuted(transferProposals)
+	if err != nil {
+		return nil, err
+	}
+
+	pendingProposals := make([]*transfer.TransferProposal, 0, len(transferProposals))
+	for i, transferProposal := range transferProposals {
+		if executed[i] {
 			log.Info().Str("messageID", transferProposal.MessageID).Msgf("Proposal %p already executed", transferProposal)
 			continue
 		}
 
+		pendingProposals = append(pendingProposals, transferProposal)
+	}
+
+	reverts, err := e.simulateProposals(pendingProposals)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, transferProposal := range pendingProposals {
+		if reverts[i] != nil {
+			log.Warn().Str("messageID", transferProposal.MessageID).Msgf("Skipping proposal %+v that would fail: %s", transferProposal, reverts[i].Reason)
+			e.storeProposalFailure(transferProposal, reverts[i].Reason)
+			continue
+		}
+
 		var propGasLimit uint64
 		l, ok := transferProposal.Data.Metadata["gasLimit"]
 		if ok {
@@ -232,6 +320,165 @@ func (e *Executor) proposalBatches(proposals []*proposal.Proposal) ([]*Batch, er
 	return batches, nil
 }
 
+// simulateProposals simulates proposal executions at the destination block all relayers agree on,
+// so all relayers skip the same proposals and sign the same batches. The block is the latest
+// destination block produced before the latest deposit of proposals. Retried proposals are not simulated
+// as their deposits are older than the state that caused the failure, and proposals are not simulated
+// if the block can not be agreed on.
+func (e *Executor) simulateProposals(proposals []*transfer.TransferProposal) ([]*bridge.RevertError, error) {
+	reverts := make([]*bridge.RevertError, len(proposals))
+	simulatedProposals := make([]*transfer.TransferProposal, 0, len(proposals))
+	indexes := make([]int, 0, len(proposals))
+	var depositTime time.Time
+	for i, prop := range proposals {
+		if prop.Data.Retried || prop.Data.Timestamp.IsZero() {
+			continue
+		}
+
+		simulatedProposals = append(simulatedProposals, prop)
+		indexes = append(indexes, i)
+		if prop.Data.Timestamp.After(depositTime) {
+			depositTime = prop.Data.Timestamp
+		}
+	}
+	if len(simulatedProposals) == 0 || e.headers == nil {
+		return reverts, nil
+	}
+
+	blockNumber, err := e.blockBefore(depositTime)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Executing %d proposals without simulation", len(simulatedProposals))
+		return reverts, nil
+	}
+
+	simulated, err := e.bridge.SimulateProposals(simulatedProposals, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	for i, revert := range simulated {
+		reverts[indexes[i]] = revert
+	}
+	return reverts, nil
+}
+
+// blockBefore returns the latest destination block with the timestamp not after the provided time.
+// It waits for the destination head to pass the time so the block can't change afterwards.
+func (e *Executor) blockBefore(t time.Time) (*big.Int, error) {
+	timestamp := uint64(t.Unix())
+	timeout := time.After(simulationBlockTimeout)
+	var head *types.Header
+	for {
+		var err error
+		head, err = e.headers.HeaderByNumber(context.Background(), nil)
+		if err == nil && head.Time > timestamp {
+			break
+		}
+
+		select {
+		case <-timeout:
+			return nil, fmt.Errorf("destination head did not pass deposit time %s", t)
+		case <-time.After(headPollInterval):
+		}
+	}
+
+	// search backwards in increasing steps for the block not after the time
+	// and then bisect between that block and the last block after the time
+	after := new(big.Int).Set(head.Number)
+	before := big.NewInt(0)
+	for step := big.NewInt(1); ; step.Lsh(step, 1) {
+		number := new(big.Int).Sub(head.Number, step)
+		if number.Sign() <= 0 {
+			break
+		}
+
+		header, err := e.headers.HeaderByNumber(context.Background(), number)
+		if err != nil {
+			return nil, err
+		}
+		if header.Time <= timestamp {
+			before = number
+			break
+		}
+		after = number
+	}
+	for new(big.Int).Sub(after, before).Cmp(big.NewInt(1)) > 0 {
+		number := new(big.Int).Rsh(new(big.Int).Add(after, before), 1)
+		header, err := e.headers.HeaderByNumber(context.Background(), number)
+		if err != nil {
+			return nil, err
+		}
+		if header.Time <= timestamp {
+			before = number
+		} else {
+			after = number
+		}
+	}
+	return before, nil
+}
+
+// submitBatch sends the proposals execution as the designated submitter or as a fallback
+// submitter after the designated submitter failed to execute proposals
+func (e *Executor) submitBatch(batch *Batch, signatureData *common.SignatureData, sessionID string, messageID string, fallback bool) error {
+	hash, err := e.executeBatch(batch, signatureData)
+	if err != nil {
+		if e.metrics != nil {
+			e.metrics.TrackWastedProposalSubmission(e.domainID)
+		}
+		_ = e.comm.Broadcast(e.host.Peerstore().Peers(), []byte{}, comm.TssFailMsg, sessionID)
+		return err
+	}
+
+	if e.metrics != nil {
+		e.metrics.TrackProposalSubmission(e.domainID, fallback)
+	}
+	log.Info().Str("messageID", messageID).Msgf("Sent proposals execution with hash: %s", hash)
+	go e.storeExecutionResults(*hash, batch, messageID)
+	return nil
+}
+
+// storeExecutionResults stores the outcome of each proposal executed in the transaction
+func (e *Executor) storeExecutionResults(hash ethCommon.Hash, batch *Batch, messageID string) {
+	results, err := e.bridge.ProposalExecutionResults(hash)
+	if err != nil {
+		log.Warn().Str("messageID", messageID).Err(err).Msgf("Failed fetching execution results of transaction %s", hash)
+		return
+	}
+
+	for _, result := range results {
+		for _, prop := range batch.proposals {
+			if prop.Source != result.OriginDomainID || prop.Data.DepositNonce != result.DepositNonce {
+				continue
+			}
+
+			if !result.Executed {
+				log.Warn().Str("messageID", messageID).Msgf("Handler execution of proposal %+v failed: %s", prop, result.Reason)
+				e.storeProposalFailure(prop, result.Reason)
+				continue
+			}
+
+			err := e.propStorer.StorePropStatus(prop.Source, prop.Destination, prop.Data.DepositNonce, store.ExecutedProp)
+			if err != nil {
+				log.Err(err).Str("messageID", messageID).Msgf("Failed storing proposal %+v status %s", prop, store.ExecutedProp)
+			}
+		}
+	}
+}
+
+// isOrphaned returns true if the proposal deposit is from a source block orphaned by a chain reorganization
+func (e *Executor) isOrphaned(prop *transfer.TransferProposal) (bool, error) {
+	if prop.Data.BlockHash == "" {
+		return false, nil
+	}
+	return e.propStorer.IsPropInvalid(prop.Source, prop.Destination, prop.Data.DepositNonce, prop.Data.BlockHash)
+}
+
+func (e *Executor) storeProposalFailure(prop *transfer.TransferProposal, reason string) {
+	err := e.propStorer.StorePropFailure(prop.Source, prop.Destination, prop.Data.DepositNonce, reason)
+	if err != nil {
+		log.Err(err).Str("messageID", prop.MessageID).Msgf("Failed storing proposal %+v failure", prop)
+	}
+}
+
 func (e *Executor) executeBatch(batch *Batch, signatureData *common.SignatureData) (*ethCommon.Hash, error) {
 	sig := []byte{}
 	sig = append(sig[:], ethCommon.LeftPadBytes(signatureData.R, 32)...)
@@ -250,12 +497,15 @@ func (e *Executor) executeBatch(batch *Batch, signatureData *common.SignatureDat
 }
 
 func (e *Executor) areProposalsExecuted(proposals []*transfer.TransferProposal) bool {
-	for _, prop := range proposals {
-		isExecuted, err := e.bridge.IsProposalExecuted(prop)
-		if err != nil || !isExecuted {
+	executed, err := e.bridge.AreProposalsExecuted(proposals)
+	if err != nil {
+		return false
+	}
+
+	for _, isExecuted := range executed {
+		if !isExecuted {
 			return false
 		}
 	}
-
 	return true
 }
@@ -0,0 +1,76 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package executor
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/suite"
+)
+
+// testChainHeaders returns headers of a chain with blocks produced every 12 seconds
+type testChainHeaders struct {
+	head    int64
+	genesis time.Time
+}
+
+func (h *testChainHeaders) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	if number == nil {
+		number = big.NewInt(h.head)
+	}
+	return &types.Header{
+		Number: number,
+		Time:   uint64(h.genesis.Add(time.Duration(number.Int64()) * 12 * time.Second).Unix()),
+	}, nil
+}
+
+type BlockBeforeTestSuite struct {
+	suite.Suite
+	headers  *testChainHeaders
+	executor *Executor
+}
+
+func TestRunBlockBeforeTestSuite(t *testing.T) {
+	suite.Run(t, new(BlockBeforeTestSuite))
+}
+
+func (s *BlockBeforeTestSuite) SetupTest() {
+	s.headers = &testChainHeaders{head: 1000, genesis: time.Unix(1700000000, 0)}
+	s.executor = &Executor{headers: s.headers}
+}
+
+func (s *BlockBeforeTestSuite) Test_BlockBeforeTime() {
+	for _, block := range []int64{0, 1, 37, 512, 998, 999} {
+		t := s.headers.genesis.Add(time.Duration(block)*12*time.Second + 5*time.Second)
+
+		number, err := s.executor.blockBefore(t)
+
+		s.Nil(err)
+		s.Equal(big.NewInt(block), number)
+	}
+}
+
+func (s *BlockBeforeTestSuite) Test_BlockWithSameTime() {
+	number, err := s.executor.blockBefore(s.headers.genesis.Add(700 * 12 * time.Second))
+
+	s.Nil(err)
+	s.Equal(big.NewInt(700), number)
+}
+
+func (s *BlockBeforeTestSuite) Test_HeadNotAfterTime() {
+	simulationBlockTimeout = time.Millisecond
+	headPollInterval = time.Millisecond
+	defer func() {
+		simulationBlockTimeout = time.Minute
+		headPollInterval = time.Second * 5
+	}()
+
+	_, err := s.executor.blockBefore(s.headers.genesis.Add(1000 * 12 * time.Second))
+
+	s.NotNil(err)
+}
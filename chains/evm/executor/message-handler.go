@@ -30,6 +30,7 @@ func (h *TransferMessageHandler) HandleMessage(msg *message.Message) (*proposal.
 		Data:        msg.Data.(transfer.TransferMessageData),
 		Type:        msg.Type,
 		ID:          msg.ID,
+		Timestamp:   msg.Timestamp,
 	}
 
 	switch transferMessage.Data.Type {
@@ -87,6 +88,9 @@ func PermissionlessGenericMessageHandler(msg *transfer.TransferMessage) (*propos
 		ResourceId:   msg.Data.ResourceId,
 		Metadata:     msg.Data.Metadata,
 		Data:         data.Bytes(),
+		Timestamp:    msg.Timestamp,
+		BlockHash:    msg.Data.BlockHash,
+		Retried:      msg.Data.Retried,
 	}, msg.ID, transfer.TransferProposalType), nil
 }
 
@@ -121,6 +125,9 @@ func ERC20MessageHandler(msg *transfer.TransferMessage) (*proposal.Proposal, err
 		ResourceId:   msg.Data.ResourceId,
 		Metadata:     msg.Data.Metadata,
 		Data:         data,
+		Timestamp:    msg.Timestamp,
+		BlockHash:    msg.Data.BlockHash,
+		Retried:      msg.Data.Retried,
 	}, msg.ID, transfer.TransferProposalType), nil
 }
 
@@ -153,6 +160,9 @@ func ERC721MessageHandler(msg *transfer.TransferMessage) (*proposal.Proposal, er
 		ResourceId:   msg.Data.ResourceId,
 		Metadata:     msg.Data.Metadata,
 		Data:         data.Bytes(),
+		Timestamp:    msg.Timestamp,
+		BlockHash:    msg.Data.BlockHash,
+		Retried:      msg.Data.Retried,
 	}, msg.ID, transfer.TransferProposalType), nil
 }
 
@@ -195,6 +205,9 @@ func ERC1155MessageHandler(msg *transfer.TransferMessage) (*proposal.Proposal, e
 		ResourceId:   msg.Data.ResourceId,
 		Metadata:     msg.Data.Metadata,
 		Data:         data,
+		Timestamp:    msg.Timestamp,
+		BlockHash:    msg.Data.BlockHash,
+		Retried:      msg.Data.Retried,
 	}, msg.ID, transfer.TransferProposalType), nil
 }
 
@@ -215,16 +228,21 @@ func GenericMessageHandler(msg *transfer.TransferMessage) (*proposal.Proposal, e
 		ResourceId:   msg.Data.ResourceId,
 		Metadata:     msg.Data.Metadata,
 		Data:         data.Bytes(),
+		Timestamp:    msg.Timestamp,
+		BlockHash:    msg.Data.BlockHash,
+		Retried:      msg.Data.Retried,
 	}, msg.ID, transfer.TransferProposalType), nil
 }
 
 type BlockFetcher interface {
-	LatestBlock() (*big.Int, error)
+	FinalizedBlock() (*big.Int, error)
 }
 
 type PropStorer interface {
 	StorePropStatus(source, destination uint8, depositNonce uint64, status store.PropStatus) error
 	PropStatus(source, destination uint8, depositNonce uint64) (store.PropStatus, error)
+	StorePropFailure(source, destination uint8, depositNonce uint64, reason string) error
+	IsPropInvalid(source, destination uint8, depositNonce uint64, blockHash string) (bool, error)
 }
 
 type DepositProcessor interface {
@@ -232,39 +250,36 @@ type DepositProcessor interface {
 }
 
 type RetryMessageHandler struct {
-	depositProcessor   DepositProcessor
-	blockConfirmations *big.Int
-	blockFetcher       BlockFetcher
-	propStorer         PropStorer
-	msgChan            chan []*message.Message
+	depositProcessor DepositProcessor
+	blockFetcher     BlockFetcher
+	propStorer       PropStorer
+	msgChan          chan []*message.Message
 }
 
 func NewRetryMessageHandler(
 	depositProcessor DepositProcessor,
 	blockFetcher BlockFetcher,
 	propStorer PropStorer,
-	blockConfirmations *big.Int,
 	msgChan chan []*message.Message) *RetryMessageHandler {
 	return &RetryMessageHandler{
-		depositProcessor:   depositProcessor,
-		blockFetcher:       blockFetcher,
-		propStorer:         propStorer,
-		blockConfirmations: blockConfirmations,
-		msgChan:            msgChan,
+		depositProcessor: depositProcessor,
+		blockFetcher:     blockFetcher,
+		propStorer:       propStorer,
+		msgChan:          msgChan,
 	}
 }
 
 func (h *RetryMessageHandler) HandleMessage(msg *message.Message) (*proposal.Proposal, error) {
 	retryData := msg.Data.(retry.RetryMessageData)
-	latestBlock, err := h.blockFetcher.LatestBlock()
+	finalizedBlock, err := h.blockFetcher.FinalizedBlock()
 	if err != nil {
 		return nil, err
 	}
-	if latestBlock.Cmp(new(big.Int).Add(retryData.BlockHeight, h.blockConfirmations)) != 1 {
+	if finalizedBlock.Cmp(retryData.BlockHeight) != 1 {
 		return nil, fmt.Errorf(
-			"latest block %s higher than receipt block number + block confirmations %s",
-			latestBlock,
-			new(big.Int).Add(retryData.BlockHeight, h.blockConfirmations),
+			"receipt block number %s is not finalized, latest finalized block %s",
+			retryData.BlockHeight,
+			finalizedBlock,
 		)
 	}
 
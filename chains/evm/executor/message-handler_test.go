@@ -705,12 +705,11 @@ func (s *RetryMessageHandlerTestSuite) SetupTest() {
 		s.mockDepositProcessor,
 		s.mockBlockFetcher,
 		s.mockPropStorer,
-		big.NewInt(5),
 		s.msgChan)
 }
 
 func (s *RetryMessageHandlerTestSuite) Test_HandleMessage_RetryTooNew() {
-	s.mockBlockFetcher.EXPECT().LatestBlock().Return(big.NewInt(105), nil)
+	s.mockBlockFetcher.EXPECT().FinalizedBlock().Return(big.NewInt(100), nil)
 
 	message := &message.Message{
 		Source:      1,
@@ -731,7 +730,7 @@ func (s *RetryMessageHandlerTestSuite) Test_HandleMessage_RetryTooNew() {
 }
 
 func (s *RetryMessageHandlerTestSuite) Test_HandleMessage_NoDeposits() {
-	s.mockBlockFetcher.EXPECT().LatestBlock().Return(big.NewInt(106), nil)
+	s.mockBlockFetcher.EXPECT().FinalizedBlock().Return(big.NewInt(101), nil)
 	s.mockDepositProcessor.EXPECT().ProcessDeposits(big.NewInt(100), big.NewInt(100)).Return(make(map[uint8][]*message.Message), nil)
 
 	message := &message.Message{
@@ -754,7 +753,7 @@ func (s *RetryMessageHandlerTestSuite) Test_HandleMessage_NoDeposits() {
 }
 
 func (s *RetryMessageHandlerTestSuite) Test_HandleMessage_ValidDeposits() {
-	s.mockBlockFetcher.EXPECT().LatestBlock().Return(big.NewInt(106), nil)
+	s.mockBlockFetcher.EXPECT().FinalizedBlock().Return(big.NewInt(101), nil)
 
 	validResource := evm.SliceTo32Bytes(common.LeftPadBytes([]byte{3}, 31))
 	invalidResource := evm.SliceTo32Bytes(common.LeftPadBytes([]byte{4}, 31))
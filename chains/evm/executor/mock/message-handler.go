@@ -36,19 +36,19 @@ func (m *MockBlockFetcher) EXPECT() *MockBlockFetcherMockRecorder {
 	return m.recorder
 }
 
-// LatestBlock mocks base method.
-func (m *MockBlockFetcher) LatestBlock() (*big.Int, error) {
+// FinalizedBlock mocks base method.
+func (m *MockBlockFetcher) FinalizedBlock() (*big.Int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "LatestBlock")
+	ret := m.ctrl.Call(m, "FinalizedBlock")
 	ret0, _ := ret[0].(*big.Int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// LatestBlock indicates an expected call of LatestBlock.
-func (mr *MockBlockFetcherMockRecorder) LatestBlock() *gomock.Call {
+// FinalizedBlock indicates an expected call of FinalizedBlock.
+func (mr *MockBlockFetcherMockRecorder) FinalizedBlock() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LatestBlock", reflect.TypeOf((*MockBlockFetcher)(nil).LatestBlock))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinalizedBlock", reflect.TypeOf((*MockBlockFetcher)(nil).FinalizedBlock))
 }
 
 // MockPropStorer is a mock of PropStorer interface.
@@ -74,6 +74,21 @@ func (m *MockPropStorer) EXPECT() *MockPropStorerMockRecorder {
 	return m.recorder
 }
 
+// IsPropInvalid mocks base method.
+func (m *MockPropStorer) IsPropInvalid(source, destination uint8, depositNonce uint64, blockHash string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsPropInvalid", source, destination, depositNonce, blockHash)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsPropInvalid indicates an expected call of IsPropInvalid.
+func (mr *MockPropStorerMockRecorder) IsPropInvalid(source, destination, depositNonce, blockHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsPropInvalid", reflect.TypeOf((*MockPropStorer)(nil).IsPropInvalid), source, destination, depositNonce, blockHash)
+}
+
 // PropStatus mocks base method.
 func (m *MockPropStorer) PropStatus(source, destination uint8, depositNonce uint64) (store.PropStatus, error) {
 	m.ctrl.T.Helper()
@@ -89,6 +104,20 @@ func (mr *MockPropStorerMockRecorder) PropStatus(source, destination, depositNon
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PropStatus", reflect.TypeOf((*MockPropStorer)(nil).PropStatus), source, destination, depositNonce)
 }
 
+// StorePropFailure mocks base method.
+func (m *MockPropStorer) StorePropFailure(source, destination uint8, depositNonce uint64, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StorePropFailure", source, destination, depositNonce, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StorePropFailure indicates an expected call of StorePropFailure.
+func (mr *MockPropStorerMockRecorder) StorePropFailure(source, destination, depositNonce, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StorePropFailure", reflect.TypeOf((*MockPropStorer)(nil).StorePropFailure), source, destination, depositNonce, reason)
+}
+
 // StorePropStatus mocks base method.
 func (m *MockPropStorer) StorePropStatus(source, destination uint8, depositNonce uint64, status store.PropStatus) error {
 	m.ctrl.T.Helper()
@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"sync"
 	"time"
 
 	"github.com/ChainSafe/sygma-relayer/chains/evm/calls/events"
+	"github.com/ChainSafe/sygma-relayer/relayer/transfer"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/rs/zerolog/log"
@@ -27,21 +29,44 @@ type DepositHandler interface {
 	HandleDeposit(sourceID, destID uint8, nonce uint64, resourceID [32]byte, calldata, handlerResponse []byte, messageID string, timestamp time.Time) (*message.Message, error)
 }
 
+type DepositTracker interface {
+	Track(block *big.Int, blockHash string, txHash string, destination uint8, nonce uint64)
+	Invalidate(forkBlock *big.Int) error
+}
+
 type DepositEventHandler struct {
 	eventListener  EventListener
 	depositHandler DepositHandler
 	bridgeAddress  common.Address
 	domainID       uint8
 	msgChan        chan []*message.Message
+	depositTracker DepositTracker
+	blockInterval  *big.Int
+
+	// subscribed holds deposits handled from subscribed logs that the polling has not reached yet
+	lock            sync.Mutex
+	subscribed      map[string]*big.Int
+	lastBlock       *big.Int
+	subscribedBlock *big.Int
 }
 
-func NewDepositEventHandler(eventListener EventListener, depositHandler DepositHandler, bridgeAddress common.Address, domainID uint8, msgChan chan []*message.Message) *DepositEventHandler {
+func NewDepositEventHandler(
+	eventListener EventListener,
+	depositHandler DepositHandler,
+	bridgeAddress common.Address,
+	domainID uint8,
+	msgChan chan []*message.Message,
+	depositTracker DepositTracker,
+	blockInterval *big.Int) *DepositEventHandler {
 	return &DepositEventHandler{
 		eventListener:  eventListener,
 		depositHandler: depositHandler,
 		bridgeAddress:  bridgeAddress,
 		domainID:       domainID,
 		msgChan:        msgChan,
+		depositTracker: depositTracker,
+		blockInterval:  blockInterval,
+		subscribed:     make(map[string]*big.Int),
 	}
 }
 
@@ -51,13 +76,135 @@ func (eh *DepositEventHandler) HandleEvents(startBlock *big.Int, endBlock *big.I
 		return err
 	}
 
+	eh.lock.Lock()
+	defer eh.lock.Unlock()
+
+	for destination, deposits := range domainDeposits {
+		domainDeposits[destination] = eh.unsubscribedDeposits(deposits)
+	}
+	for key, block := range eh.subscribed {
+		if block.Cmp(endBlock) <= 0 {
+			delete(eh.subscribed, key)
+		}
+	}
+	eh.lastBlock = new(big.Int).Set(endBlock)
+
+	eh.sendDeposits(domainDeposits)
+	return nil
+}
+
+// HandleLogs handles block ranges with final deposit logs received from a log subscription before
+// the polling reaches them. Logs only trigger the handling, the deposits are fetched for the same
+// block ranges the polling uses so every relayer resolves the same messages whether it polls or subscribes.
+// Returns logs from block ranges that are not final yet.
+func (eh *DepositEventHandler) HandleLogs(ctx context.Context, logs []types.Log, finalizedBlock *big.Int) ([]types.Log, error) {
+	eh.lock.Lock()
+	defer eh.lock.Unlock()
+
+	// block ranges are aligned to the polling which has to handle the first range
+	if eh.lastBlock == nil {
+		return nil, nil
+	}
+	handledBlock := eh.lastBlock
+	if eh.subscribedBlock != nil && eh.subscribedBlock.Cmp(handledBlock) > 0 {
+		handledBlock = eh.subscribedBlock
+	}
+
+	pending := make([]types.Log, 0)
+	startBlocks := make([]*big.Int, 0)
+	for _, l := range logs {
+		block := new(big.Int).SetUint64(l.BlockNumber)
+		if handledBlock.Cmp(block) >= 0 {
+			continue
+		}
+
+		startBlock, endBlock := eh.blockRange(block)
+		if endBlock.Cmp(finalizedBlock) > 0 {
+			pending = append(pending, l)
+			continue
+		}
+		if len(startBlocks) == 0 || startBlocks[len(startBlocks)-1].Cmp(startBlock) != 0 {
+			startBlocks = append(startBlocks, startBlock)
+		}
+	}
+
+	for _, startBlock := range startBlocks {
+		_, endBlock := eh.blockRange(startBlock)
+		domainDeposits, err := eh.ProcessDeposits(startBlock, endBlock)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, deposits := range domainDeposits {
+			for _, m := range deposits {
+				eh.subscribed[depositKey(m)] = endBlock
+			}
+		}
+		eh.subscribedBlock = endBlock
+		eh.sendDeposits(domainDeposits)
+	}
+	return pending, nil
+}
+
+// blockRange returns the polling block range that contains the block
+func (eh *DepositEventHandler) blockRange(block *big.Int) (*big.Int, *big.Int) {
+	firstBlock := new(big.Int).Add(eh.lastBlock, big.NewInt(1))
+	offset := new(big.Int).Sub(block, firstBlock)
+	offset.Sub(offset, new(big.Int).Mod(offset, eh.blockInterval))
+
+	startBlock := new(big.Int).Add(firstBlock, offset)
+	endBlock := new(big.Int).Add(startBlock, eh.blockInterval)
+	return startBlock, endBlock.Sub(endBlock, big.NewInt(1))
+}
+
+func (eh *DepositEventHandler) unsubscribedDeposits(deposits []*message.Message) []*message.Message {
+	unsubscribed := make([]*message.Message, 0)
+	for _, m := range deposits {
+		if _, ok := eh.subscribed[depositKey(m)]; ok {
+			continue
+		}
+		unsubscribed = append(unsubscribed, m)
+	}
+	return unsubscribed
+}
+
+func (eh *DepositEventHandler) sendDeposits(domainDeposits map[uint8][]*message.Message) {
 	for _, deposits := range domainDeposits {
+		if len(deposits) == 0 {
+			continue
+		}
+
 		go func(d []*message.Message) {
 			eh.msgChan <- d
 		}(deposits)
 	}
+}
 
-	return nil
+// depositKey identifies the deposit by the source block so deposits re-emitted after a reorg are not skipped
+func depositKey(m *message.Message) string {
+	data, ok := m.Data.(transfer.TransferMessageData)
+	if !ok {
+		return m.ID
+	}
+	return fmt.Sprintf("%d-%d-%s", m.Destination, data.DepositNonce, data.BlockHash)
+}
+
+// HandleReorg invalidates deposits emitted from blocks after the fork block and aligns
+// subscribed block ranges to the polling that is rewound to the fork block
+func (eh *DepositEventHandler) HandleReorg(forkBlock *big.Int) error {
+	eh.lock.Lock()
+	if eh.lastBlock != nil && eh.lastBlock.Cmp(forkBlock) > 0 {
+		eh.lastBlock = new(big.Int).Set(forkBlock)
+	}
+	if eh.subscribedBlock != nil && eh.subscribedBlock.Cmp(forkBlock) > 0 {
+		eh.subscribedBlock = new(big.Int).Set(forkBlock)
+	}
+	eh.lock.Unlock()
+
+	if eh.depositTracker == nil {
+		return nil
+	}
+	return eh.depositTracker.Invalidate(forkBlock)
 }
 
 func (eh *DepositEventHandler) ProcessDeposits(startBlock *big.Int, endBlock *big.Int) (map[uint8][]*message.Message, error) {
@@ -66,12 +213,16 @@ func (eh *DepositEventHandler) ProcessDeposits(startBlock *big.Int, endBlock *bi
 		return nil, fmt.Errorf("unable to fetch deposit events because of: %+v", err)
 	}
 
+	return eh.resolveDeposits(deposits, startBlock, endBlock), nil
+}
+
+func (eh *DepositEventHandler) resolveDeposits(deposits []*events.Deposit, startBlock *big.Int, endBlock *big.Int) map[uint8][]*message.Message {
 	domainDeposits := make(map[uint8][]*message.Message)
 	for _, d := range deposits {
 		func(d *events.Deposit) {
 			defer func() {
 				if r := recover(); r != nil {
-					log.Error().Err(err).Msgf("panic occured while handling deposit %+v", d)
+					log.Error().Msgf("panic occured while handling deposit %+v", d)
 				}
 			}()
 
@@ -82,10 +233,18 @@ func (eh *DepositEventHandler) ProcessDeposits(startBlock *big.Int, endBlock *bi
 				return
 			}
 
+			if data, ok := m.Data.(transfer.TransferMessageData); ok {
+				data.BlockHash = d.BlockHash.Hex()
+				m.Data = data
+			}
+			if eh.depositTracker != nil && d.BlockNumber != nil {
+				eh.depositTracker.Track(d.BlockNumber, d.BlockHash.Hex(), d.TxHash.Hex(), d.DestinationDomainID, d.DepositNonce)
+			}
+
 			log.Info().Str("messageID", m.ID).Msgf("Resolved message %+v in block range: %s-%s", m, startBlock.String(), endBlock.String())
 			domainDeposits[m.Destination] = append(domainDeposits[m.Destination], m)
 		}(d)
 	}
 
-	return domainDeposits, nil
+	return domainDeposits
 }
@@ -4,12 +4,14 @@
 package eventHandlers_test
 
 import (
+	"context"
 	"fmt"
 	"math/big"
 	"testing"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/suite"
 
@@ -39,7 +41,7 @@ func (s *DepositHandlerTestSuite) SetupTest() {
 	s.mockEventListener = mock_listener.NewMockEventListener(ctrl)
 	s.mockDepositHandler = mock_listener.NewMockDepositHandler(ctrl)
 	s.msgChan = make(chan []*message.Message, 2)
-	s.depositEventHandler = eventHandlers.NewDepositEventHandler(s.mockEventListener, s.mockDepositHandler, common.Address{}, s.domainID, s.msgChan)
+	s.depositEventHandler = eventHandlers.NewDepositEventHandler(s.mockEventListener, s.mockDepositHandler, common.Address{}, s.domainID, s.msgChan, nil, big.NewInt(5))
 }
 
 func (s *DepositHandlerTestSuite) Test_FetchDepositFails() {
@@ -104,7 +106,7 @@ func (s *DepositHandlerTestSuite) Test_HandleDepositFails_ExecutionContinue() {
 	msgs := <-s.msgChan
 
 	s.Nil(err)
-	s.Equal(msgs, []*message.Message{{Data: transfer.TransferMessageData{DepositNonce: 2}}})
+	s.Equal(msgs, []*message.Message{{Data: transfer.TransferMessageData{DepositNonce: 2, BlockHash: common.Hash{}.Hex()}}})
 }
 
 func (s *DepositHandlerTestSuite) Test_HandleDepositPanis_ExecutionContinues() {
@@ -155,7 +157,7 @@ func (s *DepositHandlerTestSuite) Test_HandleDepositPanis_ExecutionContinues() {
 	msgs := <-s.msgChan
 
 	s.Nil(err)
-	s.Equal(msgs, []*message.Message{{Data: transfer.TransferMessageData{DepositNonce: 2}}})
+	s.Equal(msgs, []*message.Message{{Data: transfer.TransferMessageData{DepositNonce: 2, BlockHash: common.Hash{}.Hex()}}})
 }
 
 func (s *DepositHandlerTestSuite) Test_SuccessfulHandleDeposit() {
@@ -207,5 +209,120 @@ func (s *DepositHandlerTestSuite) Test_SuccessfulHandleDeposit() {
 	msgs := <-s.msgChan
 
 	s.Nil(err)
-	s.Equal(msgs, []*message.Message{{Data: transfer.TransferMessageData{DepositNonce: 1}}, {Data: transfer.TransferMessageData{DepositNonce: 2}}})
+	s.Equal(msgs, []*message.Message{{Data: transfer.TransferMessageData{DepositNonce: 1, BlockHash: common.Hash{}.Hex()}}, {Data: transfer.TransferMessageData{DepositNonce: 2, BlockHash: common.Hash{}.Hex()}}})
+}
+
+func (s *DepositHandlerTestSuite) Test_TrackedDepositInvalidatedOnReorg() {
+	tracker := mock_listener.NewMockDepositTracker(gomock.NewController(s.T()))
+	depositEventHandler := eventHandlers.NewDepositEventHandler(s.mockEventListener, s.mockDepositHandler, common.Address{}, s.domainID, s.msgChan, tracker, big.NewInt(5))
+	d := &events.Deposit{
+		DepositNonce:        1,
+		DestinationDomainID: 2,
+		ResourceID:          [32]byte{},
+		HandlerResponse:     []byte{},
+		Data:                []byte{},
+		BlockNumber:         big.NewInt(3),
+		BlockHash:           common.Hash{1},
+		TxHash:              common.Hash{2},
+	}
+	s.mockEventListener.EXPECT().FetchDeposits(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return([]*events.Deposit{d}, nil)
+	s.mockDepositHandler.EXPECT().HandleDeposit(
+		s.domainID, d.DestinationDomainID, d.DepositNonce, d.ResourceID, d.Data, d.HandlerResponse, gomock.Any(), gomock.Any(),
+	).Return(&message.Message{Destination: 2, Data: transfer.TransferMessageData{DepositNonce: 1}}, nil)
+	tracker.EXPECT().Track(big.NewInt(3), common.Hash{1}.Hex(), common.Hash{2}.Hex(), uint8(2), uint64(1))
+	tracker.EXPECT().Invalidate(big.NewInt(2)).Return(nil)
+
+	err := depositEventHandler.HandleEvents(big.NewInt(0), big.NewInt(5))
+	msgs := <-s.msgChan
+	s.Nil(err)
+	s.Equal(msgs, []*message.Message{{Destination: 2, Data: transfer.TransferMessageData{DepositNonce: 1, BlockHash: common.Hash{1}.Hex()}}})
+
+	err = depositEventHandler.HandleReorg(big.NewInt(2))
+	s.Nil(err)
+}
+
+func (s *DepositHandlerTestSuite) Test_HandleLogs_SubscribedDepositNotResentByPolling() {
+	d := &events.Deposit{
+		DepositNonce:        1,
+		DestinationDomainID: 2,
+		BlockNumber:         big.NewInt(7),
+	}
+	s.mockEventListener.EXPECT().FetchDeposits(gomock.Any(), gomock.Any(), big.NewInt(0), big.NewInt(4)).Return([]*events.Deposit{}, nil)
+	err := s.depositEventHandler.HandleEvents(big.NewInt(0), big.NewInt(4))
+	s.Nil(err)
+
+	s.mockEventListener.EXPECT().FetchDeposits(gomock.Any(), gomock.Any(), big.NewInt(5), big.NewInt(9)).Return([]*events.Deposit{d}, nil)
+	s.mockDepositHandler.EXPECT().HandleDeposit(
+		s.domainID, d.DestinationDomainID, d.DepositNonce, d.ResourceID, d.Data, d.HandlerResponse, fmt.Sprintf("%d-%d-%d-%d", 1, 2, 5, 9), d.Timestamp,
+	).Return(&message.Message{Destination: 2, Data: transfer.TransferMessageData{DepositNonce: 1}}, nil)
+
+	pending, err := s.depositEventHandler.HandleLogs(context.Background(), []types.Log{{BlockNumber: 7}}, big.NewInt(9))
+	msgs := <-s.msgChan
+
+	s.Nil(err)
+	s.Empty(pending)
+	s.Equal(msgs, []*message.Message{{Destination: 2, Data: transfer.TransferMessageData{DepositNonce: 1, BlockHash: common.Hash{}.Hex()}}})
+
+	s.mockEventListener.EXPECT().FetchDeposits(gomock.Any(), gomock.Any(), big.NewInt(5), big.NewInt(9)).Return([]*events.Deposit{d}, nil)
+	s.mockDepositHandler.EXPECT().HandleDeposit(
+		s.domainID, d.DestinationDomainID, d.DepositNonce, d.ResourceID, d.Data, d.HandlerResponse, fmt.Sprintf("%d-%d-%d-%d", 1, 2, 5, 9), d.Timestamp,
+	).Return(&message.Message{Destination: 2, Data: transfer.TransferMessageData{DepositNonce: 1}}, nil)
+
+	err = s.depositEventHandler.HandleEvents(big.NewInt(5), big.NewInt(9))
+
+	s.Nil(err)
+	time.Sleep(time.Millisecond * 10)
+	s.Equal(len(s.msgChan), 0)
+}
+
+func (s *DepositHandlerTestSuite) Test_HandleLogs_LogsFromUnfinalizedRangePending() {
+	s.mockEventListener.EXPECT().FetchDeposits(gomock.Any(), gomock.Any(), big.NewInt(0), big.NewInt(4)).Return([]*events.Deposit{}, nil)
+	err := s.depositEventHandler.HandleEvents(big.NewInt(0), big.NewInt(4))
+	s.Nil(err)
+	s.mockEventListener.EXPECT().FetchDeposits(gomock.Any(), gomock.Any(), big.NewInt(5), big.NewInt(9)).Return([]*events.Deposit{}, nil)
+
+	logs := []types.Log{{BlockNumber: 6}, {BlockNumber: 9}, {BlockNumber: 11}}
+	pending, err := s.depositEventHandler.HandleLogs(context.Background(), logs, big.NewInt(12))
+
+	s.Nil(err)
+	s.Equal([]types.Log{{BlockNumber: 11}}, pending)
+
+	pending, err = s.depositEventHandler.HandleLogs(context.Background(), []types.Log{{BlockNumber: 8}}, big.NewInt(12))
+
+	s.Nil(err)
+	s.Empty(pending)
+}
+
+func (s *DepositHandlerTestSuite) Test_HandleLogs_LogsSkippedBeforePolling() {
+	pending, err := s.depositEventHandler.HandleLogs(context.Background(), []types.Log{{BlockNumber: 5}}, big.NewInt(10))
+
+	s.Nil(err)
+	s.Empty(pending)
+	s.Equal(len(s.msgChan), 0)
+}
+
+func (s *DepositHandlerTestSuite) Test_HandleLogs_RangesAlignedAfterReorg() {
+	s.mockEventListener.EXPECT().FetchDeposits(gomock.Any(), gomock.Any(), big.NewInt(0), big.NewInt(4)).Return([]*events.Deposit{}, nil)
+	err := s.depositEventHandler.HandleEvents(big.NewInt(0), big.NewInt(4))
+	s.Nil(err)
+	err = s.depositEventHandler.HandleReorg(big.NewInt(2))
+	s.Nil(err)
+	s.mockEventListener.EXPECT().FetchDeposits(gomock.Any(), gomock.Any(), big.NewInt(3), big.NewInt(7)).Return([]*events.Deposit{}, nil)
+
+	pending, err := s.depositEventHandler.HandleLogs(context.Background(), []types.Log{{BlockNumber: 4}}, big.NewInt(10))
+
+	s.Nil(err)
+	s.Empty(pending)
+}
+
+func (s *DepositHandlerTestSuite) Test_HandleLogs_PolledLogsSkipped() {
+	s.mockEventListener.EXPECT().FetchDeposits(gomock.Any(), gomock.Any(), big.NewInt(0), big.NewInt(5)).Return([]*events.Deposit{}, nil)
+	err := s.depositEventHandler.HandleEvents(big.NewInt(0), big.NewInt(5))
+	s.Nil(err)
+
+	pending, err := s.depositEventHandler.HandleLogs(context.Background(), []types.Log{{BlockNumber: 5}}, big.NewInt(10))
+
+	s.Nil(err)
+	s.Empty(pending)
+	s.Equal(len(s.msgChan), 0)
 }
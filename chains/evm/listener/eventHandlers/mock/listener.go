@@ -182,3 +182,52 @@ func (mr *MockDepositHandlerMockRecorder) HandleDeposit(sourceID, destID, nonce,
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleDeposit", reflect.TypeOf((*MockDepositHandler)(nil).HandleDeposit), sourceID, destID, nonce, resourceID, calldata, handlerResponse, messageID, timestamp)
 }
+
+// MockDepositTracker is a mock of DepositTracker interface.
+type MockDepositTracker struct {
+	ctrl     *gomock.Controller
+	recorder *MockDepositTrackerMockRecorder
+}
+
+// MockDepositTrackerMockRecorder is the mock recorder for MockDepositTracker.
+type MockDepositTrackerMockRecorder struct {
+	mock *MockDepositTracker
+}
+
+// NewMockDepositTracker creates a new mock instance.
+func NewMockDepositTracker(ctrl *gomock.Controller) *MockDepositTracker {
+	mock := &MockDepositTracker{ctrl: ctrl}
+	mock.recorder = &MockDepositTrackerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDepositTracker) EXPECT() *MockDepositTrackerMockRecorder {
+	return m.recorder
+}
+
+// Invalidate mocks base method.
+func (m *MockDepositTracker) Invalidate(forkBlock *big.Int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Invalidate", forkBlock)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Invalidate indicates an expected call of Invalidate.
+func (mr *MockDepositTrackerMockRecorder) Invalidate(forkBlock interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Invalidate", reflect.TypeOf((*MockDepositTracker)(nil).Invalidate), forkBlock)
+}
+
+// Track mocks base method.
+func (m *MockDepositTracker) Track(block *big.Int, blockHash, txHash string, destination uint8, nonce uint64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Track", block, blockHash, txHash, destination, nonce)
+}
+
+// Track indicates an expected call of Track.
+func (mr *MockDepositTrackerMockRecorder) Track(block, blockHash, txHash, destination, nonce interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Track", reflect.TypeOf((*MockDepositTracker)(nil).Track), block, blockHash, txHash, destination, nonce)
+}
@@ -0,0 +1,49 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./chains/evm/listener/eventHandlers/tss.go
+
+// Package mock_eventHandlers is a generated GoMock package.
+package mock_eventHandlers
+
+import (
+	reflect "reflect"
+
+	store "github.com/ChainSafe/sygma-relayer/store"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCeremonyStorer is a mock of CeremonyStorer interface.
+type MockCeremonyStorer struct {
+	ctrl     *gomock.Controller
+	recorder *MockCeremonyStorerMockRecorder
+}
+
+// MockCeremonyStorerMockRecorder is the mock recorder for MockCeremonyStorer.
+type MockCeremonyStorerMockRecorder struct {
+	mock *MockCeremonyStorer
+}
+
+// NewMockCeremonyStorer creates a new mock instance.
+func NewMockCeremonyStorer(ctrl *gomock.Controller) *MockCeremonyStorer {
+	mock := &MockCeremonyStorer{ctrl: ctrl}
+	mock.recorder = &MockCeremonyStorerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCeremonyStorer) EXPECT() *MockCeremonyStorerMockRecorder {
+	return m.recorder
+}
+
+// StoreCeremony mocks base method.
+func (m *MockCeremonyStorer) StoreCeremony(ceremony store.Ceremony) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreCeremony", ceremony)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StoreCeremony indicates an expected call of StoreCeremony.
+func (mr *MockCeremonyStorerMockRecorder) StoreCeremony(ceremony interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreCeremony", reflect.TypeOf((*MockCeremonyStorer)(nil).StoreCeremony), ceremony)
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/ChainSafe/sygma-relayer/comm"
 	"github.com/ChainSafe/sygma-relayer/comm/p2p"
+	"github.com/ChainSafe/sygma-relayer/store"
 	"github.com/ChainSafe/sygma-relayer/topology"
 	"github.com/ChainSafe/sygma-relayer/tss"
 	"github.com/ChainSafe/sygma-relayer/tss/ecdsa/keygen"
@@ -23,35 +24,45 @@ import (
 	"github.com/libp2p/go-libp2p/core/host"
 )
 
+type CeremonyStorer interface {
+	StoreCeremony(ceremony store.Ceremony) error
+}
+
 type KeygenEventHandler struct {
 	log           zerolog.Logger
 	eventListener EventListener
-	coordinator   *tss.Coordinator
+	scheduler     *tss.Scheduler
 	host          host.Host
 	communication comm.Communication
 	storer        keygen.ECDSAKeyshareStorer
+	ceremonyStore CeremonyStorer
 	bridgeAddress common.Address
+	domainID      uint8
 	threshold     int
 }
 
 func NewKeygenEventHandler(
 	logC zerolog.Context,
 	eventListener EventListener,
-	coordinator *tss.Coordinator,
+	scheduler *tss.Scheduler,
 	host host.Host,
 	communication comm.Communication,
 	storer keygen.ECDSAKeyshareStorer,
+	ceremonyStore CeremonyStorer,
 	bridgeAddress common.Address,
+	domainID uint8,
 	threshold int,
 ) *KeygenEventHandler {
 	return &KeygenEventHandler{
 		log:           logC.Logger(),
 		eventListener: eventListener,
-		coordinator:   coordinator,
+		scheduler:     scheduler,
 		host:          host,
 		communication: communication,
 		storer:        storer,
+		ceremonyStore: ceremonyStore,
 		bridgeAddress: bridgeAddress,
+		domainID:      domainID,
 		threshold:     threshold,
 	}
 }
@@ -81,13 +92,35 @@ func (eh *KeygenEventHandler) HandleEvents(
 
 	keygenBlockNumber := big.NewInt(0).SetUint64(keygenEvents[0].BlockNumber)
 	keygen := keygen.NewKeygen(eh.sessionID(keygenBlockNumber), eh.threshold, eh.host, eh.communication, eh.storer)
-	err = eh.coordinator.Execute(context.Background(), []tss.TssProcess{keygen}, make(chan interface{}, 1))
+	err = executeCeremony(eh.scheduler, eh.ceremonyStore, store.Ceremony{
+		SessionID: keygen.SessionID(),
+		Type:      eh.CeremonyType(),
+		DomainID:  eh.domainID,
+		Block:     keygenBlockNumber,
+		Threshold: eh.threshold,
+	}, keygen)
 	if err != nil {
 		log.Err(err).Msgf("Failed executing keygen")
 	}
 	return nil
 }
 
+func (eh *KeygenEventHandler) CeremonyType() store.CeremonyType {
+	return store.ECDSAKeygenCeremony
+}
+
+func (eh *KeygenEventHandler) DomainID() uint8 {
+	return eh.domainID
+}
+
+// RecoverCeremony re-runs interrupted keygen with the same session ID.
+// Relayers that already stored a keyshare join the re-run so the ceremony
+// can be finished by all parties.
+func (eh *KeygenEventHandler) RecoverCeremony(ceremony store.Ceremony) error {
+	keygen := keygen.NewKeygen(ceremony.SessionID, eh.threshold, eh.host, eh.communication, eh.storer)
+	return executeCeremony(eh.scheduler, eh.ceremonyStore, ceremony, keygen)
+}
+
 func (eh *KeygenEventHandler) sessionID(block *big.Int) string {
 	return fmt.Sprintf("keygen-%s", block.String())
 }
@@ -95,32 +128,38 @@ func (eh *KeygenEventHandler) sessionID(block *big.Int) string {
 type FrostKeygenEventHandler struct {
 	log             zerolog.Logger
 	eventListener   EventListener
-	coordinator     *tss.Coordinator
+	scheduler       *tss.Scheduler
 	host            host.Host
 	communication   comm.Communication
 	storer          frostKeygen.FrostKeyshareStorer
+	ceremonyStore   CeremonyStorer
 	contractAddress common.Address
+	domainID        uint8
 	threshold       int
 }
 
 func NewFrostKeygenEventHandler(
 	logC zerolog.Context,
 	eventListener EventListener,
-	coordinator *tss.Coordinator,
+	scheduler *tss.Scheduler,
 	host host.Host,
 	communication comm.Communication,
 	storer frostKeygen.FrostKeyshareStorer,
+	ceremonyStore CeremonyStorer,
 	contractAddress common.Address,
+	domainID uint8,
 	threshold int,
 ) *FrostKeygenEventHandler {
 	return &FrostKeygenEventHandler{
 		log:             logC.Logger(),
 		eventListener:   eventListener,
-		coordinator:     coordinator,
+		scheduler:       scheduler,
 		host:            host,
 		communication:   communication,
 		storer:          storer,
+		ceremonyStore:   ceremonyStore,
 		contractAddress: contractAddress,
+		domainID:        domainID,
 		threshold:       threshold,
 	}
 }
@@ -146,13 +185,35 @@ func (eh *FrostKeygenEventHandler) HandleEvents(
 
 	keygenBlockNumber := big.NewInt(0).SetUint64(keygenEvents[0].BlockNumber)
 	keygen := frostKeygen.NewKeygen(eh.sessionID(keygenBlockNumber), eh.threshold, eh.host, eh.communication, eh.storer)
-	err = eh.coordinator.Execute(context.Background(), []tss.TssProcess{keygen}, make(chan interface{}, 1))
+	err = executeCeremony(eh.scheduler, eh.ceremonyStore, store.Ceremony{
+		SessionID: keygen.SessionID(),
+		Type:      eh.CeremonyType(),
+		DomainID:  eh.domainID,
+		Block:     keygenBlockNumber,
+		Threshold: eh.threshold,
+	}, keygen)
 	if err != nil {
 		log.Err(err).Msgf("Failed executing keygen")
 	}
 	return nil
 }
 
+func (eh *FrostKeygenEventHandler) CeremonyType() store.CeremonyType {
+	return store.FrostKeygenCeremony
+}
+
+func (eh *FrostKeygenEventHandler) DomainID() uint8 {
+	return eh.domainID
+}
+
+// RecoverCeremony re-runs interrupted FROST keygen with the same session ID.
+// Relayers that already stored a keyshare join the re-run so the ceremony
+// can be finished by all parties.
+func (eh *FrostKeygenEventHandler) RecoverCeremony(ceremony store.Ceremony) error {
+	keygen := frostKeygen.NewKeygen(ceremony.SessionID, eh.threshold, eh.host, eh.communication, eh.storer)
+	return executeCeremony(eh.scheduler, eh.ceremonyStore, ceremony, keygen)
+}
+
 func (eh *FrostKeygenEventHandler) sessionID(block *big.Int) string {
 	return fmt.Sprintf("frost-keygen-%s", block.String())
 }
@@ -163,12 +224,14 @@ type RefreshEventHandler struct {
 	topologyStore    *topology.TopologyStore
 	eventListener    EventListener
 	bridgeAddress    common.Address
-	coordinator      *tss.Coordinator
+	scheduler        *tss.Scheduler
 	host             host.Host
 	communication    comm.Communication
 	connectionGate   *p2p.ConnectionGate
 	ecdsaStorer      resharing.SaveDataStorer
 	frostStorer      frostResharing.FrostKeyshareStorer
+	ceremonyStore    CeremonyStorer
+	domainID         uint8
 }
 
 func NewRefreshEventHandler(
@@ -176,26 +239,30 @@ func NewRefreshEventHandler(
 	topologyProvider topology.NetworkTopologyProvider,
 	topologyStore *topology.TopologyStore,
 	eventListener EventListener,
-	coordinator *tss.Coordinator,
+	scheduler *tss.Scheduler,
 	host host.Host,
 	communication comm.Communication,
 	connectionGate *p2p.ConnectionGate,
 	ecdsaStorer resharing.SaveDataStorer,
 	frostStorer frostResharing.FrostKeyshareStorer,
+	ceremonyStore CeremonyStorer,
 	bridgeAddress common.Address,
+	domainID uint8,
 ) *RefreshEventHandler {
 	return &RefreshEventHandler{
 		log:              logC.Logger(),
 		topologyProvider: topologyProvider,
 		topologyStore:    topologyStore,
 		eventListener:    eventListener,
-		coordinator:      coordinator,
+		scheduler:        scheduler,
 		host:             host,
 		communication:    communication,
 		ecdsaStorer:      ecdsaStorer,
 		frostStorer:      frostStorer,
+		ceremonyStore:    ceremonyStore,
 		connectionGate:   connectionGate,
 		bridgeAddress:    bridgeAddress,
+		domainID:         domainID,
 	}
 }
 
@@ -215,7 +282,8 @@ func (eh *RefreshEventHandler) HandleEvents(
 		return nil
 	}
 
-	hash := refreshEvents[len(refreshEvents)-1].Hash
+	refreshEvent := refreshEvents[len(refreshEvents)-1]
+	hash := refreshEvent.Hash
 	if hash == "" {
 		log.Error().Msgf("Hash cannot be empty string")
 		return nil
@@ -241,7 +309,13 @@ func (eh *RefreshEventHandler) HandleEvents(
 	resharing := resharing.NewResharing(
 		eh.sessionID(startBlock), topology.Threshold, eh.host, eh.communication, eh.ecdsaStorer,
 	)
-	err = eh.coordinator.Execute(context.Background(), []tss.TssProcess{resharing}, make(chan interface{}, 1))
+	err = executeCeremony(eh.scheduler, eh.ceremonyStore, store.Ceremony{
+		SessionID: resharing.SessionID(),
+		Type:      eh.CeremonyType(),
+		DomainID:  eh.domainID,
+		Block:     new(big.Int).SetUint64(refreshEvent.BlockNumber),
+		Threshold: topology.Threshold,
+	}, resharing)
 	if err != nil {
 		log.Err(err).Msgf("Failed executing ecdsa key refresh")
 		return nil
@@ -249,6 +323,55 @@ func (eh *RefreshEventHandler) HandleEvents(
 	return nil
 }
 
+func (eh *RefreshEventHandler) CeremonyType() store.CeremonyType {
+	return store.ECDSAResharingCeremony
+}
+
+func (eh *RefreshEventHandler) DomainID() uint8 {
+	return eh.domainID
+}
+
+// RecoverCeremony re-runs interrupted resharing with the same session ID
+// and the threshold of the topology from the refresh event
+func (eh *RefreshEventHandler) RecoverCeremony(ceremony store.Ceremony) error {
+	if ceremony.Threshold == 0 {
+		return fmt.Errorf("ceremony %s has no topology threshold", ceremony.SessionID)
+	}
+
+	resharing := resharing.NewResharing(
+		ceremony.SessionID, ceremony.Threshold, eh.host, eh.communication, eh.ecdsaStorer,
+	)
+	return executeCeremony(eh.scheduler, eh.ceremonyStore, ceremony, resharing)
+}
+
 func (eh *RefreshEventHandler) sessionID(block *big.Int) string {
 	return fmt.Sprintf("resharing-%s", block.String())
 }
+
+// executeCeremony runs the tss process and persists ceremony status
+// so interrupted ceremonies can be recovered with the same session ID.
+func executeCeremony(
+	scheduler *tss.Scheduler,
+	ceremonyStore CeremonyStorer,
+	ceremony store.Ceremony,
+	process tss.TssProcess,
+) error {
+	ceremony.Status = store.PendingCeremony
+	err := ceremonyStore.StoreCeremony(ceremony)
+	if err != nil {
+		log.Err(err).Str("SessionID", ceremony.SessionID).Msgf("Failed storing ceremony")
+	}
+
+	err = scheduler.Execute(context.Background(), tss.CeremonyPriority, 0, []tss.TssProcess{process}, make(chan interface{}, 1))
+	if err != nil {
+		ceremony.Status = store.FailedCeremony
+	} else {
+		ceremony.Status = store.CompletedCeremony
+	}
+
+	storeErr := ceremonyStore.StoreCeremony(ceremony)
+	if storeErr != nil {
+		log.Err(storeErr).Str("SessionID", ceremony.SessionID).Msgf("Failed storing ceremony")
+	}
+	return err
+}
@@ -0,0 +1,47 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package eventHandlers_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/ChainSafe/sygma-relayer/chains/evm/listener/eventHandlers"
+	mock_listener "github.com/ChainSafe/sygma-relayer/chains/evm/listener/eventHandlers/mock"
+	"github.com/ChainSafe/sygma-relayer/store"
+)
+
+type RecoverCeremonyTestSuite struct {
+	suite.Suite
+	mockCeremonyStorer *mock_listener.MockCeremonyStorer
+}
+
+func TestRunRecoverCeremonyTestSuite(t *testing.T) {
+	suite.Run(t, new(RecoverCeremonyTestSuite))
+}
+
+func (s *RecoverCeremonyTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.mockCeremonyStorer = mock_listener.NewMockCeremonyStorer(ctrl)
+}
+
+func (s *RecoverCeremonyTestSuite) Test_RefreshRecoverCeremony_MissingThreshold() {
+	handler := eventHandlers.NewRefreshEventHandler(log.With(), nil, nil, nil, nil, nil, nil, nil, nil, nil, s.mockCeremonyStorer, common.Address{}, 1)
+	ceremony := store.Ceremony{
+		SessionID: "resharing-10",
+		Type:      store.ECDSAResharingCeremony,
+		DomainID:  1,
+		Block:     big.NewInt(10),
+		Status:    store.FailedCeremony,
+	}
+
+	err := handler.RecoverCeremony(ceremony)
+
+	s.NotNil(err)
+}
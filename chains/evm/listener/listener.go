@@ -0,0 +1,243 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package listener
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+type EventHandler interface {
+	HandleEvents(startBlock *big.Int, endBlock *big.Int) error
+}
+
+// ReorgHandler is implemented by event handlers that need to revert events
+// emitted from blocks orphaned by a chain reorganization
+type ReorgHandler interface {
+	HandleReorg(forkBlock *big.Int) error
+}
+
+type ChainClient interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// FinalityFetcher resolves the latest block considered final by the finality mode of the domain
+type FinalityFetcher interface {
+	FinalizedBlock() (*big.Int, error)
+}
+
+type BlockDeltaMeter interface {
+	TrackBlockDelta(domainID uint8, head *big.Int, current *big.Int)
+}
+
+type BlockStorer interface {
+	StoreBlock(block *big.Int, domainID uint8) error
+}
+
+type BlockHashStorer interface {
+	StoreBlockHash(domainID uint8, block *big.Int, hash []byte) error
+	BlockHash(domainID uint8, block *big.Int) ([]byte, error)
+}
+
+type EVMListener struct {
+	client         ChainClient
+	finality       FinalityFetcher
+	eventHandlers  []EventHandler
+	metrics        BlockDeltaMeter
+	blockstore     BlockStorer
+	blockHashStore BlockHashStorer
+
+	domainID           uint8
+	blockRetryInterval time.Duration
+	blockInterval      *big.Int
+	reorgDepth         *big.Int
+
+	log zerolog.Logger
+}
+
+// NewEVMListener creates an EVMListener that listens to deposit events on chain
+// and calls event handler when one occurs. The listener stores the hash of the last block of
+// every processed block range and rewinds to the fork block when the chain reorganizes.
+func NewEVMListener(
+	client ChainClient,
+	finality FinalityFetcher,
+	eventHandlers []EventHandler,
+	blockstore BlockStorer,
+	blockHashStore BlockHashStorer,
+	metrics BlockDeltaMeter,
+	domainID uint8,
+	blockRetryInterval time.Duration,
+	blockInterval *big.Int,
+	reorgDepth *big.Int) *EVMListener {
+	logger := log.With().Uint8("domainID", domainID).Logger()
+	return &EVMListener{
+		log:                logger,
+		client:             client,
+		finality:           finality,
+		metrics:            metrics,
+		eventHandlers:      eventHandlers,
+		blockstore:         blockstore,
+		blockHashStore:     blockHashStore,
+		domainID:           domainID,
+		blockRetryInterval: blockRetryInterval,
+		blockInterval:      blockInterval,
+		reorgDepth:         reorgDepth,
+	}
+}
+
+// ListenToEvents goes block by block of a network and executes event handlers that are
+// configured for the listener.
+func (l *EVMListener) ListenToEvents(ctx context.Context, startBlock *big.Int) {
+	endBlock := big.NewInt(0)
+loop:
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			head, err := l.finality.FinalizedBlock()
+			if err != nil {
+				l.log.Warn().Err(err).Msg("Unable to get latest finalized block")
+				time.Sleep(l.blockRetryInterval)
+				continue
+			}
+			if startBlock == nil {
+				startBlock = big.NewInt(head.Int64())
+			}
+			endBlock.Add(startBlock, l.blockInterval)
+
+			// Sleep if the end of the range is not final yet
+			if head.Cmp(endBlock) == -1 {
+				time.Sleep(l.blockRetryInterval)
+				continue
+			}
+
+			forkBlock, err := l.forkBlock(ctx, startBlock)
+			if err != nil {
+				l.log.Warn().Err(err).Msg("Unable to check chain reorganization")
+				time.Sleep(l.blockRetryInterval)
+				continue
+			}
+			if forkBlock != nil {
+				err = l.handleReorg(forkBlock)
+				if err != nil {
+					l.log.Warn().Err(err).Msg("Unable to handle chain reorganization")
+					time.Sleep(l.blockRetryInterval)
+					continue
+				}
+
+				l.log.Warn().Msgf("Chain reorganization detected, rewinding from block %s to block %s", startBlock, forkBlock)
+				startBlock.Add(forkBlock, big.NewInt(1))
+				continue
+			}
+
+			// parent hash of the block after the range is the hash of the last block of the range
+			next, err := l.client.HeaderByNumber(ctx, endBlock)
+			if err != nil {
+				l.log.Warn().Err(err).Msgf("Unable to get block %s", endBlock)
+				time.Sleep(l.blockRetryInterval)
+				continue
+			}
+
+			l.metrics.TrackBlockDelta(l.domainID, head, endBlock)
+			l.log.Debug().Msgf("Fetching evm events for block range %s-%s", startBlock, endBlock)
+
+			lastBlock := new(big.Int).Sub(endBlock, big.NewInt(1))
+			for _, handler := range l.eventHandlers {
+				err := handler.HandleEvents(startBlock, lastBlock)
+				if err != nil {
+					l.log.Warn().Err(err).Msgf("Unable to handle events")
+					continue loop
+				}
+			}
+
+			err = l.blockHashStore.StoreBlockHash(l.domainID, lastBlock, next.ParentHash.Bytes())
+			if err != nil {
+				l.log.Warn().Err(err).Msgf("Unable to store hash of block %s", lastBlock)
+				time.Sleep(l.blockRetryInterval)
+				continue
+			}
+
+			//Write to block store. Not a critical operation, no need to retry
+			err = l.blockstore.StoreBlock(endBlock, l.domainID)
+			if err != nil {
+				l.log.Error().Str("block", endBlock.String()).Err(err).Msg("Failed to write latest block to blockstore")
+			}
+
+			startBlock.Add(startBlock, l.blockInterval)
+		}
+	}
+}
+
+// forkBlock returns the last processed block that is still part of the canonical chain if
+// the parent hash of the start block does not match the stored hash of the last processed block.
+// Returns nil if the chain was not reorganized.
+func (l *EVMListener) forkBlock(ctx context.Context, startBlock *big.Int) (*big.Int, error) {
+	block := new(big.Int).Sub(startBlock, big.NewInt(1))
+	canonical, err := l.isCanonical(ctx, block)
+	if err != nil || canonical {
+		return nil, err
+	}
+
+	oldestBlock := new(big.Int).Sub(block, l.reorgDepth)
+	for block.Cmp(oldestBlock) > 0 {
+		block.Sub(block, l.blockInterval)
+		canonical, err := l.isCanonical(ctx, block)
+		if err != nil {
+			return nil, err
+		}
+		if canonical {
+			return block, nil
+		}
+	}
+
+	l.log.Error().Msgf("Chain reorganization deeper than %s blocks, rewinding to block %s", l.reorgDepth, block)
+	return block, nil
+}
+
+// isCanonical compares the stored hash of the processed block with the parent hash of the next block.
+// Blocks without a stored hash are considered canonical as they were not processed.
+func (l *EVMListener) isCanonical(ctx context.Context, block *big.Int) (bool, error) {
+	hash, err := l.blockHashStore.BlockHash(l.domainID, block)
+	if err != nil {
+		return false, err
+	}
+	if hash == nil {
+		return true, nil
+	}
+
+	next, err := l.client.HeaderByNumber(ctx, new(big.Int).Add(block, big.NewInt(1)))
+	if err != nil {
+		return false, err
+	}
+	if bytes.Equal(next.ParentHash.Bytes(), hash) {
+		return true, nil
+	}
+
+	l.log.Warn().Msgf(
+		"Processed block %s hash %s does not match parent hash %s of the next block", block, common.BytesToHash(hash), next.ParentHash)
+	return false, nil
+}
+
+func (l *EVMListener) handleReorg(forkBlock *big.Int) error {
+	for _, handler := range l.eventHandlers {
+		reorgHandler, ok := handler.(ReorgHandler)
+		if !ok {
+			continue
+		}
+
+		err := reorgHandler.HandleReorg(forkBlock)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package listener_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/sygma-relayer/chains/evm/listener"
+	mock_listener "github.com/ChainSafe/sygma-relayer/chains/evm/listener/mock"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+)
+
+type reorgEventHandler struct {
+	*mock_listener.MockEventHandler
+	*mock_listener.MockReorgHandler
+}
+
+type ListenerTestSuite struct {
+	suite.Suite
+	listener         *listener.EVMListener
+	mockClient       *mock_listener.MockChainClient
+	mockFinality     *mock_listener.MockFinalityFetcher
+	mockEventHandler *mock_listener.MockEventHandler
+	mockReorgHandler *mock_listener.MockReorgHandler
+	mockBlockStorer  *mock_listener.MockBlockStorer
+	mockHashStorer   *mock_listener.MockBlockHashStorer
+	mockMetrics      *mock_listener.MockBlockDeltaMeter
+	domainID         uint8
+}
+
+func TestRunListenerTestSuite(t *testing.T) {
+	suite.Run(t, new(ListenerTestSuite))
+}
+
+func (s *ListenerTestSuite) SetupTest() {
+	s.domainID = 1
+	ctrl := gomock.NewController(s.T())
+	s.mockClient = mock_listener.NewMockChainClient(ctrl)
+	s.mockFinality = mock_listener.NewMockFinalityFetcher(ctrl)
+	s.mockEventHandler = mock_listener.NewMockEventHandler(ctrl)
+	s.mockReorgHandler = mock_listener.NewMockReorgHandler(ctrl)
+	s.mockBlockStorer = mock_listener.NewMockBlockStorer(ctrl)
+	s.mockHashStorer = mock_listener.NewMockBlockHashStorer(ctrl)
+	s.mockMetrics = mock_listener.NewMockBlockDeltaMeter(ctrl)
+	s.mockMetrics.EXPECT().TrackBlockDelta(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+
+	s.listener = listener.NewEVMListener(
+		s.mockClient,
+		s.mockFinality,
+		[]listener.EventHandler{reorgEventHandler{s.mockEventHandler, s.mockReorgHandler}},
+		s.mockBlockStorer,
+		s.mockHashStorer,
+		s.mockMetrics,
+		s.domainID,
+		time.Millisecond*75,
+		big.NewInt(5),
+		big.NewInt(10),
+	)
+}
+
+func (s *ListenerTestSuite) Test_ListenToEvents_StoresHashOfLastProcessedBlock() {
+	s.mockFinality.EXPECT().FinalizedBlock().Return(big.NewInt(115), nil)
+	s.mockFinality.EXPECT().FinalizedBlock().Return(big.NewInt(95), nil).AnyTimes()
+	s.mockHashStorer.EXPECT().BlockHash(s.domainID, big.NewInt(99)).Return(nil, nil)
+	s.mockClient.EXPECT().HeaderByNumber(gomock.Any(), big.NewInt(105)).Return(&types.Header{ParentHash: common.Hash{1}}, nil)
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(nil)
+	s.mockHashStorer.EXPECT().StoreBlockHash(s.domainID, big.NewInt(104), common.Hash{1}.Bytes()).Return(nil)
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(105), s.domainID).Return(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go s.listener.ListenToEvents(ctx, big.NewInt(100))
+
+	time.Sleep(time.Millisecond * 50)
+	cancel()
+}
+
+func (s *ListenerTestSuite) Test_ListenToEvents_WaitsForRangeToBeFinalized() {
+	s.mockFinality.EXPECT().FinalizedBlock().Return(big.NewInt(104), nil).AnyTimes()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go s.listener.ListenToEvents(ctx, big.NewInt(100))
+
+	time.Sleep(time.Millisecond * 50)
+	cancel()
+}
+
+func (s *ListenerTestSuite) Test_ListenToEvents_RewindsToForkBlockOnReorg() {
+	s.mockFinality.EXPECT().FinalizedBlock().Return(big.NewInt(115), nil).Times(2)
+	s.mockFinality.EXPECT().FinalizedBlock().Return(big.NewInt(95), nil).AnyTimes()
+
+	// parent of block 105 does not match processed block 104, block 99 is still canonical
+	s.mockHashStorer.EXPECT().BlockHash(s.domainID, big.NewInt(104)).Return(common.Hash{2}.Bytes(), nil)
+	s.mockClient.EXPECT().HeaderByNumber(gomock.Any(), big.NewInt(105)).Return(&types.Header{ParentHash: common.Hash{3}}, nil).Times(2)
+	s.mockHashStorer.EXPECT().BlockHash(s.domainID, big.NewInt(99)).Return(common.Hash{1}.Bytes(), nil).Times(2)
+	s.mockClient.EXPECT().HeaderByNumber(gomock.Any(), big.NewInt(100)).Return(&types.Header{ParentHash: common.Hash{1}}, nil).Times(2)
+	s.mockReorgHandler.EXPECT().HandleReorg(big.NewInt(99)).Return(nil)
+
+	// blocks 100-104 are processed again
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(nil)
+	s.mockHashStorer.EXPECT().StoreBlockHash(s.domainID, big.NewInt(104), common.Hash{3}.Bytes()).Return(nil)
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(105), s.domainID).Return(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go s.listener.ListenToEvents(ctx, big.NewInt(105))
+
+	time.Sleep(time.Millisecond * 50)
+	cancel()
+}
+
+func (s *ListenerTestSuite) Test_ListenToEvents_ReorgHandlerFailure_RetriesReorg() {
+	s.mockFinality.EXPECT().FinalizedBlock().Return(big.NewInt(115), nil)
+	s.mockFinality.EXPECT().FinalizedBlock().Return(big.NewInt(95), nil).AnyTimes()
+	s.mockHashStorer.EXPECT().BlockHash(s.domainID, big.NewInt(104)).Return(common.Hash{2}.Bytes(), nil)
+	s.mockClient.EXPECT().HeaderByNumber(gomock.Any(), big.NewInt(105)).Return(&types.Header{ParentHash: common.Hash{3}}, nil)
+	s.mockHashStorer.EXPECT().BlockHash(s.domainID, big.NewInt(99)).Return(common.Hash{1}.Bytes(), nil)
+	s.mockClient.EXPECT().HeaderByNumber(gomock.Any(), big.NewInt(100)).Return(&types.Header{ParentHash: common.Hash{1}}, nil)
+	s.mockReorgHandler.EXPECT().HandleReorg(big.NewInt(99)).Return(context.DeadlineExceeded)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go s.listener.ListenToEvents(ctx, big.NewInt(105))
+
+	time.Sleep(time.Millisecond * 50)
+	cancel()
+}
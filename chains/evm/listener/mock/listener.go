@@ -0,0 +1,288 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./chains/evm/listener/listener.go
+
+// Package mock_listener is a generated GoMock package.
+package mock_listener
+
+import (
+	context "context"
+	big "math/big"
+	reflect "reflect"
+
+	types "github.com/ethereum/go-ethereum/core/types"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockEventHandler is a mock of EventHandler interface.
+type MockEventHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventHandlerMockRecorder
+}
+
+// MockEventHandlerMockRecorder is the mock recorder for MockEventHandler.
+type MockEventHandlerMockRecorder struct {
+	mock *MockEventHandler
+}
+
+// NewMockEventHandler creates a new mock instance.
+func NewMockEventHandler(ctrl *gomock.Controller) *MockEventHandler {
+	mock := &MockEventHandler{ctrl: ctrl}
+	mock.recorder = &MockEventHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventHandler) EXPECT() *MockEventHandlerMockRecorder {
+	return m.recorder
+}
+
+// HandleEvents mocks base method.
+func (m *MockEventHandler) HandleEvents(startBlock, endBlock *big.Int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleEvents", startBlock, endBlock)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleEvents indicates an expected call of HandleEvents.
+func (mr *MockEventHandlerMockRecorder) HandleEvents(startBlock, endBlock interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleEvents", reflect.TypeOf((*MockEventHandler)(nil).HandleEvents), startBlock, endBlock)
+}
+
+// MockReorgHandler is a mock of ReorgHandler interface.
+type MockReorgHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockReorgHandlerMockRecorder
+}
+
+// MockReorgHandlerMockRecorder is the mock recorder for MockReorgHandler.
+type MockReorgHandlerMockRecorder struct {
+	mock *MockReorgHandler
+}
+
+// NewMockReorgHandler creates a new mock instance.
+func NewMockReorgHandler(ctrl *gomock.Controller) *MockReorgHandler {
+	mock := &MockReorgHandler{ctrl: ctrl}
+	mock.recorder = &MockReorgHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReorgHandler) EXPECT() *MockReorgHandlerMockRecorder {
+	return m.recorder
+}
+
+// HandleReorg mocks base method.
+func (m *MockReorgHandler) HandleReorg(forkBlock *big.Int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleReorg", forkBlock)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleReorg indicates an expected call of HandleReorg.
+func (mr *MockReorgHandlerMockRecorder) HandleReorg(forkBlock interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleReorg", reflect.TypeOf((*MockReorgHandler)(nil).HandleReorg), forkBlock)
+}
+
+// MockChainClient is a mock of ChainClient interface.
+type MockChainClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockChainClientMockRecorder
+}
+
+// MockChainClientMockRecorder is the mock recorder for MockChainClient.
+type MockChainClientMockRecorder struct {
+	mock *MockChainClient
+}
+
+// NewMockChainClient creates a new mock instance.
+func NewMockChainClient(ctrl *gomock.Controller) *MockChainClient {
+	mock := &MockChainClient{ctrl: ctrl}
+	mock.recorder = &MockChainClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChainClient) EXPECT() *MockChainClientMockRecorder {
+	return m.recorder
+}
+
+// HeaderByNumber mocks base method.
+func (m *MockChainClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HeaderByNumber", ctx, number)
+	ret0, _ := ret[0].(*types.Header)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HeaderByNumber indicates an expected call of HeaderByNumber.
+func (mr *MockChainClientMockRecorder) HeaderByNumber(ctx, number interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HeaderByNumber", reflect.TypeOf((*MockChainClient)(nil).HeaderByNumber), ctx, number)
+}
+
+// MockFinalityFetcher is a mock of FinalityFetcher interface.
+type MockFinalityFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockFinalityFetcherMockRecorder
+}
+
+// MockFinalityFetcherMockRecorder is the mock recorder for MockFinalityFetcher.
+type MockFinalityFetcherMockRecorder struct {
+	mock *MockFinalityFetcher
+}
+
+// NewMockFinalityFetcher creates a new mock instance.
+func NewMockFinalityFetcher(ctrl *gomock.Controller) *MockFinalityFetcher {
+	mock := &MockFinalityFetcher{ctrl: ctrl}
+	mock.recorder = &MockFinalityFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFinalityFetcher) EXPECT() *MockFinalityFetcherMockRecorder {
+	return m.recorder
+}
+
+// FinalizedBlock mocks base method.
+func (m *MockFinalityFetcher) FinalizedBlock() (*big.Int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinalizedBlock")
+	ret0, _ := ret[0].(*big.Int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinalizedBlock indicates an expected call of FinalizedBlock.
+func (mr *MockFinalityFetcherMockRecorder) FinalizedBlock() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinalizedBlock", reflect.TypeOf((*MockFinalityFetcher)(nil).FinalizedBlock))
+}
+
+// MockBlockDeltaMeter is a mock of BlockDeltaMeter interface.
+type MockBlockDeltaMeter struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlockDeltaMeterMockRecorder
+}
+
+// MockBlockDeltaMeterMockRecorder is the mock recorder for MockBlockDeltaMeter.
+type MockBlockDeltaMeterMockRecorder struct {
+	mock *MockBlockDeltaMeter
+}
+
+// NewMockBlockDeltaMeter creates a new mock instance.
+func NewMockBlockDeltaMeter(ctrl *gomock.Controller) *MockBlockDeltaMeter {
+	mock := &MockBlockDeltaMeter{ctrl: ctrl}
+	mock.recorder = &MockBlockDeltaMeterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlockDeltaMeter) EXPECT() *MockBlockDeltaMeterMockRecorder {
+	return m.recorder
+}
+
+// TrackBlockDelta mocks base method.
+func (m *MockBlockDeltaMeter) TrackBlockDelta(domainID uint8, head, current *big.Int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "TrackBlockDelta", domainID, head, current)
+}
+
+// TrackBlockDelta indicates an expected call of TrackBlockDelta.
+func (mr *MockBlockDeltaMeterMockRecorder) TrackBlockDelta(domainID, head, current interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrackBlockDelta", reflect.TypeOf((*MockBlockDeltaMeter)(nil).TrackBlockDelta), domainID, head, current)
+}
+
+// MockBlockStorer is a mock of BlockStorer interface.
+type MockBlockStorer struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlockStorerMockRecorder
+}
+
+// MockBlockStorerMockRecorder is the mock recorder for MockBlockStorer.
+type MockBlockStorerMockRecorder struct {
+	mock *MockBlockStorer
+}
+
+// NewMockBlockStorer creates a new mock instance.
+func NewMockBlockStorer(ctrl *gomock.Controller) *MockBlockStorer {
+	mock := &MockBlockStorer{ctrl: ctrl}
+	mock.recorder = &MockBlockStorerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlockStorer) EXPECT() *MockBlockStorerMockRecorder {
+	return m.recorder
+}
+
+// StoreBlock mocks base method.
+func (m *MockBlockStorer) StoreBlock(block *big.Int, domainID uint8) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreBlock", block, domainID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StoreBlock indicates an expected call of StoreBlock.
+func (mr *MockBlockStorerMockRecorder) StoreBlock(block, domainID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreBlock", reflect.TypeOf((*MockBlockStorer)(nil).StoreBlock), block, domainID)
+}
+
+// MockBlockHashStorer is a mock of BlockHashStorer interface.
+type MockBlockHashStorer struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlockHashStorerMockRecorder
+}
+
+// MockBlockHashStorerMockRecorder is the mock recorder for MockBlockHashStorer.
+type MockBlockHashStorerMockRecorder struct {
+	mock *MockBlockHashStorer
+}
+
+// NewMockBlockHashStorer creates a new mock instance.
+func NewMockBlockHashStorer(ctrl *gomock.Controller) *MockBlockHashStorer {
+	mock := &MockBlockHashStorer{ctrl: ctrl}
+	mock.recorder = &MockBlockHashStorerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlockHashStorer) EXPECT() *MockBlockHashStorerMockRecorder {
+	return m.recorder
+}
+
+// BlockHash mocks base method.
+func (m *MockBlockHashStorer) BlockHash(domainID uint8, block *big.Int) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockHash", domainID, block)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockHash indicates an expected call of BlockHash.
+func (mr *MockBlockHashStorerMockRecorder) BlockHash(domainID, block interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockHash", reflect.TypeOf((*MockBlockHashStorer)(nil).BlockHash), domainID, block)
+}
+
+// StoreBlockHash mocks base method.
+func (m *MockBlockHashStorer) StoreBlockHash(domainID uint8, block *big.Int, hash []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreBlockHash", domainID, block, hash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StoreBlockHash indicates an expected call of StoreBlockHash.
+func (mr *MockBlockHashStorerMockRecorder) StoreBlockHash(domainID, block, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreBlockHash", reflect.TypeOf((*MockBlockHashStorer)(nil).StoreBlockHash), domainID, block, hash)
+}
@@ -0,0 +1,91 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./chains/evm/listener/subscription.go
+
+// Package mock_listener is a generated GoMock package.
+package mock_listener
+
+import (
+	context "context"
+	big "math/big"
+	reflect "reflect"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	types "github.com/ethereum/go-ethereum/core/types"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockLogSubscriber is a mock of LogSubscriber interface.
+type MockLogSubscriber struct {
+	ctrl     *gomock.Controller
+	recorder *MockLogSubscriberMockRecorder
+}
+
+// MockLogSubscriberMockRecorder is the mock recorder for MockLogSubscriber.
+type MockLogSubscriberMockRecorder struct {
+	mock *MockLogSubscriber
+}
+
+// NewMockLogSubscriber creates a new mock instance.
+func NewMockLogSubscriber(ctrl *gomock.Controller) *MockLogSubscriber {
+	mock := &MockLogSubscriber{ctrl: ctrl}
+	mock.recorder = &MockLogSubscriberMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLogSubscriber) EXPECT() *MockLogSubscriberMockRecorder {
+	return m.recorder
+}
+
+// SubscribeFilterLogs mocks base method.
+func (m *MockLogSubscriber) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeFilterLogs", ctx, q, ch)
+	ret0, _ := ret[0].(ethereum.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeFilterLogs indicates an expected call of SubscribeFilterLogs.
+func (mr *MockLogSubscriberMockRecorder) SubscribeFilterLogs(ctx, q, ch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeFilterLogs", reflect.TypeOf((*MockLogSubscriber)(nil).SubscribeFilterLogs), ctx, q, ch)
+}
+
+// MockLogHandler is a mock of LogHandler interface.
+type MockLogHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockLogHandlerMockRecorder
+}
+
+// MockLogHandlerMockRecorder is the mock recorder for MockLogHandler.
+type MockLogHandlerMockRecorder struct {
+	mock *MockLogHandler
+}
+
+// NewMockLogHandler creates a new mock instance.
+func NewMockLogHandler(ctrl *gomock.Controller) *MockLogHandler {
+	mock := &MockLogHandler{ctrl: ctrl}
+	mock.recorder = &MockLogHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLogHandler) EXPECT() *MockLogHandlerMockRecorder {
+	return m.recorder
+}
+
+// HandleLogs mocks base method.
+func (m *MockLogHandler) HandleLogs(ctx context.Context, logs []types.Log, finalizedBlock *big.Int) ([]types.Log, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleLogs", ctx, logs, finalizedBlock)
+	ret0, _ := ret[0].([]types.Log)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HandleLogs indicates an expected call of HandleLogs.
+func (mr *MockLogHandlerMockRecorder) HandleLogs(ctx, logs, finalizedBlock interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleLogs", reflect.TypeOf((*MockLogHandler)(nil).HandleLogs), ctx, logs, finalizedBlock)
+}
@@ -0,0 +1,167 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package listener
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+type LogSubscriber interface {
+	SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error)
+}
+
+type LogHandler interface {
+	HandleLogs(ctx context.Context, logs []types.Log, finalizedBlock *big.Int) ([]types.Log, error)
+}
+
+type logKey struct {
+	blockHash common.Hash
+	index     uint
+}
+
+// LogSubscription subscribes to logs and buffers them until they are final. Logs removed by a chain
+// reorganization are dropped from the buffer. Logs missed while the subscription is down are
+// not recovered, as they are handled by the polling EVMListener.
+type LogSubscription struct {
+	subscriber LogSubscriber
+	finality   FinalityFetcher
+	handler    LogHandler
+	query      ethereum.FilterQuery
+
+	retryInterval time.Duration
+	buffer        map[logKey]types.Log
+
+	log zerolog.Logger
+}
+
+func NewLogSubscription(
+	subscriber LogSubscriber,
+	finality FinalityFetcher,
+	handler LogHandler,
+	query ethereum.FilterQuery,
+	domainID uint8,
+	retryInterval time.Duration) *LogSubscription {
+	return &LogSubscription{
+		log:           log.With().Uint8("domainID", domainID).Logger(),
+		subscriber:    subscriber,
+		finality:      finality,
+		handler:       handler,
+		query:         query,
+		retryInterval: retryInterval,
+		buffer:        make(map[logKey]types.Log),
+	}
+}
+
+// Listen subscribes to logs and handles buffered logs as soon as they are final.
+// The subscription is renewed when it fails until the context is cancelled.
+func (s *LogSubscription) Listen(ctx context.Context) {
+	for {
+		err := s.subscribe(ctx)
+		if err == nil {
+			return
+		}
+
+		s.log.Warn().Err(err).Msg("Log subscription failed, resubscribing")
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.retryInterval):
+		}
+	}
+}
+
+// subscribe receives logs until the subscription fails. Returns nil if the context is cancelled.
+func (s *LogSubscription) subscribe(ctx context.Context) error {
+	logs := make(chan types.Log)
+	sub, err := s.subscriber.SubscribeFilterLogs(ctx, s.query, logs)
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+
+	ticker := time.NewTicker(s.retryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-sub.Err():
+			return fmt.Errorf("subscription closed: %w", err)
+		case l := <-logs:
+			s.bufferLog(l)
+		case <-ticker.C:
+			err := s.handleFinalLogs(ctx)
+			if err != nil {
+				s.log.Warn().Err(err).Msg("Unable to handle subscribed logs")
+			}
+		}
+	}
+}
+
+func (s *LogSubscription) bufferLog(l types.Log) {
+	key := logKey{blockHash: l.BlockHash, index: l.Index}
+	if l.Removed {
+		s.log.Debug().Msgf("Dropping log of tx %s removed from block %d", l.TxHash, l.BlockNumber)
+		delete(s.buffer, key)
+		return
+	}
+	s.buffer[key] = l
+}
+
+// handleFinalLogs handles buffered logs from final blocks and removes them from the buffer.
+// Logs the handler returns as pending are kept in the buffer and handled again.
+func (s *LogSubscription) handleFinalLogs(ctx context.Context) error {
+	if len(s.buffer) == 0 {
+		return nil
+	}
+
+	finalizedBlock, err := s.finality.FinalizedBlock()
+	if err != nil {
+		return err
+	}
+
+	finalLogs := make([]types.Log, 0)
+	for _, l := range s.buffer {
+		if finalizedBlock.Cmp(new(big.Int).SetUint64(l.BlockNumber)) >= 0 {
+			finalLogs = append(finalLogs, l)
+		}
+	}
+	if len(finalLogs) == 0 {
+		return nil
+	}
+	sort.Slice(finalLogs, func(i, j int) bool {
+		if finalLogs[i].BlockNumber != finalLogs[j].BlockNumber {
+			return finalLogs[i].BlockNumber < finalLogs[j].BlockNumber
+		}
+		return finalLogs[i].Index < finalLogs[j].Index
+	})
+
+	pendingLogs, err := s.handler.HandleLogs(ctx, finalLogs, finalizedBlock)
+	if err != nil {
+		return err
+	}
+
+	pending := make(map[logKey]bool)
+	for _, l := range pendingLogs {
+		pending[logKey{blockHash: l.BlockHash, index: l.Index}] = true
+	}
+	for _, l := range finalLogs {
+		key := logKey{blockHash: l.BlockHash, index: l.Index}
+		if !pending[key] {
+			delete(s.buffer, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package listener_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/sygma-relayer/chains/evm/listener"
+	mock_listener "github.com/ChainSafe/sygma-relayer/chains/evm/listener/mock"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+)
+
+type LogSubscriptionTestSuite struct {
+	suite.Suite
+	subscription   *listener.LogSubscription
+	mockSubscriber *mock_listener.MockLogSubscriber
+	mockFinality   *mock_listener.MockFinalityFetcher
+	mockHandler    *mock_listener.MockLogHandler
+	logs           chan chan<- types.Log
+}
+
+func TestRunLogSubscriptionTestSuite(t *testing.T) {
+	suite.Run(t, new(LogSubscriptionTestSuite))
+}
+
+func (s *LogSubscriptionTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.mockSubscriber = mock_listener.NewMockLogSubscriber(ctrl)
+	s.mockFinality = mock_listener.NewMockFinalityFetcher(ctrl)
+	s.mockHandler = mock_listener.NewMockLogHandler(ctrl)
+	s.logs = make(chan chan<- types.Log, 1)
+	s.subscription = listener.NewLogSubscription(
+		s.mockSubscriber,
+		s.mockFinality,
+		s.mockHandler,
+		ethereum.FilterQuery{},
+		1,
+		time.Millisecond*10,
+	)
+}
+
+func (s *LogSubscriptionTestSuite) subscribe(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	s.logs <- ch
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		<-quit
+		return nil
+	}), nil
+}
+
+func (s *LogSubscriptionTestSuite) Test_Listen_HandlesLogsWhenFinal() {
+	depositLog := types.Log{BlockNumber: 10, BlockHash: common.Hash{1}}
+	s.mockSubscriber.EXPECT().SubscribeFilterLogs(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(s.subscribe)
+	s.mockFinality.EXPECT().FinalizedBlock().Return(big.NewInt(9), nil)
+	s.mockFinality.EXPECT().FinalizedBlock().Return(big.NewInt(10), nil)
+	s.mockHandler.EXPECT().HandleLogs(gomock.Any(), []types.Log{depositLog}, big.NewInt(10)).Return(nil, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.subscription.Listen(ctx)
+	logs := <-s.logs
+	logs <- depositLog
+
+	time.Sleep(time.Millisecond * 55)
+}
+
+func (s *LogSubscriptionTestSuite) Test_Listen_PendingLogsKept() {
+	depositLog := types.Log{BlockNumber: 10, BlockHash: common.Hash{1}}
+	s.mockSubscriber.EXPECT().SubscribeFilterLogs(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(s.subscribe)
+	s.mockFinality.EXPECT().FinalizedBlock().Return(big.NewInt(10), nil)
+	s.mockFinality.EXPECT().FinalizedBlock().Return(big.NewInt(12), nil)
+	s.mockHandler.EXPECT().HandleLogs(gomock.Any(), []types.Log{depositLog}, big.NewInt(10)).Return([]types.Log{depositLog}, nil)
+	s.mockHandler.EXPECT().HandleLogs(gomock.Any(), []types.Log{depositLog}, big.NewInt(12)).Return(nil, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.subscription.Listen(ctx)
+	logs := <-s.logs
+	logs <- depositLog
+
+	time.Sleep(time.Millisecond * 55)
+}
+
+func (s *LogSubscriptionTestSuite) Test_Listen_DropsRemovedLogs() {
+	depositLog := types.Log{BlockNumber: 10, BlockHash: common.Hash{1}}
+	removedLog := depositLog
+	removedLog.Removed = true
+	s.mockSubscriber.EXPECT().SubscribeFilterLogs(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(s.subscribe)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.subscription.Listen(ctx)
+	logs := <-s.logs
+	logs <- depositLog
+	logs <- removedLog
+
+	time.Sleep(time.Millisecond * 35)
+}
+
+func (s *LogSubscriptionTestSuite) Test_Listen_HandlerFails_RetriesLogs() {
+	depositLog := types.Log{BlockNumber: 10, BlockHash: common.Hash{1}}
+	s.mockSubscriber.EXPECT().SubscribeFilterLogs(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(s.subscribe)
+	s.mockFinality.EXPECT().FinalizedBlock().Return(big.NewInt(10), nil).Times(2)
+	s.mockHandler.EXPECT().HandleLogs(gomock.Any(), []types.Log{depositLog}, big.NewInt(10)).Return(nil, errors.New("error"))
+	s.mockHandler.EXPECT().HandleLogs(gomock.Any(), []types.Log{depositLog}, big.NewInt(10)).Return(nil, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.subscription.Listen(ctx)
+	logs := <-s.logs
+	logs <- depositLog
+
+	time.Sleep(time.Millisecond * 55)
+}
+
+func (s *LogSubscriptionTestSuite) Test_Listen_ResubscribesOnFailure() {
+	s.mockSubscriber.EXPECT().SubscribeFilterLogs(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+	s.mockSubscriber.EXPECT().SubscribeFilterLogs(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(s.subscribe)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.subscription.Listen(ctx)
+
+	select {
+	case <-s.logs:
+	case <-time.After(time.Second):
+		s.Fail("subscription not renewed")
+	}
+}
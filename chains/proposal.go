@@ -14,7 +14,15 @@ import (
 	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 )
 
-func ProposalsHash(proposals []*transfer.TransferProposal, chainID int64, verifContract string, bridgeVersion string) ([]byte, error) {
+// EIP712Domain is the EIP-712 domain of the bridge proposals are signed for
+type EIP712Domain struct {
+	Name              string
+	Version           string
+	ChainID           *big.Int
+	VerifyingContract string
+}
+
+func ProposalsHash(proposals []*transfer.TransferProposal, domain *EIP712Domain) ([]byte, error) {
 	formattedProps := make([]interface{}, len(proposals))
 	for i, prop := range proposals {
 		formattedProps[i] = map[string]interface{}{
@@ -47,10 +55,10 @@ func ProposalsHash(proposals []*transfer.TransferProposal, chainID int64, verifC
 		},
 		PrimaryType: "Proposals",
 		Domain: apitypes.TypedDataDomain{
-			Name:              "Bridge",
-			ChainId:           math.NewHexOrDecimal256(chainID),
-			Version:           bridgeVersion,
-			VerifyingContract: verifContract,
+			Name:              domain.Name,
+			ChainId:           (*math.HexOrDecimal256)(domain.ChainID),
+			Version:           domain.Version,
+			VerifyingContract: domain.VerifyingContract,
 		},
 		Message: message,
 	}
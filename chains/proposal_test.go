@@ -4,6 +4,7 @@
 package chains
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/ChainSafe/sygma-relayer/relayer/transfer"
@@ -36,7 +37,12 @@ func (s *ProposalTestSuite) Test_ProposalsHash() {
 	}}
 	correctRes := []byte{0xde, 0x7b, 0x5c, 0x9e, 0x8, 0x7a, 0xb4, 0xf5, 0xfb, 0xe, 0x9f, 0x73, 0xa7, 0xe5, 0xbd, 0xb, 0xdf, 0x9e, 0xeb, 0x4, 0xaa, 0xbb, 0xd0, 0xe8, 0xf8, 0xde, 0x58, 0xa2, 0x4, 0xa3, 0x3e, 0x55}
 
-	res, err := ProposalsHash(prop, 5, verifyingContract, bridgeVersion)
+	res, err := ProposalsHash(prop, &EIP712Domain{
+		Name:              "Bridge",
+		Version:           bridgeVersion,
+		ChainID:           big.NewInt(5),
+		VerifyingContract: verifyingContract,
+	})
 	s.Nil(err)
 	s.Equal(correctRes, res)
 }
@@ -0,0 +1,168 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package chains
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/ChainSafe/sygma-relayer/store"
+)
+
+type DepositInvalidator interface {
+	PropStatus(source, destination uint8, depositNonce uint64) (store.PropStatus, error)
+	StorePropStatus(source, destination uint8, depositNonce uint64, status store.PropStatus) error
+	InvalidateProp(source, destination uint8, depositNonce uint64, blockHash string) error
+}
+
+type TrackedDepositStorer interface {
+	StoreTrackedDeposits(domainID uint8, deposits store.TrackedDeposits) error
+	TrackedDeposits(domainID uint8) (store.TrackedDeposits, error)
+}
+
+// DepositTracker keeps deposits emitted from the most recent blocks so they can be
+// invalidated if their blocks get orphaned by a chain reorganization. Tracked deposits
+// are persisted so they survive relayer restarts.
+type DepositTracker struct {
+	domainID      uint8
+	depth         *big.Int
+	propStorer    DepositInvalidator
+	depositStorer TrackedDepositStorer
+
+	lock     sync.Mutex
+	deposits []store.TrackedDeposit
+	orphaned map[string]store.TrackedDeposit
+}
+
+func NewDepositTracker(domainID uint8, depth *big.Int, propStorer DepositInvalidator, depositStorer TrackedDepositStorer) (*DepositTracker, error) {
+	tracked, err := depositStorer.TrackedDeposits(domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	orphaned := make(map[string]store.TrackedDeposit)
+	for _, d := range tracked.Orphaned {
+		orphaned[d.TxHash] = d
+	}
+	return &DepositTracker{
+		domainID:      domainID,
+		depth:         depth,
+		propStorer:    propStorer,
+		depositStorer: depositStorer,
+		deposits:      tracked.Deposits,
+		orphaned:      orphaned,
+	}, nil
+}
+
+// Track records the deposit emitted from the block and forgets deposits from blocks
+// deeper than the reorg depth. If the transaction was already invalidated with the same
+// block hash, its block was not orphaned and the deposit is valid again.
+func (t *DepositTracker) Track(block *big.Int, blockHash string, txHash string, destination uint8, nonce uint64) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	orphan, ok := t.orphaned[txHash]
+	if ok && orphan.BlockHash == blockHash && orphan.Destination == destination && orphan.Nonce == nonce {
+		err := t.propStorer.StorePropStatus(t.domainID, destination, nonce, store.MissingProp)
+		if err != nil {
+			log.Err(err).Uint8("domainID", t.domainID).Msgf("Failed revalidating deposit %d-%d-%d", t.domainID, destination, nonce)
+		}
+	}
+	delete(t.orphaned, txHash)
+
+	t.deposits = append(t.deposits, store.TrackedDeposit{
+		Block:       new(big.Int).Set(block),
+		BlockHash:   blockHash,
+		TxHash:      txHash,
+		Destination: destination,
+		Nonce:       nonce,
+	})
+	t.prune(block)
+
+	err := t.store(t.deposits, t.orphaned)
+	if err != nil {
+		log.Err(err).Uint8("domainID", t.domainID).Msgf("Failed storing tracked deposits")
+	}
+}
+
+// OrphanedNonce returns the nonce of the deposit from the transaction if it was
+// invalidated because its block was orphaned
+func (t *DepositTracker) OrphanedNonce(txHash string) (uint64, bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	orphan, ok := t.orphaned[txHash]
+	return orphan.Nonce, ok
+}
+
+// Invalidate invalidates deposits from blocks after the fork block that are not yet executed.
+// Deposits are tracked until all of them are invalidated so the invalidation can be retried.
+func (t *DepositTracker) Invalidate(forkBlock *big.Int) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	deposits := make([]store.TrackedDeposit, 0)
+	orphaned := make(map[string]store.TrackedDeposit)
+	for txHash, d := range t.orphaned {
+		orphaned[txHash] = d
+	}
+	for _, d := range t.deposits {
+		if d.Block.Cmp(forkBlock) <= 0 {
+			deposits = append(deposits, d)
+			continue
+		}
+
+		status, err := t.propStorer.PropStatus(t.domainID, d.Destination, d.Nonce)
+		if err != nil {
+			return err
+		}
+		if status == store.ExecutedProp {
+			log.Error().Uint8("domainID", t.domainID).Msgf(
+				"Deposit %d-%d-%d from orphaned block %s already executed", t.domainID, d.Destination, d.Nonce, d.BlockHash)
+			continue
+		}
+
+		err = t.propStorer.InvalidateProp(t.domainID, d.Destination, d.Nonce, d.BlockHash)
+		if err != nil {
+			return err
+		}
+		log.Warn().Uint8("domainID", t.domainID).Msgf(
+			"Invalidated deposit %d-%d-%d from orphaned block %s", t.domainID, d.Destination, d.Nonce, d.BlockHash)
+		orphaned[d.TxHash] = d
+	}
+
+	err := t.store(deposits, orphaned)
+	if err != nil {
+		return err
+	}
+	t.deposits = deposits
+	t.orphaned = orphaned
+	return nil
+}
+
+// prune forgets deposits from blocks deeper than the reorg depth. Orphaned deposits are
+// kept until their transactions are included again so the deposits keep their nonces.
+func (t *DepositTracker) prune(head *big.Int) {
+	oldest := new(big.Int).Sub(head, t.depth)
+	deposits := t.deposits[:0]
+	for _, d := range t.deposits {
+		if d.Block.Cmp(oldest) > 0 {
+			deposits = append(deposits, d)
+		}
+	}
+	t.deposits = deposits
+}
+
+func (t *DepositTracker) store(deposits []store.TrackedDeposit, orphaned map[string]store.TrackedDeposit) error {
+	tracked := store.TrackedDeposits{
+		Deposits: deposits,
+		Orphaned: make([]store.TrackedDeposit, 0, len(orphaned)),
+	}
+	for _, d := range orphaned {
+		tracked.Orphaned = append(tracked.Orphaned, d)
+	}
+	return t.depositStorer.StoreTrackedDeposits(t.domainID, tracked)
+}
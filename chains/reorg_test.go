@@ -0,0 +1,165 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package chains
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/sygma-relayer/store"
+	"github.com/stretchr/testify/suite"
+)
+
+type testPropStore struct {
+	statuses map[string]store.PropStatus
+	orphans  map[string]string
+}
+
+func (s *testPropStore) key(source, destination uint8, depositNonce uint64) string {
+	return fmt.Sprintf("%d-%d-%d", source, destination, depositNonce)
+}
+
+func (s *testPropStore) PropStatus(source, destination uint8, depositNonce uint64) (store.PropStatus, error) {
+	status, ok := s.statuses[s.key(source, destination, depositNonce)]
+	if !ok {
+		return store.MissingProp, nil
+	}
+	return status, nil
+}
+
+func (s *testPropStore) StorePropStatus(source, destination uint8, depositNonce uint64, status store.PropStatus) error {
+	s.statuses[s.key(source, destination, depositNonce)] = status
+	return nil
+}
+
+func (s *testPropStore) InvalidateProp(source, destination uint8, depositNonce uint64, blockHash string) error {
+	s.orphans[s.key(source, destination, depositNonce)] = blockHash
+	return s.StorePropStatus(source, destination, depositNonce, store.InvalidProp)
+}
+
+type testDepositStore struct {
+	deposits map[uint8]store.TrackedDeposits
+}
+
+func (s *testDepositStore) StoreTrackedDeposits(domainID uint8, deposits store.TrackedDeposits) error {
+	s.deposits[domainID] = deposits
+	return nil
+}
+
+func (s *testDepositStore) TrackedDeposits(domainID uint8) (store.TrackedDeposits, error) {
+	return s.deposits[domainID], nil
+}
+
+type DepositTrackerTestSuite struct {
+	suite.Suite
+	propStore    *testPropStore
+	depositStore *testDepositStore
+	tracker      *DepositTracker
+}
+
+func TestRunDepositTrackerTestSuite(t *testing.T) {
+	suite.Run(t, new(DepositTrackerTestSuite))
+}
+
+func (s *DepositTrackerTestSuite) SetupTest() {
+	s.propStore = &testPropStore{
+		statuses: make(map[string]store.PropStatus),
+		orphans:  make(map[string]string),
+	}
+	s.depositStore = &testDepositStore{
+		deposits: make(map[uint8]store.TrackedDeposits),
+	}
+	s.tracker, _ = NewDepositTracker(1, big.NewInt(10), s.propStore, s.depositStore)
+}
+
+func (s *DepositTrackerTestSuite) restartTracker() {
+	tracker, err := NewDepositTracker(1, big.NewInt(10), s.propStore, s.depositStore)
+	s.Nil(err)
+	s.tracker = tracker
+}
+
+func (s *DepositTrackerTestSuite) Test_Invalidate_InvalidatesDepositsAfterForkBlock() {
+	s.tracker.Track(big.NewInt(100), "0xa", "tx1", 2, 1)
+	s.tracker.Track(big.NewInt(101), "0xb", "tx2", 2, 2)
+
+	err := s.tracker.Invalidate(big.NewInt(100))
+
+	s.Nil(err)
+	s.NotContains(s.propStore.statuses, "1-2-1")
+	s.Equal(store.InvalidProp, s.propStore.statuses["1-2-2"])
+	s.Equal("0xb", s.propStore.orphans["1-2-2"])
+	nonce, ok := s.tracker.OrphanedNonce("tx2")
+	s.True(ok)
+	s.Equal(uint64(2), nonce)
+}
+
+func (s *DepositTrackerTestSuite) Test_Invalidate_ExecutedDepositNotInvalidated() {
+	s.tracker.Track(big.NewInt(101), "0xb", "tx2", 2, 2)
+	s.propStore.statuses["1-2-2"] = store.ExecutedProp
+
+	err := s.tracker.Invalidate(big.NewInt(100))
+
+	s.Nil(err)
+	s.Equal(store.ExecutedProp, s.propStore.statuses["1-2-2"])
+	_, ok := s.tracker.OrphanedNonce("tx2")
+	s.False(ok)
+}
+
+func (s *DepositTrackerTestSuite) Test_Track_RevalidatesDepositFromSameBlock() {
+	s.tracker.Track(big.NewInt(101), "0xb", "tx2", 2, 2)
+	_ = s.tracker.Invalidate(big.NewInt(100))
+
+	s.tracker.Track(big.NewInt(101), "0xb", "tx2", 2, 2)
+
+	s.Equal(store.MissingProp, s.propStore.statuses["1-2-2"])
+	_, ok := s.tracker.OrphanedNonce("tx2")
+	s.False(ok)
+}
+
+func (s *DepositTrackerTestSuite) Test_Track_ReemittedDepositKeepsOrphanInvalid() {
+	s.tracker.Track(big.NewInt(101), "0xb", "tx2", 2, 2)
+	_ = s.tracker.Invalidate(big.NewInt(100))
+
+	s.tracker.Track(big.NewInt(102), "0xc", "tx2", 2, 2)
+
+	s.Equal(store.InvalidProp, s.propStore.statuses["1-2-2"])
+	s.Equal("0xb", s.propStore.orphans["1-2-2"])
+}
+
+func (s *DepositTrackerTestSuite) Test_Track_ForgetsDepositsDeeperThanReorgDepth() {
+	s.tracker.Track(big.NewInt(100), "0xa", "tx1", 2, 1)
+	s.tracker.Track(big.NewInt(111), "0xb", "tx2", 2, 2)
+
+	err := s.tracker.Invalidate(big.NewInt(99))
+
+	s.Nil(err)
+	s.NotContains(s.propStore.statuses, "1-2-1")
+	s.Equal(store.InvalidProp, s.propStore.statuses["1-2-2"])
+}
+
+func (s *DepositTrackerTestSuite) Test_Invalidate_DepositsTrackedBeforeRestartInvalidated() {
+	s.tracker.Track(big.NewInt(100), "0xa", "tx1", 2, 1)
+	s.tracker.Track(big.NewInt(101), "0xb", "tx2", 2, 2)
+	s.restartTracker()
+
+	err := s.tracker.Invalidate(big.NewInt(100))
+
+	s.Nil(err)
+	s.NotContains(s.propStore.statuses, "1-2-1")
+	s.Equal(store.InvalidProp, s.propStore.statuses["1-2-2"])
+	s.Equal("0xb", s.propStore.orphans["1-2-2"])
+}
+
+func (s *DepositTrackerTestSuite) Test_OrphanedNonce_KeptAfterRestartAndReorgDepth() {
+	s.tracker.Track(big.NewInt(101), "0xb", "tx2", 2, 2)
+	_ = s.tracker.Invalidate(big.NewInt(100))
+	s.restartTracker()
+
+	s.tracker.Track(big.NewInt(120), "0xc", "tx3", 2, 3)
+
+	nonce, ok := s.tracker.OrphanedNonce("tx2")
+	s.True(ok)
+	s.Equal(uint64(2), nonce)
+}
@@ -0,0 +1,42 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package chains
+
+import (
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/ChainSafe/sygma-relayer/tss/util"
+)
+
+type SubmissionMetrics interface {
+	TrackProposalSubmission(domainID uint8, fallback bool)
+	TrackSkippedProposalSubmission(domainID uint8)
+	TrackWastedProposalSubmission(domainID uint8)
+}
+
+// maxSubmissionDelaySteps limits the submission delay, so participants after the last step
+// submit together instead of waiting for the back-off of every participant in front of them
+var maxSubmissionDelaySteps = 3
+
+// SubmissionDelay returns how long the relayer waits before submitting proposals signed in the session.
+// Signing participants are ordered by the session ID, so every session has a different designated
+// submitter that submits immediately while other participants submit one after another
+// with the back-off, only if proposals are not executed by then.
+// The delay is capped at maxSubmissionDelaySteps back-offs.
+func SubmissionDelay(self peer.ID, participants peer.IDSlice, sessionID string, backOff time.Duration) time.Duration {
+	step := len(participants)
+	for i, p := range util.SortPeersForSession(participants, sessionID) {
+		if p.ID == self {
+			step = i
+			break
+		}
+	}
+
+	if step > maxSubmissionDelaySteps {
+		step = maxSubmissionDelaySteps
+	}
+	return time.Duration(step) * backOff
+}
@@ -0,0 +1,63 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package chains
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/sygma-relayer/tss/util"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/suite"
+)
+
+type SubmissionDelayTestSuite struct {
+	suite.Suite
+	peers peer.IDSlice
+}
+
+func TestRunSubmissionDelayTestSuite(t *testing.T) {
+	suite.Run(t, new(SubmissionDelayTestSuite))
+}
+
+func (s *SubmissionDelayTestSuite) SetupTest() {
+	p1, _ := peer.Decode("QmcvEg7jGvuxdsUFRUiE4VdrL2P1Yeju5L83BsJvvXz7zX")
+	p2, _ := peer.Decode("QmeTuMtdpPB7zKDgmobEwSvxodrf5aFVSmBXX3SQJVjJaT")
+	p3, _ := peer.Decode("QmYAYuLUPNwYEBYJaKHcE7NKjUhiUV8txx2xDXHvcYa1xK")
+	s.peers = peer.IDSlice{p1, p2, p3}
+}
+
+func (s *SubmissionDelayTestSuite) Test_ParticipantsDelayedBySessionOrder() {
+	sortedPeers := util.SortPeersForSession(s.peers, "session")
+
+	for i, p := range sortedPeers {
+		s.Equal(time.Duration(i)*time.Minute, SubmissionDelay(p.ID, s.peers, "session", time.Minute))
+	}
+}
+
+func (s *SubmissionDelayTestSuite) Test_DesignatedSubmitterChangesWithSession() {
+	submitters := make(map[peer.ID]bool)
+	for _, sessionID := range []string{"1", "2", "3", "4", "5", "6"} {
+		submitters[util.SortPeersForSession(s.peers, sessionID)[0].ID] = true
+	}
+
+	s.Greater(len(submitters), 1)
+}
+
+func (s *SubmissionDelayTestSuite) Test_NonParticipantSubmitsLast() {
+	delay := SubmissionDelay(peer.ID("other"), s.peers, "session", time.Minute)
+
+	s.Equal(3*time.Minute, delay)
+}
+
+func (s *SubmissionDelayTestSuite) Test_DelayCapped() {
+	maxSubmissionDelaySteps = 1
+	defer func() { maxSubmissionDelaySteps = 3 }()
+	sortedPeers := util.SortPeersForSession(s.peers, "session")
+
+	s.Equal(time.Duration(0), SubmissionDelay(sortedPeers[0].ID, s.peers, "session", time.Minute))
+	s.Equal(time.Minute, SubmissionDelay(sortedPeers[1].ID, s.peers, "session", time.Minute))
+	s.Equal(time.Minute, SubmissionDelay(sortedPeers[2].ID, s.peers, "session", time.Minute))
+	s.Equal(time.Minute, SubmissionDelay(peer.ID("other"), s.peers, "session", time.Minute))
+}
@@ -23,6 +23,9 @@ type RawSubstrateConfig struct {
 	BlockRetryInterval       uint64 `mapstructure:"blockRetryInterval" default:"5"`
 	SubstrateNetwork         int64  `mapstructure:"substrateNetwork"`
 	Tip                      uint64 `mapstructure:"tip"`
+	SubmissionBackOff        uint64 `mapstructure:"submissionBackOff" default:"120"`
+	BridgeVersion            string `mapstructure:"bridgeVersion" default:"3.1.0"`
+	VerifyingContract        string `mapstructure:"verifyingContract" default:"6CdE2Cd82a4F8B74693Ff5e194c19CA08c2d1c68"`
 }
 
 type SubstrateConfig struct {
@@ -33,13 +36,17 @@ type SubstrateConfig struct {
 	BlockRetryInterval time.Duration
 	SubstrateNetwork   uint16
 	Tip                uint64
+	SubmissionBackOff  time.Duration
+	// BridgeVersion and VerifyingContract are fields of the EIP-712 domain proposals are signed for
+	BridgeVersion     string
+	VerifyingContract string
 }
 
 func (c *SubstrateConfig) String() string {
 	kp, _ := signature.KeyringPairFromSecret(c.GeneralChainConfig.Key, c.SubstrateNetwork)
 	return fmt.Sprintf(`Name: '%s', Id: '%d', Type: '%s', BlockstorePath: '%s', FreshStart: '%t', 
 							  LatestBlock: '%t', Key address: '%s', StartBlock: '%s', BlockInterval: '%s', 
-                              BlockRetryInterval: '%s', ChainID: '%d', Tip: '%d', SubstrateNetworkPrefix: "%d"`,
+                              BlockRetryInterval: '%s', ChainID: '%d', Tip: '%d', SubstrateNetworkPrefix: "%d", SubmissionBackOff: '%s', BridgeVersion: '%s', VerifyingContract: '%s'`,
 		c.GeneralChainConfig.Name,
 		*c.GeneralChainConfig.Id,
 		c.GeneralChainConfig.Type,
@@ -53,6 +60,9 @@ func (c *SubstrateConfig) String() string {
 		c.ChainID,
 		c.Tip,
 		c.SubstrateNetwork,
+		c.SubmissionBackOff,
+		c.BridgeVersion,
+		c.VerifyingContract,
 	)
 }
 
@@ -92,6 +102,9 @@ func NewSubstrateConfig(chainConfig map[string]interface{}) (*SubstrateConfig, e
 		BlockInterval:      big.NewInt(c.BlockInterval),
 		SubstrateNetwork:   uint16(c.SubstrateNetwork),
 		Tip:                uint64(c.Tip),
+		SubmissionBackOff:  time.Duration(c.SubmissionBackOff) * time.Second,
+		BridgeVersion:      c.BridgeVersion,
+		VerifyingContract:  c.VerifyingContract,
 	}
 
 	return config, nil
@@ -62,6 +62,9 @@ func (s *NewSubstrateConfigTestSuite) Test_ValidConfig() {
 		SubstrateNetwork:   uint16(0),
 		BlockInterval:      big.NewInt(5),
 		BlockRetryInterval: time.Duration(5) * time.Second,
+		SubmissionBackOff:  time.Duration(120) * time.Second,
+		BridgeVersion:      "3.1.0",
+		VerifyingContract:  "6CdE2Cd82a4F8B74693Ff5e194c19CA08c2d1c68",
 	})
 }
 
@@ -75,6 +78,9 @@ func (s *NewSubstrateConfigTestSuite) Test_ValidConfigWithCustomParams() {
 		"startBlock":         1000,
 		"blockRetryInterval": 10,
 		"blockInterval":      2,
+		"submissionBackOff":  30,
+		"bridgeVersion":      "3.2.0",
+		"verifyingContract":  "0x5798e01f4b1d8f6a5d91167414f3a915d021bc4a",
 	}
 
 	actualConfig, err := NewSubstrateConfig(rawConfig)
@@ -93,5 +99,8 @@ func (s *NewSubstrateConfigTestSuite) Test_ValidConfigWithCustomParams() {
 		StartBlock:         big.NewInt(1000),
 		BlockInterval:      big.NewInt(2),
 		BlockRetryInterval: time.Duration(10) * time.Second,
+		SubmissionBackOff:  time.Duration(30) * time.Second,
+		BridgeVersion:      "3.2.0",
+		VerifyingContract:  "0x5798e01f4b1d8f6a5d91167414f3a915d021bc4a",
 	})
 }
@@ -10,6 +10,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/ChainSafe/sygma-relayer/chains"
 	"github.com/ChainSafe/sygma-relayer/relayer/transfer"
 	"github.com/binance-chain/tss-lib/common"
 	"github.com/sourcegraph/conc/pool"
@@ -41,32 +42,44 @@ type BridgePallet interface {
 }
 
 type Executor struct {
-	coordinator *tss.Coordinator
-	host        host.Host
-	comm        comm.Communication
-	fetcher     signing.SaveDataFetcher
-	bridge      BridgePallet
-	conn        *connection.Connection
-	exitLock    *sync.RWMutex
+	domainID          uint8
+	scheduler         *tss.Scheduler
+	host              host.Host
+	comm              comm.Communication
+	fetcher           signing.SaveDataFetcher
+	bridge            BridgePallet
+	propStorer        PropStorer
+	conn              *connection.Connection
+	exitLock          *sync.RWMutex
+	submissionBackOff time.Duration
+	metrics           chains.SubmissionMetrics
 }
 
 func NewExecutor(
+	domainID uint8,
 	host host.Host,
 	comm comm.Communication,
-	coordinator *tss.Coordinator,
+	scheduler *tss.Scheduler,
 	bridgePallet BridgePallet,
+	propStorer PropStorer,
 	fetcher signing.SaveDataFetcher,
 	conn *connection.Connection,
 	exitLock *sync.RWMutex,
+	submissionBackOff time.Duration,
+	metrics chains.SubmissionMetrics,
 ) *Executor {
 	return &Executor{
-		host:        host,
-		comm:        comm,
-		coordinator: coordinator,
-		bridge:      bridgePallet,
-		fetcher:     fetcher,
-		conn:        conn,
-		exitLock:    exitLock,
+		domainID:          domainID,
+		host:              host,
+		comm:              comm,
+		scheduler:         scheduler,
+		bridge:            bridgePallet,
+		propStorer:        propStorer,
+		fetcher:           fetcher,
+		conn:              conn,
+		exitLock:          exitLock,
+		submissionBackOff: submissionBackOff,
+		metrics:           metrics,
 	}
 }
 
@@ -84,6 +97,15 @@ func (e *Executor) Execute(proposals []*proposal.Proposal) error {
 			Type:        prop.Type,
 			MessageID:   prop.MessageID,
 		}
+
+		isOrphaned, err := e.isOrphaned(transferProposal)
+		if err != nil {
+			return err
+		}
+		if isOrphaned {
+			log.Warn().Str("messageID", transferProposal.MessageID).Msgf("Skipping proposal %+v from orphaned block", transferProposal)
+			continue
+		}
 		transferProposals = append(transferProposals, transferProposal)
 
 		isExecuted, err := e.bridge.IsProposalExecuted(transferProposal)
@@ -96,7 +118,7 @@ func (e *Executor) Execute(proposals []*proposal.Proposal) error {
 
 		proposals = append(proposals, prop)
 	}
-	if len(proposals) == 0 {
+	if len(proposals) == 0 || len(transferProposals) == 0 {
 		return nil
 	}
 
@@ -125,7 +147,7 @@ func (e *Executor) Execute(proposals []*proposal.Proposal) error {
 
 	pool := pool.New().WithErrors()
 	pool.Go(func() error {
-		err := e.coordinator.Execute(executionContext, []tss.TssProcess{signing}, sigChn)
+		err := e.scheduler.Execute(executionContext, tss.SigningPriority(transfer.IsRetried(transferProposals)), transfer.LatestDepositNonce(transferProposals), []tss.TssProcess{signing}, sigChn)
 		if err != nil {
 			cancelWatch()
 		}
@@ -133,18 +155,26 @@ func (e *Executor) Execute(proposals []*proposal.Proposal) error {
 		return err
 	})
 	pool.Go(func() error {
-		return e.watchExecution(watchContext, cancelExecution, transferProposals, sigChn, messageID)
+		return e.watchExecution(watchContext, cancelExecution, transferProposals, sigChn, signing, messageID)
 	})
 	return pool.Wait()
 }
 
-func (e *Executor) watchExecution(ctx context.Context, cancelExecution context.CancelFunc, proposals []*transfer.TransferProposal, sigChn chan interface{}, sessionID string) error {
+func (e *Executor) watchExecution(
+	ctx context.Context,
+	cancelExecution context.CancelFunc,
+	proposals []*transfer.TransferProposal,
+	sigChn chan interface{},
+	signing *signing.Signing,
+	sessionID string) error {
 	ticker := time.NewTicker(executionCheckPeriod)
 	timeout := time.NewTicker(signingTimeout)
 	defer ticker.Stop()
 	defer timeout.Stop()
 	defer cancelExecution()
 
+	var signatureData *common.SignatureData
+	var submissionTimer <-chan time.Time
 	for {
 		select {
 		case sigResult := <-sigChn:
@@ -154,14 +184,27 @@ func (e *Executor) watchExecution(ctx context.Context, cancelExecution context.C
 					continue
 				}
 
-				signatureData := sigResult.(*common.SignatureData)
-				hash, sub, err := e.executeProposal(proposals, signatureData)
-				if err != nil {
-					_ = e.comm.Broadcast(e.host.Peerstore().Peers(), []byte{}, comm.TssFailMsg, sessionID)
-					return err
+				signatureData = sigResult.(*common.SignatureData)
+				delay := chains.SubmissionDelay(e.host.ID(), signing.Peers, sessionID, e.submissionBackOff)
+				if delay > 0 {
+					log.Info().Str("messageID", sessionID).Msgf("Submitting proposals execution if not executed in %s", delay)
+					submissionTimer = time.After(delay)
+					continue
+				}
+
+				return e.submitProposals(proposals, signatureData, sessionID, false)
+			}
+		case <-submissionTimer:
+			{
+				if e.areProposalsExecuted(proposals) {
+					if e.metrics != nil {
+						e.metrics.TrackSkippedProposalSubmission(e.domainID)
+					}
+					log.Info().Str("messageID", sessionID).Msgf("Proposals executed by designated submitter")
+					return nil
 				}
 
-				return e.bridge.TrackExtrinsic(hash, sub)
+				return e.submitProposals(proposals, signatureData, sessionID, true)
 			}
 		case <-ticker.C:
 			{
@@ -184,6 +227,24 @@ func (e *Executor) watchExecution(ctx context.Context, cancelExecution context.C
 	}
 }
 
+// submitProposals submits the proposals execution as the designated submitter or as a fallback
+// submitter after the designated submitter failed to execute proposals
+func (e *Executor) submitProposals(proposals []*transfer.TransferProposal, signatureData *common.SignatureData, sessionID string, fallback bool) error {
+	hash, sub, err := e.executeProposal(proposals, signatureData)
+	if err != nil {
+		if e.metrics != nil {
+			e.metrics.TrackWastedProposalSubmission(e.domainID)
+		}
+		_ = e.comm.Broadcast(e.host.Peerstore().Peers(), []byte{}, comm.TssFailMsg, sessionID)
+		return err
+	}
+
+	if e.metrics != nil {
+		e.metrics.TrackProposalSubmission(e.domainID, fallback)
+	}
+	return e.bridge.TrackExtrinsic(hash, sub)
+}
+
 func (e *Executor) executeProposal(proposals []*transfer.TransferProposal, signatureData *common.SignatureData) (types.Hash, *author.ExtrinsicStatusSubscription, error) {
 	sig := []byte{}
 	sig = append(sig[:], ethCommon.LeftPadBytes(signatureData.R, 32)...)
@@ -199,6 +260,14 @@ func (e *Executor) executeProposal(proposals []*transfer.TransferProposal, signa
 	return hash, sub, err
 }
 
+// isOrphaned returns true if the proposal deposit is from a source block orphaned by a chain reorganization
+func (e *Executor) isOrphaned(prop *transfer.TransferProposal) (bool, error) {
+	if prop.Data.BlockHash == "" {
+		return false, nil
+	}
+	return e.propStorer.IsPropInvalid(prop.Source, prop.Destination, prop.Data.DepositNonce, prop.Data.BlockHash)
+}
+
 func (e *Executor) areProposalsExecuted(proposals []*transfer.TransferProposal) bool {
 	for _, prop := range proposals {
 		isExecuted, err := e.bridge.IsProposalExecuted(prop)
@@ -57,12 +57,15 @@ func fungibleTransferMessageHandler(m *transfer.TransferMessage) (*proposal.Prop
 		ResourceId:   m.Data.ResourceId,
 		Metadata:     m.Data.Metadata,
 		Data:         data,
+		BlockHash:    m.Data.BlockHash,
+		Retried:      m.Data.Retried,
 	}, m.ID, transfer.TransferProposalType), nil
 }
 
 type PropStorer interface {
 	StorePropStatus(source, destination uint8, depositNonce uint64, status store.PropStatus) error
 	PropStatus(source, destination uint8, depositNonce uint64) (store.PropStatus, error)
+	IsPropInvalid(source, destination uint8, depositNonce uint64, blockHash string) (bool, error)
 }
 
 type BlockFetcher interface {
@@ -17,9 +17,6 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-const bridgeVersion = "3.1.0"
-const verifyingContract = "6CdE2Cd82a4F8B74693Ff5e194c19CA08c2d1c68"
-
 type BridgeProposal struct {
 	OriginDomainID uint8
 	DepositNonce   uint64
@@ -29,13 +26,21 @@ type BridgeProposal struct {
 
 type Pallet struct {
 	*client.SubstrateClient
+	bridgeVersion     string
+	verifyingContract string
 }
 
+// NewPallet creates the bridge pallet that signs proposals for the EIP-712 domain
+// of the bridge version and verifying contract
 func NewPallet(
 	client *client.SubstrateClient,
+	bridgeVersion string,
+	verifyingContract string,
 ) *Pallet {
 	return &Pallet{
-		client,
+		SubstrateClient:   client,
+		bridgeVersion:     bridgeVersion,
+		verifyingContract: verifyingContract,
 	}
 }
 
@@ -61,7 +66,12 @@ func (p *Pallet) ExecuteProposals(
 }
 
 func (p *Pallet) ProposalsHash(proposals []*transfer.TransferProposal) ([]byte, error) {
-	return chains.ProposalsHash(proposals, p.ChainID.Int64(), verifyingContract, bridgeVersion)
+	return chains.ProposalsHash(proposals, &chains.EIP712Domain{
+		Name:              "Bridge",
+		Version:           p.bridgeVersion,
+		ChainID:           p.ChainID,
+		VerifyingContract: p.verifyingContract,
+	})
 }
 
 func (p *Pallet) IsProposalExecuted(prop *transfer.TransferProposal) (bool, error) {
@@ -0,0 +1,15 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ceremony
+
+import "github.com/spf13/cobra"
+
+var CeremonyCLI = &cobra.Command{
+	Use:   "ceremony",
+	Short: "admin commands for managing keygen and resharing ceremonies",
+}
+
+func init() {
+	CeremonyCLI.AddCommand(retriggerCMD)
+}
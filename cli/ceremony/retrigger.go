@@ -0,0 +1,52 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ceremony
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	retriggerCMD = &cobra.Command{
+		Use:   "retrigger",
+		Short: "Re-trigger ceremony for block",
+		Long: "CLI re-triggers keygen or resharing ceremonies started by an event on " +
+			"the provided block on a running relayer",
+		RunE: retrigger,
+	}
+)
+
+var (
+	block string
+	url   string
+)
+
+func init() {
+	retriggerCMD.PersistentFlags().StringVar(&block, "block", "", "block of the event that started the ceremony")
+	_ = retriggerCMD.MarkFlagRequired("block")
+	retriggerCMD.PersistentFlags().StringVar(&url, "url", "http://localhost:9001", "relayer health endpoint url")
+}
+
+func retrigger(cmd *cobra.Command, args []string) error {
+	resp, err := http.Post(fmt.Sprintf("%s/ceremony/retrigger?block=%s", url, block), "text/plain", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed re-triggering ceremony: %s", body)
+	}
+
+	fmt.Printf("Ceremony for block %s re-triggered\n", block)
+	return nil
+}
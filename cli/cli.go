@@ -8,8 +8,10 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/ChainSafe/sygma-relayer/cli/ceremony"
 	"github.com/ChainSafe/sygma-relayer/cli/keygen"
 	"github.com/ChainSafe/sygma-relayer/cli/peer"
+	"github.com/ChainSafe/sygma-relayer/cli/session"
 	"github.com/ChainSafe/sygma-relayer/cli/topology"
 	"github.com/ChainSafe/sygma-relayer/cli/utils"
 	"github.com/ChainSafe/sygma-relayer/config"
@@ -31,7 +33,7 @@ func init() {
 }
 
 func Execute() {
-	rootCMD.AddCommand(runCMD, peer.PeerCLI, topology.TopologyCLI, utils.UtilsCLI, keygen.KeygenCLI)
+	rootCMD.AddCommand(runCMD, peer.PeerCLI, topology.TopologyCLI, utils.UtilsCLI, keygen.KeygenCLI, ceremony.CeremonyCLI, session.SessionCLI)
 	if err := rootCMD.Execute(); err != nil {
 		log.Fatal().Err(err).Msg("failed to execute root cmd")
 	}
@@ -0,0 +1,15 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package session
+
+import "github.com/spf13/cobra"
+
+var SessionCLI = &cobra.Command{
+	Use:   "session",
+	Short: "commands for debugging recorded tss sessions",
+}
+
+func init() {
+	SessionCLI.AddCommand(replayCMD)
+}
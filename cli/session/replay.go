@@ -0,0 +1,85 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package session
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ChainSafe/sygma-relayer/comm/recorder"
+	"github.com/ChainSafe/sygma-relayer/keyshare"
+	"github.com/ChainSafe/sygma-relayer/tss/replay"
+)
+
+var (
+	replayCMD = &cobra.Command{
+		Use:   "replay",
+		Short: "Replay recorded signing session",
+		Long: "CLI replays signing session messages recorded by a relayer through the ECDSA or FROST " +
+			"state machine with the keyshare of the recording relayer and reports parties that sent malformed messages",
+		RunE: replaySession,
+	}
+)
+
+var (
+	record       string
+	protocol     string
+	keysharePath string
+)
+
+func init() {
+	replayCMD.PersistentFlags().StringVar(&record, "record", "", "path to the recorded session file")
+	_ = replayCMD.MarkFlagRequired("record")
+	replayCMD.PersistentFlags().StringVar(&keysharePath, "keyshare", "", "path to the keyshare of the recording relayer")
+	_ = replayCMD.MarkFlagRequired("keyshare")
+	replayCMD.PersistentFlags().StringVar(&protocol, "protocol", "ecdsa", "signing protocol of the session (ecdsa|frost)")
+}
+
+func replaySession(cmd *cobra.Command, args []string) error {
+	session, err := recorder.LoadSession(record)
+	if err != nil {
+		return err
+	}
+
+	var reports []*replay.Report
+	switch protocol {
+	case "ecdsa":
+		{
+			key, err := keyshare.NewECDSAKeyshareStore(keysharePath).GetKeyshare()
+			if err != nil {
+				return err
+			}
+			reports, err = replay.ECDSASigning(session, key)
+			if err != nil {
+				return err
+			}
+		}
+	case "frost":
+		{
+			key, err := keyshare.NewFrostKeyshareStore(keysharePath).GetKeyshare()
+			if err != nil {
+				return err
+			}
+			reports, err = replay.FrostSigning(session, key)
+			if err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported protocol %s", protocol)
+	}
+
+	fmt.Printf("Session %s recorded by %s\n", session.ID, session.Self.Pretty())
+	for i, report := range reports {
+		fmt.Printf("\nAttempt %d with peers %s\n", i, report.Peers)
+		fmt.Printf("Messages verified by state machine: %d, validated: %d\n", report.Verified, report.Validated)
+		if len(report.Culprits) == 0 {
+			fmt.Println("No malformed messages found")
+			continue
+		}
+		fmt.Printf("Malformed messages sent by %s: %s\n", report.Culprits, report.Err)
+	}
+	return nil
+}
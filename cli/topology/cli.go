@@ -13,4 +13,6 @@ var TopologyCLI = &cobra.Command{
 func init() {
 	TopologyCLI.AddCommand(encryptTopologyCMD)
 	TopologyCLI.AddCommand(testTopologyCMD)
+	TopologyCLI.AddCommand(migrateTopologyCMD)
+	TopologyCLI.AddCommand(signTopologyCMD)
 }
@@ -20,8 +20,9 @@ var (
 	encryptTopologyCMD = &cobra.Command{
 		Use:   "encrypt",
 		Short: "encrypt provided topology with AES",
-		Long:  "Algorithm used is AES CTR. IV and CT returned are in hex.",
-		RunE:  encryptTopology,
+		Long: "Algorithm used is AES-256-GCM with the key derived from the encryption key with argon2id. " +
+			"Versioned topology envelope is returned in hex.",
+		RunE: encryptTopology,
 	}
 )
 
@@ -38,8 +39,10 @@ func init() {
 }
 
 func encryptTopology(cmd *cobra.Command, args []string) error {
-	cipherKey := []byte(encryptionKey)
-	aesEncryption, _ := topology.NewAESEncryption(cipherKey)
+	encryption, err := topology.NewTopologyEncryption([]byte(encryptionKey), false)
+	if err != nil {
+		return err
+	}
 	topologyFile, err := os.Open(path)
 	defer func() {
 		err := topologyFile.Close()
@@ -60,7 +63,7 @@ func encryptTopology(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("topology was wrong formed %s", err.Error())
 	}
-	ct, err := aesEncryption.Encrypt(byteValue)
+	ct, err := encryption.Encrypt(byteValue)
 	if err != nil {
 		return err
 	}
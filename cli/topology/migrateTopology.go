@@ -0,0 +1,90 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package topology
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ChainSafe/sygma-relayer/topology"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	migrateTopologyCMD = &cobra.Command{
+		Use:   "migrate",
+		Short: "re-encrypt legacy AES CTR topology into the versioned topology envelope",
+		Long: "Decrypts topology encrypted with AES CTR and encrypts it with AES-256-GCM with the key " +
+			"derived from the encryption key with argon2id. Versioned topology envelope is returned in hex.",
+		RunE: migrateTopology,
+	}
+)
+
+var (
+	migrateURL  string
+	migratePath string
+	migrateKey  string
+)
+
+func init() {
+	migrateTopologyCMD.PersistentFlags().StringVar(&migrateURL, "url", "", "url to fetch encrypted topology")
+	migrateTopologyCMD.PersistentFlags().StringVar(&migratePath, "path", "", "path to file with encrypted topology in hex")
+	migrateTopologyCMD.PersistentFlags().StringVar(&migrateKey, "encryption-key", "", "password used to encrypt topology")
+	_ = migrateTopologyCMD.MarkFlagRequired("encryption-key")
+}
+
+func migrateTopology(cmd *cobra.Command, args []string) error {
+	var source topology.Source
+	switch {
+	case migrateURL != "" && migratePath == "":
+		source = topology.NewURLSource(migrateURL, http.DefaultClient)
+	case migratePath != "" && migrateURL == "":
+		source = topology.NewFileSource(migratePath)
+	default:
+		return fmt.Errorf("either url or path of the encrypted topology has to be provided")
+	}
+
+	encryption, err := topology.NewTopologyEncryption([]byte(migrateKey), true)
+	if err != nil {
+		return err
+	}
+	ct, err := source.EncryptedTopology()
+	if err != nil {
+		return err
+	}
+	if !topology.IsLegacyCiphertext(ct) {
+		return fmt.Errorf("topology is already encrypted with the versioned envelope")
+	}
+
+	pt, err := encryption.Decrypt(ct)
+	if err != nil {
+		return err
+	}
+	// Testing that topology was decrypted with the correct key
+	rawTopology := &topology.RawTopology{}
+	err = json.Unmarshal(pt, rawTopology)
+	if err != nil {
+		return fmt.Errorf("decrypted topology was wrong formed %s", err.Error())
+	}
+	_, err = topology.ProcessRawTopology(rawTopology)
+	if err != nil {
+		return err
+	}
+
+	migratedCt, err := encryption.Encrypt(pt)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Encrypted topology is: %x \n", migratedCt)
+	h := sha256.New()
+	h.Write(migratedCt)
+	eh := hex.EncodeToString(h.Sum(nil))
+	fmt.Printf("Hash of the topology %s", eh)
+	return nil
+}
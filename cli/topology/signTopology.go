@@ -0,0 +1,78 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package topology
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ChainSafe/sygma-relayer/topology"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	signTopologyCMD = &cobra.Command{
+		Use:   "sign",
+		Short: "sign provided topology with the governance key",
+		Long: "Signs the topology as an Ethereum signed message and appends the signature to topology signatures. " +
+			"Each governance key signs the same topology file before it is encrypted.",
+		RunE: signTopology,
+	}
+)
+
+var (
+	signPath       string
+	signOutputPath string
+	signPrivateKey string
+)
+
+func init() {
+	signTopologyCMD.PersistentFlags().StringVar(&signPath, "path", "", "path to json file with network topology")
+	_ = signTopologyCMD.MarkFlagRequired("path")
+	signTopologyCMD.PersistentFlags().StringVar(&signPrivateKey, "private-key", "", "hex encoded governance private key")
+	_ = signTopologyCMD.MarkFlagRequired("private-key")
+	signTopologyCMD.PersistentFlags().StringVar(&signOutputPath, "output", "", "path where the signed topology is written, defaults to the topology path")
+}
+
+func signTopology(cmd *cobra.Command, args []string) error {
+	key, err := crypto.HexToECDSA(signPrivateKey)
+	if err != nil {
+		return err
+	}
+	byteValue, err := os.ReadFile(signPath)
+	if err != nil {
+		return err
+	}
+	rawTopology := &topology.RawTopology{}
+	err = json.Unmarshal(byteValue, rawTopology)
+	if err != nil {
+		return fmt.Errorf("topology was wrong formed %s", err.Error())
+	}
+	_, err = topology.ProcessRawTopology(rawTopology)
+	if err != nil {
+		return err
+	}
+
+	err = topology.SignTopology(rawTopology, key)
+	if err != nil {
+		return err
+	}
+	signedTopology, err := json.MarshalIndent(rawTopology, "", "    ")
+	if err != nil {
+		return err
+	}
+	if signOutputPath == "" {
+		signOutputPath = signPath
+	}
+	err = os.WriteFile(signOutputPath, signedTopology, 0600)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Topology signed by %s and written to %s\n", crypto.PubkeyToAddress(key.PublicKey), signOutputPath)
+	return nil
+}
@@ -27,6 +27,9 @@ var (
 	url           string
 	hash          string
 	decryptionKey string
+
+	governanceKeys      []string
+	governanceThreshold int
 )
 
 func init() {
@@ -35,14 +38,20 @@ func init() {
 	testTopologyCMD.PersistentFlags().StringVar(&url, "url", "", "url to fetch topology")
 	_ = testTopologyCMD.MarkFlagRequired("url")
 	testTopologyCMD.PersistentFlags().StringVar(&hash, "hash", "", "hash of topology")
+	testTopologyCMD.PersistentFlags().StringSliceVar(&governanceKeys, "governance-keys", []string{}, "addresses of governance keys that sign topology")
+	testTopologyCMD.PersistentFlags().IntVar(&governanceThreshold, "governance-threshold", 0, "number of required governance signatures")
 
 }
 
 func testTopology(cmd *cobra.Command, args []string) error {
 	config := relayer.TopologyConfiguration{
-		EncryptionKey: decryptionKey,
-		Url:           url,
-		Path:          "",
+		EncryptionKey:       decryptionKey,
+		Url:                 url,
+		Path:                "",
+		GovernanceKeys:      governanceKeys,
+		GovernanceThreshold: governanceThreshold,
+		// legacy topology is decrypted so it can be tested before migration
+		AllowLegacyEncryption: true,
 	}
 	nt, err := topology.NewNetworkTopologyProvider(config, http.DefaultClient)
 	if err != nil {
@@ -52,6 +61,10 @@ func testTopology(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	ct, err := topology.NewURLSource(url, http.DefaultClient).EncryptedTopology()
+	if err == nil && topology.IsLegacyCiphertext(ct) {
+		fmt.Printf("Topology is encrypted with legacy AES CTR, migrate it with the migrate command\n")
+	}
 
 	fmt.Printf("Everything is fine your topology is \n")
 	fmt.Printf("%+v", decryptedTopology)
@@ -12,12 +12,18 @@ type WrappedMessage struct {
 	MessageType MessageType `json:"message_type"`
 	SessionID   string      `json:"message_id"`
 	Payload     []byte      `json:"payload"`
-	From        peer.ID     `json:"-"`
+	// Timestamp is the unix time in milliseconds when the message was sent
+	Timestamp int64 `json:"timestamp,omitempty"`
+	// Nonce is a random number that together with the timestamp prevents message replays
+	Nonce uint64 `json:"nonce,omitempty"`
+	// Signature signs the message with the libp2p key of the sender
+	Signature []byte  `json:"signature,omitempty"`
+	From      peer.ID `json:"-"`
 }
 
 // Communication defines methods for communicating between peers
 type Communication interface {
-	// CloseSession closes and removes all streams for that session
+	// CloseSession releases streams used by the session so they can be reused by other sessions
 	CloseSession(sessionID string)
 	// Broadcast sends message to provided peers
 	// If error has occurred on sending any message, broadcast will be aborted and error will be sent to errChan
@@ -227,6 +227,7 @@ func InitializeHostsAndCommunications(numberOfActors int, protocolID protocol.ID
 		com := p2p.NewCommunication(
 			testHosts[i],
 			protocolID,
+			p2p.CommunicationConfig{},
 		)
 		testCommunications = append(testCommunications, com)
 	}
@@ -99,6 +99,7 @@ func (s *BullyTestSuite) SetupIndividualTest(c BullyTestCase) ([]elector.Coordin
 		com := p2p.NewCommunication(
 			testHosts[i],
 			s.testProtocolID,
+			p2p.CommunicationConfig{},
 		)
 
 		if !c.isLeaderActive && testHosts[i].ID() == initialCoordinator {
@@ -5,6 +5,7 @@ package elector
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/ChainSafe/sygma-relayer/comm"
 	"github.com/ChainSafe/sygma-relayer/comm/p2p"
@@ -19,8 +20,23 @@ type CoordinatorElectorType int
 const (
 	Static CoordinatorElectorType = iota
 	Bully
+	Weighted
 )
 
+// ParseCoordinatorElectorType returns the elector type from its configuration name
+func ParseCoordinatorElectorType(electorType string) (CoordinatorElectorType, error) {
+	switch electorType {
+	case "static":
+		return Static, nil
+	case "bully":
+		return Bully, nil
+	case "weighted":
+		return Weighted, nil
+	default:
+		return Static, fmt.Errorf("unknown coordinator elector type %s", electorType)
+	}
+}
+
 const ProtocolID protocol.ID = "/sygma/coordinator/1.0.0"
 
 type CoordinatorElector interface {
@@ -33,16 +49,25 @@ type CoordinatorElectorFactory struct {
 	h      host.Host
 	comm   comm.Communication
 	config relayer.BullyConfig
+	scorer PeerScorer
 }
 
-// NewCoordinatorElectorFactory creates new CoordinatorElectorFactory
-func NewCoordinatorElectorFactory(h host.Host, config relayer.BullyConfig) *CoordinatorElectorFactory {
-	communication := p2p.NewCommunication(h, ProtocolID)
+// NewCoordinatorElectorFactory creates new CoordinatorElectorFactory.
+// Scorer is used by the weighted elector and can be nil in which case all peers
+// have the same score.
+func NewCoordinatorElectorFactory(
+	h host.Host, config relayer.BullyConfig, scorer PeerScorer, commConfig p2p.CommunicationConfig,
+) *CoordinatorElectorFactory {
+	communication := p2p.NewCommunication(h, ProtocolID, commConfig)
+	if scorer == nil {
+		scorer = NewHealthScorer(h.ID())
+	}
 
 	return &CoordinatorElectorFactory{
 		h:      h,
 		comm:   communication,
 		config: config,
+		scorer: scorer,
 	}
 }
 
@@ -55,6 +80,8 @@ func (c *CoordinatorElectorFactory) CoordinatorElector(
 		return NewCoordinatorElector(sessionID)
 	case Bully:
 		return NewBullyCoordinatorElector(sessionID, c.h, c.config, c.comm)
+	case Weighted:
+		return NewWeightedCoordinatorElector(sessionID, c.h, c.config, c.comm, c.scorer)
 	default:
 		return nil
 	}
@@ -0,0 +1,93 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package elector
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+const (
+	// healthDecay is the weight of the latest health check result in the moving averages
+	healthDecay = 0.2
+	// referenceLatency is the latency at which the peer score is halved
+	referenceLatency = time.Second
+	// scorePrecision is used to round scores so small latency differences
+	// between peers do not change the coordinator
+	scorePrecision = 10
+)
+
+type PeerScorer interface {
+	// Score returns a peer score between 0 and 1 where higher is healthier
+	Score(peerID peer.ID) float64
+}
+
+type peerHealth struct {
+	successRate float64
+	latency     time.Duration
+}
+
+// HealthScorer calculates peer scores from the success rate and
+// latency of communication health checks
+type HealthScorer struct {
+	mu     sync.RWMutex
+	hostID peer.ID
+	health map[peer.ID]*peerHealth
+}
+
+func NewHealthScorer(hostID peer.ID) *HealthScorer {
+	return &HealthScorer{
+		hostID: hostID,
+		health: make(map[peer.ID]*peerHealth),
+	}
+}
+
+// TrackPeerHealth updates exponential moving averages of peer success rate and latency
+func (s *HealthScorer) TrackPeerHealth(peerID peer.ID, latency time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	success := 1.0
+	if err != nil {
+		success = 0
+	}
+
+	health, ok := s.health[peerID]
+	if !ok {
+		s.health[peerID] = &peerHealth{
+			successRate: success,
+			latency:     latency,
+		}
+		return
+	}
+
+	health.successRate = (1-healthDecay)*health.successRate + healthDecay*success
+	if err == nil {
+		health.latency = time.Duration((1-healthDecay)*float64(health.latency) + healthDecay*float64(latency))
+	}
+}
+
+// Score returns rounded peer score. The host can't measure its own health, so the
+// host and untracked peers are scored as healthy.
+func (s *HealthScorer) Score(peerID peer.ID) float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	health, ok := s.health[peerID]
+	if !ok || peerID == s.hostID {
+		return 1
+	}
+	return round(health.score())
+}
+
+func (h *peerHealth) score() float64 {
+	return h.successRate / (1 + float64(h.latency)/float64(referenceLatency))
+}
+
+func round(score float64) float64 {
+	return math.Round(score*scorePrecision) / scorePrecision
+}
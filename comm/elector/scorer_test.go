@@ -0,0 +1,58 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package elector_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/sygma-relayer/comm/elector"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/suite"
+)
+
+type HealthScorerTestSuite struct {
+	suite.Suite
+	scorer *elector.HealthScorer
+	hostID peer.ID
+}
+
+func TestRunHealthScorerTestSuite(t *testing.T) {
+	suite.Run(t, new(HealthScorerTestSuite))
+}
+
+func (s *HealthScorerTestSuite) SetupTest() {
+	s.hostID = peer.ID("host")
+	s.scorer = elector.NewHealthScorer(s.hostID)
+}
+
+func (s *HealthScorerTestSuite) Test_Score_NoHealthData() {
+	s.Equal(s.scorer.Score(s.hostID), 1.0)
+	s.Equal(s.scorer.Score(peer.ID("peer1")), 1.0)
+}
+
+func (s *HealthScorerTestSuite) Test_Score_HighLatencyPeerScoredLower() {
+	s.scorer.TrackPeerHealth(peer.ID("peer1"), 20*time.Millisecond, nil)
+	s.scorer.TrackPeerHealth(peer.ID("peer2"), 300*time.Millisecond, nil)
+
+	s.Equal(s.scorer.Score(peer.ID("peer1")), 1.0)
+	s.Equal(s.scorer.Score(peer.ID("peer2")), 0.8)
+}
+
+func (s *HealthScorerTestSuite) Test_Score_FailedChecksLowerScore() {
+	s.scorer.TrackPeerHealth(peer.ID("peer1"), 20*time.Millisecond, nil)
+	s.scorer.TrackPeerHealth(peer.ID("peer1"), 0, errors.New("error"))
+	s.scorer.TrackPeerHealth(peer.ID("peer1"), 0, errors.New("error"))
+
+	s.Equal(s.scorer.Score(peer.ID("peer1")), 0.6)
+}
+
+func (s *HealthScorerTestSuite) Test_Score_HostAndUntrackedPeersScoredAsHealthy() {
+	s.scorer.TrackPeerHealth(peer.ID("peer1"), 300*time.Millisecond, nil)
+	s.scorer.TrackPeerHealth(s.hostID, 300*time.Millisecond, nil)
+
+	s.Equal(s.scorer.Score(s.hostID), 1.0)
+	s.Equal(s.scorer.Score(peer.ID("peer3")), 1.0)
+}
@@ -0,0 +1,243 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package elector
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/ChainSafe/sygma-relayer/config/relayer"
+	"github.com/ChainSafe/sygma-relayer/tss/util"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/exp/slices"
+)
+
+// weightedCoordinatorElector elects the healthiest peer as the coordinator. Peers exchange
+// their local scores of other peers so the coordinator is elected from the same scores on
+// every peer. Peers then exchange their elected candidates and fall back to bully coordinator
+// election unless all peers elected the same candidate.
+type weightedCoordinatorElector struct {
+	sessionID string
+	hostID    peer.ID
+	comm      comm.Communication
+	conf      relayer.BullyConfig
+	scorer    PeerScorer
+	fallback  CoordinatorElector
+}
+
+func NewWeightedCoordinatorElector(
+	sessionID string, host host.Host, config relayer.BullyConfig, communication comm.Communication, scorer PeerScorer,
+) CoordinatorElector {
+	return &weightedCoordinatorElector{
+		sessionID: sessionID,
+		hostID:    host.ID(),
+		comm:      communication,
+		conf:      config,
+		scorer:    scorer,
+		fallback:  NewBullyCoordinatorElector(sessionID, host, config, communication),
+	}
+}
+
+// Coordinator returns the peer with the highest median score reported by other peers, with ties
+// broken by the static session order. Peers don't score themselves, so a peer's score only reflects
+// how other peers observe it. If any peer doesn't send its scores or elects a different candidate
+// in the election wait time, bully coordinator election is executed on provided peers.
+func (wc *weightedCoordinatorElector) Coordinator(ctx context.Context, peers peer.IDSlice) (peer.ID, error) {
+	sortedPeers := util.SortPeersForSession(peers, wc.sessionID)
+	if len(sortedPeers) == 0 {
+		return peer.ID(""), nil
+	}
+	if len(wc.otherPeers(peers)) == 0 {
+		return sortedPeers[0].ID, nil
+	}
+
+	scores, err := wc.localScores(peers)
+	if err != nil {
+		return "", err
+	}
+	reports, err := wc.collectReports(ctx, scores, peers)
+	if err != nil {
+		return "", err
+	}
+
+	candidate := peer.ID("")
+	if reports != nil {
+		candidate = wc.candidate(sortedPeers, reports)
+	}
+	agreed, err := wc.agree(ctx, candidate, peers)
+	if err != nil {
+		return "", err
+	}
+	if agreed {
+		return candidate, nil
+	}
+
+	log.Info().Str("SessionID", wc.sessionID).Msgf("Peers did not agree on the coordinator, starting bully coordinator election")
+	return wc.fallback.Coordinator(ctx, peers)
+}
+
+// collectReports exchanges local scores with peers and returns reports of all peers
+// or nil if any report is missing in the election wait time
+func (wc *weightedCoordinatorElector) collectReports(
+	ctx context.Context, scores []byte, peers peer.IDSlice,
+) (map[peer.ID]map[string]float64, error) {
+	received := make(chan *comm.WrappedMessage, len(peers))
+	go wc.exchange(comm.CoordinatorProposalMsg, scores, peers, received)
+
+	reports := make(map[peer.ID]map[string]float64)
+	hostReport := make(map[string]float64)
+	_ = json.Unmarshal(scores, &hostReport)
+	reports[wc.hostID] = hostReport
+	reported := map[peer.ID]bool{wc.hostID: true}
+	timeout := time.After(wc.conf.ElectionWaitTime)
+	for !hasAllPeers(peers, reported) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timeout:
+			return nil, nil
+		case msg := <-received:
+			report := make(map[string]float64)
+			err := json.Unmarshal(msg.Payload, &report)
+			if err != nil {
+				log.Debug().Str("SessionID", wc.sessionID).Msgf("Peer %s sent invalid scores", msg.From)
+				continue
+			}
+			reports[msg.From] = report
+			reported[msg.From] = true
+		}
+	}
+	return reports, nil
+}
+
+// agree exchanges the elected candidate with peers and returns true if all peers
+// elected the same candidate in the election wait time. An empty candidate means
+// the peer could not elect the candidate and makes other peers fall back immediately.
+func (wc *weightedCoordinatorElector) agree(ctx context.Context, candidate peer.ID, peers peer.IDSlice) (bool, error) {
+	received := make(chan *comm.WrappedMessage, len(peers))
+	go wc.exchange(comm.CoordinatorAgreementMsg, []byte(candidate), peers, received)
+	if candidate == "" {
+		return false, nil
+	}
+
+	agreed := map[peer.ID]bool{wc.hostID: true}
+	timeout := time.After(wc.conf.ElectionWaitTime)
+	for !hasAllPeers(peers, agreed) {
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-timeout:
+			return false, nil
+		case msg := <-received:
+			if peer.ID(msg.Payload) != candidate {
+				log.Info().Str("SessionID", wc.sessionID).Msgf("Peer %s elected a different coordinator", msg.From)
+				return false, nil
+			}
+			agreed[msg.From] = true
+		}
+	}
+	return true, nil
+}
+
+// exchange sends the payload to peers and answers payloads of peers for the whole
+// election wait time so peers that started later can still collect it.
+// The first payload of every peer is sent to the received channel.
+func (wc *weightedCoordinatorElector) exchange(
+	msgType comm.MessageType, payload []byte, peers peer.IDSlice, received chan *comm.WrappedMessage,
+) {
+	msgChan := make(chan *comm.WrappedMessage, len(peers))
+	subID := wc.comm.Subscribe(wc.sessionID, msgType, msgChan)
+	defer wc.comm.UnSubscribe(subID)
+
+	_ = wc.comm.Broadcast(peers, payload, msgType, wc.sessionID)
+
+	timeout := time.After(wc.conf.ElectionWaitTime)
+	answered := make(map[peer.ID]bool)
+	for {
+		select {
+		case <-timeout:
+			return
+		case msg := <-msgChan:
+			if !slices.Contains(peers, msg.From) || answered[msg.From] {
+				continue
+			}
+
+			answered[msg.From] = true
+			_ = wc.comm.Broadcast(peer.IDSlice{msg.From}, payload, msgType, wc.sessionID)
+			received <- msg
+		}
+	}
+}
+
+func hasAllPeers(peers peer.IDSlice, received map[peer.ID]bool) bool {
+	for _, p := range peers {
+		if !received[p] {
+			return false
+		}
+	}
+	return true
+}
+
+// localScores returns encoded scores of other peers from the local health view
+func (wc *weightedCoordinatorElector) localScores(peers peer.IDSlice) ([]byte, error) {
+	scores := make(map[string]float64)
+	for _, p := range wc.otherPeers(peers) {
+		scores[p.String()] = wc.scorer.Score(p)
+	}
+	return json.Marshal(scores)
+}
+
+// candidate returns the peer with the highest median score reported by other peers
+func (wc *weightedCoordinatorElector) candidate(sortedPeers util.SortablePeerSlice, reports map[peer.ID]map[string]float64) peer.ID {
+	candidate := sortedPeers[0].ID
+	candidateScore := reportedScore(candidate, sortedPeers, reports)
+	for _, p := range sortedPeers[1:] {
+		score := reportedScore(p.ID, sortedPeers, reports)
+		if score > candidateScore {
+			candidate = p.ID
+			candidateScore = score
+		}
+	}
+	return candidate
+}
+
+// reportedScore returns the upper median of scores other peers reported for the peer, so a single
+// peer can not lower scores of other peers by reporting them as unhealthy. Reported scores are
+// clamped to the valid score range and peers without a reported score are considered healthy.
+func reportedScore(peerID peer.ID, sortedPeers util.SortablePeerSlice, reports map[peer.ID]map[string]float64) float64 {
+	scores := make([]float64, 0)
+	for _, reporter := range sortedPeers {
+		if reporter.ID == peerID {
+			continue
+		}
+
+		score, ok := reports[reporter.ID][peerID.String()]
+		if !ok {
+			score = 1
+		}
+		scores = append(scores, math.Max(0, math.Min(1, score)))
+	}
+	if len(scores) == 0 {
+		return 1
+	}
+
+	sort.Float64s(scores)
+	return round(scores[len(scores)/2])
+}
+
+func (wc *weightedCoordinatorElector) otherPeers(peers peer.IDSlice) peer.IDSlice {
+	otherPeers := make(peer.IDSlice, 0)
+	for _, p := range peers {
+		if p != wc.hostID {
+			otherPeers = append(otherPeers, p)
+		}
+	}
+	return otherPeers
+}
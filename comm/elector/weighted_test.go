@@ -0,0 +1,169 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package elector_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/sygma-relayer/comm/elector"
+	"github.com/ChainSafe/sygma-relayer/comm/p2p"
+	"github.com/ChainSafe/sygma-relayer/config/relayer"
+	"github.com/ChainSafe/sygma-relayer/topology"
+	"github.com/ChainSafe/sygma-relayer/tss/util"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/sourcegraph/conc/pool"
+	"github.com/stretchr/testify/suite"
+)
+
+type testScorer map[peer.ID]float64
+
+func (s testScorer) Score(peerID peer.ID) float64 {
+	score, ok := s[peerID]
+	if !ok {
+		return 1
+	}
+	return score
+}
+
+type WeightedTestSuite struct {
+	suite.Suite
+	testHosts     []host.Host
+	testPeers     peer.IDSlice
+	testSessionID string
+	config        relayer.BullyConfig
+	portOffset    int
+}
+
+func TestRunWeightedCoordinatorElectorTestSuite(t *testing.T) {
+	suite.Run(t, new(WeightedTestSuite))
+}
+
+func (s *WeightedTestSuite) SetupSuite() {
+	s.testSessionID = "1"
+	s.portOffset = 4500
+	s.config = relayer.BullyConfig{
+		PingWaitTime:     1 * time.Second,
+		PingBackOff:      1 * time.Second,
+		PingInterval:     1 * time.Second,
+		ElectionWaitTime: 2 * time.Second,
+		BullyWaitTime:    5 * time.Second,
+	}
+}
+
+func (s *WeightedTestSuite) SetupTest() {
+	topology := &topology.NetworkTopology{
+		Peers: []*peer.AddrInfo{},
+	}
+	privateKeys := []crypto.PrivKey{}
+	for i := 0; i < numberOfTestHosts; i++ {
+		privKeyForHost, _, _ := crypto.GenerateKeyPair(crypto.ECDSA, 1)
+		privateKeys = append(privateKeys, privKeyForHost)
+		peerID, _ := peer.IDFromPrivateKey(privKeyForHost)
+		addrInfoForHost, _ := peer.AddrInfoFromString(fmt.Sprintf(
+			"/ip4/127.0.0.1/tcp/%d/p2p/%s", s.portOffset+i, peerID.Pretty(),
+		))
+		topology.Peers = append(topology.Peers, addrInfoForHost)
+	}
+
+	s.testHosts = []host.Host{}
+	s.testPeers = peer.IDSlice{}
+	for i := 0; i < numberOfTestHosts; i++ {
+		newHost, _ := p2p.NewHost(privateKeys[i], topology, p2p.NewConnectionGate(topology), uint16(s.portOffset+i))
+		s.testHosts = append(s.testHosts, newHost)
+		s.testPeers = append(s.testPeers, newHost.ID())
+	}
+	s.portOffset += numberOfTestHosts
+}
+
+func (s *WeightedTestSuite) TearDownTest() {
+	for _, testHost := range s.testHosts {
+		_ = testHost.Close()
+	}
+}
+
+func (s *WeightedTestSuite) electCoordinators(hosts []host.Host, scorers []elector.PeerScorer) []peer.ID {
+	coordinators := make([]peer.ID, len(hosts))
+	p := pool.New().WithErrors()
+	for i, testHost := range hosts {
+		i := i
+		communication := p2p.NewCommunication(testHost, elector.ProtocolID, p2p.CommunicationConfig{})
+		e := elector.NewWeightedCoordinatorElector(s.testSessionID, testHost, s.config, communication, scorers[i])
+		p.Go(func() error {
+			coordinator, err := e.Coordinator(context.Background(), s.testPeers)
+			coordinators[i] = coordinator
+			return err
+		})
+	}
+	s.Nil(p.Wait())
+	return coordinators
+}
+
+func (s *WeightedTestSuite) Test_Coordinator_EqualScores() {
+	scorer := testScorer{}
+
+	coordinators := s.electCoordinators(s.testHosts, []elector.PeerScorer{scorer, scorer, scorer})
+
+	sortedPeers := util.SortPeersForSession(s.testPeers, s.testSessionID)
+	for _, coordinator := range coordinators {
+		s.Equal(sortedPeers[0].ID, coordinator)
+	}
+}
+
+func (s *WeightedTestSuite) Test_Coordinator_UnhealthyPeerSkipped() {
+	sortedPeers := util.SortPeersForSession(s.testPeers, s.testSessionID)
+	scorer := testScorer{sortedPeers[0].ID: 0.5}
+
+	coordinators := s.electCoordinators(s.testHosts, []elector.PeerScorer{scorer, scorer, scorer})
+
+	for _, coordinator := range coordinators {
+		s.Equal(sortedPeers[1].ID, coordinator)
+	}
+}
+
+func (s *WeightedTestSuite) Test_Coordinator_DifferentLocalScoresAgree() {
+	sortedPeers := util.SortPeersForSession(s.testPeers, s.testSessionID)
+	scorer := testScorer{sortedPeers[0].ID: 0.2}
+
+	start := time.Now()
+	coordinators := s.electCoordinators(s.testHosts, []elector.PeerScorer{scorer, testScorer{}, scorer})
+
+	s.True(time.Since(start) < s.config.ElectionWaitTime)
+	for _, coordinator := range coordinators {
+		s.Equal(coordinators[0], coordinator)
+	}
+}
+
+func (s *WeightedTestSuite) Test_Coordinator_MissingScoresFallsBackToBully() {
+	scorer := testScorer{}
+
+	start := time.Now()
+	coordinators := s.electCoordinators(s.testHosts[:2], []elector.PeerScorer{scorer, scorer})
+
+	s.True(time.Since(start) >= s.config.ElectionWaitTime)
+	s.Equal(coordinators[0], coordinators[1])
+}
+
+func (s *WeightedTestSuite) Test_Coordinator_LowReportsOfSinglePeerIgnored() {
+	sortedPeers := util.SortPeersForSession(s.testPeers, s.testSessionID)
+	honestScorer := testScorer{}
+	lowScorer := testScorer{sortedPeers[0].ID: 0, sortedPeers[1].ID: 0}
+	scorers := make([]elector.PeerScorer, len(s.testHosts))
+	for i, testHost := range s.testHosts {
+		scorers[i] = honestScorer
+		if testHost.ID() == sortedPeers[2].ID {
+			scorers[i] = lowScorer
+		}
+	}
+
+	coordinators := s.electCoordinators(s.testHosts, scorers)
+
+	for _, coordinator := range coordinators {
+		s.Equal(sortedPeers[0].ID, coordinator)
+	}
+}
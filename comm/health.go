@@ -12,13 +12,28 @@ import (
 
 const HealthTimeout = 10 * time.Second
 
+// PeerHealthTracker records the result and latency of each peer health check
+type PeerHealthTracker interface {
+	TrackPeerHealth(peerID peer.ID, latency time.Duration, err error)
+}
+
 func ExecuteCommHealthCheck(communication Communication, peers peer.IDSlice) []*CommunicationError {
+	return ExecuteTrackedCommHealthCheck(communication, peers, nil)
+}
+
+// ExecuteTrackedCommHealthCheck executes the health check and reports the result
+// for each peer to the provided tracker
+func ExecuteTrackedCommHealthCheck(communication Communication, peers peer.IDSlice, tracker PeerHealthTracker) []*CommunicationError {
 	sessionID := "health-session"
 	defer communication.CloseSession(sessionID)
 	log.Debug().Msgf("ExecuteCommHealthCheck for peers %s", peers.String())
 	errors := make([]*CommunicationError, 0)
 	for _, p := range peers {
+		start := time.Now()
 		err := communication.Broadcast([]peer.ID{p}, []byte{}, Unknown, sessionID)
+		if tracker != nil {
+			tracker.TrackPeerHealth(p, time.Since(start), err)
+		}
 		if err != nil {
 			errors = append(errors, err.(*CommunicationError))
 		}
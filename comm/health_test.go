@@ -5,6 +5,7 @@ package comm_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/ChainSafe/sygma-relayer/comm"
 	"github.com/golang/mock/gomock"
@@ -14,6 +15,19 @@ import (
 	"github.com/stretchr/testify/suite"
 )
 
+type healthResult struct {
+	peerID peer.ID
+	err    error
+}
+
+type testTracker struct {
+	results []healthResult
+}
+
+func (t *testTracker) TrackPeerHealth(peerID peer.ID, latency time.Duration, err error) {
+	t.results = append(t.results, healthResult{peerID: peerID, err: err})
+}
+
 type CommunicationHealthTestSuite struct {
 	suite.Suite
 	mockController     *gomock.Controller
@@ -81,3 +95,20 @@ func (s *CommunicationHealthTestSuite) TestCommHealth_AllPeersOffline() {
 	s.NotEmpty(errors)
 	s.Equal(2, len(errors))
 }
+
+func (s *CommunicationHealthTestSuite) TestCommHealth_TracksPeerHealth() {
+	broadcastPeers := peer.IDSlice{s.testHosts[1].ID(), s.testHosts[2].ID()}
+	tracker := &testTracker{}
+	// close one peer
+	_ = s.testHosts[2].Close()
+	errors := comm.ExecuteTrackedCommHealthCheck(
+		s.testCommunications[0], broadcastPeers, tracker,
+	)
+
+	s.Equal(1, len(errors))
+	s.Equal(2, len(tracker.results))
+	s.Equal(broadcastPeers[0], tracker.results[0].peerID)
+	s.Nil(tracker.results[0].err)
+	s.Equal(broadcastPeers[1], tracker.results[1].peerID)
+	s.NotNil(tracker.results[1].err)
+}
@@ -3,6 +3,8 @@
 
 package comm
 
+import "fmt"
+
 // MessageType represents message type identificator
 type MessageType uint8
 
@@ -35,6 +37,18 @@ const (
 	CoordinatorPingResponseMsg
 	// Unknown message type
 	Unknown
+	// CoordinatorProposalMsg message type used to communicate the coordinator candidate calculated by the sender.
+	// Message types added after Unknown are appended so values of existing message types do not change.
+	CoordinatorProposalMsg
+	// CoordinatorAgreementMsg message type used to communicate the coordinator candidate elected by the sender.
+	CoordinatorAgreementMsg
+	// CeremonyRecoveryMsg message type used to request peers to re-run a failed keygen or resharing ceremony.
+	CeremonyRecoveryMsg
+	// TreeBroadcastAckMsg message type used to acknowledge messages received through the tree broadcast.
+	TreeBroadcastAckMsg
+
+	// lastMessageType is the number of message types
+	lastMessageType
 )
 
 // String implements fmt.Stringer
@@ -66,7 +80,25 @@ func (msgType MessageType) String() string {
 		return "CoordinatorPingMsg"
 	case CoordinatorPingResponseMsg:
 		return "CoordinatorPingResponseMsg"
+	case CoordinatorProposalMsg:
+		return "CoordinatorProposalMsg"
+	case CoordinatorAgreementMsg:
+		return "CoordinatorAgreementMsg"
+	case CeremonyRecoveryMsg:
+		return "CeremonyRecoveryMsg"
+	case TreeBroadcastAckMsg:
+		return "TreeBroadcastAckMsg"
 	default:
 		return "UnknownMsg"
 	}
 }
+
+// ParseMessageType returns the message type with the provided name
+func ParseMessageType(name string) (MessageType, error) {
+	for msgType := TssKeyGenMsg; msgType < lastMessageType; msgType++ {
+		if msgType != Unknown && msgType.String() == name {
+			return msgType, nil
+		}
+	}
+	return Unknown, fmt.Errorf("unknown message type %s", name)
+}
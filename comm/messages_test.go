@@ -0,0 +1,40 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package comm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type MessageTypeTestSuite struct {
+	suite.Suite
+}
+
+func TestRunMessageTypeTestSuite(t *testing.T) {
+	suite.Run(t, new(MessageTypeTestSuite))
+}
+
+func (s *MessageTypeTestSuite) Test_MessageTypeValues_Unchanged() {
+	s.Equal(MessageType(12), CoordinatorPingResponseMsg)
+	s.Equal(MessageType(13), Unknown)
+	s.Equal(MessageType(14), CoordinatorProposalMsg)
+	s.Equal(MessageType(15), CoordinatorAgreementMsg)
+	s.Equal(MessageType(16), CeremonyRecoveryMsg)
+	s.Equal(MessageType(17), TreeBroadcastAckMsg)
+}
+
+func (s *MessageTypeTestSuite) Test_ParseMessageType_AppendedType() {
+	msgType, err := ParseMessageType("CoordinatorProposalMsg")
+
+	s.Nil(err)
+	s.Equal(CoordinatorProposalMsg, msgType)
+}
+
+func (s *MessageTypeTestSuite) Test_ParseMessageType_Unknown() {
+	_, err := ParseMessageType("UnknownMsg")
+
+	s.NotNil(err)
+}
@@ -0,0 +1,180 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package p2p
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	comm "github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	// EnvelopeVersion is the latest supported version of the binary envelope
+	EnvelopeVersion = 1
+	// defaultMaxMessageSize limits the size of received messages if limits are not configured
+	defaultMaxMessageSize = 64 << 20
+)
+
+// protobuf field numbers of the envelope
+const (
+	versionField     protowire.Number = 1
+	messageTypeField protowire.Number = 2
+	sessionIDField   protowire.Number = 3
+	payloadField     protowire.Number = 4
+	timestampField   protowire.Number = 5
+	nonceField       protowire.Number = 6
+	signatureField   protowire.Number = 7
+)
+
+// EnvelopeProtocolID returns the protocol ID used for binary envelopes.
+// Peers that don't support it negotiate the JSON protocol ID instead.
+func EnvelopeProtocolID(protocolID protocol.ID) protocol.ID {
+	return protocolID + "/pb/1.0.0"
+}
+
+// MarshalEnvelope encodes the message as a protobuf envelope:
+//
+//	message Envelope {
+//	  uint32 version = 1;
+//	  uint32 message_type = 2;
+//	  string session_id = 3;
+//	  bytes payload = 4;
+//	  int64 timestamp = 5;
+//	  uint64 nonce = 6;
+//	  bytes signature = 7;
+//	}
+//
+// Payload is written as raw bytes so it is not encoded again as in the JSON format.
+func MarshalEnvelope(msg *comm.WrappedMessage) []byte {
+	b := make([]byte, 0, len(msg.Payload)+len(msg.SessionID)+len(msg.Signature)+40)
+	b = appendMessageFields(b, msg)
+	if len(msg.Signature) > 0 {
+		b = protowire.AppendTag(b, signatureField, protowire.BytesType)
+		b = protowire.AppendBytes(b, msg.Signature)
+	}
+	return b
+}
+
+// signingBytes returns bytes of the message signed by the sender. Protocol ID is
+// included so a message can't be replayed to a different protocol.
+func signingBytes(protocolID protocol.ID, msg *comm.WrappedMessage) []byte {
+	b := make([]byte, 0, len(protocolID)+len(msg.Payload)+len(msg.SessionID)+40)
+	b = protowire.AppendString(b, string(protocolID))
+	return appendMessageFields(b, msg)
+}
+
+func appendMessageFields(b []byte, msg *comm.WrappedMessage) []byte {
+	b = protowire.AppendTag(b, versionField, protowire.VarintType)
+	b = protowire.AppendVarint(b, EnvelopeVersion)
+	b = protowire.AppendTag(b, messageTypeField, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(msg.MessageType))
+	b = protowire.AppendTag(b, sessionIDField, protowire.BytesType)
+	b = protowire.AppendString(b, msg.SessionID)
+	b = protowire.AppendTag(b, payloadField, protowire.BytesType)
+	b = protowire.AppendBytes(b, msg.Payload)
+	if msg.Timestamp != 0 {
+		b = protowire.AppendTag(b, timestampField, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(msg.Timestamp))
+	}
+	if msg.Nonce != 0 {
+		b = protowire.AppendTag(b, nonceField, protowire.VarintType)
+		b = protowire.AppendVarint(b, msg.Nonce)
+	}
+	return b
+}
+
+// UnmarshalEnvelope decodes the protobuf envelope. Unknown fields are skipped
+// while envelopes with a newer version than supported are rejected.
+func UnmarshalEnvelope(b []byte) (*comm.WrappedMessage, error) {
+	msg := &comm.WrappedMessage{}
+	var version uint64
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return nil, fmt.Errorf("invalid envelope tag: %w", protowire.ParseError(n))
+		}
+		b = b[n:]
+
+		switch {
+		case num == versionField && typ == protowire.VarintType:
+			version, n = protowire.ConsumeVarint(b)
+		case num == messageTypeField && typ == protowire.VarintType:
+			var msgType uint64
+			msgType, n = protowire.ConsumeVarint(b)
+			msg.MessageType = comm.MessageType(msgType)
+		case num == sessionIDField && typ == protowire.BytesType:
+			msg.SessionID, n = protowire.ConsumeString(b)
+		case num == payloadField && typ == protowire.BytesType:
+			var payload []byte
+			payload, n = protowire.ConsumeBytes(b)
+			msg.Payload = append([]byte{}, payload...)
+		case num == timestampField && typ == protowire.VarintType:
+			var timestamp uint64
+			timestamp, n = protowire.ConsumeVarint(b)
+			msg.Timestamp = int64(timestamp)
+		case num == nonceField && typ == protowire.VarintType:
+			msg.Nonce, n = protowire.ConsumeVarint(b)
+		case num == signatureField && typ == protowire.BytesType:
+			var signature []byte
+			signature, n = protowire.ConsumeBytes(b)
+			msg.Signature = append([]byte{}, signature...)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return nil, fmt.Errorf("invalid envelope field %d: %w", num, protowire.ParseError(n))
+		}
+		b = b[n:]
+	}
+
+	if version == 0 {
+		return nil, fmt.Errorf("missing envelope version")
+	}
+	if version > EnvelopeVersion {
+		return nil, fmt.Errorf("unsupported envelope version %d", version)
+	}
+	return msg, nil
+}
+
+// ReadEnvelope reads the length prefixed envelope from the stream and returns error
+// if the envelope is larger than max size
+func ReadEnvelope(r *bufio.Reader, maxSize int) ([]byte, error) {
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return []byte{}, err
+	}
+	if size > uint64(maxSize) {
+		return []byte{}, fmt.Errorf("%w %d: envelope size %d", ErrMessageTooLarge, maxSize, size)
+	}
+
+	envelope := make([]byte, size)
+	_, err = io.ReadFull(r, envelope)
+	if err != nil {
+		return []byte{}, err
+	}
+	return envelope, nil
+}
+
+// WriteEnvelope writes the length prefixed envelope to stream
+func WriteEnvelope(envelope []byte, w *bufio.Writer) error {
+	_, err := w.Write(protowire.AppendVarint(nil, uint64(len(envelope))))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(envelope)
+	if err != nil {
+		return err
+	}
+
+	err = w.Flush()
+	if err != nil {
+		return fmt.Errorf("fail to flush stream: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package p2p_test
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+
+	comm "github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/ChainSafe/sygma-relayer/comm/p2p"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+type EnvelopeTestSuite struct {
+	suite.Suite
+}
+
+func TestRunEnvelopeTestSuite(t *testing.T) {
+	suite.Run(t, new(EnvelopeTestSuite))
+}
+
+func (s *EnvelopeTestSuite) Test_MarshalUnmarshal_ValidMessage() {
+	msg := &comm.WrappedMessage{
+		MessageType: comm.TssKeySignMsg,
+		SessionID:   "1",
+		Payload:     []byte("payload"),
+	}
+
+	decodedMsg, err := p2p.UnmarshalEnvelope(p2p.MarshalEnvelope(msg))
+
+	s.Nil(err)
+	s.Equal(msg, decodedMsg)
+}
+
+func (s *EnvelopeTestSuite) Test_Unmarshal_UnknownFieldSkipped() {
+	msg := &comm.WrappedMessage{
+		MessageType: comm.TssKeySignMsg,
+		SessionID:   "1",
+		Payload:     []byte("payload"),
+	}
+	envelope := p2p.MarshalEnvelope(msg)
+	envelope = protowire.AppendTag(envelope, 15, protowire.BytesType)
+	envelope = protowire.AppendBytes(envelope, []byte("unknown"))
+
+	decodedMsg, err := p2p.UnmarshalEnvelope(envelope)
+
+	s.Nil(err)
+	s.Equal(msg, decodedMsg)
+}
+
+func (s *EnvelopeTestSuite) Test_Unmarshal_NewerVersion() {
+	envelope := protowire.AppendTag([]byte{}, 1, protowire.VarintType)
+	envelope = protowire.AppendVarint(envelope, p2p.EnvelopeVersion+1)
+
+	_, err := p2p.UnmarshalEnvelope(envelope)
+
+	s.NotNil(err)
+}
+
+func (s *EnvelopeTestSuite) Test_Unmarshal_MissingVersion() {
+	envelope := protowire.AppendTag([]byte{}, 3, protowire.BytesType)
+	envelope = protowire.AppendString(envelope, "1")
+
+	_, err := p2p.UnmarshalEnvelope(envelope)
+
+	s.NotNil(err)
+}
+
+func (s *EnvelopeTestSuite) Test_Unmarshal_TruncatedEnvelope() {
+	envelope := p2p.MarshalEnvelope(&comm.WrappedMessage{
+		MessageType: comm.TssKeySignMsg,
+		SessionID:   "1",
+		Payload:     []byte("payload"),
+	})
+
+	_, err := p2p.UnmarshalEnvelope(envelope[:len(envelope)-2])
+
+	s.NotNil(err)
+}
+
+func (s *EnvelopeTestSuite) Test_WriteReadEnvelope() {
+	buf := new(bytes.Buffer)
+	w := bufio.NewWriter(buf)
+	err := p2p.WriteEnvelope([]byte("first\n"), w)
+	s.Nil(err)
+	err = p2p.WriteEnvelope([]byte("second"), w)
+	s.Nil(err)
+
+	r := bufio.NewReader(buf)
+	first, err := p2p.ReadEnvelope(r, 1024)
+	s.Nil(err)
+	second, err := p2p.ReadEnvelope(r, 1024)
+	s.Nil(err)
+
+	s.Equal([]byte("first\n"), first)
+	s.Equal([]byte("second"), second)
+}
+
+func (s *EnvelopeTestSuite) Test_ReadEnvelope_SizeLimitExceeded() {
+	buf := new(bytes.Buffer)
+	w := bufio.NewWriter(buf)
+	err := p2p.WriteEnvelope([]byte("envelope"), w)
+	s.Nil(err)
+
+	_, err = p2p.ReadEnvelope(bufio.NewReader(buf), 4)
+
+	s.NotNil(err)
+}
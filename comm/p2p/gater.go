@@ -28,12 +28,18 @@ func (cg *ConnectionGate) SetTopology(topology *topology.NetworkTopology) {
 	cg.topology = topology
 }
 
+// InterceptPeerDial allows dialing topology peers and relays used by topology peers
 func (cg *ConnectionGate) InterceptPeerDial(p peer.ID) (allow bool) {
-	return cg.topology.IsAllowedPeer(p)
+	return cg.topology.IsAllowedPeer(p) || cg.topology.IsAllowedRelay(p)
 }
 
+// InterceptSecured allows connections from topology peers. Relays outside of
+// the topology are allowed only for outbound connections.
 func (cg *ConnectionGate) InterceptSecured(nd network.Direction, p peer.ID, cm network.ConnMultiaddrs) (allow bool) {
-	return cg.topology.IsAllowedPeer(p)
+	if cg.topology.IsAllowedPeer(p) {
+		return true
+	}
+	return nd == network.DirOutbound && cg.topology.IsAllowedRelay(p)
 }
 
 func (cg *ConnectionGate) InterceptAddrDial(peer.ID, ma.Multiaddr) (allow bool) {
@@ -47,3 +53,13 @@ func (cg *ConnectionGate) InterceptAccept(network.ConnMultiaddrs) (allow bool) {
 func (cg *ConnectionGate) InterceptUpgraded(network.Conn) (allow bool, reason control.DisconnectReason) {
 	return true, 0
 }
+
+// AllowReserve implements relay ACLFilter so only topology peers can reserve a relay slot
+func (cg *ConnectionGate) AllowReserve(p peer.ID, a ma.Multiaddr) bool {
+	return cg.topology.IsAllowedPeer(p)
+}
+
+// AllowConnect implements relay ACLFilter so the relay is used only between topology peers
+func (cg *ConnectionGate) AllowConnect(src peer.ID, srcAddr ma.Multiaddr, dest peer.ID) bool {
+	return cg.topology.IsAllowedPeer(src) && cg.topology.IsAllowedPeer(dest)
+}
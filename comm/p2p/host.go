@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/ChainSafe/sygma-relayer/config/relayer"
 	"github.com/ChainSafe/sygma-relayer/topology"
 
 	libp2p "github.com/libp2p/go-libp2p"
@@ -14,12 +15,15 @@ import (
 	"github.com/libp2p/go-libp2p/core/host"
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/libp2p/go-libp2p/core/peerstore"
+	"github.com/libp2p/go-libp2p/p2p/host/autorelay"
+	relayv2 "github.com/libp2p/go-libp2p/p2p/protocol/circuitv2/relay"
 	"github.com/libp2p/go-libp2p/p2p/security/noise"
 	"github.com/rs/zerolog/log"
 )
 
-// NewHost creates new host.Host from private key and relayer configuration
-func NewHost(privKey crypto.PrivKey, networkTopology *topology.NetworkTopology, cg *ConnectionGate, port uint16) (host.Host, error) {
+// NewHost creates new host.Host from private key and relayer configuration.
+// Additional options are applied after the default ones so they can override them.
+func NewHost(privKey crypto.PrivKey, networkTopology *topology.NetworkTopology, cg *ConnectionGate, port uint16, extraOpts ...libp2p.Option) (host.Host, error) {
 	if privKey == nil {
 		return nil, errors.New("unable to create libp2p host: private key not defined")
 	}
@@ -31,6 +35,7 @@ func NewHost(privKey crypto.PrivKey, networkTopology *topology.NetworkTopology,
 		libp2p.Security(noise.ID, noise.New),
 		libp2p.ConnectionGater(cg),
 	}
+	opts = append(opts, extraOpts...)
 
 	h, err := libp2p.New(opts...)
 	if err != nil {
@@ -54,6 +59,45 @@ func LoadPeers(h host.Host, peers []*peer.AddrInfo) {
 
 	for _, p := range peers {
 		log.Debug().Msgf("Adding new peer with ID %s", p.ID)
-		h.Peerstore().AddAddr(p.ID, p.Addrs[0], peerstore.PermanentAddrTTL)
+		h.Peerstore().AddAddrs(p.ID, p.Addrs, peerstore.PermanentAddrTTL)
 	}
 }
+
+// NATTraversalOptions returns host options that allow relayers behind NAT to communicate.
+// Relayer that has relayed addresses in the topology reserves slots on its relays, relay service
+// is provided only to topology peers and relayed connections are upgraded with hole punching.
+func NATTraversalOptions(privKey crypto.PrivKey, config relayer.NATTraversalConfig, networkTopology *topology.NetworkTopology, cg *ConnectionGate) ([]libp2p.Option, error) {
+	opts := make([]libp2p.Option, 0)
+	if !config.EnableRelay && !config.EnableRelayService && !config.EnableHolePunching {
+		return opts, nil
+	}
+
+	hostID, err := peer.IDFromPrivateKey(privKey)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, libp2p.EnableRelay())
+	if config.EnableRelay {
+		relays := networkTopology.Relays(hostID)
+		if len(relays) > 0 {
+			opts = append(
+				opts,
+				libp2p.EnableAutoRelay(autorelay.WithStaticRelays(relays)),
+				libp2p.ForceReachabilityPrivate(),
+			)
+		}
+	}
+	if config.EnableRelayService {
+		opts = append(
+			opts,
+			// relayed connections are not limited since tss messages can be larger than default limits
+			libp2p.EnableRelayService(relayv2.WithLimit(nil), relayv2.WithACL(cg)),
+			libp2p.ForceReachabilityPublic(),
+		)
+	}
+	if config.EnableHolePunching {
+		opts = append(opts, libp2p.EnableHolePunching())
+	}
+	return opts, nil
+}
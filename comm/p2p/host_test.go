@@ -118,3 +118,15 @@ func (s *LoadPeersTestSuite) Test_LoadPeers_RemovesOldAndSetsNewPeers() {
 	s.Equal(peerInSlice(newP2.ID, s.host.Peerstore().Peers()), true)
 	s.Equal(len(s.host.Peerstore().Peers()), 2)
 }
+
+func (s *LoadPeersTestSuite) Test_LoadPeers_AddsAllPeerAddresses() {
+	directAddress := "/dns4/relayer2/tcp/9001/p2p/QmeTuMtdpPB7zKDgmobEwSvxodrf5aFVSmBXX3SQJVjJaT"
+	relayedAddress := "/dns4/relayer3/tcp/9002/p2p/QmYAYuLUPNwYEBYJaKHcE7NKjUhiUV8txx2xDXHvcYa1xK/p2p-circuit/p2p/QmeTuMtdpPB7zKDgmobEwSvxodrf5aFVSmBXX3SQJVjJaT"
+	direct, _ := peer.AddrInfoFromString(directAddress)
+	relayed, _ := peer.AddrInfoFromString(relayedAddress)
+
+	p2p.LoadPeers(s.host, []*peer.AddrInfo{direct, relayed})
+
+	s.Equal(len(s.host.Peerstore().Peers()), 1)
+	s.Equal(len(s.host.Peerstore().Addrs(direct.ID)), 2)
+}
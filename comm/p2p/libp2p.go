@@ -6,15 +6,22 @@ package p2p
 import (
 	"bufio"
 	"context"
+	"crypto/rand"
+	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"sync"
+	"time"
 
 	comm "github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/libp2p/go-libp2p/core/crypto"
 	"github.com/libp2p/go-libp2p/core/host"
 	"github.com/libp2p/go-libp2p/core/network"
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/libp2p/go-libp2p/core/protocol"
-	madns "github.com/multiformats/go-multiaddr-dns"
+	ma "github.com/multiformats/go-multiaddr"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/sourcegraph/conc/pool"
@@ -24,26 +31,53 @@ const (
 	defaultBufferSize = 20480
 )
 
+// CommunicationConfig configures authentication and limits of received messages
+type CommunicationConfig struct {
+	// RequireSignedMessages rejects unsigned messages, it should be enabled once all peers in the network sign messages
+	RequireSignedMessages bool
+	// Limiter limits size and rate of received messages, only the default size limit is applied if nil
+	Limiter *MessageLimiter
+	// Resolver caches resolved peer addresses, addresses are cached for the default TTL if nil
+	Resolver *AddressResolver
+}
+
 type Libp2pCommunication struct {
 	SessionSubscriptionManager
-	h             host.Host
-	protocolID    protocol.ID
-	logger        zerolog.Logger
-	streamManager *StreamManager
+	h                     host.Host
+	protocolID            protocol.ID
+	logger                zerolog.Logger
+	streamManager         *StreamManager
+	requireSignedMessages bool
+	limiter               *MessageLimiter
+	resolver              *AddressResolver
 }
 
-func NewCommunication(h host.Host, protocolID protocol.ID) Libp2pCommunication {
+// NewCommunication creates libp2p communication for the protocol ID. All sent messages are signed
+// with the host key and signed messages are verified.
+func NewCommunication(h host.Host, protocolID protocol.ID, config CommunicationConfig) Libp2pCommunication {
 	logger := log.With().Str("Module", "communication").Str("Peer", h.ID().Pretty()).Logger()
+	limiter := config.Limiter
+	if limiter == nil {
+		limiter = defaultMessageLimiter()
+	}
+	resolver := config.Resolver
+	if resolver == nil {
+		resolver = defaultAddressResolver()
+	}
 	c := Libp2pCommunication{
 		SessionSubscriptionManager: NewSessionSubscriptionManager(),
 		h:                          h,
 		protocolID:                 protocolID,
 		logger:                     logger,
 		streamManager:              NewStreamManager(),
+		requireSignedMessages:      config.RequireSignedMessages,
+		limiter:                    limiter,
+		resolver:                   resolver,
 	}
 
-	// start processing incoming messages
+	// start processing incoming messages, JSON protocol is kept for peers that don't support envelopes
 	c.h.SetStreamHandler(c.protocolID, c.StreamHandlerFunc)
+	c.h.SetStreamHandler(EnvelopeProtocolID(c.protocolID), c.EnvelopeStreamHandlerFunc)
 	return c
 }
 
@@ -66,11 +100,11 @@ func (c Libp2pCommunication) Broadcast(
 		Payload:     msg,
 		From:        hostID,
 	}
-	marshaledMsg, err := json.Marshal(wMsg)
+	err := stampMessage(&wMsg)
 	if err != nil {
-		c.logger.Error().Err(err).Str("SessionID", sessionID).Msg("unable to marshal message")
 		return err
 	}
+	encodedMsg := newEncodedMessage(c.protocolID, &wMsg)
 	c.logger.Debug().Str("MsgType", msgType.String()).Str("SessionID", sessionID).Msg(
 		"broadcasting message",
 	)
@@ -83,7 +117,7 @@ func (c Libp2pCommunication) Broadcast(
 
 		peerID := peerID
 		p.Go(func() error {
-			err := c.sendMessage(peerID, marshaledMsg, msgType, sessionID)
+			err := c.sendMessage(peerID, encodedMsg, msgType, sessionID)
 			if err != nil {
 				return &comm.CommunicationError{
 					Peer: peerID,
@@ -134,12 +168,25 @@ func (c Libp2pCommunication) StreamHandlerFunc(s network.Stream) {
 	c.ProcessMessagesFromStream(s)
 }
 
+// EnvelopeStreamHandlerFunc processes incoming streams of the envelope protocol
+func (c Libp2pCommunication) EnvelopeStreamHandlerFunc(s network.Stream) {
+	defer func() {
+		err := s.Close()
+		if err != nil {
+			log.Warn().Msgf("Error closing incoming stream because of: %s", err.Error())
+		}
+	}()
+	c.ProcessEnvelopesFromStream(s)
+}
+
+// ProcessMessagesFromStream processes newline delimited JSON messages from the stream
 func (c Libp2pCommunication) ProcessMessagesFromStream(s network.Stream) {
 	remotePeerID := s.Conn().RemotePeer()
 	r := bufio.NewReader(s)
 	for {
-		msgBytes, err := ReadStream(r)
+		msgBytes, err := ReadStream(r, c.limiter.MaxReadSize())
 		if err != nil {
+			c.logReadError(remotePeerID, err)
 			return
 		}
 
@@ -149,47 +196,125 @@ func (c Libp2pCommunication) ProcessMessagesFromStream(s network.Stream) {
 			return
 		}
 		wrappedMsg.From = remotePeerID
+		c.processMessage(s, &wrappedMsg, len(msgBytes))
+	}
+}
+
+// ProcessEnvelopesFromStream processes length prefixed binary envelopes from the stream
+func (c Libp2pCommunication) ProcessEnvelopesFromStream(s network.Stream) {
+	remotePeerID := s.Conn().RemotePeer()
+	r := bufio.NewReader(s)
+	for {
+		envelope, err := ReadEnvelope(r, c.limiter.MaxReadSize())
+		if err != nil {
+			c.logReadError(remotePeerID, err)
+			return
+		}
 
-		c.logger.Trace().Str(
-			"From", wrappedMsg.From.String()).Str(
-			"MsgType", wrappedMsg.MessageType.String()).Str(
-			"SessionID", wrappedMsg.SessionID).Msg(
-			"processed message",
-		)
-
-		subscribers := c.GetSubscribers(wrappedMsg.SessionID, wrappedMsg.MessageType)
-		for _, sub := range subscribers {
-			sub := sub
-			go func() {
-				sub <- &wrappedMsg
-			}()
+		wrappedMsg, err := UnmarshalEnvelope(envelope)
+		if err != nil {
+			log.Err(err).Msg("Error unmarshaling envelope")
+			return
 		}
+		wrappedMsg.From = remotePeerID
+		c.processMessage(s, wrappedMsg, len(envelope))
 	}
 }
 
-func (c Libp2pCommunication) sendMessage(
-	to peer.ID,
-	msg []byte,
-	msgType comm.MessageType,
-	sessionID string,
-) error {
-	err := c.resolveDNS(to)
+// logReadError logs errors other than the stream being closed and tracks messages over the size limit
+func (c Libp2pCommunication) logReadError(remotePeerID peer.ID, err error) {
+	if errors.Is(err, io.EOF) || errors.Is(err, network.ErrReset) {
+		return
+	}
+
+	c.logger.Warn().Err(err).Str("From", remotePeerID.String()).Msg("failed reading stream")
+	if errors.Is(err, ErrMessageTooLarge) {
+		c.limiter.Drop(comm.Unknown, SizeLimitDrop)
+	}
+}
+
+func (c Libp2pCommunication) processMessage(s network.Stream, wrappedMsg *comm.WrappedMessage, size int) {
+	logger := c.logger.With().Str(
+		"From", wrappedMsg.From.String()).Str(
+		"MsgType", wrappedMsg.MessageType.String()).Str(
+		"SessionID", wrappedMsg.SessionID).Logger()
+
+	if size > c.limiter.MaxSize(wrappedMsg.MessageType) {
+		logger.Warn().Msgf("rejected message of size %d over the limit", size)
+		c.limiter.Drop(wrappedMsg.MessageType, SizeLimitDrop)
+		return
+	}
+
+	if !c.limiter.Allow(wrappedMsg.From) {
+		logger.Warn().Msg("rejected message over the peer rate limit")
+		c.limiter.Drop(wrappedMsg.MessageType, RateLimitDrop)
+		return
+	}
+
+	err := c.verifyMessage(s, wrappedMsg)
 	if err != nil {
-		return err
+		logger.Warn().Err(err).Msg("rejected message")
+		c.limiter.Drop(wrappedMsg.MessageType, VerificationDrop)
+		return
 	}
 
-	var stream network.Stream
-	stream, err = c.streamManager.Stream(sessionID, to)
+	logger.Trace().Msg("processed message")
+
+	c.limiter.Deliver(wrappedMsg, func() []chan *comm.WrappedMessage {
+		return c.GetSubscribers(wrappedMsg.SessionID, wrappedMsg.MessageType)
+	})
+}
+
+// stampMessage sets the timestamp and a random nonce of the message
+func stampMessage(msg *comm.WrappedMessage) error {
+	nonce := make([]byte, 8)
+	_, err := rand.Read(nonce)
 	if err != nil {
-		// try to open the stream again if it failed the first time
-		stream, err = c.h.NewStream(context.TODO(), to, c.protocolID)
-		if err != nil {
-			return err
+		return err
+	}
+	msg.Nonce = binary.BigEndian.Uint64(nonce)
+	msg.Timestamp = time.Now().UnixMilli()
+	return nil
+}
+
+// verifyMessage checks the signature of the message against the key the sender used to
+// secure the connection and rejects replayed messages
+func (c Libp2pCommunication) verifyMessage(s network.Stream, msg *comm.WrappedMessage) error {
+	if len(msg.Signature) == 0 {
+		if c.requireSignedMessages {
+			return fmt.Errorf("unsigned message")
 		}
-		c.streamManager.AddStream(sessionID, to, stream)
+		return nil
+	}
+
+	return c.verifySignature(c.protocolID, s.Conn().RemotePublicKey(), msg)
+}
+
+// verifySignature checks the message was signed by the key for the protocol and is not replayed
+func (c Libp2pCommunication) verifySignature(protocolID protocol.ID, pubKey crypto.PubKey, msg *comm.WrappedMessage) error {
+	if pubKey == nil {
+		return fmt.Errorf("missing public key of peer %s", msg.From)
+	}
+	valid, err := pubKey.Verify(signingBytes(protocolID, msg), msg.Signature)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return fmt.Errorf("invalid message signature")
 	}
 
-	err = WriteStream(msg, bufio.NewWriterSize(stream, defaultBufferSize))
+	return c.CheckReplay(msg.From, time.UnixMilli(msg.Timestamp), msg.Nonce)
+}
+
+func (c Libp2pCommunication) sendMessage(
+	to peer.ID,
+	msg *encodedMessage,
+	msgType comm.MessageType,
+	sessionID string,
+) error {
+	err := c.send(c.streamManager, to, sessionID, func(stream *PooledStream) error {
+		return c.writeMessage(stream, msg)
+	}, EnvelopeProtocolID(c.protocolID), c.protocolID)
 	if err != nil {
 		c.logger.Error().Str("To", to.String()).Err(err).Msg("unable to send message")
 		return err
@@ -203,27 +328,157 @@ func (c Libp2pCommunication) sendMessage(
 	return nil
 }
 
-func (c Libp2pCommunication) resolveDNS(peerID peer.ID) error {
-	pi := c.h.Peerstore().PeerInfo(peerID)
-	resolver, err := madns.NewResolver()
+// send writes to the pooled stream of the session opened with one of the protocols
+func (c Libp2pCommunication) send(
+	streamManager *StreamManager,
+	to peer.ID,
+	sessionID string,
+	write func(stream *PooledStream) error,
+	protocols ...protocol.ID,
+) error {
+	err := c.connect(to)
 	if err != nil {
 		return err
 	}
-	if len(pi.Addrs) == 0 {
-		return fmt.Errorf("peer %s has no defined addresses", peerID.Pretty())
+
+	stream, err := c.stream(streamManager, to, sessionID, protocols...)
+	if err != nil {
+		return err
+	}
+	err = write(stream)
+	if err != nil {
+		// pooled stream could have been closed by the peer so the message is sent again on a new stream
+		streamManager.RemoveStream(to, stream)
+		stream, err = c.stream(streamManager, to, sessionID, protocols...)
+		if err != nil {
+			return err
+		}
+		err = write(stream)
+	}
+	return err
+}
+
+// stream returns the pooled stream of the session or opens a new stream to the peer
+func (c Libp2pCommunication) stream(streamManager *StreamManager, to peer.ID, sessionID string, protocols ...protocol.ID) (*PooledStream, error) {
+	stream, err := streamManager.Stream(sessionID, to)
+	if err == nil {
+		return stream, nil
+	}
+
+	// transient connections are allowed so peers reachable only through a relay receive messages
+	ctx := network.WithUseTransient(context.TODO(), "relayed peer")
+	newStream, err := c.h.NewStream(ctx, to, protocols...)
+	if err != nil {
+		return nil, err
+	}
+	stream = streamManager.AddStream(sessionID, to, newStream)
+	if stream.Stream != newStream {
+		// other message of the session opened the stream concurrently
+		_ = newStream.Close()
 	}
+	return stream, nil
+}
 
-	addr, err := resolver.Resolve(context.Background(), pi.Addrs[0])
+func (c Libp2pCommunication) writeMessage(stream *PooledStream, msg *encodedMessage) error {
+	// host key is not kept in the peerstore so the key that secures the connection is used
+	err := msg.sign(stream.Conn().LocalPrivateKey())
 	if err != nil {
 		return err
 	}
-	err = c.h.Connect(context.TODO(), peer.AddrInfo{
+
+	return stream.WriteMessage(func(w *bufio.Writer) error {
+		if stream.Protocol() == EnvelopeProtocolID(c.protocolID) {
+			return WriteEnvelope(msg.envelope(), w)
+		}
+
+		jsonMsg, err := msg.json()
+		if err != nil {
+			return err
+		}
+		return WriteStream(jsonMsg, w)
+	})
+}
+
+// connect connects to the peer if there is no open connection. Peers can have multiple direct
+// and relayed addresses so all of them are resolved and the host dials them until one succeeds.
+func (c Libp2pCommunication) connect(peerID peer.ID) error {
+	if c.h.Network().Connectedness(peerID) == network.Connected {
+		return nil
+	}
+
+	pi := c.h.Peerstore().PeerInfo(peerID)
+	if len(pi.Addrs) == 0 {
+		return fmt.Errorf("peer %s has no defined addresses", peerID.Pretty())
+	}
+
+	addrs := make([]ma.Multiaddr, 0, len(pi.Addrs))
+	for _, a := range pi.Addrs {
+		resolved, err := c.resolver.Resolve(context.Background(), a)
+		if err != nil {
+			c.logger.Debug().Err(err).Msgf("unable to resolve address %s of peer %s", a, peerID.Pretty())
+			continue
+		}
+		addrs = append(addrs, resolved...)
+	}
+	if len(addrs) == 0 {
+		return fmt.Errorf("unable to resolve any address of peer %s", peerID.Pretty())
+	}
+
+	err := c.h.Connect(context.TODO(), peer.AddrInfo{
 		ID:    peerID,
-		Addrs: addr,
+		Addrs: addrs,
 	})
 	if err != nil {
+		// peer could have changed its IP so addresses are resolved again on the next attempt
+		c.resolver.Invalidate(pi.Addrs)
 		return err
 	}
 
 	return nil
 }
+
+// encodedMessage lazily signs and encodes the message in formats negotiated with peers
+// so the message is signed and each format is encoded at most once per broadcast
+type encodedMessage struct {
+	protocolID protocol.ID
+	msg        *comm.WrappedMessage
+
+	signOnce sync.Once
+	signErr  error
+
+	envelopeOnce  sync.Once
+	envelopeBytes []byte
+
+	jsonOnce  sync.Once
+	jsonBytes []byte
+	jsonErr   error
+}
+
+func newEncodedMessage(protocolID protocol.ID, msg *comm.WrappedMessage) *encodedMessage {
+	return &encodedMessage{protocolID: protocolID, msg: msg}
+}
+
+func (m *encodedMessage) sign(privKey crypto.PrivKey) error {
+	m.signOnce.Do(func() {
+		if privKey == nil {
+			m.signErr = fmt.Errorf("missing private key")
+			return
+		}
+		m.msg.Signature, m.signErr = privKey.Sign(signingBytes(m.protocolID, m.msg))
+	})
+	return m.signErr
+}
+
+func (m *encodedMessage) envelope() []byte {
+	m.envelopeOnce.Do(func() {
+		m.envelopeBytes = MarshalEnvelope(m.msg)
+	})
+	return m.envelopeBytes
+}
+
+func (m *encodedMessage) json() ([]byte, error) {
+	m.jsonOnce.Do(func() {
+		m.jsonBytes, m.jsonErr = json.Marshal(m.msg)
+	})
+	return m.jsonBytes, m.jsonErr
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	comm "github.com/ChainSafe/sygma-relayer/comm"
 	"github.com/ChainSafe/sygma-relayer/comm/p2p"
@@ -47,7 +48,8 @@ func (s *Libp2pCommunicationTestSuite) SetupTest() {
 func (s *Libp2pCommunicationTestSuite) TestLibp2pCommunication_MessageProcessing_ValidMessage() {
 	s.mockHost.EXPECT().ID().Return(s.allowedPeers[0])
 	s.mockHost.EXPECT().SetStreamHandler(s.testProtocolID, gomock.Any()).Return()
-	c := p2p.NewCommunication(s.mockHost, s.testProtocolID)
+	s.mockHost.EXPECT().SetStreamHandler(p2p.EnvelopeProtocolID(s.testProtocolID), gomock.Any()).Return()
+	c := p2p.NewCommunication(s.mockHost, s.testProtocolID, p2p.CommunicationConfig{})
 
 	msgChannel := make(chan *comm.WrappedMessage)
 	c.Subscribe("1", comm.CoordinatorPingMsg, msgChannel)
@@ -84,10 +86,51 @@ func (s *Libp2pCommunicationTestSuite) TestLibp2pCommunication_MessageProcessing
 	s.Nil(msg.Payload)
 }
 
+func (s *Libp2pCommunicationTestSuite) TestLibp2pCommunication_MessageProcessing_UnsignedMessageRejected() {
+	s.mockHost.EXPECT().ID().Return(s.allowedPeers[0])
+	s.mockHost.EXPECT().SetStreamHandler(s.testProtocolID, gomock.Any()).Return()
+	s.mockHost.EXPECT().SetStreamHandler(p2p.EnvelopeProtocolID(s.testProtocolID), gomock.Any()).Return()
+	c := p2p.NewCommunication(s.mockHost, s.testProtocolID, p2p.CommunicationConfig{RequireSignedMessages: true})
+
+	msgChannel := make(chan *comm.WrappedMessage)
+	c.Subscribe("1", comm.CoordinatorPingMsg, msgChannel)
+
+	testWrappedMsg := comm.WrappedMessage{
+		MessageType: comm.CoordinatorPingMsg,
+		SessionID:   "1",
+		Payload:     nil,
+	}
+	bytes, _ := json.Marshal(testWrappedMsg)
+
+	mockStream := mock_network.NewMockStream(s.mockController)
+	mockConn := mock_network.NewMockConn(s.mockController)
+	mockConn.EXPECT().RemotePeer().Return(s.allowedPeers[0])
+	mockStream.EXPECT().Conn().Return(mockConn)
+
+	firstCall := mockStream.EXPECT().Read(gomock.Any()).DoAndReturn(func(p []byte) (n int, err error) {
+		copy(p[:], []byte(fmt.Sprintf("%s \n", string(bytes[:]))))
+		return len(bytes), nil
+	})
+	secondCall := mockStream.EXPECT().Read(gomock.Any()).DoAndReturn(func(p []byte) (n int, err error) {
+		copy(p[:], []byte("\n"))
+		return len(bytes), nil
+	})
+	gomock.InOrder(firstCall, secondCall)
+
+	c.ProcessMessagesFromStream(mockStream)
+
+	select {
+	case <-msgChannel:
+		s.Fail("unsigned message processed")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
 func (s *Libp2pCommunicationTestSuite) TestLibp2pCommunication_StreamHandlerFunction_ValidMessageWithSubscribers() {
 	s.mockHost.EXPECT().ID().Return(s.allowedPeers[0])
 	s.mockHost.EXPECT().SetStreamHandler(s.testProtocolID, gomock.Any()).Return()
-	c := p2p.NewCommunication(s.mockHost, s.testProtocolID)
+	s.mockHost.EXPECT().SetStreamHandler(p2p.EnvelopeProtocolID(s.testProtocolID), gomock.Any()).Return()
+	c := p2p.NewCommunication(s.mockHost, s.testProtocolID, p2p.CommunicationConfig{})
 
 	testWrappedMsg := comm.WrappedMessage{
 		MessageType: comm.CoordinatorPingMsg,
@@ -165,7 +208,7 @@ func (s *Libp2pCommunicationTestSuite) TestLibp2pCommunication_SendReceiveMessag
 		connectionGate := p2p.NewConnectionGate(topology)
 		newHost, _ := p2p.NewHost(privateKeys[i], topology, connectionGate, uint16(4000+portOffset+i))
 		testHosts = append(testHosts, newHost)
-		communications = append(communications, p2p.NewCommunication(newHost, protocol.ID(protocolID)))
+		communications = append(communications, p2p.NewCommunication(newHost, protocol.ID(protocolID), p2p.CommunicationConfig{RequireSignedMessages: true}))
 	}
 
 	msgChn := make(chan *comm.WrappedMessage)
@@ -181,16 +224,130 @@ func (s *Libp2pCommunicationTestSuite) TestLibp2pCommunication_SendReceiveMessag
 	s.Nil(err)
 	largeMsg := <-msgChn
 
-	s.Equal(pingMsg, &comm.WrappedMessage{
+	s.NotEmpty(pingMsg.Signature)
+	s.Equal(unsignedMessage(pingMsg), &comm.WrappedMessage{
 		MessageType: comm.CoordinatorPingMsg,
 		SessionID:   "1",
 		Payload:     []byte{},
 		From:        testHosts[0].ID(),
 	})
-	s.Equal(largeMsg, &comm.WrappedMessage{
+	s.NotEmpty(largeMsg.Signature)
+	s.Equal(unsignedMessage(largeMsg), &comm.WrappedMessage{
 		MessageType: comm.TssKeySignMsg,
 		SessionID:   "2",
 		Payload:     msgBytes,
 		From:        testHosts[0].ID(),
 	})
 }
+
+func (s *Libp2pCommunicationTestSuite) TestLibp2pCommunication_SendReceiveMessage_JSONFallback() {
+	var testHosts []host.Host
+	var communications []p2p.Libp2pCommunication
+	numberOfTestHosts := 2
+	portOffset := 10
+	protocolID := "/p2p/test"
+
+	topology := &topology.NetworkTopology{
+		Peers: []*peer.AddrInfo{},
+	}
+
+	privateKeys := []crypto.PrivKey{}
+	for i := 0; i < numberOfTestHosts; i++ {
+		privKeyForHost, _, _ := crypto.GenerateKeyPair(crypto.ECDSA, 1)
+		privateKeys = append(privateKeys, privKeyForHost)
+		peerID, _ := peer.IDFromPrivateKey(privKeyForHost)
+		addrInfoForHost, _ := peer.AddrInfoFromString(fmt.Sprintf(
+			"/ip4/127.0.0.1/tcp/%d/p2p/%s", 4000+portOffset+i, peerID.Pretty(),
+		))
+		topology.Peers = append(topology.Peers, addrInfoForHost)
+	}
+
+	for i := 0; i < numberOfTestHosts; i++ {
+		connectionGate := p2p.NewConnectionGate(topology)
+		newHost, _ := p2p.NewHost(privateKeys[i], topology, connectionGate, uint16(4000+portOffset+i))
+		testHosts = append(testHosts, newHost)
+		communications = append(communications, p2p.NewCommunication(newHost, protocol.ID(protocolID), p2p.CommunicationConfig{RequireSignedMessages: true}))
+	}
+	// simulate peer running the version without envelope support
+	testHosts[1].RemoveStreamHandler(p2p.EnvelopeProtocolID(protocol.ID(protocolID)))
+
+	msgChn := make(chan *comm.WrappedMessage)
+	communications[1].SubscribeTo("1", comm.TssKeySignMsg, msgChn)
+
+	msgBytes, _ := message.MarshalTssMessage([]byte("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"), true)
+	err := communications[0].Broadcast([]peer.ID{testHosts[1].ID()}, msgBytes, comm.TssKeySignMsg, "1")
+	s.Nil(err)
+	msg := <-msgChn
+
+	s.NotEmpty(msg.Signature)
+	s.Equal(unsignedMessage(msg), &comm.WrappedMessage{
+		MessageType: comm.TssKeySignMsg,
+		SessionID:   "1",
+		Payload:     msgBytes,
+		From:        testHosts[0].ID(),
+	})
+}
+
+func (s *Libp2pCommunicationTestSuite) TestLibp2pCommunication_SendReceiveMessage_StreamReusedAcrossSessions() {
+	var testHosts []host.Host
+	var communications []p2p.Libp2pCommunication
+	numberOfTestHosts := 2
+	portOffset := 20
+	protocolID := "/p2p/test"
+
+	topology := &topology.NetworkTopology{
+		Peers: []*peer.AddrInfo{},
+	}
+
+	privateKeys := []crypto.PrivKey{}
+	for i := 0; i < numberOfTestHosts; i++ {
+		privKeyForHost, _, _ := crypto.GenerateKeyPair(crypto.ECDSA, 1)
+		privateKeys = append(privateKeys, privKeyForHost)
+		peerID, _ := peer.IDFromPrivateKey(privKeyForHost)
+		addrInfoForHost, _ := peer.AddrInfoFromString(fmt.Sprintf(
+			"/ip4/127.0.0.1/tcp/%d/p2p/%s", 4000+portOffset+i, peerID.Pretty(),
+		))
+		topology.Peers = append(topology.Peers, addrInfoForHost)
+	}
+
+	for i := 0; i < numberOfTestHosts; i++ {
+		connectionGate := p2p.NewConnectionGate(topology)
+		newHost, _ := p2p.NewHost(privateKeys[i], topology, connectionGate, uint16(4000+portOffset+i))
+		testHosts = append(testHosts, newHost)
+		communications = append(communications, p2p.NewCommunication(newHost, protocol.ID(protocolID), p2p.CommunicationConfig{RequireSignedMessages: true}))
+	}
+
+	msgChn := make(chan *comm.WrappedMessage)
+	communications[1].SubscribeTo("1", comm.CoordinatorPingMsg, msgChn)
+	communications[1].SubscribeTo("2", comm.CoordinatorPingMsg, msgChn)
+
+	err := communications[0].Broadcast([]peer.ID{testHosts[1].ID()}, []byte{}, comm.CoordinatorPingMsg, "1")
+	s.Nil(err)
+	msg1 := <-msgChn
+	communications[0].CloseSession("1")
+
+	err = communications[0].Broadcast([]peer.ID{testHosts[1].ID()}, []byte{}, comm.CoordinatorPingMsg, "2")
+	s.Nil(err)
+	msg2 := <-msgChn
+
+	s.Equal("1", msg1.SessionID)
+	s.Equal("2", msg2.SessionID)
+	envelopeStreams := 0
+	for _, conn := range testHosts[0].Network().ConnsToPeer(testHosts[1].ID()) {
+		for _, stream := range conn.GetStreams() {
+			if stream.Protocol() == p2p.EnvelopeProtocolID(protocol.ID(protocolID)) {
+				envelopeStreams++
+			}
+		}
+	}
+	s.Equal(1, envelopeStreams)
+}
+
+// unsignedMessage returns copy of the message without signature fields
+func unsignedMessage(msg *comm.WrappedMessage) *comm.WrappedMessage {
+	unsignedMsg := *msg
+	unsignedMsg.Timestamp = 0
+	unsignedMsg.Nonce = 0
+	unsignedMsg.Signature = nil
+	return &unsignedMsg
+}
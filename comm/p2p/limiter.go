@@ -0,0 +1,242 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package p2p
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	comm "github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/ChainSafe/sygma-relayer/config/relayer"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+const (
+	defaultMaxPendingMessages = 100
+	defaultDeliveryTimeout    = time.Minute
+)
+
+// SubscriptionWaitTime is how long messages received before the session subscribed to them wait
+// for subscribers, as peers can send session messages before the session starts on the host
+var SubscriptionWaitTime = 5 * time.Second
+
+var subscriptionPollInterval = 50 * time.Millisecond
+
+// reasons for dropping received messages
+const (
+	SizeLimitDrop       = "size"
+	RateLimitDrop       = "rate"
+	DeliveryTimeoutDrop = "timeout"
+	PendingLimitDrop    = "pending"
+	VerificationDrop    = "verification"
+)
+
+type DroppedMessageMeter interface {
+	TrackDroppedMessage(msgType comm.MessageType, reason string)
+}
+
+type tokenBucket struct {
+	tokens     float64
+	lastRefill time.Time
+}
+
+type peerLimits struct {
+	bucket tokenBucket
+}
+
+type pendingKey struct {
+	peerID    peer.ID
+	sessionID string
+}
+
+// MessageLimiter limits the size and rate of messages received from peers. Only peers allowed
+// by the ConnectionGate can open streams, so state is kept for topology peers only.
+// Single limiter should be shared by all communications of the host so the rate is limited per peer.
+type MessageLimiter struct {
+	maxSize            int
+	maxSizes           map[comm.MessageType]int
+	rate               float64
+	burst              float64
+	maxPendingMessages int
+	deliveryTimeout    time.Duration
+	metrics            DroppedMessageMeter
+
+	lock  sync.Mutex
+	peers map[peer.ID]*peerLimits
+	// pending counts messages of a peer session waiting for subscribers
+	pending map[pendingKey]int
+}
+
+func defaultMessageLimiter() *MessageLimiter {
+	return &MessageLimiter{
+		maxSize:            defaultMaxMessageSize,
+		maxSizes:           make(map[comm.MessageType]int),
+		maxPendingMessages: defaultMaxPendingMessages,
+		deliveryTimeout:    defaultDeliveryTimeout,
+		peers:              make(map[peer.ID]*peerLimits),
+		pending:            make(map[pendingKey]int),
+	}
+}
+
+func NewMessageLimiter(config relayer.MessageLimitsConfig, metrics DroppedMessageMeter) (*MessageLimiter, error) {
+	maxSizes := make(map[comm.MessageType]int)
+	for name, size := range config.MaxMessageSizes {
+		msgType, err := comm.ParseMessageType(name)
+		if err != nil {
+			return nil, err
+		}
+		maxSizes[msgType] = size
+	}
+
+	return &MessageLimiter{
+		maxSize:            config.MaxMessageSize,
+		maxSizes:           maxSizes,
+		rate:               config.RateLimit,
+		burst:              float64(config.RateBurst),
+		maxPendingMessages: config.MaxPendingMessages,
+		deliveryTimeout:    config.DeliveryTimeout,
+		metrics:            metrics,
+		peers:              make(map[peer.ID]*peerLimits),
+		pending:            make(map[pendingKey]int),
+	}, nil
+}
+
+// MaxSize returns the maximal size of the encoded message of the message type
+func (l *MessageLimiter) MaxSize(msgType comm.MessageType) int {
+	size, ok := l.maxSizes[msgType]
+	if !ok {
+		return l.maxSize
+	}
+	return size
+}
+
+// MaxReadSize returns the maximal size of any encoded message which is read from the stream
+// before the message type is known
+func (l *MessageLimiter) MaxReadSize() int {
+	maxSize := l.maxSize
+	for _, size := range l.maxSizes {
+		if size > maxSize {
+			maxSize = size
+		}
+	}
+	return maxSize
+}
+
+// Allow takes a token from the peer bucket and returns false if the peer exceeded its rate.
+// Rate is not limited if it is not positive.
+func (l *MessageLimiter) Allow(peerID peer.ID) bool {
+	if l.rate <= 0 {
+		return true
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	limits := l.peerLimits(peerID)
+	now := time.Now()
+	limits.bucket.tokens += now.Sub(limits.bucket.lastRefill).Seconds() * l.rate
+	if limits.bucket.tokens > l.burst {
+		limits.bucket.tokens = l.burst
+	}
+	limits.bucket.lastRefill = now
+
+	if limits.bucket.tokens < 1 {
+		return false
+	}
+	limits.bucket.tokens--
+	return true
+}
+
+// Deliver sends the message to subscribers in the background. Messages without subscribers
+// wait for subscribers up to the subscription wait time. Messages are dropped if the peer
+// has max pending messages for the session, so a stalled session doesn't block the stream reader
+// or other sessions. Messages not received by subscribers in the delivery timeout are dropped.
+func (l *MessageLimiter) Deliver(msg *comm.WrappedMessage, subscribers func() []chan *comm.WrappedMessage) {
+	key := pendingKey{peerID: msg.From, sessionID: msg.SessionID}
+	l.lock.Lock()
+	if l.pending[key] >= l.maxPendingMessages {
+		l.lock.Unlock()
+		l.Drop(msg.MessageType, PendingLimitDrop)
+		return
+	}
+	l.pending[key]++
+	l.lock.Unlock()
+
+	go func() {
+		defer l.delivered(key)
+
+		subs := awaitSubscribers(subscribers)
+		if len(subs) == 0 {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), l.deliveryTimeout)
+		defer cancel()
+		// subscribers receive the message independently so a slow subscriber doesn't block others
+		wg := sync.WaitGroup{}
+		for _, sub := range subs {
+			sub := sub
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				select {
+				case sub <- msg:
+				case <-ctx.Done():
+					l.Drop(msg.MessageType, DeliveryTimeoutDrop)
+				}
+			}()
+		}
+		wg.Wait()
+	}()
+}
+
+// awaitSubscribers returns subscribers once there are any or no subscribers after the subscription wait time
+func awaitSubscribers(subscribers func() []chan *comm.WrappedMessage) []chan *comm.WrappedMessage {
+	timeout := time.After(SubscriptionWaitTime)
+	for {
+		subs := subscribers()
+		if len(subs) > 0 {
+			return subs
+		}
+
+		select {
+		case <-timeout:
+			return subs
+		case <-time.After(subscriptionPollInterval):
+		}
+	}
+}
+
+func (l *MessageLimiter) delivered(key pendingKey) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.pending[key]--
+	if l.pending[key] <= 0 {
+		delete(l.pending, key)
+	}
+}
+
+// Drop tracks the dropped message
+func (l *MessageLimiter) Drop(msgType comm.MessageType, reason string) {
+	if l.metrics != nil {
+		l.metrics.TrackDroppedMessage(msgType, reason)
+	}
+}
+
+// peerLimits returns limits of the peer and creates them if missing, it expects the lock to be held
+func (l *MessageLimiter) peerLimits(peerID peer.ID) *peerLimits {
+	limits, ok := l.peers[peerID]
+	if !ok {
+		limits = &peerLimits{
+			bucket: tokenBucket{
+				tokens:     l.burst,
+				lastRefill: time.Now(),
+			},
+		}
+		l.peers[peerID] = limits
+	}
+	return limits
+}
@@ -0,0 +1,206 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package p2p_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	comm "github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/ChainSafe/sygma-relayer/comm/p2p"
+	"github.com/ChainSafe/sygma-relayer/config/relayer"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/suite"
+)
+
+type testDropMeter struct {
+	lock    sync.Mutex
+	dropped map[string]int
+}
+
+func (m *testDropMeter) TrackDroppedMessage(msgType comm.MessageType, reason string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.dropped[reason]++
+}
+
+func (m *testDropMeter) Dropped(reason string) int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.dropped[reason]
+}
+
+type MessageLimiterTestSuite struct {
+	suite.Suite
+	meter  *testDropMeter
+	config relayer.MessageLimitsConfig
+	peerID peer.ID
+}
+
+func TestRunMessageLimiterTestSuite(t *testing.T) {
+	suite.Run(t, new(MessageLimiterTestSuite))
+}
+
+func (s *MessageLimiterTestSuite) SetupTest() {
+	s.meter = &testDropMeter{dropped: make(map[string]int)}
+	s.config = relayer.MessageLimitsConfig{
+		MaxMessageSize: 100,
+		MaxMessageSizes: map[string]int{
+			"TssKeySignMsg": 1000,
+		},
+		RateLimit:          0.001,
+		RateBurst:          2,
+		MaxPendingMessages: 1,
+		DeliveryTimeout:    time.Millisecond * 100,
+	}
+	s.peerID, _ = peer.Decode("QmZHPnN3CKiTAp8VaJqszbf8m7v4mPh15M421KpVdYHF54")
+}
+
+func (s *MessageLimiterTestSuite) Test_NewMessageLimiter_InvalidMessageType() {
+	s.config.MaxMessageSizes["Invalid"] = 10
+
+	_, err := p2p.NewMessageLimiter(s.config, s.meter)
+
+	s.NotNil(err)
+}
+
+func (s *MessageLimiterTestSuite) Test_MaxSize() {
+	limiter, err := p2p.NewMessageLimiter(s.config, s.meter)
+	s.Nil(err)
+
+	s.Equal(1000, limiter.MaxSize(comm.TssKeySignMsg))
+	s.Equal(100, limiter.MaxSize(comm.CoordinatorPingMsg))
+	s.Equal(1000, limiter.MaxReadSize())
+}
+
+func (s *MessageLimiterTestSuite) Test_Allow_RateExceeded() {
+	limiter, err := p2p.NewMessageLimiter(s.config, s.meter)
+	s.Nil(err)
+
+	s.True(limiter.Allow(s.peerID))
+	s.True(limiter.Allow(s.peerID))
+	s.False(limiter.Allow(s.peerID))
+	s.True(limiter.Allow(peer.ID("other")))
+}
+
+func (s *MessageLimiterTestSuite) Test_Allow_RateNotLimited() {
+	s.config.RateLimit = 0
+	limiter, err := p2p.NewMessageLimiter(s.config, s.meter)
+	s.Nil(err)
+
+	for i := 0; i < 10; i++ {
+		s.True(limiter.Allow(s.peerID))
+	}
+}
+
+func subscribers(subs ...chan *comm.WrappedMessage) func() []chan *comm.WrappedMessage {
+	return func() []chan *comm.WrappedMessage {
+		return subs
+	}
+}
+
+func (s *MessageLimiterTestSuite) Test_Deliver_DeliveredToAllSubscribers() {
+	limiter, err := p2p.NewMessageLimiter(s.config, s.meter)
+	s.Nil(err)
+	msg := &comm.WrappedMessage{MessageType: comm.TssKeySignMsg, From: s.peerID}
+	firstSub := make(chan *comm.WrappedMessage)
+	secondSub := make(chan *comm.WrappedMessage)
+
+	limiter.Deliver(msg, subscribers(firstSub, secondSub))
+
+	s.Equal(msg, <-firstSub)
+	s.Equal(msg, <-secondSub)
+}
+
+func (s *MessageLimiterTestSuite) Test_Deliver_SlowSubscriberDoesNotBlockOthers() {
+	limiter, err := p2p.NewMessageLimiter(s.config, s.meter)
+	s.Nil(err)
+	msg := &comm.WrappedMessage{MessageType: comm.TssKeySignMsg, From: s.peerID}
+	firstSub := make(chan *comm.WrappedMessage)
+	secondSub := make(chan *comm.WrappedMessage)
+
+	limiter.Deliver(msg, subscribers(firstSub, secondSub))
+
+	s.Equal(msg, <-secondSub)
+	s.Equal(msg, <-firstSub)
+}
+
+func (s *MessageLimiterTestSuite) Test_Deliver_DroppedAfterTimeout() {
+	limiter, err := p2p.NewMessageLimiter(s.config, s.meter)
+	s.Nil(err)
+	msg := &comm.WrappedMessage{MessageType: comm.TssKeySignMsg, SessionID: "1", From: s.peerID}
+	sub := make(chan *comm.WrappedMessage)
+
+	limiter.Deliver(msg, subscribers(sub))
+
+	s.Eventually(func() bool {
+		return s.meter.Dropped(p2p.DeliveryTimeoutDrop) == 1
+	}, time.Second, time.Millisecond)
+}
+
+func (s *MessageLimiterTestSuite) Test_Deliver_DroppedWhenSessionPendingFull() {
+	limiter, err := p2p.NewMessageLimiter(s.config, s.meter)
+	s.Nil(err)
+	msg := &comm.WrappedMessage{MessageType: comm.TssKeySignMsg, SessionID: "1", From: s.peerID}
+	sub := make(chan *comm.WrappedMessage)
+
+	limiter.Deliver(msg, subscribers(sub))
+	// dropped without blocking as only one message of the session can be pending
+	limiter.Deliver(msg, subscribers(sub))
+
+	s.Equal(1, s.meter.Dropped(p2p.PendingLimitDrop))
+	s.Equal(msg, <-sub)
+}
+
+func (s *MessageLimiterTestSuite) Test_Deliver_OtherSessionNotBlocked() {
+	limiter, err := p2p.NewMessageLimiter(s.config, s.meter)
+	s.Nil(err)
+	stalledMsg := &comm.WrappedMessage{MessageType: comm.TssKeySignMsg, SessionID: "1", From: s.peerID}
+	msg := &comm.WrappedMessage{MessageType: comm.TssKeySignMsg, SessionID: "2", From: s.peerID}
+	stalledSub := make(chan *comm.WrappedMessage)
+	sub := make(chan *comm.WrappedMessage)
+
+	limiter.Deliver(stalledMsg, subscribers(stalledSub))
+	limiter.Deliver(msg, subscribers(sub))
+
+	s.Equal(msg, <-sub)
+	s.Equal(0, s.meter.Dropped(p2p.PendingLimitDrop))
+}
+
+func (s *MessageLimiterTestSuite) Test_Deliver_DeliveredToLaterSubscriber() {
+	limiter, err := p2p.NewMessageLimiter(s.config, s.meter)
+	s.Nil(err)
+	msg := &comm.WrappedMessage{MessageType: comm.TssKeySignMsg, SessionID: "1", From: s.peerID}
+	sub := make(chan *comm.WrappedMessage)
+	subscribed := make(chan struct{})
+
+	limiter.Deliver(msg, func() []chan *comm.WrappedMessage {
+		select {
+		case <-subscribed:
+			return []chan *comm.WrappedMessage{sub}
+		default:
+			return []chan *comm.WrappedMessage{}
+		}
+	})
+	close(subscribed)
+
+	s.Equal(msg, <-sub)
+}
+
+func (s *MessageLimiterTestSuite) Test_Deliver_PendingReleasedWithoutSubscribers() {
+	p2p.SubscriptionWaitTime = time.Millisecond * 100
+	defer func() { p2p.SubscriptionWaitTime = time.Second * 5 }()
+	limiter, err := p2p.NewMessageLimiter(s.config, s.meter)
+	s.Nil(err)
+	msg := &comm.WrappedMessage{MessageType: comm.TssKeySignMsg, SessionID: "1", From: s.peerID}
+	sub := make(chan *comm.WrappedMessage)
+
+	limiter.Deliver(msg, subscribers())
+	time.Sleep(time.Millisecond * 300)
+	limiter.Deliver(msg, subscribers(sub))
+
+	s.Equal(msg, <-sub)
+	s.Equal(0, s.meter.Dropped(p2p.PendingLimitDrop))
+}
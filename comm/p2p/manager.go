@@ -4,6 +4,7 @@
 package p2p
 
 import (
+	"bufio"
 	"fmt"
 	"sync"
 
@@ -12,69 +13,141 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-// StreamManager manages instances of network.Stream
+const defaultMaxStreamsPerPeer = 4
+
+// PooledStream is a stream shared by multiple sessions. Messages contain the session ID
+// so the receiver demultiplexes them to session subscribers.
+type PooledStream struct {
+	network.Stream
+	writeLock sync.Mutex
+	sessions  int
+}
+
+// WriteMessage serializes writes of concurrent sessions so messages are not interleaved
+func (s *PooledStream) WriteMessage(write func(w *bufio.Writer) error) error {
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+
+	return write(bufio.NewWriterSize(s.Stream, defaultBufferSize))
+}
+
+// StreamManager manages a pool of streams for each peer
 //
-// Each stream is mapped to a specific session, by sessionID
+// Session is pinned to a single stream of the peer until it is released
+// so messages of the session are received in the order they were sent.
+// Streams are reused by later sessions and closed only when they fail.
 type StreamManager struct {
-	streamsBySessionID map[string]map[peer.ID]network.Stream
-	streamLocker       *sync.Mutex
+	maxStreamsPerPeer int
+	streamsByPeerID   map[peer.ID][]*PooledStream
+	streamsBySession  map[string]map[peer.ID]*PooledStream
+	streamLocker      *sync.Mutex
 }
 
 // NewStreamManager creates new StreamManager
 func NewStreamManager() *StreamManager {
 	return &StreamManager{
-		streamsBySessionID: make(map[string]map[peer.ID]network.Stream),
-		streamLocker:       &sync.Mutex{},
+		maxStreamsPerPeer: defaultMaxStreamsPerPeer,
+		streamsByPeerID:   make(map[peer.ID][]*PooledStream),
+		streamsBySession:  make(map[string]map[peer.ID]*PooledStream),
+		streamLocker:      &sync.Mutex{},
 	}
 }
 
-// ReleaseStream removes reference on streams mapped to provided sessionID and closes them
+// ReleaseStreams unpins streams from the session so they can be reused by other sessions
 func (sm *StreamManager) ReleaseStreams(sessionID string) {
 	sm.streamLocker.Lock()
 	defer sm.streamLocker.Unlock()
 
-	streams, ok := sm.streamsBySessionID[sessionID]
+	streams, ok := sm.streamsBySession[sessionID]
 	if !ok {
 		return
 	}
 
-	for peer, stream := range streams {
-		err := stream.Close()
-		if err != nil {
-			log.Err(err).Msgf("Cannot close stream to peer %s", peer.Pretty())
-		}
+	for _, stream := range streams {
+		stream.sessions--
 	}
-
-	delete(sm.streamsBySessionID, sessionID)
+	delete(sm.streamsBySession, sessionID)
 }
 
-// AddStream saves and maps provided stream to sessionID
-func (sm *StreamManager) AddStream(sessionID string, peerID peer.ID, stream network.Stream) {
+// AddStream adds the stream to the peer pool and pins it to the session.
+// Returns the stream already pinned to the session if it exists, in which case the
+// provided stream is not added and should be closed by the caller.
+func (sm *StreamManager) AddStream(sessionID string, peerID peer.ID, stream network.Stream) *PooledStream {
 	sm.streamLocker.Lock()
 	defer sm.streamLocker.Unlock()
 
-	_, ok := sm.streamsBySessionID[sessionID]
-	if !ok {
-		sm.streamsBySessionID[sessionID] = make(map[peer.ID]network.Stream)
+	pinned, ok := sm.streamsBySession[sessionID][peerID]
+	if ok {
+		return pinned
 	}
 
-	_, ok = sm.streamsBySessionID[sessionID][peerID]
+	pooledStream := &PooledStream{Stream: stream}
+	sm.streamsByPeerID[peerID] = append(sm.streamsByPeerID[peerID], pooledStream)
+	sm.pin(sessionID, peerID, pooledStream)
+	return pooledStream
+}
+
+// Stream fetches the stream pinned to the session or pins the least used stream of the peer.
+// Returns error if the session should open a new stream because all pooled
+// streams are used by other sessions and the pool is not full.
+func (sm *StreamManager) Stream(sessionID string, peerID peer.ID) (*PooledStream, error) {
+	sm.streamLocker.Lock()
+	defer sm.streamLocker.Unlock()
+
+	pinned, ok := sm.streamsBySession[sessionID][peerID]
 	if ok {
-		return
+		return pinned, nil
 	}
 
-	sm.streamsBySessionID[sessionID][peerID] = stream
+	var leastUsed *PooledStream
+	for _, stream := range sm.streamsByPeerID[peerID] {
+		if leastUsed == nil || stream.sessions < leastUsed.sessions {
+			leastUsed = stream
+		}
+	}
+	if leastUsed == nil || (leastUsed.sessions > 0 && len(sm.streamsByPeerID[peerID]) < sm.maxStreamsPerPeer) {
+		return nil, fmt.Errorf("no stream for peerID %s", peerID)
+	}
+
+	sm.pin(sessionID, peerID, leastUsed)
+	return leastUsed, nil
 }
 
-// Stream fetches stream by peer and session ID
-func (sm *StreamManager) Stream(sessionID string, peerID peer.ID) (network.Stream, error) {
+// RemoveStream resets the failed stream and removes it from the pool and all sessions
+func (sm *StreamManager) RemoveStream(peerID peer.ID, stream *PooledStream) {
 	sm.streamLocker.Lock()
 	defer sm.streamLocker.Unlock()
 
-	stream, ok := sm.streamsBySessionID[sessionID][peerID]
+	streams := sm.streamsByPeerID[peerID]
+	for i, s := range streams {
+		if s == stream {
+			sm.streamsByPeerID[peerID] = append(streams[:i:i], streams[i+1:]...)
+			break
+		}
+	}
+	if len(sm.streamsByPeerID[peerID]) == 0 {
+		delete(sm.streamsByPeerID, peerID)
+	}
+
+	for _, streams := range sm.streamsBySession {
+		if streams[peerID] == stream {
+			delete(streams, peerID)
+		}
+	}
+
+	err := stream.Reset()
+	if err != nil {
+		log.Err(err).Msgf("Cannot reset stream to peer %s", peerID.Pretty())
+	}
+}
+
+// pin maps the stream to the session, it expects the lock to be held
+func (sm *StreamManager) pin(sessionID string, peerID peer.ID, stream *PooledStream) {
+	_, ok := sm.streamsBySession[sessionID]
 	if !ok {
-		return nil, fmt.Errorf("no stream for peerID %s", peerID)
+		sm.streamsBySession[sessionID] = make(map[peer.ID]*PooledStream)
 	}
 
-	return stream, nil
+	sm.streamsBySession[sessionID][peerID] = stream
+	stream.sessions++
 }
@@ -4,6 +4,7 @@
 package p2p_test
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/ChainSafe/sygma-relayer/comm/p2p"
@@ -27,25 +28,25 @@ func (s *StreamManagerTestSuite) SetupTest() {
 	s.mockController = gomock.NewController(s.T())
 }
 
-func (s *StreamManagerTestSuite) Test_ManagingSubscriptions_Success() {
+func (s *StreamManagerTestSuite) Test_ReleaseStreams_KeepsStreamsForReuse() {
 	streamManager := p2p.NewStreamManager()
 
 	stream1 := mock_network.NewMockStream(s.mockController)
 	stream2 := mock_network.NewMockStream(s.mockController)
-	stream3 := mock_network.NewMockStream(s.mockController)
 
 	peerID1, _ := peer.Decode("QmcW3oMdSqoEcjbyd51auqC23vhKX6BqfcZcY2HJ3sKAZR")
 	peerID2, _ := peer.Decode("QmZHPnN3CKiTAp8VaJqszbf8m7v4mPh15M421KpVdYHF54")
 
-	streamManager.AddStream("1", peerID1, stream1)
 	streamManager.AddStream("1", peerID1, stream1)
 	streamManager.AddStream("1", peerID2, stream2)
-	streamManager.AddStream("2", peerID1, stream3)
-
-	stream1.EXPECT().Close().Times(1).Return(nil)
-	stream2.EXPECT().Close().Times(1).Return(nil)
-
 	streamManager.ReleaseStreams("1")
+
+	reusedStream, err := streamManager.Stream("2", peerID1)
+	s.Nil(err)
+	s.Equal(stream1, reusedStream.Stream)
+	reusedStream, err = streamManager.Stream("2", peerID2)
+	s.Nil(err)
+	s.Equal(stream2, reusedStream.Stream)
 }
 
 func (s *StreamManagerTestSuite) Test_FetchStream_NoStream() {
@@ -66,20 +67,69 @@ func (s *StreamManagerTestSuite) Test_FetchStream_ValidStream() {
 	expectedStream, err := streamManager.Stream("1", peerID1)
 
 	s.Nil(err)
-	s.Equal(stream, expectedStream)
+	s.Equal(stream, expectedStream.Stream)
+}
+
+func (s *StreamManagerTestSuite) Test_FetchStream_UsedStreamsWithFreePoolSlots() {
+	streamManager := p2p.NewStreamManager()
+
+	stream := mock_network.NewMockStream(s.mockController)
+	peerID1, _ := peer.Decode("QmcW3oMdSqoEcjbyd51auqC23vhKX6BqfcZcY2HJ3sKAZR")
+	streamManager.AddStream("1", peerID1, stream)
+
+	_, err := streamManager.Stream("2", peerID1)
+
+	s.NotNil(err)
+}
+
+func (s *StreamManagerTestSuite) Test_FetchStream_SharesLeastUsedStreamWhenPoolIsFull() {
+	streamManager := p2p.NewStreamManager()
+
+	peerID1, _ := peer.Decode("QmcW3oMdSqoEcjbyd51auqC23vhKX6BqfcZcY2HJ3sKAZR")
+	streams := make([]*mock_network.MockStream, 4)
+	for i := range streams {
+		streams[i] = mock_network.NewMockStream(s.mockController)
+		streamManager.AddStream(fmt.Sprint(i), peerID1, streams[i])
+	}
+	streamManager.ReleaseStreams("2")
+
+	sharedStream, err := streamManager.Stream("4", peerID1)
+	s.Nil(err)
+	s.Equal(streams[2], sharedStream.Stream)
+
+	sharedStream, err = streamManager.Stream("5", peerID1)
+	s.Nil(err)
+	s.Equal(streams[0], sharedStream.Stream)
 }
 
-func (s *StreamManagerTestSuite) Test_AddStream_IgnoresExistingPeer() {
+func (s *StreamManagerTestSuite) Test_AddStream_ReturnsPinnedStream() {
 	streamManager := p2p.NewStreamManager()
 
 	stream1 := mock_network.NewMockStream(s.mockController)
 	stream2 := mock_network.NewMockStream(s.mockController)
 	peerID1, _ := peer.Decode("QmcW3oMdSqoEcjbyd51auqC23vhKX6BqfcZcY2HJ3sKAZR")
 	streamManager.AddStream("1", peerID1, stream1)
-	streamManager.AddStream("1", peerID1, stream2)
+	pinnedStream := streamManager.AddStream("1", peerID1, stream2)
 
 	expectedStream, err := streamManager.Stream("1", peerID1)
 
 	s.Nil(err)
-	s.Equal(stream1, expectedStream)
+	s.Equal(stream1, pinnedStream.Stream)
+	s.Equal(stream1, expectedStream.Stream)
+}
+
+func (s *StreamManagerTestSuite) Test_RemoveStream_ResetsAndRemovesStream() {
+	streamManager := p2p.NewStreamManager()
+
+	stream := mock_network.NewMockStream(s.mockController)
+	peerID1, _ := peer.Decode("QmcW3oMdSqoEcjbyd51auqC23vhKX6BqfcZcY2HJ3sKAZR")
+	pooledStream := streamManager.AddStream("1", peerID1, stream)
+	stream.EXPECT().Reset().Times(1).Return(nil)
+
+	streamManager.RemoveStream(peerID1, pooledStream)
+
+	_, err := streamManager.Stream("1", peerID1)
+	s.NotNil(err)
+	_, err = streamManager.Stream("2", peerID1)
+	s.NotNil(err)
 }
@@ -0,0 +1,84 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package p2p
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	ma "github.com/multiformats/go-multiaddr"
+	madns "github.com/multiformats/go-multiaddr-dns"
+)
+
+const defaultDNSCacheTTL = 5 * time.Minute
+
+type Resolver interface {
+	Resolve(ctx context.Context, addr ma.Multiaddr) ([]ma.Multiaddr, error)
+}
+
+type resolvedAddrs struct {
+	addrs   []ma.Multiaddr
+	expires time.Time
+}
+
+// AddressResolver caches resolved peer addresses so DNS is not queried on every connection.
+// Single resolver should be shared by all communications of the host.
+type AddressResolver struct {
+	resolver Resolver
+	ttl      time.Duration
+
+	lock  sync.Mutex
+	cache map[string]resolvedAddrs
+}
+
+func NewAddressResolver(resolver Resolver, ttl time.Duration) *AddressResolver {
+	return &AddressResolver{
+		resolver: resolver,
+		ttl:      ttl,
+		cache:    make(map[string]resolvedAddrs),
+	}
+}
+
+func defaultAddressResolver() *AddressResolver {
+	return NewAddressResolver(madns.DefaultResolver, defaultDNSCacheTTL)
+}
+
+// Resolve returns cached addresses of the multiaddress or resolves them if the cache expired.
+// Addresses without DNS components are returned as they are.
+func (r *AddressResolver) Resolve(ctx context.Context, addr ma.Multiaddr) ([]ma.Multiaddr, error) {
+	if !madns.Matches(addr) {
+		return []ma.Multiaddr{addr}, nil
+	}
+
+	r.lock.Lock()
+	cached, ok := r.cache[addr.String()]
+	r.lock.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.addrs, nil
+	}
+
+	addrs, err := r.resolver.Resolve(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.cache[addr.String()] = resolvedAddrs{
+		addrs:   addrs,
+		expires: time.Now().Add(r.ttl),
+	}
+	return addrs, nil
+}
+
+// Invalidate removes cached addresses so they are resolved again on the next connection
+func (r *AddressResolver) Invalidate(addrs []ma.Multiaddr) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for _, addr := range addrs {
+		delete(r.cache, addr.String())
+	}
+}
@@ -0,0 +1,105 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package p2p_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/sygma-relayer/comm/p2p"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/stretchr/testify/suite"
+)
+
+type testResolver struct {
+	calls int
+	err   error
+}
+
+func (r *testResolver) Resolve(ctx context.Context, addr ma.Multiaddr) ([]ma.Multiaddr, error) {
+	r.calls++
+	if r.err != nil {
+		return nil, r.err
+	}
+	return []ma.Multiaddr{ma.StringCast(fmt.Sprintf("/ip4/10.0.0.%d/tcp/9000", r.calls))}, nil
+}
+
+type AddressResolverTestSuite struct {
+	suite.Suite
+	dnsAddr ma.Multiaddr
+}
+
+func TestRunAddressResolverTestSuite(t *testing.T) {
+	suite.Run(t, new(AddressResolverTestSuite))
+}
+
+func (s *AddressResolverTestSuite) SetupTest() {
+	s.dnsAddr = ma.StringCast("/dns4/relayer1/tcp/9000")
+}
+
+func (s *AddressResolverTestSuite) Test_Resolve_CachesResolvedAddresses() {
+	dnsResolver := &testResolver{}
+	resolver := p2p.NewAddressResolver(dnsResolver, time.Minute)
+
+	addrs1, err := resolver.Resolve(context.Background(), s.dnsAddr)
+	s.Nil(err)
+	addrs2, err := resolver.Resolve(context.Background(), s.dnsAddr)
+	s.Nil(err)
+
+	s.Equal(1, dnsResolver.calls)
+	s.Equal(addrs1, addrs2)
+}
+
+func (s *AddressResolverTestSuite) Test_Resolve_ExpiredCache() {
+	dnsResolver := &testResolver{}
+	resolver := p2p.NewAddressResolver(dnsResolver, time.Millisecond)
+
+	_, err := resolver.Resolve(context.Background(), s.dnsAddr)
+	s.Nil(err)
+	time.Sleep(time.Millisecond * 2)
+	addrs, err := resolver.Resolve(context.Background(), s.dnsAddr)
+	s.Nil(err)
+
+	s.Equal(2, dnsResolver.calls)
+	s.Equal("/ip4/10.0.0.2/tcp/9000", addrs[0].String())
+}
+
+func (s *AddressResolverTestSuite) Test_Resolve_InvalidatedCache() {
+	dnsResolver := &testResolver{}
+	resolver := p2p.NewAddressResolver(dnsResolver, time.Minute)
+
+	_, err := resolver.Resolve(context.Background(), s.dnsAddr)
+	s.Nil(err)
+	resolver.Invalidate([]ma.Multiaddr{s.dnsAddr})
+	_, err = resolver.Resolve(context.Background(), s.dnsAddr)
+	s.Nil(err)
+
+	s.Equal(2, dnsResolver.calls)
+}
+
+func (s *AddressResolverTestSuite) Test_Resolve_IPAddressNotResolved() {
+	dnsResolver := &testResolver{}
+	resolver := p2p.NewAddressResolver(dnsResolver, time.Minute)
+	addr := ma.StringCast("/ip4/127.0.0.1/tcp/9000")
+
+	addrs, err := resolver.Resolve(context.Background(), addr)
+
+	s.Nil(err)
+	s.Equal([]ma.Multiaddr{addr}, addrs)
+	s.Equal(0, dnsResolver.calls)
+}
+
+func (s *AddressResolverTestSuite) Test_Resolve_ErrorNotCached() {
+	dnsResolver := &testResolver{err: fmt.Errorf("error")}
+	resolver := p2p.NewAddressResolver(dnsResolver, time.Minute)
+
+	_, err := resolver.Resolve(context.Background(), s.dnsAddr)
+	s.NotNil(err)
+	_, err = resolver.Resolve(context.Background(), s.dnsAddr)
+	s.NotNil(err)
+
+	s.Equal(2, dnsResolver.calls)
+}
@@ -4,16 +4,49 @@
 package p2p
 
 import (
+	"fmt"
 	"sync"
+	"time"
 
 	comm "github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/libp2p/go-libp2p/core/peer"
 )
 
+// MaxMessageAge is the maximal allowed difference between the message timestamp and
+// the local time. Nonces are cached for this period so replayed messages are rejected.
+const MaxMessageAge = 5 * time.Minute
+
+type messageNonce struct {
+	from  peer.ID
+	nonce uint64
+}
+
+type replayCache struct {
+	// nonces of received messages mapped to the time they can be removed from the cache
+	nonces    map[messageNonce]time.Time
+	nextPrune time.Time
+}
+
+// prune removes expired nonces at most once per MaxMessageAge
+func (c *replayCache) prune(now time.Time) {
+	if now.Before(c.nextPrune) {
+		return
+	}
+
+	for key, expiry := range c.nonces {
+		if now.After(expiry) {
+			delete(c.nonces, key)
+		}
+	}
+	c.nextPrune = now.Add(MaxMessageAge)
+}
+
 // SessionSubscriptionManager manages channel subscriptions by comm.SessionID
 type SessionSubscriptionManager struct {
 	lock *sync.Mutex
 	// sessionID -> messageType -> subscriptionID
 	subscribersMap map[string]map[comm.MessageType]map[string]chan *comm.WrappedMessage
+	replayCache    *replayCache
 }
 
 func NewSessionSubscriptionManager() SessionSubscriptionManager {
@@ -22,7 +55,30 @@ func NewSessionSubscriptionManager() SessionSubscriptionManager {
 		subscribersMap: make(
 			map[string]map[comm.MessageType]map[string]chan *comm.WrappedMessage,
 		),
+		replayCache: &replayCache{
+			nonces: make(map[messageNonce]time.Time),
+		},
+	}
+}
+
+// CheckReplay returns error if the message timestamp is not within MaxMessageAge of the local
+// time or if a message with the same nonce was already received from the peer.
+func (ms *SessionSubscriptionManager) CheckReplay(from peer.ID, timestamp time.Time, nonce uint64) error {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+
+	now := time.Now()
+	if timestamp.Before(now.Add(-MaxMessageAge)) || timestamp.After(now.Add(MaxMessageAge)) {
+		return fmt.Errorf("message timestamp %s outside of allowed window", timestamp)
+	}
+
+	ms.replayCache.prune(now)
+	key := messageNonce{from: from, nonce: nonce}
+	if _, ok := ms.replayCache.nonces[key]; ok {
+		return fmt.Errorf("message with nonce %d from %s already received", nonce, from)
 	}
+	ms.replayCache.nonces[key] = timestamp.Add(MaxMessageAge)
+	return nil
 }
 
 func (ms *SessionSubscriptionManager) GetSubscribers(
@@ -4,8 +4,11 @@
 package p2p_test
 
 import (
-	"github.com/ChainSafe/sygma-relayer/comm/p2p"
 	"testing"
+	"time"
+
+	"github.com/ChainSafe/sygma-relayer/comm/p2p"
+	"github.com/libp2p/go-libp2p/core/peer"
 
 	comm "github.com/ChainSafe/sygma-relayer/comm"
 	"github.com/stretchr/testify/suite"
@@ -66,3 +69,32 @@ func (s *SessionSubscriptionManagerTestSuite) TestSessionSubscriptionManager_Man
 	subscribers = subscriptionManager.GetSubscribers("2", comm.CoordinatorPingMsg)
 	s.Len(subscribers, 0)
 }
+
+func (s *SessionSubscriptionManagerTestSuite) TestSessionSubscriptionManager_CheckReplay_FreshMessages() {
+	subscriptionManager := p2p.NewSessionSubscriptionManager()
+
+	err := subscriptionManager.CheckReplay(peer.ID("QmZHPnN3CKiTAp8VaJqszbf8m7v4mPh15M421KpVdYHF54"), time.Now(), 1)
+	s.Nil(err)
+	err = subscriptionManager.CheckReplay(peer.ID("QmZHPnN3CKiTAp8VaJqszbf8m7v4mPh15M421KpVdYHF54"), time.Now(), 2)
+	s.Nil(err)
+	err = subscriptionManager.CheckReplay(peer.ID("QmeWhpY8tknHS29gzf9TAsNEwfejTCNJ7vFpmkV6rNUgyq"), time.Now(), 1)
+	s.Nil(err)
+}
+
+func (s *SessionSubscriptionManagerTestSuite) TestSessionSubscriptionManager_CheckReplay_ReplayedNonce() {
+	subscriptionManager := p2p.NewSessionSubscriptionManager()
+
+	err := subscriptionManager.CheckReplay(peer.ID("QmZHPnN3CKiTAp8VaJqszbf8m7v4mPh15M421KpVdYHF54"), time.Now(), 1)
+	s.Nil(err)
+	err = subscriptionManager.CheckReplay(peer.ID("QmZHPnN3CKiTAp8VaJqszbf8m7v4mPh15M421KpVdYHF54"), time.Now(), 1)
+	s.NotNil(err)
+}
+
+func (s *SessionSubscriptionManagerTestSuite) TestSessionSubscriptionManager_CheckReplay_ExpiredTimestamp() {
+	subscriptionManager := p2p.NewSessionSubscriptionManager()
+
+	err := subscriptionManager.CheckReplay(peer.ID("QmZHPnN3CKiTAp8VaJqszbf8m7v4mPh15M421KpVdYHF54"), time.Now().Add(-p2p.MaxMessageAge-time.Minute), 1)
+	s.NotNil(err)
+	err = subscriptionManager.CheckReplay(peer.ID("QmZHPnN3CKiTAp8VaJqszbf8m7v4mPh15M421KpVdYHF54"), time.Now().Add(p2p.MaxMessageAge+time.Minute), 2)
+	s.NotNil(err)
+}
@@ -0,0 +1,488 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package p2p
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	comm "github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"github.com/sourcegraph/conc/pool"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// TreeBroadcastAckTimeout is the time the sender waits for recipients to acknowledge the message
+// before sending it directly to recipients that didn't acknowledge it
+var TreeBroadcastAckTimeout = time.Second * 5
+
+// maxTreeFrameOverhead is the maximal size of the tree broadcast frame without the message envelope
+const maxTreeFrameOverhead = 32 << 10
+
+// protobuf field numbers of the tree broadcast frame
+const (
+	treeEnvelopeField  protowire.Number = 1
+	treeOriginKeyField protowire.Number = 2
+	treeRecipientField protowire.Number = 3
+	treeForwardField   protowire.Number = 4
+)
+
+// TreeBroadcastProtocolID returns the protocol ID used for tree broadcast frames
+func TreeBroadcastProtocolID(protocolID protocol.ID) protocol.ID {
+	return protocolID + "/tree-broadcast/1.0.0"
+}
+
+// treeBroadcastMessageTypes are message types that can be sent through the tree broadcast. These are
+// tss messages and session broadcasts of the coordinator. Coordinator election messages are always
+// sent directly so the election doesn't depend on forwarding peers.
+var treeBroadcastMessageTypes = map[comm.MessageType]bool{
+	comm.TssKeyGenMsg:   true,
+	comm.TssKeySignMsg:  true,
+	comm.TssReshareMsg:  true,
+	comm.TssInitiateMsg: true,
+	comm.TssStartMsg:    true,
+	comm.TssFailMsg:     true,
+}
+
+// TreeBroadcastCommunication broadcasts messages to large peer sets through a tree of peers.
+// It is a custom forwarding protocol on top of libp2p streams and not a libp2p pubsub (GossipSub) router.
+// Sender sends the message to fanout peers and each of them forwards it to its part of the
+// remaining recipients, so a peer sends at most fanout messages per broadcast. Messages to at most
+// fanout recipients, like tss point-to-point messages, are sent directly with the Libp2pCommunication.
+//
+// The sender signs the message together with the full recipient set, so forwarding peers can't modify
+// the message or forward it to other peers. Recipients acknowledge the message directly to the sender,
+// which sends the message directly to recipients that didn't acknowledge it in the ack timeout,
+// so a forwarding peer can't silently drop its part of recipients. All peers need to use the tree broadcast.
+type TreeBroadcastCommunication struct {
+	Libp2pCommunication
+	treeProtocolID protocol.ID
+	treeStreams    *StreamManager
+	fanout         int
+}
+
+func NewTreeBroadcastCommunication(h host.Host, protocolID protocol.ID, config CommunicationConfig, fanout int) TreeBroadcastCommunication {
+	c := TreeBroadcastCommunication{
+		Libp2pCommunication: NewCommunication(h, protocolID, config),
+		treeProtocolID:      TreeBroadcastProtocolID(protocolID),
+		treeStreams:         NewStreamManager(),
+		fanout:              fanout,
+	}
+
+	c.h.SetStreamHandler(c.treeProtocolID, c.TreeStreamHandlerFunc)
+	return c
+}
+
+func (c TreeBroadcastCommunication) CloseSession(sessionID string) {
+	c.Libp2pCommunication.CloseSession(sessionID)
+	c.treeStreams.ReleaseStreams(sessionID)
+}
+
+func (c TreeBroadcastCommunication) Broadcast(
+	peers peer.IDSlice,
+	msg []byte,
+	msgType comm.MessageType,
+	sessionID string,
+) error {
+	hostID := c.h.ID()
+	recipients := make(peer.IDSlice, 0, len(peers))
+	for _, p := range peers {
+		if p != hostID {
+			recipients = append(recipients, p)
+		}
+	}
+	if !treeBroadcastMessageTypes[msgType] || len(recipients) <= c.fanout {
+		return c.Libp2pCommunication.Broadcast(recipients, msg, msgType, sessionID)
+	}
+
+	wMsg := comm.WrappedMessage{
+		MessageType: msgType,
+		SessionID:   sessionID,
+		Payload:     msg,
+		From:        hostID,
+	}
+	err := stampMessage(&wMsg)
+	if err != nil {
+		return err
+	}
+	c.logger.Debug().Str("MsgType", msgType.String()).Str("SessionID", sessionID).Msg(
+		"tree broadcasting message",
+	)
+
+	acks := make(chan *comm.WrappedMessage, len(recipients))
+	subID := c.Subscribe(sessionID, comm.TreeBroadcastAckMsg, acks)
+	defer c.UnSubscribe(subID)
+
+	// forwarding load is spread between peers across broadcasts
+	forwardTo := append(peer.IDSlice{}, recipients...)
+	rand.Shuffle(len(forwardTo), func(i, j int) {
+		forwardTo[i], forwardTo[j] = forwardTo[j], forwardTo[i]
+	})
+	err = c.forward(newTreeMessage(c.treeProtocolID, &wMsg, recipients), forwardTo)
+	if err != nil {
+		c.logger.Warn().Err(err).Str("SessionID", sessionID).Msg("unable to tree broadcast message to all peers")
+	}
+
+	missing := awaitAcks(acks, wMsg.Nonce, recipients)
+	if len(missing) == 0 {
+		return nil
+	}
+	c.logger.Warn().Str("SessionID", sessionID).Msgf("%d peers didn't acknowledge the message, sending it directly", len(missing))
+	return c.Libp2pCommunication.Broadcast(missing, msg, msgType, sessionID)
+}
+
+// forward splits peers between fanout peers which forward the message to the rest of their part.
+// If a peer is unreachable, the message is forwarded to its part of peers instead.
+func (c TreeBroadcastCommunication) forward(msg *treeMessage, forwardTo peer.IDSlice) error {
+	p := pool.New().WithErrors().WithFirstError()
+	for _, part := range splitRecipients(forwardTo, c.fanout) {
+		part := part
+		p.Go(func() error {
+			err := c.sendFrame(part[0], msg, part[1:])
+			if err == nil {
+				return nil
+			}
+
+			c.logger.Warn().Err(err).Str("To", part[0].Pretty()).Msg("unable to send tree broadcast message, forwarding to its peers")
+			if len(part) > 1 {
+				_ = c.forward(msg, part[1:])
+			}
+			return &comm.CommunicationError{
+				Peer: part[0],
+				Err:  err,
+			}
+		})
+	}
+
+	return p.Wait()
+}
+
+func (c TreeBroadcastCommunication) sendFrame(to peer.ID, msg *treeMessage, forwardTo peer.IDSlice) error {
+	return c.send(c.treeStreams, to, msg.msg.SessionID, func(stream *PooledStream) error {
+		// host key is not kept in the peerstore so the key that secures the connection is used
+		err := msg.sign(stream.Conn().LocalPrivateKey())
+		if err != nil {
+			return err
+		}
+
+		return stream.WriteMessage(func(w *bufio.Writer) error {
+			return WriteEnvelope(msg.frame(forwardTo), w)
+		})
+	}, c.treeProtocolID)
+}
+
+// TreeStreamHandlerFunc processes incoming streams of the tree broadcast protocol
+func (c TreeBroadcastCommunication) TreeStreamHandlerFunc(s network.Stream) {
+	defer func() {
+		err := s.Close()
+		if err != nil {
+			c.logger.Warn().Msgf("Error closing incoming stream because of: %s", err.Error())
+		}
+	}()
+	c.ProcessTreeFramesFromStream(s)
+}
+
+// ProcessTreeFramesFromStream processes length prefixed tree broadcast frames from the stream
+func (c TreeBroadcastCommunication) ProcessTreeFramesFromStream(s network.Stream) {
+	remotePeerID := s.Conn().RemotePeer()
+	r := bufio.NewReader(s)
+	for {
+		frame, err := ReadEnvelope(r, c.limiter.MaxReadSize()+maxTreeFrameOverhead)
+		if err != nil {
+			c.logReadError(remotePeerID, err)
+			return
+		}
+
+		err = c.processFrame(remotePeerID, frame)
+		if err != nil {
+			c.logger.Warn().Err(err).Str("From", remotePeerID.String()).Msg("rejected tree broadcast frame")
+		}
+	}
+}
+
+func (c TreeBroadcastCommunication) processFrame(forwarder peer.ID, b []byte) error {
+	frame, err := unmarshalTreeFrame(b)
+	if err != nil {
+		return err
+	}
+	wrappedMsg, err := UnmarshalEnvelope(frame.envelope)
+	if err != nil {
+		return err
+	}
+	originKey, err := crypto.UnmarshalPublicKey(frame.originKey)
+	if err != nil {
+		return err
+	}
+	wrappedMsg.From, err = peer.IDFromPublicKey(originKey)
+	if err != nil {
+		return err
+	}
+
+	if len(frame.envelope) > c.limiter.MaxSize(wrappedMsg.MessageType) {
+		c.limiter.Drop(wrappedMsg.MessageType, SizeLimitDrop)
+		return fmt.Errorf("message of size %d over the limit", len(frame.envelope))
+	}
+	if !c.limiter.Allow(forwarder) {
+		c.limiter.Drop(wrappedMsg.MessageType, RateLimitDrop)
+		return fmt.Errorf("message over the peer rate limit")
+	}
+	if len(c.h.Peerstore().Addrs(wrappedMsg.From)) == 0 {
+		c.limiter.Drop(wrappedMsg.MessageType, VerificationDrop)
+		return fmt.Errorf("message from unknown peer %s", wrappedMsg.From)
+	}
+	err = c.verifyFrame(originKey, wrappedMsg, frame)
+	if err != nil {
+		c.limiter.Drop(wrappedMsg.MessageType, VerificationDrop)
+		return err
+	}
+
+	if len(frame.forwardTo) > 0 {
+		go func() {
+			err := c.forward(newForwardedTreeMessage(wrappedMsg, frame), frame.forwardTo)
+			if err != nil {
+				c.logger.Warn().Err(err).Str("SessionID", wrappedMsg.SessionID).Msg("unable to forward tree broadcast message")
+			}
+		}()
+	}
+	go c.acknowledge(wrappedMsg)
+
+	c.logger.Trace().Str(
+		"From", wrappedMsg.From.String()).Str(
+		"MsgType", wrappedMsg.MessageType.String()).Str(
+		"SessionID", wrappedMsg.SessionID).Msg("processed tree broadcast message")
+
+	c.limiter.Deliver(wrappedMsg, func() []chan *comm.WrappedMessage {
+		return c.GetSubscribers(wrappedMsg.SessionID, wrappedMsg.MessageType)
+	})
+	return nil
+}
+
+// verifyFrame checks the host and peers it should forward the message to are recipients
+// signed by the sender and that the message is not replayed
+func (c TreeBroadcastCommunication) verifyFrame(originKey crypto.PubKey, msg *comm.WrappedMessage, frame *treeFrame) error {
+	valid, err := originKey.Verify(treeSigningBytes(c.treeProtocolID, msg, frame.recipients), msg.Signature)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return fmt.Errorf("invalid message signature")
+	}
+
+	recipients := make(map[peer.ID]bool)
+	for _, recipient := range frame.recipients {
+		recipients[recipient] = true
+	}
+	if !recipients[c.h.ID()] {
+		return fmt.Errorf("host is not a recipient of the message")
+	}
+	for _, p := range frame.forwardTo {
+		if !recipients[p] {
+			return fmt.Errorf("peer %s is not a recipient of the message", p)
+		}
+	}
+
+	return c.CheckReplay(msg.From, time.UnixMilli(msg.Timestamp), msg.Nonce)
+}
+
+// acknowledge sends the ack for the message directly to the sender
+func (c TreeBroadcastCommunication) acknowledge(msg *comm.WrappedMessage) {
+	ack := make([]byte, 8)
+	binary.BigEndian.PutUint64(ack, msg.Nonce)
+	err := c.Libp2pCommunication.Broadcast(peer.IDSlice{msg.From}, ack, comm.TreeBroadcastAckMsg, msg.SessionID)
+	if err != nil {
+		c.logger.Warn().Err(err).Str("SessionID", msg.SessionID).Msg("unable to acknowledge tree broadcast message")
+	}
+}
+
+// awaitAcks waits for acks of the message with the nonce and returns recipients
+// that didn't acknowledge the message in the ack timeout
+func awaitAcks(acks chan *comm.WrappedMessage, nonce uint64, recipients peer.IDSlice) peer.IDSlice {
+	pending := make(map[peer.ID]bool)
+	for _, recipient := range recipients {
+		pending[recipient] = true
+	}
+
+	timeout := time.NewTimer(TreeBroadcastAckTimeout)
+	defer timeout.Stop()
+	for len(pending) > 0 {
+		select {
+		case ack := <-acks:
+			if len(ack.Payload) == 8 && binary.BigEndian.Uint64(ack.Payload) == nonce {
+				delete(pending, ack.From)
+			}
+		case <-timeout.C:
+			missing := make(peer.IDSlice, 0, len(pending))
+			for _, recipient := range recipients {
+				if pending[recipient] {
+					missing = append(missing, recipient)
+				}
+			}
+			return missing
+		}
+	}
+	return nil
+}
+
+// splitRecipients splits recipients into at most fanout parts of similar size
+func splitRecipients(recipients peer.IDSlice, fanout int) []peer.IDSlice {
+	parts := fanout
+	if parts > len(recipients) {
+		parts = len(recipients)
+	}
+
+	split := make([]peer.IDSlice, 0, parts)
+	start := 0
+	for i := 0; i < parts; i++ {
+		size := len(recipients) / parts
+		if i < len(recipients)%parts {
+			size++
+		}
+		split = append(split, recipients[start:start+size])
+		start += size
+	}
+	return split
+}
+
+// treeSigningBytes returns bytes signed by the sender which cover the message and all its recipients
+func treeSigningBytes(protocolID protocol.ID, msg *comm.WrappedMessage, recipients peer.IDSlice) []byte {
+	b := signingBytes(protocolID, msg)
+	for _, recipient := range recipients {
+		b = protowire.AppendTag(b, treeRecipientField, protowire.BytesType)
+		b = protowire.AppendBytes(b, []byte(recipient))
+	}
+	return b
+}
+
+// treeMessage lazily signs and encodes the message so it is signed once per broadcast
+type treeMessage struct {
+	protocolID protocol.ID
+	msg        *comm.WrappedMessage
+	recipients peer.IDSlice
+
+	signOnce  sync.Once
+	signErr   error
+	envelope  []byte
+	originKey []byte
+}
+
+func newTreeMessage(protocolID protocol.ID, msg *comm.WrappedMessage, recipients peer.IDSlice) *treeMessage {
+	return &treeMessage{protocolID: protocolID, msg: msg, recipients: recipients}
+}
+
+// newForwardedTreeMessage creates the message signed by the origin peer
+func newForwardedTreeMessage(msg *comm.WrappedMessage, frame *treeFrame) *treeMessage {
+	m := &treeMessage{
+		msg:        msg,
+		recipients: frame.recipients,
+		envelope:   frame.envelope,
+		originKey:  frame.originKey,
+	}
+	m.signOnce.Do(func() {})
+	return m
+}
+
+func (m *treeMessage) sign(privKey crypto.PrivKey) error {
+	m.signOnce.Do(func() {
+		if privKey == nil {
+			m.signErr = fmt.Errorf("missing private key")
+			return
+		}
+		m.originKey, m.signErr = crypto.MarshalPublicKey(privKey.GetPublic())
+		if m.signErr != nil {
+			return
+		}
+		m.msg.Signature, m.signErr = privKey.Sign(treeSigningBytes(m.protocolID, m.msg, m.recipients))
+		if m.signErr != nil {
+			return
+		}
+		m.envelope = MarshalEnvelope(m.msg)
+	})
+	return m.signErr
+}
+
+// frame encodes the tree broadcast frame for the peer responsible for forwarding to forwardTo peers:
+//
+//	message TreeBroadcastFrame {
+//	  bytes envelope = 1;
+//	  bytes origin_key = 2;
+//	  repeated bytes recipients = 3;
+//	  repeated bytes forward_to = 4;
+//	}
+func (m *treeMessage) frame(forwardTo peer.IDSlice) []byte {
+	b := make([]byte, 0, len(m.envelope)+len(m.originKey)+(len(m.recipients)+len(forwardTo))*40+16)
+	b = protowire.AppendTag(b, treeEnvelopeField, protowire.BytesType)
+	b = protowire.AppendBytes(b, m.envelope)
+	b = protowire.AppendTag(b, treeOriginKeyField, protowire.BytesType)
+	b = protowire.AppendBytes(b, m.originKey)
+	for _, recipient := range m.recipients {
+		b = protowire.AppendTag(b, treeRecipientField, protowire.BytesType)
+		b = protowire.AppendBytes(b, []byte(recipient))
+	}
+	for _, p := range forwardTo {
+		b = protowire.AppendTag(b, treeForwardField, protowire.BytesType)
+		b = protowire.AppendBytes(b, []byte(p))
+	}
+	return b
+}
+
+type treeFrame struct {
+	envelope   []byte
+	originKey  []byte
+	recipients peer.IDSlice
+	forwardTo  peer.IDSlice
+}
+
+func unmarshalTreeFrame(b []byte) (*treeFrame, error) {
+	frame := &treeFrame{
+		recipients: make(peer.IDSlice, 0),
+		forwardTo:  make(peer.IDSlice, 0),
+	}
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return nil, fmt.Errorf("invalid tree broadcast frame tag: %w", protowire.ParseError(n))
+		}
+		b = b[n:]
+
+		var value []byte
+		switch {
+		case num == treeEnvelopeField && typ == protowire.BytesType:
+			value, n = protowire.ConsumeBytes(b)
+			frame.envelope = append([]byte{}, value...)
+		case num == treeOriginKeyField && typ == protowire.BytesType:
+			value, n = protowire.ConsumeBytes(b)
+			frame.originKey = append([]byte{}, value...)
+		case (num == treeRecipientField || num == treeForwardField) && typ == protowire.BytesType:
+			value, n = protowire.ConsumeBytes(b)
+			p, err := peer.IDFromBytes(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid tree broadcast peer: %w", err)
+			}
+			if num == treeRecipientField {
+				frame.recipients = append(frame.recipients, p)
+			} else {
+				frame.forwardTo = append(frame.forwardTo, p)
+			}
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return nil, fmt.Errorf("invalid tree broadcast frame field %d: %w", num, protowire.ParseError(n))
+		}
+		b = b[n:]
+	}
+
+	if len(frame.envelope) == 0 || len(frame.originKey) == 0 {
+		return nil, fmt.Errorf("missing tree broadcast frame envelope or origin key")
+	}
+	return frame, nil
+}
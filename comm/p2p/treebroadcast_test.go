@@ -0,0 +1,234 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package p2p_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	comm "github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/ChainSafe/sygma-relayer/comm/p2p"
+	"github.com/ChainSafe/sygma-relayer/topology"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+type TreeBroadcastCommunicationTestSuite struct {
+	suite.Suite
+}
+
+func TestRunTreeBroadcastCommunicationTestSuite(t *testing.T) {
+	suite.Run(t, new(TreeBroadcastCommunicationTestSuite))
+}
+
+func (s *TreeBroadcastCommunicationTestSuite) SetupTest() {
+	p2p.TreeBroadcastAckTimeout = time.Millisecond * 500
+}
+
+func (s *TreeBroadcastCommunicationTestSuite) setupHosts(numberOfHosts int, port int, fanout int) ([]host.Host, []p2p.TreeBroadcastCommunication) {
+	topology := &topology.NetworkTopology{
+		Peers: []*peer.AddrInfo{},
+	}
+	privateKeys := []crypto.PrivKey{}
+	for i := 0; i < numberOfHosts; i++ {
+		privKeyForHost, _, _ := crypto.GenerateKeyPair(crypto.ECDSA, 1)
+		privateKeys = append(privateKeys, privKeyForHost)
+		peerID, _ := peer.IDFromPrivateKey(privKeyForHost)
+		addrInfoForHost, _ := peer.AddrInfoFromString(fmt.Sprintf(
+			"/ip4/127.0.0.1/tcp/%d/p2p/%s", port+i, peerID.Pretty(),
+		))
+		topology.Peers = append(topology.Peers, addrInfoForHost)
+	}
+
+	hosts := []host.Host{}
+	communications := []p2p.TreeBroadcastCommunication{}
+	for i := 0; i < numberOfHosts; i++ {
+		newHost, err := p2p.NewHost(privateKeys[i], topology, p2p.NewConnectionGate(topology), uint16(port+i))
+		s.Nil(err)
+		hosts = append(hosts, newHost)
+		communications = append(communications, p2p.NewTreeBroadcastCommunication(
+			newHost, protocol.ID("/p2p/test"), p2p.CommunicationConfig{RequireSignedMessages: true}, fanout,
+		))
+	}
+	return hosts, communications
+}
+
+func (s *TreeBroadcastCommunicationTestSuite) receive(msgChn chan *comm.WrappedMessage) *comm.WrappedMessage {
+	select {
+	case msg := <-msgChn:
+		return msg
+	case <-time.After(time.Second * 10):
+		s.Fail("message not received")
+		return nil
+	}
+}
+
+func (s *TreeBroadcastCommunicationTestSuite) Test_Broadcast_SentToAllPeers() {
+	hosts, communications := s.setupHosts(6, 4200, 2)
+
+	peers := peer.IDSlice{}
+	msgChns := []chan *comm.WrappedMessage{}
+	for i, h := range hosts {
+		peers = append(peers, h.ID())
+		msgChn := make(chan *comm.WrappedMessage, 2)
+		communications[i].Subscribe("1", comm.TssKeySignMsg, msgChn)
+		msgChns = append(msgChns, msgChn)
+	}
+
+	err := communications[0].Broadcast(peers, []byte("payload"), comm.TssKeySignMsg, "1")
+	s.Nil(err)
+
+	for i := 1; i < len(hosts); i++ {
+		msg := s.receive(msgChns[i])
+		s.Equal(hosts[0].ID(), msg.From)
+		s.Equal([]byte("payload"), msg.Payload)
+		s.Equal("1", msg.SessionID)
+	}
+	time.Sleep(time.Millisecond * 100)
+	for _, msgChn := range msgChns {
+		s.Len(msgChn, 0)
+	}
+}
+
+func (s *TreeBroadcastCommunicationTestSuite) Test_Broadcast_UnreachablePeerRecipientsReceiveMessage() {
+	hosts, communications := s.setupHosts(6, 4210, 2)
+
+	peers := peer.IDSlice{}
+	msgChns := []chan *comm.WrappedMessage{}
+	for i, h := range hosts {
+		peers = append(peers, h.ID())
+		msgChn := make(chan *comm.WrappedMessage, 2)
+		communications[i].Subscribe("1", comm.TssKeySignMsg, msgChn)
+		msgChns = append(msgChns, msgChn)
+	}
+	hosts[3].Close()
+
+	err := communications[0].Broadcast(peers, []byte("payload"), comm.TssKeySignMsg, "1")
+	s.NotNil(err)
+
+	for i := 1; i < len(hosts); i++ {
+		if i == 3 {
+			continue
+		}
+		msg := s.receive(msgChns[i])
+		s.Equal(hosts[0].ID(), msg.From)
+	}
+}
+
+func (s *TreeBroadcastCommunicationTestSuite) Test_Broadcast_DroppedMessagesSentDirectly() {
+	hosts, communications := s.setupHosts(6, 4220, 2)
+
+	peers := peer.IDSlice{}
+	msgChns := []chan *comm.WrappedMessage{}
+	for i, h := range hosts {
+		if i != 0 {
+			// forwarding peers silently drop the message
+			h.SetStreamHandler(p2p.TreeBroadcastProtocolID("/p2p/test"), func(stream network.Stream) {
+				_, _ = io.Copy(io.Discard, stream)
+				_ = stream.Close()
+			})
+		}
+		peers = append(peers, h.ID())
+		msgChn := make(chan *comm.WrappedMessage, 2)
+		communications[i].Subscribe("1", comm.TssKeySignMsg, msgChn)
+		msgChns = append(msgChns, msgChn)
+	}
+
+	err := communications[0].Broadcast(peers, []byte("payload"), comm.TssKeySignMsg, "1")
+	s.Nil(err)
+
+	for i := 1; i < len(hosts); i++ {
+		msg := s.receive(msgChns[i])
+		s.Equal(hosts[0].ID(), msg.From)
+		s.Equal([]byte("payload"), msg.Payload)
+	}
+}
+
+func (s *TreeBroadcastCommunicationTestSuite) Test_Broadcast_RedirectedMessageRejected() {
+	hosts, communications := s.setupHosts(6, 4240, 2)
+	protocolID := p2p.TreeBroadcastProtocolID("/p2p/test")
+
+	peers := peer.IDSlice{}
+	for _, h := range hosts[:5] {
+		peers = append(peers, h.ID())
+	}
+	msgChn := make(chan *comm.WrappedMessage, 2)
+	communications[5].Subscribe("1", comm.TssKeySignMsg, msgChn)
+	redirected := make(chan error, 1)
+	for _, h := range hosts[1:5] {
+		h := h
+		// forwarding peers try to redirect the message to a peer that is not a recipient
+		h.SetStreamHandler(protocolID, func(stream network.Stream) {
+			defer stream.Close()
+			frame, err := p2p.ReadEnvelope(bufio.NewReader(stream), 1<<20)
+			if err != nil {
+				return
+			}
+			frame = protowire.AppendTag(frame, 4, protowire.BytesType)
+			frame = protowire.AppendBytes(frame, []byte(hosts[5].ID()))
+
+			redirectStream, err := h.NewStream(context.Background(), hosts[5].ID(), protocolID)
+			if err == nil {
+				defer redirectStream.Close()
+				err = p2p.WriteEnvelope(frame, bufio.NewWriter(redirectStream))
+			}
+			select {
+			case redirected <- err:
+			default:
+			}
+		})
+	}
+
+	_ = communications[0].Broadcast(peers, []byte("payload"), comm.TssKeySignMsg, "1")
+
+	s.Nil(<-redirected)
+	time.Sleep(time.Millisecond * 500)
+	s.Len(msgChn, 0)
+}
+
+func (s *TreeBroadcastCommunicationTestSuite) Test_Broadcast_TssMessageToSinglePeerSentDirectly() {
+	hosts, communications := s.setupHosts(3, 4230, 2)
+	hosts[1].RemoveStreamHandler(p2p.TreeBroadcastProtocolID("/p2p/test"))
+
+	msgChn := make(chan *comm.WrappedMessage, 1)
+	communications[1].Subscribe("1", comm.TssKeySignMsg, msgChn)
+
+	err := communications[0].Broadcast(peer.IDSlice{hosts[1].ID()}, []byte("payload"), comm.TssKeySignMsg, "1")
+	s.Nil(err)
+
+	msg := s.receive(msgChn)
+	s.Equal(hosts[0].ID(), msg.From)
+}
+
+func (s *TreeBroadcastCommunicationTestSuite) Test_Broadcast_ElectionMessageSentDirectly() {
+	hosts, communications := s.setupHosts(6, 4250, 2)
+
+	peers := peer.IDSlice{}
+	msgChns := []chan *comm.WrappedMessage{}
+	for i, h := range hosts {
+		h.RemoveStreamHandler(p2p.TreeBroadcastProtocolID("/p2p/test"))
+		peers = append(peers, h.ID())
+		msgChn := make(chan *comm.WrappedMessage, 2)
+		communications[i].Subscribe("1", comm.CoordinatorSelectMsg, msgChn)
+		msgChns = append(msgChns, msgChn)
+	}
+
+	err := communications[0].Broadcast(peers, []byte("payload"), comm.CoordinatorSelectMsg, "1")
+	s.Nil(err)
+
+	for i := 1; i < len(hosts); i++ {
+		msg := s.receive(msgChns[i])
+		s.Equal(hosts[0].ID(), msg.From)
+		s.Equal([]byte("payload"), msg.Payload)
+	}
+}
@@ -5,23 +5,38 @@ package p2p
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"strings"
 )
 
-// ReadStream reads data from the given stream
-func ReadStream(r *bufio.Reader) ([]byte, error) {
-	msg, err := r.ReadString('\n')
-	if err != nil {
-		return []byte{}, err
+var ErrMessageTooLarge = errors.New("message exceeds size limit")
+
+// ReadStream reads data from the given stream and returns error if the message
+// is larger than max size
+func ReadStream(r *bufio.Reader, maxSize int) ([]byte, error) {
+	msg := make([]byte, 0)
+	for {
+		line, err := r.ReadSlice('\n')
+		msg = append(msg, line...)
+		// newline delimiter is not part of the message
+		if len(msg) > maxSize+1 {
+			return []byte{}, fmt.Errorf("%w %d", ErrMessageTooLarge, maxSize)
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err != nil {
+			return []byte{}, err
+		}
+		break
 	}
 
-	if msg == "" {
+	if len(msg) == 0 {
 		return []byte{}, fmt.Errorf("end of stream reached")
 	}
 
-	msg = strings.Trim(msg, "\n")
-	return []byte(msg), nil
+	return []byte(strings.Trim(string(msg), "\n")), nil
 }
 
 // WriteStream writes the message to stream
@@ -0,0 +1,165 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package recorder
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/rs/zerolog/log"
+)
+
+type Direction string
+
+const (
+	Inbound  Direction = "inbound"
+	Outbound Direction = "outbound"
+)
+
+// Record is a single message sent or received in the session
+type Record struct {
+	Direction   Direction        `json:"direction"`
+	From        peer.ID          `json:"from"`
+	To          peer.IDSlice     `json:"to"`
+	MessageType comm.MessageType `json:"messageType"`
+	SessionID   string           `json:"sessionID"`
+	Payload     []byte           `json:"payload"`
+	Timestamp   time.Time        `json:"timestamp"`
+}
+
+// RecordingCommunication writes every message sent and received by the wrapped
+// communication to a file of the message session
+type RecordingCommunication struct {
+	comm.Communication
+	self peer.ID
+	dir  string
+
+	fileLock sync.Mutex
+
+	subscriptionLock sync.Mutex
+	subscriptions    map[comm.SubscriptionID]chan struct{}
+}
+
+func NewRecordingCommunication(communication comm.Communication, self peer.ID, dir string) (*RecordingCommunication, error) {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RecordingCommunication{
+		Communication: communication,
+		self:          self,
+		dir:           dir,
+		subscriptions: make(map[comm.SubscriptionID]chan struct{}),
+	}, nil
+}
+
+// Broadcast records the message and sends it with the wrapped communication
+func (c *RecordingCommunication) Broadcast(peers peer.IDSlice, msg []byte, msgType comm.MessageType, sessionID string) error {
+	c.record(&Record{
+		Direction:   Outbound,
+		From:        c.self,
+		To:          peers,
+		MessageType: msgType,
+		SessionID:   sessionID,
+		Payload:     msg,
+		Timestamp:   time.Now(),
+	})
+	return c.Communication.Broadcast(peers, msg, msgType, sessionID)
+}
+
+// Subscribe records messages received by the subscription before they are
+// forwarded to the channel
+func (c *RecordingCommunication) Subscribe(sessionID string, msgType comm.MessageType, channel chan *comm.WrappedMessage) comm.SubscriptionID {
+	recordChn := make(chan *comm.WrappedMessage)
+	subID := c.Communication.Subscribe(sessionID, msgType, recordChn)
+
+	done := make(chan struct{})
+	c.subscriptionLock.Lock()
+	c.subscriptions[subID] = done
+	c.subscriptionLock.Unlock()
+
+	go c.forward(recordChn, channel, done)
+	return subID
+}
+
+func (c *RecordingCommunication) UnSubscribe(subID comm.SubscriptionID) {
+	c.Communication.UnSubscribe(subID)
+
+	c.subscriptionLock.Lock()
+	defer c.subscriptionLock.Unlock()
+	done, ok := c.subscriptions[subID]
+	if !ok {
+		return
+	}
+	close(done)
+	delete(c.subscriptions, subID)
+}
+
+func (c *RecordingCommunication) forward(recordChn chan *comm.WrappedMessage, channel chan *comm.WrappedMessage, done chan struct{}) {
+	for {
+		select {
+		case msg := <-recordChn:
+			{
+				c.record(&Record{
+					Direction:   Inbound,
+					From:        msg.From,
+					To:          peer.IDSlice{c.self},
+					MessageType: msg.MessageType,
+					SessionID:   msg.SessionID,
+					Payload:     msg.Payload,
+					Timestamp:   time.Now(),
+				})
+
+				select {
+				case channel <- msg:
+				case <-done:
+					return
+				}
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
+func (c *RecordingCommunication) record(record *Record) {
+	recordBytes, err := json.Marshal(record)
+	if err != nil {
+		log.Err(err).Str("SessionID", record.SessionID).Msgf("Failed encoding message record")
+		return
+	}
+
+	c.fileLock.Lock()
+	defer c.fileLock.Unlock()
+
+	f, err := os.OpenFile(SessionPath(c.dir, record.SessionID), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		log.Err(err).Str("SessionID", record.SessionID).Msgf("Failed opening session record file")
+		return
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(recordBytes, '\n'))
+	if err != nil {
+		log.Err(err).Str("SessionID", record.SessionID).Msgf("Failed writing message record")
+	}
+}
+
+// SessionPath returns path of the file where messages of the session are recorded
+func SessionPath(dir string, sessionID string) string {
+	fileName := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.' {
+			return r
+		}
+		return '_'
+	}, sessionID)
+	return filepath.Join(dir, fileName+".jsonl")
+}
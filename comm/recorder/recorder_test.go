@@ -0,0 +1,125 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package recorder_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/sygma-relayer/comm"
+	mock_comm "github.com/ChainSafe/sygma-relayer/comm/mock"
+	"github.com/ChainSafe/sygma-relayer/comm/recorder"
+	"github.com/ChainSafe/sygma-relayer/tss/message"
+	"github.com/golang/mock/gomock"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/suite"
+)
+
+type RecordingCommunicationTestSuite struct {
+	suite.Suite
+	mockCommunication *mock_comm.MockCommunication
+	recorder          *recorder.RecordingCommunication
+	dir               string
+	self              peer.ID
+	other             peer.ID
+}
+
+func TestRunRecordingCommunicationTestSuite(t *testing.T) {
+	suite.Run(t, new(RecordingCommunicationTestSuite))
+}
+
+func (s *RecordingCommunicationTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.mockCommunication = mock_comm.NewMockCommunication(ctrl)
+	s.self, _ = peer.Decode("QmcW3oMdSqoEcjbyd51auqC23vhKX6BqfcZcY2HJ3sKAZR")
+	s.other, _ = peer.Decode("QmZHPnN3CKiTAp8VaJqszbf8m7v4mPh15M421KpVdYHF54")
+	s.dir = s.T().TempDir()
+
+	var err error
+	s.recorder, err = recorder.NewRecordingCommunication(s.mockCommunication, s.self, s.dir)
+	s.Nil(err)
+}
+
+func (s *RecordingCommunicationTestSuite) Test_Broadcast_RecordsOutboundMessage() {
+	s.mockCommunication.EXPECT().Broadcast(peer.IDSlice{s.other}, []byte("msg"), comm.TssKeySignMsg, "1-2").Return(nil)
+
+	err := s.recorder.Broadcast(peer.IDSlice{s.other}, []byte("msg"), comm.TssKeySignMsg, "1-2")
+	s.Nil(err)
+
+	session, err := recorder.LoadSession(recorder.SessionPath(s.dir, "1-2"))
+	s.Nil(err)
+	s.Equal("1-2", session.ID)
+	s.Equal(s.self, session.Self)
+	s.Len(session.Records, 1)
+	s.Equal(recorder.Outbound, session.Records[0].Direction)
+	s.Equal(peer.IDSlice{s.other}, session.Records[0].To)
+	s.Equal([]byte("msg"), session.Records[0].Payload)
+}
+
+func (s *RecordingCommunicationTestSuite) Test_Subscribe_RecordsAndForwardsInboundMessage() {
+	var recordChn chan *comm.WrappedMessage
+	s.mockCommunication.EXPECT().Subscribe("1-2", comm.TssKeySignMsg, gomock.Any()).DoAndReturn(
+		func(sessionID string, msgType comm.MessageType, channel chan *comm.WrappedMessage) comm.SubscriptionID {
+			recordChn = channel
+			return comm.NewSubscriptionID(sessionID, msgType)
+		})
+	channel := make(chan *comm.WrappedMessage)
+	subID := s.recorder.Subscribe("1-2", comm.TssKeySignMsg, channel)
+
+	msg := &comm.WrappedMessage{
+		MessageType: comm.TssKeySignMsg,
+		SessionID:   "1-2",
+		Payload:     []byte("msg"),
+		From:        s.other,
+	}
+	recordChn <- msg
+	select {
+	case received := <-channel:
+		s.Equal(msg, received)
+	case <-time.After(time.Second):
+		s.Fail("message not forwarded")
+	}
+
+	s.mockCommunication.EXPECT().UnSubscribe(subID)
+	s.recorder.UnSubscribe(subID)
+
+	session, err := recorder.LoadSession(recorder.SessionPath(s.dir, "1-2"))
+	s.Nil(err)
+	s.Equal(s.self, session.Self)
+	s.Len(session.Records, 1)
+	s.Equal(recorder.Inbound, session.Records[0].Direction)
+	s.Equal(s.other, session.Records[0].From)
+}
+
+func (s *RecordingCommunicationTestSuite) Test_Attempts_SplitByStartMessages() {
+	s.mockCommunication.EXPECT().Broadcast(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	startMsg1, _ := message.MarshalStartMessage([]byte("1"))
+	startMsg2, _ := message.MarshalStartMessage([]byte("2"))
+
+	_ = s.recorder.Broadcast(peer.IDSlice{s.other}, []byte("ready"), comm.TssReadyMsg, "1-2")
+	_ = s.recorder.Broadcast(peer.IDSlice{s.other}, startMsg1, comm.TssStartMsg, "1-2")
+	_ = s.recorder.Broadcast(peer.IDSlice{s.other}, []byte("msg1"), comm.TssKeySignMsg, "1-2")
+	_ = s.recorder.Broadcast(peer.IDSlice{s.other}, startMsg2, comm.TssStartMsg, "1-2")
+	_ = s.recorder.Broadcast(peer.IDSlice{s.other}, []byte("msg2"), comm.TssKeySignMsg, "1-2")
+	_ = s.recorder.Broadcast(peer.IDSlice{s.other}, []byte("msg3"), comm.TssKeySignMsg, "1-2")
+
+	session, err := recorder.LoadSession(recorder.SessionPath(s.dir, "1-2"))
+	s.Nil(err)
+	attempts, err := session.Attempts()
+
+	s.Nil(err)
+	s.Len(attempts, 2)
+	s.Equal([]byte("1"), attempts[0].Params)
+	s.Len(attempts[0].Records, 1)
+	s.Equal([]byte("2"), attempts[1].Params)
+	s.Len(attempts[1].Records, 2)
+	s.Empty(attempts[1].Inbound(comm.TssKeySignMsg))
+}
+
+func (s *RecordingCommunicationTestSuite) Test_SessionPath_SanitizesSessionID() {
+	path := recorder.SessionPath(s.dir, "../1/2")
+
+	s.Equal(s.dir+string(os.PathSeparator)+".._1_2.jsonl", path)
+}
@@ -0,0 +1,117 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package recorder
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/ChainSafe/sygma-relayer/tss/message"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+const maxRecordSize = 16 << 20
+
+// Session contains messages recorded by a single party of the session
+type Session struct {
+	ID      string
+	Self    peer.ID
+	Records []*Record
+}
+
+// Attempt is a single run of the tss process started by the coordinator start message
+type Attempt struct {
+	// Params are process start params sent by the coordinator
+	Params  []byte
+	Records []*Record
+}
+
+// LoadSession reads recorded session messages from the file
+func LoadSession(path string) (*Session, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	session := &Session{
+		Records: make([]*Record, 0),
+	}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRecordSize)
+	for scanner.Scan() {
+		record := &Record{}
+		err := json.Unmarshal(scanner.Bytes(), record)
+		if err != nil {
+			return nil, fmt.Errorf("invalid record %d: %w", len(session.Records), err)
+		}
+
+		if session.ID == "" {
+			session.ID = record.SessionID
+		}
+		if record.SessionID != session.ID {
+			return nil, fmt.Errorf("record %d of session %s found in session %s", len(session.Records), record.SessionID, session.ID)
+		}
+		switch record.Direction {
+		case Outbound:
+			session.Self = record.From
+		case Inbound:
+			if len(record.To) == 1 {
+				session.Self = record.To[0]
+			}
+		}
+		session.Records = append(session.Records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(session.Records) == 0 {
+		return nil, fmt.Errorf("no records in %s", path)
+	}
+
+	return session, nil
+}
+
+// Attempts splits session records by start messages so process retries
+// with different peer subsets can be replayed separately
+func (s *Session) Attempts() ([]*Attempt, error) {
+	attempts := make([]*Attempt, 0)
+	var attempt *Attempt
+	for _, record := range s.Records {
+		if record.MessageType == comm.TssStartMsg {
+			startMsg, err := message.UnmarshalStartMessage(record.Payload)
+			if err != nil {
+				return nil, err
+			}
+
+			attempt = &Attempt{
+				Params:  startMsg.Params,
+				Records: make([]*Record, 0),
+			}
+			attempts = append(attempts, attempt)
+			continue
+		}
+		if attempt == nil {
+			continue
+		}
+
+		attempt.Records = append(attempt.Records, record)
+	}
+
+	return attempts, nil
+}
+
+// Inbound returns messages of the type received during the attempt
+func (a *Attempt) Inbound(msgType comm.MessageType) []*Record {
+	records := make([]*Record, 0)
+	for _, record := range a.Records {
+		if record.Direction == Inbound && record.MessageType == msgType {
+			records = append(records, record)
+		}
+	}
+	return records
+}
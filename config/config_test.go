@@ -98,18 +98,32 @@ func (s *GetConfigTestSuite) Test_GetConfigFromENV() {
 					Url:           "http://test.com",
 					Path:          "path",
 				},
-				Port:                    9000,
-				KeysharePath:            "/cfg/keyshares/0.keyshare",
-				FrostKeysharePath:       "/cfg/keyshares/0-frost.keyshare",
-				Key:                     "test-pk",
-				CommHealthCheckInterval: 5 * time.Minute,
+				Port:                        9000,
+				KeysharePath:                "/cfg/keyshares/0.keyshare",
+				FrostKeysharePath:           "/cfg/keyshares/0-frost.keyshare",
+				Key:                         "test-pk",
+				CommHealthCheckInterval:     5 * time.Minute,
+				CeremonyRecoveryInterval:    5 * time.Minute,
+				MaxCeremonyRecoveryAttempts: 5,
+				MaxConcurrentProcesses:      10,
+				DNSCacheTTL:                 5 * time.Minute,
+				TreeBroadcastFanout:         4,
+				MessageLimits: relayer.MessageLimitsConfig{
+					MaxMessageSize:     67108864,
+					MaxMessageSizes:    map[string]int{},
+					RateLimit:          200,
+					RateBurst:          1000,
+					MaxPendingMessages: 100,
+					DeliveryTimeout:    time.Minute,
+				},
 			},
 			BullyConfig: relayer.BullyConfig{
-				PingWaitTime:     1 * time.Second,
-				PingBackOff:      1 * time.Second,
-				PingInterval:     1 * time.Second,
-				ElectionWaitTime: 2 * time.Second,
-				BullyWaitTime:    3 * time.Minute,
+				PingWaitTime:       1 * time.Second,
+				PingBackOff:        1 * time.Second,
+				PingInterval:       1 * time.Second,
+				ElectionWaitTime:   2 * time.Second,
+				BullyWaitTime:      3 * time.Minute,
+				CoordinatorElector: "static",
 			},
 			UploaderConfig: relayer.UploaderConfig{
 				MaxRetries:     5,
@@ -215,18 +229,32 @@ func (s *GetConfigTestSuite) Test_GetConfigFromENV_RandomOrder() {
 					Url:           "http://test.com",
 					Path:          "path",
 				},
-				Port:                    9000,
-				KeysharePath:            "/cfg/keyshares/0.keyshare",
-				FrostKeysharePath:       "/cfg/keyshares/0-frost.keyshare",
-				Key:                     "test-pk",
-				CommHealthCheckInterval: 5 * time.Minute,
+				Port:                        9000,
+				KeysharePath:                "/cfg/keyshares/0.keyshare",
+				FrostKeysharePath:           "/cfg/keyshares/0-frost.keyshare",
+				Key:                         "test-pk",
+				CommHealthCheckInterval:     5 * time.Minute,
+				CeremonyRecoveryInterval:    5 * time.Minute,
+				MaxCeremonyRecoveryAttempts: 5,
+				MaxConcurrentProcesses:      10,
+				DNSCacheTTL:                 5 * time.Minute,
+				TreeBroadcastFanout:         4,
+				MessageLimits: relayer.MessageLimitsConfig{
+					MaxMessageSize:     67108864,
+					MaxMessageSizes:    map[string]int{},
+					RateLimit:          200,
+					RateBurst:          1000,
+					MaxPendingMessages: 100,
+					DeliveryTimeout:    time.Minute,
+				},
 			},
 			BullyConfig: relayer.BullyConfig{
-				PingWaitTime:     1 * time.Second,
-				PingBackOff:      1 * time.Second,
-				PingInterval:     1 * time.Second,
-				ElectionWaitTime: 2 * time.Second,
-				BullyWaitTime:    3 * time.Minute,
+				PingWaitTime:       1 * time.Second,
+				PingBackOff:        1 * time.Second,
+				PingInterval:       1 * time.Second,
+				ElectionWaitTime:   2 * time.Second,
+				BullyWaitTime:      3 * time.Minute,
+				CoordinatorElector: "static",
 			},
 			UploaderConfig: relayer.UploaderConfig{
 				MaxRetries:     5,
@@ -453,6 +481,40 @@ func (s *GetConfigTestSuite) Test_GetConfigFromFile() {
 			errorMsg:   "topology configuration encryption key not provided",
 			outConfig:  config.Config{},
 		},
+		{
+			name: "topology quorum bigger than sources",
+			inConfig: config.RawConfig{
+				RelayerConfig: relayer.RawRelayerConfig{
+					LogLevel: "info",
+					MpcConfig: relayer.RawMpcRelayerConfig{
+						TopologyConfiguration: relayer.TopologyConfiguration{
+							EncryptionKey: "enc-key",
+							Path:          "path",
+							Sources: []relayer.TopologySource{
+								{Type: "file", Path: "topology"},
+							},
+							Quorum: 2,
+						},
+						Port: "2020",
+					},
+					UploaderConfig: relayer.UploaderConfig{
+						URL:            "https://testIPFSProvider.com",
+						AuthToken:      "testToken",
+						MaxRetries:     5,
+						MaxElapsedTime: 5 * time.Minute,
+					},
+				},
+
+				ChainConfigs: []map[string]interface{}{{
+					"id":   float64(1),
+					"type": "evm",
+					"name": "chain1",
+				}},
+			},
+			shouldFail: true,
+			errorMsg:   "topology configuration quorum bigger than the number of sources",
+			outConfig:  config.Config{},
+		},
 		{
 			name: "set default values in config",
 			inConfig: config.RawConfig{
@@ -495,14 +557,28 @@ func (s *GetConfigTestSuite) Test_GetConfigFromFile() {
 							Url:           "url",
 							Path:          "path",
 						},
-						CommHealthCheckInterval: 5 * time.Minute,
+						CommHealthCheckInterval:     5 * time.Minute,
+						CeremonyRecoveryInterval:    5 * time.Minute,
+						MaxCeremonyRecoveryAttempts: 5,
+						MaxConcurrentProcesses:      10,
+						DNSCacheTTL:                 5 * time.Minute,
+						TreeBroadcastFanout:         4,
+						MessageLimits: relayer.MessageLimitsConfig{
+							MaxMessageSize:     67108864,
+							MaxMessageSizes:    map[string]int{},
+							RateLimit:          200,
+							RateBurst:          1000,
+							MaxPendingMessages: 100,
+							DeliveryTimeout:    time.Minute,
+						},
 					},
 					BullyConfig: relayer.BullyConfig{
-						PingWaitTime:     1 * time.Second,
-						PingBackOff:      1 * time.Second,
-						PingInterval:     1 * time.Second,
-						ElectionWaitTime: 2 * time.Second,
-						BullyWaitTime:    3 * time.Minute,
+						PingWaitTime:       1 * time.Second,
+						PingBackOff:        1 * time.Second,
+						PingInterval:       1 * time.Second,
+						ElectionWaitTime:   2 * time.Second,
+						BullyWaitTime:      3 * time.Minute,
+						CoordinatorElector: "static",
 					},
 					UploaderConfig: relayer.UploaderConfig{
 						URL:            "https://testIPFSProvider.com",
@@ -573,14 +649,28 @@ func (s *GetConfigTestSuite) Test_GetConfigFromFile() {
 							Url:           "url",
 							Path:          "path",
 						},
-						CommHealthCheckInterval: 10 * time.Minute,
+						CommHealthCheckInterval:     10 * time.Minute,
+						CeremonyRecoveryInterval:    5 * time.Minute,
+						MaxCeremonyRecoveryAttempts: 5,
+						MaxConcurrentProcesses:      10,
+						DNSCacheTTL:                 5 * time.Minute,
+						TreeBroadcastFanout:         4,
+						MessageLimits: relayer.MessageLimitsConfig{
+							MaxMessageSize:     67108864,
+							MaxMessageSizes:    map[string]int{},
+							RateLimit:          200,
+							RateBurst:          1000,
+							MaxPendingMessages: 100,
+							DeliveryTimeout:    time.Minute,
+						},
 					},
 					BullyConfig: relayer.BullyConfig{
-						PingWaitTime:     time.Second,
-						PingBackOff:      time.Second,
-						PingInterval:     time.Second,
-						ElectionWaitTime: time.Second,
-						BullyWaitTime:    time.Second,
+						PingWaitTime:       time.Second,
+						PingBackOff:        time.Second,
+						PingInterval:       time.Second,
+						ElectionWaitTime:   time.Second,
+						BullyWaitTime:      time.Second,
+						CoordinatorElector: "static",
 					},
 					UploaderConfig: relayer.UploaderConfig{
 						URL:            "https://testIPFSProvider.com",
@@ -25,26 +25,79 @@ type RelayerConfig struct {
 }
 
 type MpcRelayerConfig struct {
-	TopologyConfiguration   TopologyConfiguration
-	Port                    uint16
-	KeysharePath            string
-	FrostKeysharePath       string
-	Key                     string
-	CommHealthCheckInterval time.Duration
+	TopologyConfiguration    TopologyConfiguration
+	Port                     uint16
+	KeysharePath             string
+	FrostKeysharePath        string
+	Key                      string
+	CommHealthCheckInterval  time.Duration
+	CeremonyRecoveryInterval time.Duration
+	// MaxCeremonyRecoveryAttempts is the number of times a failed ceremony is recovered
+	MaxCeremonyRecoveryAttempts int
+	// EnableCeremonyRetrigger exposes the ceremony retrigger endpoint on the health port
+	EnableCeremonyRetrigger bool
+	MaxConcurrentProcesses  int
+	RequireSignedMessages   bool
+	DNSCacheTTL             time.Duration
+	EnableTreeBroadcast     bool
+	TreeBroadcastFanout     int
+	MessageLimits           MessageLimitsConfig
+	NATTraversal            NATTraversalConfig
+	SessionRecordPath       string
+}
+
+type MessageLimitsConfig struct {
+	MaxMessageSize     int
+	MaxMessageSizes    map[string]int
+	RateLimit          float64
+	RateBurst          int
+	MaxPendingMessages int
+	DeliveryTimeout    time.Duration
 }
 
 type BullyConfig struct {
-	PingWaitTime     time.Duration
-	PingBackOff      time.Duration
-	PingInterval     time.Duration
-	ElectionWaitTime time.Duration
-	BullyWaitTime    time.Duration
+	PingWaitTime       time.Duration
+	PingBackOff        time.Duration
+	PingInterval       time.Duration
+	ElectionWaitTime   time.Duration
+	BullyWaitTime      time.Duration
+	CoordinatorElector string
+}
+
+type NATTraversalConfig struct {
+	// EnableRelay allows connecting to peers through circuit relays from their relayed topology addresses
+	EnableRelay bool `mapstructure:"EnableRelay" json:"enableRelay"`
+	// EnableRelayService makes a publicly reachable relayer act as a relay for other topology peers
+	EnableRelayService bool `mapstructure:"EnableRelayService" json:"enableRelayService"`
+	// EnableHolePunching upgrades relayed connections to direct connections when possible
+	EnableHolePunching bool `mapstructure:"EnableHolePunching" json:"enableHolePunching"`
 }
 
 type TopologyConfiguration struct {
 	EncryptionKey string `mapstructure:"EncryptionKey" json:"encryptionKey"`
 	Url           string `mapstructure:"Url" json:"url"`
 	Path          string `mapstructure:"Path" json:"path"`
+	// Sources are read instead of the Url if configured
+	Sources []TopologySource `mapstructure:"Sources" json:"sources"`
+	// Quorum is the number of sources that have to agree on the topology, defaults to the majority of sources
+	Quorum int `mapstructure:"Quorum" json:"quorum"`
+	// GovernanceKeys are addresses of keys that sign the topology
+	GovernanceKeys []string `mapstructure:"GovernanceKeys" json:"governanceKeys"`
+	// GovernanceThreshold is the number of governance keys that have to sign the topology, defaults to all keys
+	GovernanceThreshold int `mapstructure:"GovernanceThreshold" json:"governanceThreshold"`
+	// AllowLegacyEncryption allows reading topology encrypted with the unauthenticated legacy AES CTR encryption.
+	// Legacy topology is rejected by default, so it should be migrated with the topology migrate command before upgrading.
+	AllowLegacyEncryption bool `mapstructure:"AllowLegacyEncryption" json:"allowLegacyEncryption"`
+}
+
+type TopologySource struct {
+	// Type is one of url, ipfs, file or contract
+	Type string `mapstructure:"Type" json:"type"`
+	// Url is the topology url, the IPFS gateway url or the EVM rpc endpoint of the registry contract
+	Url     string `mapstructure:"Url" json:"url"`
+	Cid     string `mapstructure:"Cid" json:"cid"`
+	Path    string `mapstructure:"Path" json:"path"`
+	Address string `mapstructure:"Address" json:"address"`
 }
 
 type UploaderConfig struct {
@@ -66,29 +119,59 @@ type RawRelayerConfig struct {
 }
 
 type RawMpcRelayerConfig struct {
-	KeysharePath            string                `mapstructure:"KeysharePath" json:"keysharePath"`
-	FrostKeysharePath       string                `mapstructure:"FrostKeysharePath" json:"frostKeysharePath"`
-	Key                     string                `mapstructure:"Key" json:"key"`
-	Port                    string                `mapstructure:"Port" json:"port" default:"9000"`
-	TopologyConfiguration   TopologyConfiguration `mapstructure:"TopologyConfiguration" json:"topologyConfiguration"`
-	CommHealthCheckInterval string                `mapstructure:"CommHealthCheckInterval" json:"commHealthCheckInterval" default:"5m"`
+	KeysharePath                string                 `mapstructure:"KeysharePath" json:"keysharePath"`
+	FrostKeysharePath           string                 `mapstructure:"FrostKeysharePath" json:"frostKeysharePath"`
+	Key                         string                 `mapstructure:"Key" json:"key"`
+	Port                        string                 `mapstructure:"Port" json:"port" default:"9000"`
+	TopologyConfiguration       TopologyConfiguration  `mapstructure:"TopologyConfiguration" json:"topologyConfiguration"`
+	CommHealthCheckInterval     string                 `mapstructure:"CommHealthCheckInterval" json:"commHealthCheckInterval" default:"5m"`
+	CeremonyRecoveryInterval    string                 `mapstructure:"CeremonyRecoveryInterval" json:"ceremonyRecoveryInterval" default:"5m"`
+	MaxCeremonyRecoveryAttempts string                 `mapstructure:"MaxCeremonyRecoveryAttempts" json:"maxCeremonyRecoveryAttempts" default:"5"`
+	EnableCeremonyRetrigger     bool                   `mapstructure:"EnableCeremonyRetrigger" json:"enableCeremonyRetrigger"`
+	MaxConcurrentProcesses      string                 `mapstructure:"MaxConcurrentProcesses" json:"maxConcurrentProcesses" default:"10"`
+	RequireSignedMessages       bool                   `mapstructure:"RequireSignedMessages" json:"requireSignedMessages"`
+	DNSCacheTTL                 string                 `mapstructure:"DNSCacheTTL" json:"dnsCacheTTL" default:"5m"`
+	EnableTreeBroadcast         bool                   `mapstructure:"EnableTreeBroadcast" json:"enableTreeBroadcast"`
+	TreeBroadcastFanout         string                 `mapstructure:"TreeBroadcastFanout" json:"treeBroadcastFanout" default:"4"`
+	MessageLimits               RawMessageLimitsConfig `mapstructure:"MessageLimits" json:"messageLimits"`
+	NATTraversal                NATTraversalConfig     `mapstructure:"NATTraversal" json:"natTraversal"`
+	SessionRecordPath           string                 `mapstructure:"SessionRecordPath" json:"sessionRecordPath"`
+}
+
+type RawMessageLimitsConfig struct {
+	// MaxMessageSize is the maximal size in bytes of received messages of types without a specific limit
+	MaxMessageSize string `mapstructure:"MaxMessageSize" json:"maxMessageSize" default:"67108864"`
+	// MaxMessageSizes maps message type names to their maximal size in bytes
+	MaxMessageSizes map[string]uint64 `mapstructure:"MaxMessageSizes" json:"maxMessageSizes"`
+	// RateLimit is the number of messages per second received from a single peer
+	RateLimit          string `mapstructure:"RateLimit" json:"rateLimit" default:"200"`
+	RateBurst          string `mapstructure:"RateBurst" json:"rateBurst" default:"1000"`
+	MaxPendingMessages string `mapstructure:"MaxPendingMessages" json:"maxPendingMessages" default:"100"`
+	DeliveryTimeout    string `mapstructure:"DeliveryTimeout" json:"deliveryTimeout" default:"1m"`
 }
 
 type RawBullyConfig struct {
-	PingWaitTime     string `mapstructure:"PingWaitTime" json:"pingWaitTime" default:"1s"`
-	PingBackOff      string `mapstructure:"PingBackOff" json:"pingBackOff" default:"1s"`
-	PingInterval     string `mapstructure:"PingInterval" json:"pingInterval" default:"1s"`
-	ElectionWaitTime string `mapstructure:"ElectionWaitTime" json:"electionWaitTime" default:"2s"`
-	BullyWaitTime    string `mapstructure:"BullyWaitTime" json:"bullyWaitTime" default:"3m"`
+	PingWaitTime       string `mapstructure:"PingWaitTime" json:"pingWaitTime" default:"1s"`
+	PingBackOff        string `mapstructure:"PingBackOff" json:"pingBackOff" default:"1s"`
+	PingInterval       string `mapstructure:"PingInterval" json:"pingInterval" default:"1s"`
+	ElectionWaitTime   string `mapstructure:"ElectionWaitTime" json:"electionWaitTime" default:"2s"`
+	BullyWaitTime      string `mapstructure:"BullyWaitTime" json:"bullyWaitTime" default:"3m"`
+	CoordinatorElector string `mapstructure:"CoordinatorElector" json:"coordinatorElector" default:"static"`
 }
 
 func (c *RawRelayerConfig) Validate() error {
 	if c.MpcConfig.TopologyConfiguration.EncryptionKey == "" {
 		return errors.New("topology configuration encryption key not provided")
 	}
-	if c.MpcConfig.TopologyConfiguration.Url == "" {
+	if c.MpcConfig.TopologyConfiguration.Url == "" && len(c.MpcConfig.TopologyConfiguration.Sources) == 0 {
 		return errors.New("topology configuration url not provided")
 	}
+	if c.MpcConfig.TopologyConfiguration.Quorum > len(c.MpcConfig.TopologyConfiguration.Sources) {
+		return errors.New("topology configuration quorum bigger than the number of sources")
+	}
+	if c.MpcConfig.TopologyConfiguration.GovernanceThreshold > len(c.MpcConfig.TopologyConfiguration.GovernanceKeys) {
+		return errors.New("topology configuration governance threshold bigger than the number of governance keys")
+	}
 	if c.MpcConfig.TopologyConfiguration.Path == "" {
 		return errors.New("topology configuration path not provided")
 	}
@@ -145,6 +228,7 @@ func parseMpcConfig(rawConfig RawRelayerConfig) (MpcRelayerConfig, error) {
 	mpcConfig.Port = uint16(port)
 
 	mpcConfig.TopologyConfiguration = rawConfig.MpcConfig.TopologyConfiguration
+	mpcConfig.NATTraversal = rawConfig.MpcConfig.NATTraversal
 	mpcConfig.KeysharePath = rawConfig.MpcConfig.KeysharePath
 	mpcConfig.FrostKeysharePath = rawConfig.MpcConfig.FrostKeysharePath
 	mpcConfig.Key = rawConfig.MpcConfig.Key
@@ -155,9 +239,90 @@ func parseMpcConfig(rawConfig RawRelayerConfig) (MpcRelayerConfig, error) {
 	}
 	mpcConfig.CommHealthCheckInterval = duration
 
+	ceremonyRecoveryInterval, err := time.ParseDuration(rawConfig.MpcConfig.CeremonyRecoveryInterval)
+	if err != nil {
+		return MpcRelayerConfig{}, fmt.Errorf("unable to parse ceremony recovery interval time: %w", err)
+	}
+	mpcConfig.CeremonyRecoveryInterval = ceremonyRecoveryInterval
+
+	maxCeremonyRecoveryAttempts, err := strconv.ParseUint(rawConfig.MpcConfig.MaxCeremonyRecoveryAttempts, 0, 16)
+	if err != nil {
+		return MpcRelayerConfig{}, fmt.Errorf("unable to parse max ceremony recovery attempts %s", rawConfig.MpcConfig.MaxCeremonyRecoveryAttempts)
+	}
+	mpcConfig.MaxCeremonyRecoveryAttempts = int(maxCeremonyRecoveryAttempts)
+	mpcConfig.EnableCeremonyRetrigger = rawConfig.MpcConfig.EnableCeremonyRetrigger
+
+	maxConcurrentProcesses, err := strconv.ParseUint(rawConfig.MpcConfig.MaxConcurrentProcesses, 0, 16)
+	if err != nil || maxConcurrentProcesses == 0 {
+		return MpcRelayerConfig{}, fmt.Errorf("unable to parse max concurrent processes %s", rawConfig.MpcConfig.MaxConcurrentProcesses)
+	}
+	mpcConfig.MaxConcurrentProcesses = int(maxConcurrentProcesses)
+	mpcConfig.RequireSignedMessages = rawConfig.MpcConfig.RequireSignedMessages
+
+	dnsCacheTTL, err := time.ParseDuration(rawConfig.MpcConfig.DNSCacheTTL)
+	if err != nil {
+		return MpcRelayerConfig{}, fmt.Errorf("unable to parse dns cache ttl: %w", err)
+	}
+	mpcConfig.DNSCacheTTL = dnsCacheTTL
+
+	mpcConfig.EnableTreeBroadcast = rawConfig.MpcConfig.EnableTreeBroadcast
+	treeBroadcastFanout, err := strconv.ParseUint(rawConfig.MpcConfig.TreeBroadcastFanout, 0, 16)
+	if err != nil || treeBroadcastFanout == 0 {
+		return MpcRelayerConfig{}, fmt.Errorf("unable to parse tree broadcast fanout %s", rawConfig.MpcConfig.TreeBroadcastFanout)
+	}
+	mpcConfig.TreeBroadcastFanout = int(treeBroadcastFanout)
+	mpcConfig.SessionRecordPath = rawConfig.MpcConfig.SessionRecordPath
+
+	messageLimits, err := parseMessageLimitsConfig(rawConfig.MpcConfig.MessageLimits)
+	if err != nil {
+		return MpcRelayerConfig{}, err
+	}
+	mpcConfig.MessageLimits = messageLimits
+
 	return mpcConfig, nil
 }
 
+func parseMessageLimitsConfig(rawConfig RawMessageLimitsConfig) (MessageLimitsConfig, error) {
+	maxMessageSize, err := strconv.ParseUint(rawConfig.MaxMessageSize, 0, 32)
+	if err != nil {
+		return MessageLimitsConfig{}, fmt.Errorf("unable to parse max message size: %w", err)
+	}
+
+	maxMessageSizes := make(map[string]int)
+	for msgType, size := range rawConfig.MaxMessageSizes {
+		maxMessageSizes[msgType] = int(size)
+	}
+
+	rateLimit, err := strconv.ParseFloat(rawConfig.RateLimit, 64)
+	if err != nil {
+		return MessageLimitsConfig{}, fmt.Errorf("unable to parse message rate limit: %w", err)
+	}
+
+	rateBurst, err := strconv.ParseUint(rawConfig.RateBurst, 0, 32)
+	if err != nil {
+		return MessageLimitsConfig{}, fmt.Errorf("unable to parse message rate burst: %w", err)
+	}
+
+	maxPendingMessages, err := strconv.ParseUint(rawConfig.MaxPendingMessages, 0, 32)
+	if err != nil || maxPendingMessages == 0 {
+		return MessageLimitsConfig{}, fmt.Errorf("unable to parse max pending messages %s", rawConfig.MaxPendingMessages)
+	}
+
+	deliveryTimeout, err := time.ParseDuration(rawConfig.DeliveryTimeout)
+	if err != nil {
+		return MessageLimitsConfig{}, fmt.Errorf("unable to parse message delivery timeout: %w", err)
+	}
+
+	return MessageLimitsConfig{
+		MaxMessageSize:     int(maxMessageSize),
+		MaxMessageSizes:    maxMessageSizes,
+		RateLimit:          rateLimit,
+		RateBurst:          int(rateBurst),
+		MaxPendingMessages: int(maxPendingMessages),
+		DeliveryTimeout:    deliveryTimeout,
+	}, nil
+}
+
 func parseBullyConfig(rawConfig RawRelayerConfig) (BullyConfig, error) {
 	electionWaitTime, err := time.ParseDuration(rawConfig.BullyConfig.ElectionWaitTime)
 	if err != nil {
@@ -185,10 +350,11 @@ func parseBullyConfig(rawConfig RawRelayerConfig) (BullyConfig, error) {
 	}
 
 	return BullyConfig{
-		PingWaitTime:     pingWaitTime,
-		PingBackOff:      pingBackOff,
-		PingInterval:     pingInterval,
-		ElectionWaitTime: electionWaitTime,
-		BullyWaitTime:    bullyWaitTime,
+		PingWaitTime:       pingWaitTime,
+		PingBackOff:        pingBackOff,
+		PingInterval:       pingInterval,
+		ElectionWaitTime:   electionWaitTime,
+		BullyWaitTime:      bullyWaitTime,
+		CoordinatorElector: rawConfig.BullyConfig.CoordinatorElector,
 	}, nil
 }
@@ -47,6 +47,7 @@ type TestClient interface {
 	SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error)
 	TransactionByHash(ctx context.Context, hash common.Hash) (tx *types.Transaction, isPending bool, err error)
 	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+	CallContext(ctx context.Context, target interface{}, rpcMethod string, args ...interface{}) error
 }
 
 func Test_EVMBtc(t *testing.T) {
@@ -121,7 +122,8 @@ func (s *IntegrationTestSuite) Test_Erc20Deposit_EVM_to_Btc() {
 	s.Equal(len(balanceBefore), 0)
 
 	transactor1 := signAndSend.NewSignAndSendTransactor(s.fabric, s.gasPricer, s.evmClient)
-	bridgeContract1 := bridge.NewBridgeContract(s.evmClient, s.evmConfig.BridgeAddr, transactor1)
+	bridgeContract1, err := bridge.NewBridgeContract(s.evmClient, s.evmConfig.BridgeAddr, transactor1, "")
+	s.Nil(err)
 	erc20DepositData := evm.ConstructErc20DepositData([]byte("bcrt1pja8aknn7te4empmghnyqnrtjqn0lyg5zy3p5jsdp4le930wnpnxsrtd3ht"), amountToDeposit)
 	_, err = bridgeContract1.ExecuteTransaction("deposit", transactor.TransactOptions{Value: s.evmConfig.BasicFee}, uint8(4), s.evmConfig.Erc20LockReleaseResourceID, erc20DepositData, []byte{})
 	s.Nil(err)
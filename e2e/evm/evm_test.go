@@ -43,6 +43,7 @@ type TestClient interface {
 	SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error)
 	TransactionByHash(ctx context.Context, hash common.Hash) (tx *types.Transaction, isPending bool, err error)
 	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+	CallContext(ctx context.Context, target interface{}, rpcMethod string, args ...interface{}) error
 }
 
 // Alice key is used by the relayer, Charlie key is used as admin and depositer
@@ -156,7 +157,8 @@ func (s *IntegrationTestSuite) Test_Erc20Deposit() {
 
 	transactor1 := signAndSend.NewSignAndSendTransactor(s.fabric1, s.gasPricer1, s.client1)
 	erc20Contract1 := erc20.NewERC20Contract(s.client1, s.config1.Erc20Addr, transactor1)
-	bridgeContract1 := bridge.NewBridgeContract(s.client1, s.config1.BridgeAddr, transactor1)
+	bridgeContract1, err := bridge.NewBridgeContract(s.client1, s.config1.BridgeAddr, transactor1, "")
+	s.Nil(err)
 
 	transactor2 := signAndSend.NewSignAndSendTransactor(s.fabric2, s.gasPricer2, s.client2)
 	erc20Contract2 := erc20.NewERC20Contract(s.client2, s.config2.Erc20Addr, transactor2)
@@ -198,7 +200,8 @@ func (s *IntegrationTestSuite) Test_Erc721Deposit() {
 	// erc721 contract for evm1
 	transactor1 := signAndSend.NewSignAndSendTransactor(s.fabric1, s.gasPricer1, s.client1)
 	erc721Contract1 := erc721.NewErc721Contract(s.client1, s.config1.Erc721Addr, transactor1)
-	bridgeContract1 := bridge.NewBridgeContract(s.client1, s.config1.BridgeAddr, transactor1)
+	bridgeContract1, err := bridge.NewBridgeContract(s.client1, s.config1.BridgeAddr, transactor1, "")
+	s.Nil(err)
 
 	// erc721 contract for evm2
 	transactor2 := signAndSend.NewSignAndSendTransactor(s.fabric2, s.gasPricer2, s.client2)
@@ -206,7 +209,7 @@ func (s *IntegrationTestSuite) Test_Erc721Deposit() {
 
 	// Mint token and give approval
 	// This is done here so token only exists on evm1
-	_, err := erc721Contract1.Mint(tokenId, metadata, s.client1.From(), transactor.TransactOptions{})
+	_, err = erc721Contract1.Mint(tokenId, metadata, s.client1.From(), transactor.TransactOptions{})
 	s.Nil(err, "Mint failed")
 	_, err = erc721Contract1.Approve(tokenId, s.config1.Erc721HandlerAddr, transactor.TransactOptions{})
 	s.Nil(err, "Approve failed")
@@ -243,7 +246,8 @@ func (s *IntegrationTestSuite) Test_Erc721Deposit() {
 
 func (s *IntegrationTestSuite) Test_PermissionlessGenericDeposit() {
 	transactor1 := signAndSend.NewSignAndSendTransactor(s.fabric1, s.gasPricer1, s.client1)
-	bridgeContract1 := bridge.NewBridgeContract(s.client1, s.config1.BridgeAddr, transactor1)
+	bridgeContract1, err := bridge.NewBridgeContract(s.client1, s.config1.BridgeAddr, transactor1, "")
+	s.Nil(err)
 
 	byteArrayToHash, _ := substrateTypes.NewI64(int64(rand.Int())).MarshalJSON()
 	hash := substrateTypes.NewHash(byteArrayToHash)
@@ -256,7 +260,7 @@ func (s *IntegrationTestSuite) Test_PermissionlessGenericDeposit() {
 	metadata = append(metadata, common.LeftPadBytes(depositor.Bytes(), 32)...)
 
 	permissionlessGenericDepositData := evm.ConstructPermissionlessGenericDepositData(metadata, []byte(functionSig), contractAddress.Bytes(), depositor.Bytes(), maxFee)
-	_, err := bridgeContract1.ExecuteTransaction("deposit", transactor.TransactOptions{Value: s.config1.BasicFee}, uint8(2), s.config1.PermissionlessGenericResourceID, permissionlessGenericDepositData, []byte{})
+	_, err = bridgeContract1.ExecuteTransaction("deposit", transactor.TransactOptions{Value: s.config1.BasicFee}, uint8(2), s.config1.PermissionlessGenericResourceID, permissionlessGenericDepositData, []byte{})
 	s.Nil(err)
 
 	err = evm.WaitForProposalExecuted(s.client2, s.config2.BridgeAddr)
@@ -268,7 +272,8 @@ func (s *IntegrationTestSuite) Test_RetryDeposit() {
 	amountToMint := big.NewInt(0).Mul(big.NewInt(350), big.NewInt(0).Exp(big.NewInt(10), big.NewInt(18), nil))
 
 	transactor1 := signAndSend.NewSignAndSendTransactor(s.fabric1, s.gasPricer1, s.client1)
-	bridgeContract1 := bridge.NewBridgeContract(s.client1, s.config1.BridgeAddr, transactor1)
+	bridgeContract1, err := bridge.NewBridgeContract(s.client1, s.config1.BridgeAddr, transactor1, "")
+	s.Nil(err)
 
 	transactor2 := signAndSend.NewSignAndSendTransactor(s.fabric2, s.gasPricer2, s.client2)
 	erc20Contract2 := erc20.NewERC20Contract(s.client2, s.config2.Erc20LockReleaseAddr, transactor2)
@@ -313,7 +318,8 @@ func (s *IntegrationTestSuite) Test_MultipleDeposits() {
 	dstAddr := keystore.TestKeyRing.EthereumKeys[keystore.BobKey].CommonAddress()
 
 	transactor1 := signAndSend.NewSignAndSendTransactor(s.fabric1, s.gasPricer1, s.client1)
-	bridgeContract1 := bridge.NewBridgeContract(s.client1, s.config1.BridgeAddr, transactor1)
+	bridgeContract1, err := bridge.NewBridgeContract(s.client1, s.config1.BridgeAddr, transactor1, "")
+	s.Nil(err)
 
 	transactor2 := signAndSend.NewSignAndSendTransactor(s.fabric2, s.gasPricer2, s.client2)
 	erc20Contract2 := erc20.NewERC20Contract(s.client2, s.config2.Erc20Addr, transactor2)
@@ -360,7 +366,8 @@ func (s *IntegrationTestSuite) Test_Erc1155Deposit() {
 	// 1155 contract for evm1
 	transactor1 := signAndSend.NewSignAndSendTransactor(s.fabric1, s.gasPricer1, s.client1)
 	erc1155Contract1 := erc1155.NewErc1155Contract(s.client1, s.config1.Erc1155Addr, transactor1)
-	bridgeContract1 := bridge.NewBridgeContract(s.client1, s.config1.BridgeAddr, transactor1)
+	bridgeContract1, err := bridge.NewBridgeContract(s.client1, s.config1.BridgeAddr, transactor1, "")
+	s.Nil(err)
 
 	// 1155 contract for evm2
 	transactor2 := signAndSend.NewSignAndSendTransactor(s.fabric2, s.gasPricer2, s.client2)
@@ -368,7 +375,7 @@ func (s *IntegrationTestSuite) Test_Erc1155Deposit() {
 
 	// Mint token and give approval
 	// This is done here so token only exists on evm1
-	_, err := erc1155Contract1.Mint(tokenId, amount, []byte{0}, s.client1.From(), txOptions)
+	_, err = erc1155Contract1.Mint(tokenId, amount, []byte{0}, s.client1.From(), txOptions)
 	s.Nil(err, "Mint failed")
 	_, err = erc1155Contract1.Approve(tokenId, s.config1.Erc1155HandlerAddr, txOptions)
 	s.Nil(err, "Approve failed")
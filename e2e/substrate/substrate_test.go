@@ -39,6 +39,7 @@ type TestClient interface {
 	SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error)
 	TransactionByHash(ctx context.Context, hash common.Hash) (tx *types.Transaction, isPending bool, err error)
 	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+	CallContext(ctx context.Context, target interface{}, rpcMethod string, args ...interface{}) error
 }
 
 func Test_EVMSubstrate(t *testing.T) {
@@ -168,7 +169,8 @@ var amountToDeposit = big.NewInt(100000000000000)
 func (s *IntegrationTestSuite) Test_Erc20Deposit_EVM_to_Substrate() {
 	transactor1 := signAndSend.NewSignAndSendTransactor(s.fabric, s.gasPricer, s.evmClient)
 	erc20Contract1 := erc20.NewERC20Contract(s.evmClient, s.evmConfig.Erc20LockReleaseAddr, transactor1)
-	bridgeContract1 := bridge.NewBridgeContract(s.evmClient, s.evmConfig.BridgeAddr, transactor1)
+	bridgeContract1, err := bridge.NewBridgeContract(s.evmClient, s.evmConfig.BridgeAddr, transactor1, "")
+	s.Nil(err)
 
 	senderBalBefore, err := erc20Contract1.GetBalance(s.evmClient.From())
 	s.Nil(err)
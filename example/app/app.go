@@ -22,7 +22,6 @@ import (
 	"github.com/ChainSafe/sygma-relayer/relayer/transfer"
 	propStore "github.com/ChainSafe/sygma-relayer/store"
 	"github.com/ChainSafe/sygma-relayer/tss"
-	"github.com/sygmaprotocol/sygma-core/chains/evm/listener"
 	"github.com/sygmaprotocol/sygma-core/chains/evm/transactor/gas"
 	"github.com/sygmaprotocol/sygma-core/chains/evm/transactor/transaction"
 	coreSubstrate "github.com/sygmaprotocol/sygma-core/chains/substrate"
@@ -35,8 +34,10 @@ import (
 	"github.com/sygmaprotocol/sygma-core/store"
 	"github.com/sygmaprotocol/sygma-core/store/lvldb"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/libp2p/go-libp2p/core/crypto"
+	madns "github.com/multiformats/go-multiaddr-dns"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/viper"
 
@@ -57,15 +58,18 @@ import (
 
 	"github.com/ChainSafe/sygma-relayer/chains/evm/calls/contracts/bridge"
 	"github.com/ChainSafe/sygma-relayer/chains/evm/calls/events"
+	evmClient "github.com/ChainSafe/sygma-relayer/chains/evm/client"
 	"github.com/ChainSafe/sygma-relayer/chains/evm/executor"
+	"github.com/ChainSafe/sygma-relayer/chains/evm/listener"
 	"github.com/ChainSafe/sygma-relayer/chains/evm/listener/depositHandlers"
 	hubEventHandlers "github.com/ChainSafe/sygma-relayer/chains/evm/listener/eventHandlers"
+	"github.com/ChainSafe/sygma-relayer/comm"
 	"github.com/ChainSafe/sygma-relayer/comm/elector"
 	"github.com/ChainSafe/sygma-relayer/comm/p2p"
+	"github.com/ChainSafe/sygma-relayer/comm/recorder"
 	"github.com/ChainSafe/sygma-relayer/config"
 	"github.com/ChainSafe/sygma-relayer/keyshare"
 	"github.com/ChainSafe/sygma-relayer/topology"
-	evmClient "github.com/sygmaprotocol/sygma-core/chains/evm/client"
 )
 
 func Run() error {
@@ -99,16 +103,19 @@ func Run() error {
 	}
 
 	connectionGate := p2p.NewConnectionGate(networkTopology)
-	host, err := p2p.NewHost(priv, networkTopology, connectionGate, configuration.RelayerConfig.MpcConfig.Port)
+	natOpts, err := p2p.NATTraversalOptions(priv, configuration.RelayerConfig.MpcConfig.NATTraversal, networkTopology, connectionGate)
+	if err != nil {
+		panic(err)
+	}
+	host, err := p2p.NewHost(priv, networkTopology, connectionGate, configuration.RelayerConfig.MpcConfig.Port, natOpts...)
 	if err != nil {
 		panic(err)
 	}
 
-	communication := p2p.NewCommunication(host, "p2p/sygma")
-	electorFactory := elector.NewCoordinatorElectorFactory(host, configuration.RelayerConfig.BullyConfig)
-	coordinator := tss.NewCoordinator(host, communication, electorFactory)
 	keyshareStore := keyshare.NewECDSAKeyshareStore(configuration.RelayerConfig.MpcConfig.KeysharePath)
 	frostKeyshareStore := keyshare.NewFrostKeyshareStore(configuration.RelayerConfig.MpcConfig.FrostKeysharePath)
+	ceremonyStore := propStore.NewCeremonyStore(db)
+	blockHashStore := propStore.NewBlockHashStore(db)
 	propStore := propStore.NewPropStore(db)
 
 	// wait until executions are done and then stop further executions before exiting
@@ -133,7 +140,30 @@ func Run() error {
 		panic(err)
 	}
 
+	messageLimiter, err := p2p.NewMessageLimiter(configuration.RelayerConfig.MpcConfig.MessageLimits, sygmaMetrics)
+	panicOnError(err)
+	commConfig := p2p.CommunicationConfig{
+		RequireSignedMessages: configuration.RelayerConfig.MpcConfig.RequireSignedMessages,
+		Limiter:               messageLimiter,
+		Resolver:              p2p.NewAddressResolver(madns.DefaultResolver, configuration.RelayerConfig.MpcConfig.DNSCacheTTL),
+	}
+	var communication comm.Communication = p2p.NewCommunication(host, "p2p/sygma", commConfig)
+	if configuration.RelayerConfig.MpcConfig.EnableTreeBroadcast {
+		communication = p2p.NewTreeBroadcastCommunication(host, "p2p/sygma", commConfig, configuration.RelayerConfig.MpcConfig.TreeBroadcastFanout)
+	}
+	if configuration.RelayerConfig.MpcConfig.SessionRecordPath != "" {
+		communication, err = recorder.NewRecordingCommunication(communication, host.ID(), configuration.RelayerConfig.MpcConfig.SessionRecordPath)
+		panicOnError(err)
+	}
+	healthScorer := elector.NewHealthScorer(host.ID())
+	electorFactory := elector.NewCoordinatorElectorFactory(host, configuration.RelayerConfig.BullyConfig, healthScorer, commConfig)
+	coordinator := tss.NewCoordinator(host, communication, electorFactory)
+	coordinator.ElectorType, err = elector.ParseCoordinatorElectorType(configuration.RelayerConfig.BullyConfig.CoordinatorElector)
+	panicOnError(err)
+
+	scheduler := tss.NewScheduler(coordinator, configuration.RelayerConfig.MpcConfig.MaxConcurrentProcesses, sygmaMetrics)
 	msgChan := make(chan []*message.Message)
+	ceremonyRecoverers := make([]jobs.CeremonyRecoverer, 0)
 	domains := make(map[uint8]relayer.RelayedChain)
 	for _, chainConfig := range configuration.ChainConfigs {
 		switch chainConfig["type"] {
@@ -144,8 +174,12 @@ func Run() error {
 				kp, err := secp256k1.NewKeypairFromString(config.GeneralChainConfig.Key)
 				panicOnError(err)
 
-				client, err := evmClient.NewEVMClient(config.GeneralChainConfig.Endpoint, kp)
+				client, err := evmClient.NewEVMClient(*config.GeneralChainConfig.Id, config.Endpoints, kp, config.MaxHeadLag, sygmaMetrics)
 				panicOnError(err)
+				client.WrapLogFetchers(func(endpoint string, fetcher evmClient.LogFetcher) evmClient.LogFetcher {
+					return events.NewRangeFetcher(fetcher, *config.GeneralChainConfig.Id, endpoint, config.MaxLogRange, events.LimitRecoveryInterval)
+				})
+				go client.Monitor(ctx, config.HealthCheckInterval)
 
 				log.Info().Str("domain", config.String()).Msgf("Registering EVM domain")
 
@@ -157,7 +191,8 @@ func Run() error {
 				})
 				t := monitored.NewMonitoredTransactor(*config.GeneralChainConfig.Id, transaction.NewTransaction, gasPricer, sygmaMetrics, client, config.MaxGasPrice, config.GasIncreasePercentage)
 				go t.Monitor(ctx, time.Minute*3, time.Minute*10, time.Minute)
-				bridgeContract := bridge.NewBridgeContract(client, bridgeAddress, t)
+				bridgeContract, err := bridge.NewBridgeContract(client, bridgeAddress, t, config.BridgeVersion)
+				panicOnError(err)
 
 				depositHandler := depositHandlers.NewETHDepositHandler(bridgeContract)
 				for _, handler := range config.Handlers {
@@ -180,26 +215,54 @@ func Run() error {
 						}
 					}
 				}
-				depositListener := events.NewListener(client)
-				tssListener := events.NewListener(client)
+				var depositVerifier events.LogVerifier
+				if config.DepositQuorum > 0 {
+					receiptFetchers := make([]events.ReceiptFetcher, 0)
+					for _, c := range client.EndpointClients() {
+						receiptFetchers = append(receiptFetchers, c)
+					}
+					depositVerifier, err = events.NewQuorumVerifier(*config.GeneralChainConfig.Id, receiptFetchers, config.DepositQuorum, sygmaMetrics)
+					panicOnError(err)
+				}
+				finality, err := evmClient.NewFinality(client, config.Finality, config.BlockConfirmations)
+				panicOnError(err)
+				depositListener := events.NewListener(client, client, depositVerifier)
+				tssListener := events.NewListener(client, client, nil)
 				eventHandlers := make([]listener.EventHandler, 0)
 				l := log.With().Str("chain", fmt.Sprintf("%v", config.GeneralChainConfig.Name)).Uint8("domainID", *config.GeneralChainConfig.Id)
 
-				depositEventHandler := hubEventHandlers.NewDepositEventHandler(depositListener, depositHandler, bridgeAddress, *config.GeneralChainConfig.Id, msgChan)
+				depositTracker, err := chains.NewDepositTracker(*config.GeneralChainConfig.Id, config.ReorgDepth, propStore, blockHashStore)
+				panicOnError(err)
+				depositEventHandler := hubEventHandlers.NewDepositEventHandler(depositListener, depositHandler, bridgeAddress, *config.GeneralChainConfig.Id, msgChan, depositTracker, config.BlockInterval)
 				eventHandlers = append(eventHandlers, depositEventHandler)
-				eventHandlers = append(eventHandlers, hubEventHandlers.NewKeygenEventHandler(l, tssListener, coordinator, host, communication, keyshareStore, bridgeAddress, networkTopology.Threshold))
-				eventHandlers = append(eventHandlers, hubEventHandlers.NewFrostKeygenEventHandler(l, tssListener, coordinator, host, communication, frostKeyshareStore, frostAddress, networkTopology.Threshold))
-				eventHandlers = append(eventHandlers, hubEventHandlers.NewRefreshEventHandler(l, nil, nil, tssListener, coordinator, host, communication, connectionGate, keyshareStore, frostKeyshareStore, bridgeAddress))
+				keygenEventHandler := hubEventHandlers.NewKeygenEventHandler(l, tssListener, scheduler, host, communication, keyshareStore, ceremonyStore, bridgeAddress, *config.GeneralChainConfig.Id, networkTopology.Threshold)
+				frostKeygenEventHandler := hubEventHandlers.NewFrostKeygenEventHandler(l, tssListener, scheduler, host, communication, frostKeyshareStore, ceremonyStore, frostAddress, *config.GeneralChainConfig.Id, networkTopology.Threshold)
+				refreshEventHandler := hubEventHandlers.NewRefreshEventHandler(l, nil, nil, tssListener, scheduler, host, communication, connectionGate, keyshareStore, frostKeyshareStore, ceremonyStore, bridgeAddress, *config.GeneralChainConfig.Id)
+				ceremonyRecoverers = append(ceremonyRecoverers, keygenEventHandler, frostKeygenEventHandler, refreshEventHandler)
+				eventHandlers = append(eventHandlers, keygenEventHandler)
+				eventHandlers = append(eventHandlers, frostKeygenEventHandler)
+				eventHandlers = append(eventHandlers, refreshEventHandler)
 				eventHandlers = append(eventHandlers, hubEventHandlers.NewRetryV1EventHandler(l, tssListener, depositHandler, propStore, bridgeAddress, *config.GeneralChainConfig.Id, config.BlockConfirmations, msgChan))
 				if config.Retry != "" {
 					eventHandlers = append(eventHandlers, hubEventHandlers.NewRetryV2EventHandler(l, tssListener, common.HexToAddress(config.Retry), *config.GeneralChainConfig.Id, msgChan))
 				}
-				evmListener := listener.NewEVMListener(client, eventHandlers, blockstore, sygmaMetrics, *config.GeneralChainConfig.Id, config.BlockRetryInterval, config.BlockConfirmations, config.BlockInterval)
+				evmListener := listener.NewEVMListener(client, finality, eventHandlers, blockstore, blockHashStore, sygmaMetrics, *config.GeneralChainConfig.Id, config.BlockRetryInterval, config.BlockInterval, config.ReorgDepth)
+				if config.DepositSubscription {
+					depositQuery := ethereum.FilterQuery{
+						Addresses: []common.Address{bridgeAddress},
+						Topics:    [][]common.Hash{{events.DepositSig.GetTopic()}},
+					}
+					go listener.NewLogSubscription(client, finality, depositEventHandler, depositQuery, *config.GeneralChainConfig.Id, config.BlockRetryInterval).Listen(ctx)
+				}
 
 				mh := message.NewMessageHandler()
-				mh.RegisterMessageHandler(retry.RetryMessageType, executor.NewRetryMessageHandler(depositEventHandler, client, propStore, config.BlockConfirmations, msgChan))
+				mh.RegisterMessageHandler(retry.RetryMessageType, executor.NewRetryMessageHandler(depositEventHandler, finality, propStore, msgChan))
 				mh.RegisterMessageHandler(transfer.TransferMessageType, &executor.TransferMessageHandler{})
-				executor := executor.NewExecutor(host, communication, coordinator, bridgeContract, keyshareStore, exitLock, config.GasLimit.Uint64(), config.TransferGas)
+				evmExecutor := executor.NewExecutor(*config.GeneralChainConfig.Id, host, communication, scheduler, bridgeContract, client, propStore, keyshareStore, exitLock, config.GasLimit.Uint64(), config.TransferGas, config.SubmissionBackOff, sygmaMetrics)
+				var proposalExecutor coreEvm.ProposalExecutor = evmExecutor
+				if config.AggregationWindow > 0 {
+					proposalExecutor = executor.NewProposalAggregator(evmExecutor, client, config.AggregationWindow, config.AggregationDelay, config.AggregationMaxProposals)
+				}
 
 				startBlock, err := blockstore.GetStartBlock(*config.GeneralChainConfig.Id, config.StartBlock, config.GeneralChainConfig.LatestBlock, config.GeneralChainConfig.FreshStart)
 				if err != nil {
@@ -216,7 +279,7 @@ func Run() error {
 				if err != nil {
 					panic(err)
 				}
-				chain := coreEvm.NewEVMChain(evmListener, mh, executor, *config.GeneralChainConfig.Id, startBlock)
+				chain := coreEvm.NewEVMChain(evmListener, mh, proposalExecutor, *config.GeneralChainConfig.Id, startBlock)
 
 				domains[*config.GeneralChainConfig.Id] = chain
 			}
@@ -237,7 +300,7 @@ func Run() error {
 				}
 
 				substrateClient := substrateClient.NewSubstrateClient(conn, &keyPair, config.ChainID, config.Tip)
-				bridgePallet := substratePallet.NewPallet(substrateClient)
+				bridgePallet := substratePallet.NewPallet(substrateClient, config.BridgeVersion, config.VerifyingContract)
 
 				log.Info().Str("domain", config.String()).Msgf("Registering substrate domain")
 
@@ -254,7 +317,7 @@ func Run() error {
 				mh.RegisterMessageHandler(transfer.TransferMessageType, &substrateExecutor.SubstrateMessageHandler{})
 				mh.RegisterMessageHandler(retry.RetryMessageType, substrateExecutor.NewRetryMessageHandler(depositEventHandler, conn, propStore, msgChan))
 
-				sExecutor := substrateExecutor.NewExecutor(host, communication, coordinator, bridgePallet, keyshareStore, conn, exitLock)
+				sExecutor := substrateExecutor.NewExecutor(*config.GeneralChainConfig.Id, host, communication, scheduler, bridgePallet, propStore, keyshareStore, conn, exitLock, config.SubmissionBackOff, sygmaMetrics)
 
 				startBlock, err := blockstore.GetStartBlock(*config.GeneralChainConfig.Id, config.StartBlock, config.GeneralChainConfig.LatestBlock, config.GeneralChainConfig.FreshStart)
 				if err != nil {
@@ -298,10 +361,12 @@ func Run() error {
 					resources[resource.ResourceID] = resource
 				}
 				depositHandler := &btcListener.BtcDepositHandler{}
-				depositEventHandler := btcListener.NewFungibleTransferEventHandler(l, *config.GeneralChainConfig.Id, depositHandler, msgChan, conn, resources, config.FeeAddress)
+				depositTracker, err := chains.NewDepositTracker(*config.GeneralChainConfig.Id, config.ReorgDepth, propStore, blockHashStore)
+				panicOnError(err)
+				depositEventHandler := btcListener.NewFungibleTransferEventHandler(l, *config.GeneralChainConfig.Id, depositHandler, msgChan, conn, resources, config.FeeAddress, depositTracker)
 				eventHandlers := make([]btcListener.EventHandler, 0)
 				eventHandlers = append(eventHandlers, depositEventHandler)
-				listener := btcListener.NewBtcListener(conn, eventHandlers, config, blockstore)
+				listener := btcListener.NewBtcListener(conn, eventHandlers, config, blockstore, blockHashStore)
 
 				mempool := mempool.NewMempoolAPI(config.MempoolUrl)
 
@@ -313,7 +378,7 @@ func Run() error {
 					propStore,
 					host,
 					communication,
-					coordinator,
+					scheduler,
 					frostKeyshareStore,
 					conn,
 					mempool,
@@ -331,7 +396,11 @@ func Run() error {
 		}
 	}
 
-	go jobs.StartCommunicationHealthCheckJob(host, configuration.RelayerConfig.MpcConfig.CommHealthCheckInterval, sygmaMetrics)
+	healthComm := p2p.NewCommunication(host, "p2p/health", commConfig)
+	go jobs.StartCommunicationHealthCheckJob(host, healthComm, configuration.RelayerConfig.MpcConfig.CommHealthCheckInterval, sygmaMetrics, healthScorer)
+
+	ceremonyRecoveryJob := jobs.NewCeremonyRecoveryJob(host, healthComm, ceremonyStore, ceremonyRecoverers, configuration.RelayerConfig.MpcConfig.CeremonyRecoveryInterval, configuration.RelayerConfig.MpcConfig.MaxCeremonyRecoveryAttempts)
+	go ceremonyRecoveryJob.Start()
 	r := relayer.NewRelayer(domains, sygmaMetrics)
 
 	go r.Start(ctx, msgChan)
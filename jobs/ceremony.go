@@ -0,0 +1,311 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package jobs
+
+import (
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/ChainSafe/sygma-relayer/store"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/rs/zerolog/log"
+)
+
+type CeremonyStorer interface {
+	StoreCeremony(ceremony store.Ceremony) error
+	Ceremonies() ([]store.Ceremony, error)
+}
+
+type CeremonyRecoverer interface {
+	CeremonyType() store.CeremonyType
+	DomainID() uint8
+	RecoverCeremony(ceremony store.Ceremony) error
+}
+
+// RecoverySessionID is the session used to exchange ceremony recovery requests
+const RecoverySessionID = "ceremony-recovery"
+
+type recovererKey struct {
+	domainID     uint8
+	ceremonyType store.CeremonyType
+}
+
+// CeremonyRecoveryJob re-runs keygen and resharing ceremonies that failed or were
+// interrupted by a restart, once all peers from the peerstore are reachable again.
+// The relayer recovering a ceremony coordinates the recovery by requesting all peers
+// to re-run the ceremony with the same session ID, including peers that completed it.
+// Ceremonies are recovered at most max attempts times and ceremonies older than
+// a completed ceremony of the same type are dropped as superseded.
+type CeremonyRecoveryJob struct {
+	h             host.Host
+	communication comm.Communication
+	ceremonyStore CeremonyStorer
+	recoverers    map[recovererKey]CeremonyRecoverer
+	interval      time.Duration
+	maxAttempts   int
+
+	runningLock sync.Mutex
+	running     map[string]bool
+}
+
+func NewCeremonyRecoveryJob(
+	h host.Host,
+	healthComm comm.Communication,
+	ceremonyStore CeremonyStorer,
+	recoverers []CeremonyRecoverer,
+	interval time.Duration,
+	maxAttempts int,
+) *CeremonyRecoveryJob {
+	recovererMap := make(map[recovererKey]CeremonyRecoverer)
+	for _, recoverer := range recoverers {
+		recovererMap[recovererKey{
+			domainID:     recoverer.DomainID(),
+			ceremonyType: recoverer.CeremonyType(),
+		}] = recoverer
+	}
+
+	return &CeremonyRecoveryJob{
+		h:             h,
+		communication: healthComm,
+		ceremonyStore: ceremonyStore,
+		recoverers:    recovererMap,
+		interval:      interval,
+		maxAttempts:   maxAttempts,
+		running:       make(map[string]bool),
+	}
+}
+
+// Start marks ceremonies left pending by a previous run as failed, joins recoveries
+// requested by peers and periodically retries failed ceremonies when all peers are available.
+func (j *CeremonyRecoveryJob) Start() {
+	ceremonies, err := j.ceremonyStore.Ceremonies()
+	if err != nil {
+		log.Err(err).Msg("Failed fetching stored ceremonies")
+	}
+	for _, ceremony := range ceremonies {
+		if ceremony.Status != store.PendingCeremony {
+			continue
+		}
+
+		log.Warn().Str("SessionID", ceremony.SessionID).Msgf("Ceremony interrupted by restart")
+		ceremony.Status = store.FailedCeremony
+		err = j.ceremonyStore.StoreCeremony(ceremony)
+		if err != nil {
+			log.Err(err).Str("SessionID", ceremony.SessionID).Msg("Failed storing ceremony")
+		}
+	}
+
+	go j.listenForRecoveryRequests()
+	for {
+		time.Sleep(j.interval)
+		j.RecoverFailedCeremonies()
+	}
+}
+
+func (j *CeremonyRecoveryJob) listenForRecoveryRequests() {
+	msgChan := make(chan *comm.WrappedMessage)
+	subID := j.communication.Subscribe(RecoverySessionID, comm.CeremonyRecoveryMsg, msgChan)
+	defer j.communication.UnSubscribe(subID)
+
+	for msg := range msgChan {
+		err := j.JoinRecovery(string(msg.Payload))
+		if err != nil {
+			log.Warn().Err(err).Str("SessionID", string(msg.Payload)).Msgf("Rejected ceremony recovery request from %s", msg.From)
+		}
+	}
+}
+
+// JoinRecovery re-runs the stored ceremony with the session ID requested by a peer.
+// Completed ceremonies are re-run as well, so the ceremony can be finished by all parties.
+func (j *CeremonyRecoveryJob) JoinRecovery(sessionID string) error {
+	ceremonies, err := j.ceremonyStore.Ceremonies()
+	if err != nil {
+		return err
+	}
+
+	for _, ceremony := range ceremonies {
+		if ceremony.SessionID != sessionID {
+			continue
+		}
+		if ceremony.Status == store.SupersededCeremony {
+			return fmt.Errorf("ceremony %s superseded", sessionID)
+		}
+
+		log.Info().Str("SessionID", sessionID).Msgf("Joining %s ceremony recovery", ceremony.Type)
+		go j.recoverCeremony(ceremony, false)
+		return nil
+	}
+	return fmt.Errorf("no ceremony found for session %s", sessionID)
+}
+
+// Retrigger re-runs stored ceremonies started by an event on the provided block
+// on all peers. Retriggered ceremonies get a fresh set of recovery attempts.
+func (j *CeremonyRecoveryJob) Retrigger(block *big.Int) error {
+	ceremonies, err := j.ceremonyStore.Ceremonies()
+	if err != nil {
+		return err
+	}
+
+	retriggered := false
+	for _, ceremony := range ceremonies {
+		if ceremony.Block == nil || ceremony.Block.Cmp(block) != 0 {
+			continue
+		}
+		if ceremony.Status == store.PendingCeremony {
+			return fmt.Errorf("ceremony %s already pending", ceremony.SessionID)
+		}
+
+		retriggered = true
+		ceremony.Attempts = 0
+		go j.recoverCeremony(ceremony, true)
+	}
+	if !retriggered {
+		return fmt.Errorf("no ceremony found for block %s", block)
+	}
+
+	return nil
+}
+
+// HandleRetrigger is an admin endpoint that re-triggers ceremonies for the block
+// provided in the block query parameter
+func (j *CeremonyRecoveryJob) HandleRetrigger(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	block, ok := new(big.Int).SetString(r.URL.Query().Get("block"), 10)
+	if !ok {
+		http.Error(w, "invalid block", http.StatusBadRequest)
+		return
+	}
+
+	err := j.Retrigger(block)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	_, _ = w.Write([]byte("ok"))
+}
+
+// RecoverFailedCeremonies re-runs failed ceremonies that are not superseded
+// and have recovery attempts left if all peers are available
+func (j *CeremonyRecoveryJob) RecoverFailedCeremonies() {
+	ceremonies, err := j.ceremonyStore.Ceremonies()
+	if err != nil {
+		log.Err(err).Msg("Failed fetching stored ceremonies")
+		return
+	}
+
+	latestCompleted := make(map[recovererKey]*big.Int)
+	for _, ceremony := range ceremonies {
+		if ceremony.Status != store.CompletedCeremony || ceremony.Block == nil {
+			continue
+		}
+		key := recovererKey{domainID: ceremony.DomainID, ceremonyType: ceremony.Type}
+		if latest, ok := latestCompleted[key]; !ok || ceremony.Block.Cmp(latest) > 0 {
+			latestCompleted[key] = ceremony.Block
+		}
+	}
+
+	failedCeremonies := make([]store.Ceremony, 0)
+	for _, ceremony := range ceremonies {
+		if ceremony.Status != store.FailedCeremony {
+			continue
+		}
+
+		latest, ok := latestCompleted[recovererKey{domainID: ceremony.DomainID, ceremonyType: ceremony.Type}]
+		if ok && ceremony.Block != nil && ceremony.Block.Cmp(latest) < 0 {
+			log.Info().Str("SessionID", ceremony.SessionID).Msgf("Dropping ceremony superseded by ceremony from block %s", latest)
+			ceremony.Status = store.SupersededCeremony
+			err = j.ceremonyStore.StoreCeremony(ceremony)
+			if err != nil {
+				log.Err(err).Str("SessionID", ceremony.SessionID).Msg("Failed storing ceremony")
+			}
+			continue
+		}
+		if ceremony.Attempts >= j.maxAttempts {
+			log.Debug().Str("SessionID", ceremony.SessionID).Msgf("Ceremony reached max recovery attempts")
+			continue
+		}
+
+		failedCeremonies = append(failedCeremonies, ceremony)
+	}
+	if len(failedCeremonies) == 0 {
+		return
+	}
+
+	communicationErrors := comm.ExecuteCommHealthCheck(j.communication, j.h.Peerstore().Peers())
+	if len(communicationErrors) > 0 {
+		log.Info().Msgf("Skipping ceremony recovery as %d peers are unavailable", len(communicationErrors))
+		return
+	}
+
+	for _, ceremony := range failedCeremonies {
+		j.recoverCeremony(ceremony, true)
+	}
+}
+
+// recoverCeremony re-runs the ceremony unless it is already running. If the relayer
+// coordinates the recovery, peers are requested to re-run the ceremony as well.
+func (j *CeremonyRecoveryJob) recoverCeremony(ceremony store.Ceremony, coordinate bool) {
+	recoverer, ok := j.recoverers[recovererKey{domainID: ceremony.DomainID, ceremonyType: ceremony.Type}]
+	if !ok {
+		log.Warn().Str("SessionID", ceremony.SessionID).Msgf("No recoverer registered for ceremony type %s on domain %d", ceremony.Type, ceremony.DomainID)
+		return
+	}
+	if !j.startRunning(ceremony.SessionID) {
+		log.Debug().Str("SessionID", ceremony.SessionID).Msgf("Ceremony recovery already running")
+		return
+	}
+	defer j.stopRunning(ceremony.SessionID)
+
+	if coordinate {
+		err := j.requestRecovery(ceremony.SessionID, j.h.Peerstore().Peers())
+		if err != nil {
+			log.Err(err).Str("SessionID", ceremony.SessionID).Msg("Failed requesting ceremony recovery from peers")
+			return
+		}
+	}
+
+	ceremony.Attempts++
+	err := j.ceremonyStore.StoreCeremony(ceremony)
+	if err != nil {
+		log.Err(err).Str("SessionID", ceremony.SessionID).Msg("Failed storing ceremony")
+		return
+	}
+
+	log.Info().Str("SessionID", ceremony.SessionID).Msgf("Recovering %s ceremony, attempt %d", ceremony.Type, ceremony.Attempts)
+	err = recoverer.RecoverCeremony(ceremony)
+	if err != nil {
+		log.Err(err).Str("SessionID", ceremony.SessionID).Msgf("Failed recovering ceremony")
+	}
+}
+
+func (j *CeremonyRecoveryJob) requestRecovery(sessionID string, peers peer.IDSlice) error {
+	return j.communication.Broadcast(peers, []byte(sessionID), comm.CeremonyRecoveryMsg, RecoverySessionID)
+}
+
+func (j *CeremonyRecoveryJob) startRunning(sessionID string) bool {
+	j.runningLock.Lock()
+	defer j.runningLock.Unlock()
+
+	if j.running[sessionID] {
+		return false
+	}
+	j.running[sessionID] = true
+	return true
+}
+
+func (j *CeremonyRecoveryJob) stopRunning(sessionID string) {
+	j.runningLock.Lock()
+	defer j.runningLock.Unlock()
+
+	delete(j.running, sessionID)
+}
@@ -0,0 +1,289 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package jobs_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/sygma-relayer/comm"
+	mock_communication "github.com/ChainSafe/sygma-relayer/comm/mock"
+	mock_host "github.com/ChainSafe/sygma-relayer/comm/p2p/mock/host"
+	"github.com/ChainSafe/sygma-relayer/jobs"
+	mock_jobs "github.com/ChainSafe/sygma-relayer/jobs/mock"
+	"github.com/ChainSafe/sygma-relayer/store"
+	"github.com/golang/mock/gomock"
+	"github.com/libp2p/go-libp2p/p2p/host/peerstore/pstoremem"
+	"github.com/stretchr/testify/suite"
+)
+
+type CeremonyRecoveryJobTestSuite struct {
+	suite.Suite
+	job                       *jobs.CeremonyRecoveryJob
+	mockHost                  *mock_host.MockHost
+	mockCommunication         *mock_communication.MockCommunication
+	mockCeremonyStorer        *mock_jobs.MockCeremonyStorer
+	mockFirstDomainRecoverer  *mock_jobs.MockCeremonyRecoverer
+	mockSecondDomainRecoverer *mock_jobs.MockCeremonyRecoverer
+}
+
+func TestRunCeremonyRecoveryJobTestSuite(t *testing.T) {
+	suite.Run(t, new(CeremonyRecoveryJobTestSuite))
+}
+
+func (s *CeremonyRecoveryJobTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.mockHost = mock_host.NewMockHost(ctrl)
+	s.mockCommunication = mock_communication.NewMockCommunication(ctrl)
+	s.mockCeremonyStorer = mock_jobs.NewMockCeremonyStorer(ctrl)
+	s.mockFirstDomainRecoverer = mock_jobs.NewMockCeremonyRecoverer(ctrl)
+	s.mockFirstDomainRecoverer.EXPECT().DomainID().Return(uint8(1))
+	s.mockFirstDomainRecoverer.EXPECT().CeremonyType().Return(store.ECDSAResharingCeremony)
+	s.mockSecondDomainRecoverer = mock_jobs.NewMockCeremonyRecoverer(ctrl)
+	s.mockSecondDomainRecoverer.EXPECT().DomainID().Return(uint8(2))
+	s.mockSecondDomainRecoverer.EXPECT().CeremonyType().Return(store.ECDSAResharingCeremony)
+
+	s.job = jobs.NewCeremonyRecoveryJob(
+		s.mockHost,
+		s.mockCommunication,
+		s.mockCeremonyStorer,
+		[]jobs.CeremonyRecoverer{s.mockFirstDomainRecoverer, s.mockSecondDomainRecoverer},
+		time.Minute,
+		3,
+	)
+}
+
+func (s *CeremonyRecoveryJobTestSuite) expectHealthCheck() {
+	ps, err := pstoremem.NewPeerstore()
+	s.Nil(err)
+	s.mockHost.EXPECT().Peerstore().Return(ps)
+	s.mockCommunication.EXPECT().CloseSession("health-session")
+}
+
+func (s *CeremonyRecoveryJobTestSuite) expectRecoveryRequest(sessionID string) {
+	ps, err := pstoremem.NewPeerstore()
+	s.Nil(err)
+	s.mockHost.EXPECT().Peerstore().Return(ps)
+	s.mockCommunication.EXPECT().Broadcast(gomock.Any(), []byte(sessionID), comm.CeremonyRecoveryMsg, jobs.RecoverySessionID).Return(nil)
+}
+
+func (s *CeremonyRecoveryJobTestSuite) Test_RecoverFailedCeremonies_RecoversWithDomainRecoverer() {
+	ceremony := store.Ceremony{
+		SessionID: "resharing-10",
+		Type:      store.ECDSAResharingCeremony,
+		DomainID:  2,
+		Block:     big.NewInt(10),
+		Threshold: 2,
+		Status:    store.FailedCeremony,
+		Attempts:  1,
+	}
+	s.mockCeremonyStorer.EXPECT().Ceremonies().Return([]store.Ceremony{ceremony}, nil)
+	s.expectHealthCheck()
+	recoveredCeremony := ceremony
+	recoveredCeremony.Attempts = 2
+	s.expectRecoveryRequest("resharing-10")
+	s.mockCeremonyStorer.EXPECT().StoreCeremony(recoveredCeremony).Return(nil)
+	s.mockSecondDomainRecoverer.EXPECT().RecoverCeremony(recoveredCeremony).Return(nil)
+
+	s.job.RecoverFailedCeremonies()
+}
+
+func (s *CeremonyRecoveryJobTestSuite) Test_RecoverFailedCeremonies_MaxAttemptsReached() {
+	ceremony := store.Ceremony{
+		SessionID: "resharing-10",
+		Type:      store.ECDSAResharingCeremony,
+		DomainID:  1,
+		Block:     big.NewInt(10),
+		Status:    store.FailedCeremony,
+		Attempts:  3,
+	}
+	s.mockCeremonyStorer.EXPECT().Ceremonies().Return([]store.Ceremony{ceremony}, nil)
+
+	s.job.RecoverFailedCeremonies()
+}
+
+func (s *CeremonyRecoveryJobTestSuite) Test_RecoverFailedCeremonies_SupersededCeremonyDropped() {
+	failedCeremony := store.Ceremony{
+		SessionID: "resharing-10",
+		Type:      store.ECDSAResharingCeremony,
+		DomainID:  1,
+		Block:     big.NewInt(10),
+		Status:    store.FailedCeremony,
+	}
+	completedCeremony := store.Ceremony{
+		SessionID: "resharing-20",
+		Type:      store.ECDSAResharingCeremony,
+		DomainID:  1,
+		Block:     big.NewInt(20),
+		Status:    store.CompletedCeremony,
+	}
+	s.mockCeremonyStorer.EXPECT().Ceremonies().Return([]store.Ceremony{failedCeremony, completedCeremony}, nil)
+	supersededCeremony := failedCeremony
+	supersededCeremony.Status = store.SupersededCeremony
+	s.mockCeremonyStorer.EXPECT().StoreCeremony(supersededCeremony).Return(nil)
+
+	s.job.RecoverFailedCeremonies()
+}
+
+func (s *CeremonyRecoveryJobTestSuite) Test_RecoverFailedCeremonies_CompletedCeremonyOnOtherDomain() {
+	failedCeremony := store.Ceremony{
+		SessionID: "resharing-10",
+		Type:      store.ECDSAResharingCeremony,
+		DomainID:  1,
+		Block:     big.NewInt(10),
+		Threshold: 2,
+		Status:    store.FailedCeremony,
+	}
+	completedCeremony := store.Ceremony{
+		SessionID: "resharing-20",
+		Type:      store.ECDSAResharingCeremony,
+		DomainID:  2,
+		Block:     big.NewInt(20),
+		Status:    store.CompletedCeremony,
+	}
+	s.mockCeremonyStorer.EXPECT().Ceremonies().Return([]store.Ceremony{failedCeremony, completedCeremony}, nil)
+	s.expectHealthCheck()
+	recoveredCeremony := failedCeremony
+	recoveredCeremony.Attempts = 1
+	s.expectRecoveryRequest("resharing-10")
+	s.mockCeremonyStorer.EXPECT().StoreCeremony(recoveredCeremony).Return(nil)
+	s.mockFirstDomainRecoverer.EXPECT().RecoverCeremony(recoveredCeremony).Return(errors.New("error"))
+
+	s.job.RecoverFailedCeremonies()
+}
+
+func (s *CeremonyRecoveryJobTestSuite) Test_Retrigger_NoCeremonyForBlock() {
+	s.mockCeremonyStorer.EXPECT().Ceremonies().Return([]store.Ceremony{{
+		SessionID: "resharing-10",
+		Type:      store.ECDSAResharingCeremony,
+		Block:     big.NewInt(10),
+		Status:    store.FailedCeremony,
+	}}, nil)
+
+	err := s.job.Retrigger(big.NewInt(11))
+
+	s.NotNil(err)
+}
+
+func (s *CeremonyRecoveryJobTestSuite) Test_Retrigger_PendingCeremony() {
+	s.mockCeremonyStorer.EXPECT().Ceremonies().Return([]store.Ceremony{{
+		SessionID: "resharing-10",
+		Type:      store.ECDSAResharingCeremony,
+		Block:     big.NewInt(10),
+		Status:    store.PendingCeremony,
+	}}, nil)
+
+	err := s.job.Retrigger(big.NewInt(10))
+
+	s.NotNil(err)
+}
+
+func (s *CeremonyRecoveryJobTestSuite) Test_Retrigger_ResetsAttempts() {
+	ceremony := store.Ceremony{
+		SessionID: "resharing-10",
+		Type:      store.ECDSAResharingCeremony,
+		DomainID:  1,
+		Block:     big.NewInt(10),
+		Threshold: 2,
+		Status:    store.FailedCeremony,
+		Attempts:  3,
+	}
+	s.mockCeremonyStorer.EXPECT().Ceremonies().Return([]store.Ceremony{ceremony}, nil)
+	recoveredCeremony := ceremony
+	recoveredCeremony.Attempts = 1
+	s.expectRecoveryRequest("resharing-10")
+	s.mockCeremonyStorer.EXPECT().StoreCeremony(recoveredCeremony).Return(nil)
+	recovered := make(chan struct{})
+	s.mockFirstDomainRecoverer.EXPECT().RecoverCeremony(recoveredCeremony).DoAndReturn(func(ceremony store.Ceremony) error {
+		close(recovered)
+		return nil
+	})
+
+	err := s.job.Retrigger(big.NewInt(10))
+
+	s.Nil(err)
+	select {
+	case <-recovered:
+	case <-time.After(time.Second):
+		s.Fail("ceremony not retriggered")
+	}
+}
+
+func (s *CeremonyRecoveryJobTestSuite) Test_RecoverFailedCeremonies_RecoveryRequestFails() {
+	ceremony := store.Ceremony{
+		SessionID: "resharing-10",
+		Type:      store.ECDSAResharingCeremony,
+		DomainID:  2,
+		Block:     big.NewInt(10),
+		Threshold: 2,
+		Status:    store.FailedCeremony,
+	}
+	s.mockCeremonyStorer.EXPECT().Ceremonies().Return([]store.Ceremony{ceremony}, nil)
+	s.expectHealthCheck()
+	ps, err := pstoremem.NewPeerstore()
+	s.Nil(err)
+	s.mockHost.EXPECT().Peerstore().Return(ps)
+	s.mockCommunication.EXPECT().Broadcast(gomock.Any(), []byte("resharing-10"), comm.CeremonyRecoveryMsg, jobs.RecoverySessionID).Return(errors.New("error"))
+
+	s.job.RecoverFailedCeremonies()
+}
+
+func (s *CeremonyRecoveryJobTestSuite) Test_JoinRecovery_CompletedCeremonyRerun() {
+	ceremony := store.Ceremony{
+		SessionID: "resharing-10",
+		Type:      store.ECDSAResharingCeremony,
+		DomainID:  1,
+		Block:     big.NewInt(10),
+		Threshold: 2,
+		Status:    store.CompletedCeremony,
+	}
+	s.mockCeremonyStorer.EXPECT().Ceremonies().Return([]store.Ceremony{ceremony}, nil)
+	recoveredCeremony := ceremony
+	recoveredCeremony.Attempts = 1
+	s.mockCeremonyStorer.EXPECT().StoreCeremony(recoveredCeremony).Return(nil)
+	recovered := make(chan store.Ceremony, 1)
+	s.mockFirstDomainRecoverer.EXPECT().RecoverCeremony(recoveredCeremony).DoAndReturn(func(ceremony store.Ceremony) error {
+		recovered <- ceremony
+		return nil
+	})
+
+	err := s.job.JoinRecovery("resharing-10")
+
+	s.Nil(err)
+	select {
+	case <-recovered:
+	case <-time.After(time.Second):
+		s.Fail("ceremony not recovered")
+	}
+}
+
+func (s *CeremonyRecoveryJobTestSuite) Test_JoinRecovery_UnknownSession() {
+	s.mockCeremonyStorer.EXPECT().Ceremonies().Return([]store.Ceremony{{
+		SessionID: "resharing-10",
+		Type:      store.ECDSAResharingCeremony,
+		DomainID:  1,
+		Block:     big.NewInt(10),
+		Status:    store.FailedCeremony,
+	}}, nil)
+
+	err := s.job.JoinRecovery("resharing-11")
+
+	s.NotNil(err)
+}
+
+func (s *CeremonyRecoveryJobTestSuite) Test_JoinRecovery_SupersededCeremony() {
+	s.mockCeremonyStorer.EXPECT().Ceremonies().Return([]store.Ceremony{{
+		SessionID: "resharing-10",
+		Type:      store.ECDSAResharingCeremony,
+		DomainID:  1,
+		Block:     big.NewInt(10),
+		Status:    store.SupersededCeremony,
+	}}, nil)
+
+	err := s.job.JoinRecovery("resharing-10")
+
+	s.NotNil(err)
+}
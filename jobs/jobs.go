@@ -7,7 +7,6 @@ import (
 	"time"
 
 	"github.com/ChainSafe/sygma-relayer/comm"
-	"github.com/ChainSafe/sygma-relayer/comm/p2p"
 	"github.com/libp2p/go-libp2p/core/host"
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/rs/zerolog/log"
@@ -17,8 +16,9 @@ type RelayerStatusMeter interface {
 	TrackRelayerStatus(unavailable peer.IDSlice, all peer.IDSlice)
 }
 
-func StartCommunicationHealthCheckJob(h host.Host, interval time.Duration, metrics RelayerStatusMeter) {
-	healthComm := p2p.NewCommunication(h, "p2p/health")
+func StartCommunicationHealthCheckJob(
+	h host.Host, healthComm comm.Communication, interval time.Duration, metrics RelayerStatusMeter, tracker comm.PeerHealthTracker,
+) {
 	for {
 		time.Sleep(interval)
 		log.Debug().Msg("Starting communication health check")
@@ -26,7 +26,7 @@ func StartCommunicationHealthCheckJob(h host.Host, interval time.Duration, metri
 		all := h.Peerstore().Peers()
 		unavailable := make(peer.IDSlice, 0)
 
-		communicationErrors := comm.ExecuteCommHealthCheck(healthComm, h.Peerstore().Peers())
+		communicationErrors := comm.ExecuteTrackedCommHealthCheck(healthComm, h.Peerstore().Peers(), tracker)
 		for _, cerr := range communicationErrors {
 			log.Err(cerr).Msg("communication error on ExecuteCommHealthCheck")
 			unavailable = append(unavailable, cerr.Peer)
@@ -0,0 +1,129 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./jobs/ceremony.go
+
+// Package mock_jobs is a generated GoMock package.
+package mock_jobs
+
+import (
+	reflect "reflect"
+
+	store "github.com/ChainSafe/sygma-relayer/store"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCeremonyStorer is a mock of CeremonyStorer interface.
+type MockCeremonyStorer struct {
+	ctrl     *gomock.Controller
+	recorder *MockCeremonyStorerMockRecorder
+}
+
+// MockCeremonyStorerMockRecorder is the mock recorder for MockCeremonyStorer.
+type MockCeremonyStorerMockRecorder struct {
+	mock *MockCeremonyStorer
+}
+
+// NewMockCeremonyStorer creates a new mock instance.
+func NewMockCeremonyStorer(ctrl *gomock.Controller) *MockCeremonyStorer {
+	mock := &MockCeremonyStorer{ctrl: ctrl}
+	mock.recorder = &MockCeremonyStorerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCeremonyStorer) EXPECT() *MockCeremonyStorerMockRecorder {
+	return m.recorder
+}
+
+// Ceremonies mocks base method.
+func (m *MockCeremonyStorer) Ceremonies() ([]store.Ceremony, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ceremonies")
+	ret0, _ := ret[0].([]store.Ceremony)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Ceremonies indicates an expected call of Ceremonies.
+func (mr *MockCeremonyStorerMockRecorder) Ceremonies() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ceremonies", reflect.TypeOf((*MockCeremonyStorer)(nil).Ceremonies))
+}
+
+// StoreCeremony mocks base method.
+func (m *MockCeremonyStorer) StoreCeremony(ceremony store.Ceremony) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreCeremony", ceremony)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StoreCeremony indicates an expected call of StoreCeremony.
+func (mr *MockCeremonyStorerMockRecorder) StoreCeremony(ceremony interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreCeremony", reflect.TypeOf((*MockCeremonyStorer)(nil).StoreCeremony), ceremony)
+}
+
+// MockCeremonyRecoverer is a mock of CeremonyRecoverer interface.
+type MockCeremonyRecoverer struct {
+	ctrl     *gomock.Controller
+	recorder *MockCeremonyRecovererMockRecorder
+}
+
+// MockCeremonyRecovererMockRecorder is the mock recorder for MockCeremonyRecoverer.
+type MockCeremonyRecovererMockRecorder struct {
+	mock *MockCeremonyRecoverer
+}
+
+// NewMockCeremonyRecoverer creates a new mock instance.
+func NewMockCeremonyRecoverer(ctrl *gomock.Controller) *MockCeremonyRecoverer {
+	mock := &MockCeremonyRecoverer{ctrl: ctrl}
+	mock.recorder = &MockCeremonyRecovererMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCeremonyRecoverer) EXPECT() *MockCeremonyRecovererMockRecorder {
+	return m.recorder
+}
+
+// CeremonyType mocks base method.
+func (m *MockCeremonyRecoverer) CeremonyType() store.CeremonyType {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CeremonyType")
+	ret0, _ := ret[0].(store.CeremonyType)
+	return ret0
+}
+
+// CeremonyType indicates an expected call of CeremonyType.
+func (mr *MockCeremonyRecovererMockRecorder) CeremonyType() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CeremonyType", reflect.TypeOf((*MockCeremonyRecoverer)(nil).CeremonyType))
+}
+
+// DomainID mocks base method.
+func (m *MockCeremonyRecoverer) DomainID() uint8 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DomainID")
+	ret0, _ := ret[0].(uint8)
+	return ret0
+}
+
+// DomainID indicates an expected call of DomainID.
+func (mr *MockCeremonyRecovererMockRecorder) DomainID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DomainID", reflect.TypeOf((*MockCeremonyRecoverer)(nil).DomainID))
+}
+
+// RecoverCeremony mocks base method.
+func (m *MockCeremonyRecoverer) RecoverCeremony(ceremony store.Ceremony) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecoverCeremony", ceremony)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecoverCeremony indicates an expected call of RecoverCeremony.
+func (mr *MockCeremonyRecovererMockRecorder) RecoverCeremony(ceremony interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecoverCeremony", reflect.TypeOf((*MockCeremonyRecoverer)(nil).RecoverCeremony), ceremony)
+}
@@ -0,0 +1,81 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package metrics
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	api "go.opentelemetry.io/otel/metric"
+)
+
+type ExecutorMetrics struct {
+	opts api.MeasurementOption
+
+	proposalSubmissionsCounter        api.Int64Counter
+	skippedProposalSubmissionsCounter api.Int64Counter
+	wastedProposalSubmissionsCounter  api.Int64Counter
+}
+
+// NewExecutorMetrics initializes metrics related to proposal submissions
+func NewExecutorMetrics(ctx context.Context, meter api.Meter, opts api.MeasurementOption) (*ExecutorMetrics, error) {
+	proposalSubmissionsCounter, err := meter.Int64Counter(
+		"relayer.ProposalSubmissions",
+		api.WithDescription("Number of proposal execution submissions"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	skippedProposalSubmissionsCounter, err := meter.Int64Counter(
+		"relayer.SkippedProposalSubmissions",
+		api.WithDescription("Number of fallback submissions skipped because proposals were already executed"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	wastedProposalSubmissionsCounter, err := meter.Int64Counter(
+		"relayer.WastedProposalSubmissions",
+		api.WithDescription("Number of failed proposal execution submissions"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExecutorMetrics{
+		opts:                              opts,
+		proposalSubmissionsCounter:        proposalSubmissionsCounter,
+		skippedProposalSubmissionsCounter: skippedProposalSubmissionsCounter,
+		wastedProposalSubmissionsCounter:  wastedProposalSubmissionsCounter,
+	}, nil
+}
+
+// TrackProposalSubmission tracks proposal submissions by the designated or fallback submitter
+func (m *ExecutorMetrics) TrackProposalSubmission(domainID uint8, fallback bool) {
+	m.proposalSubmissionsCounter.Add(
+		context.Background(),
+		1,
+		m.opts,
+		api.WithAttributes(attribute.Int64("domainID", int64(domainID)), attribute.Bool("fallback", fallback)),
+	)
+}
+
+// TrackSkippedProposalSubmission tracks fallback submissions skipped because proposals were executed
+func (m *ExecutorMetrics) TrackSkippedProposalSubmission(domainID uint8) {
+	m.skippedProposalSubmissionsCounter.Add(
+		context.Background(),
+		1,
+		m.opts,
+		api.WithAttributes(attribute.Int64("domainID", int64(domainID))),
+	)
+}
+
+// TrackWastedProposalSubmission tracks proposal submissions that failed
+func (m *ExecutorMetrics) TrackWastedProposalSubmission(domainID uint8) {
+	m.wastedProposalSubmissionsCounter.Add(
+		context.Background(),
+		1,
+		m.opts,
+		api.WithAttributes(attribute.Int64("domainID", int64(domainID))),
+	)
+}
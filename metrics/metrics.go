@@ -15,6 +15,8 @@ type SygmaMetrics struct {
 	*observability.RelayerMetrics
 	*MpcMetrics
 	*HostMetrics
+	*ExecutorMetrics
+	*RPCMetrics
 }
 
 // NewSygmaMetrics creates an instance of metrics
@@ -36,9 +38,21 @@ func NewSygmaMetrics(ctx context.Context, meter api.Meter, env, relayerID, versi
 		return nil, err
 	}
 
+	executorMetrics, err := NewExecutorMetrics(ctx, meter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcMetrics, err := NewRPCMetrics(ctx, meter, opts)
+	if err != nil {
+		return nil, err
+	}
+
 	return &SygmaMetrics{
-		RelayerMetrics: relayerMetrics,
-		MpcMetrics:     mpcMetrics,
-		HostMetrics:    hostMetrics,
+		RelayerMetrics:  relayerMetrics,
+		MpcMetrics:      mpcMetrics,
+		HostMetrics:     hostMetrics,
+		ExecutorMetrics: executorMetrics,
+		RPCMetrics:      rpcMetrics,
 	}, nil
 }
@@ -3,22 +3,29 @@ package metrics
 import (
 	"context"
 
+	"github.com/ChainSafe/sygma-relayer/comm"
 	"github.com/libp2p/go-libp2p/core/peer"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
 	api "go.opentelemetry.io/otel/metric"
 )
 
 type MpcMetrics struct {
+	opts                   api.MeasurementOption
 	totalRelayersGauge     api.Int64ObservableGauge
 	availableRelayersGauge api.Int64ObservableGauge
+	tssQueueDepthGauge     api.Int64ObservableGauge
+	droppedMessagesCounter api.Int64Counter
 	totalRelayerCount      *int64
 	availableRelayerCount  *int64
+	tssQueueDepth          *int64
 }
 
 // NewMpcMetrics initializes metrics related to the MPC set
 func NewMpcMetrics(ctx context.Context, meter metric.Meter, opts metric.MeasurementOption) (*MpcMetrics, error) {
 	totalRelayerCount := new(int64)
 	availableRelayerCount := new(int64)
+	tssQueueDepth := new(int64)
 	totalRelayersGauge, err := meter.Int64ObservableGauge(
 		"relayer.TotalRelayers",
 		api.WithInt64Callback(func(context context.Context, result api.Int64Observer) error {
@@ -41,12 +48,34 @@ func NewMpcMetrics(ctx context.Context, meter metric.Meter, opts metric.Measurem
 	if err != nil {
 		return nil, err
 	}
+	tssQueueDepthGauge, err := meter.Int64ObservableGauge(
+		"relayer.TssQueueDepth",
+		api.WithInt64Callback(func(context context.Context, result api.Int64Observer) error {
+			result.Observe(*tssQueueDepth, opts)
+			return nil
+		}),
+		api.WithDescription("Number of tss processes waiting for execution"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	droppedMessagesCounter, err := meter.Int64Counter(
+		"relayer.DroppedP2PMessages",
+		api.WithDescription("Number of p2p messages dropped because of limits or failed verification"),
+	)
+	if err != nil {
+		return nil, err
+	}
 
 	return &MpcMetrics{
+		opts:                   opts,
 		totalRelayersGauge:     totalRelayersGauge,
 		availableRelayersGauge: availableRelayersGauge,
+		tssQueueDepthGauge:     tssQueueDepthGauge,
+		droppedMessagesCounter: droppedMessagesCounter,
 		totalRelayerCount:      totalRelayerCount,
 		availableRelayerCount:  availableRelayerCount,
+		tssQueueDepth:          tssQueueDepth,
 	}, nil
 }
 
@@ -54,3 +83,16 @@ func (m *MpcMetrics) TrackRelayerStatus(unavailable peer.IDSlice, all peer.IDSli
 	*m.totalRelayerCount = int64(len(all))
 	*m.availableRelayerCount = int64(len(all) - len(unavailable))
 }
+
+func (m *MpcMetrics) TrackTssQueueDepth(depth int) {
+	*m.tssQueueDepth = int64(depth)
+}
+
+func (m *MpcMetrics) TrackDroppedMessage(msgType comm.MessageType, reason string) {
+	m.droppedMessagesCounter.Add(
+		context.Background(),
+		1,
+		m.opts,
+		api.WithAttributes(attribute.String("type", msgType.String())),
+		api.WithAttributes(attribute.String("reason", reason)))
+}
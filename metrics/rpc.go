@@ -0,0 +1,85 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package metrics
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	api "go.opentelemetry.io/otel/metric"
+)
+
+type RPCMetrics struct {
+	opts api.MeasurementOption
+
+	rpcErrorsCounter         api.Int64Counter
+	rpcFailoversCounter      api.Int64Counter
+	depositMismatchesCounter api.Int64Counter
+}
+
+// NewRPCMetrics initializes metrics related to chain RPC endpoints
+func NewRPCMetrics(ctx context.Context, meter api.Meter, opts api.MeasurementOption) (*RPCMetrics, error) {
+	rpcErrorsCounter, err := meter.Int64Counter(
+		"relayer.RPCErrors",
+		api.WithDescription("Number of failed calls per RPC endpoint"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	rpcFailoversCounter, err := meter.Int64Counter(
+		"relayer.RPCFailovers",
+		api.WithDescription("Number of fail overs to the RPC endpoint"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	depositMismatchesCounter, err := meter.Int64Counter(
+		"relayer.DepositMismatches",
+		api.WithDescription("Security alert of deposit logs or receipts that RPC endpoints do not agree on"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RPCMetrics{
+		opts:                     opts,
+		rpcErrorsCounter:         rpcErrorsCounter,
+		rpcFailoversCounter:      rpcFailoversCounter,
+		depositMismatchesCounter: depositMismatchesCounter,
+	}, nil
+}
+
+// TrackRPCError tracks failed calls of the RPC endpoint
+func (m *RPCMetrics) TrackRPCError(domainID uint8, endpoint string, method string) {
+	m.rpcErrorsCounter.Add(
+		context.Background(),
+		1,
+		m.opts,
+		api.WithAttributes(
+			attribute.Int64("domainID", int64(domainID)),
+			attribute.String("endpoint", endpoint),
+			attribute.String("method", method)),
+	)
+}
+
+// TrackRPCFailover tracks fail overs to the RPC endpoint
+func (m *RPCMetrics) TrackRPCFailover(domainID uint8, endpoint string) {
+	m.rpcFailoversCounter.Add(
+		context.Background(),
+		1,
+		m.opts,
+		api.WithAttributes(attribute.Int64("domainID", int64(domainID)), attribute.String("endpoint", endpoint)),
+	)
+}
+
+// TrackDepositMismatch tracks deposit logs or receipts that RPC endpoints do not agree on
+func (m *RPCMetrics) TrackDepositMismatch(domainID uint8) {
+	m.depositMismatchesCounter.Add(
+		context.Background(),
+		1,
+		m.opts,
+		api.WithAttributes(attribute.Int64("domainID", int64(domainID))),
+	)
+}
@@ -57,6 +57,9 @@ func FilterDeposits(
 				continue
 			}
 
+			// retried deposits are marked so their signing is prioritized over new deposits
+			data.Retried = true
+			deposit.Data = data
 			filteredDeposits = append(filteredDeposits, deposit)
 		}
 	}
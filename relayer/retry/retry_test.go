@@ -94,6 +94,7 @@ func (s *FilterDepositsTestSuite) Test_FilterDeposits() {
 			},
 		},
 		{
+			ID:          "3-4-100-100",
 			Source:      invalidDomain,
 			Destination: validDomain,
 			Data: transfer.TransferMessageData{
@@ -110,6 +111,7 @@ func (s *FilterDepositsTestSuite) Test_FilterDeposits() {
 			},
 		},
 		{
+			ID:          "3-4-101-101",
 			Source:      invalidDomain,
 			Destination: validDomain,
 			Data: transfer.TransferMessageData{
@@ -128,19 +130,23 @@ func (s *FilterDepositsTestSuite) Test_FilterDeposits() {
 
 	expectedDeposits := []*message.Message{
 		{
+			ID:          "3-4-100-100",
 			Source:      invalidDomain,
 			Destination: validDomain,
 			Data: transfer.TransferMessageData{
 				DepositNonce: failedNonce,
 				ResourceId:   validResource,
+				Retried:      true,
 			},
 		},
 		{
+			ID:          "3-4-101-101",
 			Source:      invalidDomain,
 			Destination: validDomain,
 			Data: transfer.TransferMessageData{
 				DepositNonce: pendingNonce,
 				ResourceId:   validResource,
+				Retried:      true,
 			},
 		},
 	}
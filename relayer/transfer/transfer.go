@@ -4,6 +4,8 @@
 package transfer
 
 import (
+	"time"
+
 	"github.com/sygmaprotocol/sygma-core/relayer/message"
 	"github.com/sygmaprotocol/sygma-core/relayer/proposal"
 )
@@ -24,6 +26,10 @@ type TransferMessageData struct {
 	Metadata     map[string]interface{}
 	Payload      []interface{}
 	Type         TransferType
+	// BlockHash is the hash of the source block that contains the deposit
+	BlockHash string
+	// Retried is set for retried deposits so their signing is prioritized over new deposits
+	Retried bool
 }
 
 const (
@@ -37,6 +43,7 @@ type TransferMessage struct {
 	Data        TransferMessageData
 	Type        message.MessageType
 	ID          string
+	Timestamp   time.Time
 }
 
 type TransferProposalData struct {
@@ -44,6 +51,12 @@ type TransferProposalData struct {
 	ResourceId   [32]byte
 	Metadata     map[string]interface{}
 	Data         []byte
+	// Timestamp is the source block timestamp of the deposit
+	Timestamp time.Time
+	// BlockHash is the hash of the source block that contains the deposit
+	BlockHash string
+	// Retried is set for retried deposits so their signing is prioritized over new deposits
+	Retried bool
 }
 
 type TransferProposal struct {
@@ -53,3 +66,24 @@ type TransferProposal struct {
 	Type        proposal.ProposalType
 	MessageID   string
 }
+
+// LatestDepositNonce returns the highest deposit nonce of the proposals
+func LatestDepositNonce(proposals []*TransferProposal) uint64 {
+	var nonce uint64
+	for _, prop := range proposals {
+		if prop.Data.DepositNonce > nonce {
+			nonce = prop.Data.DepositNonce
+		}
+	}
+	return nonce
+}
+
+// IsRetried returns true if any of the proposals was created from a retried deposit
+func IsRetried(proposals []*TransferProposal) bool {
+	for _, prop := range proposals {
+		if prop.Data.Retried {
+			return true
+		}
+	}
+	return false
+}
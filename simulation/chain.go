@@ -0,0 +1,274 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package simulation
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/sygma-relayer/chains/evm/calls/contracts/bridge"
+	"github.com/ChainSafe/sygma-relayer/relayer/transfer"
+	ethCommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/sygmaprotocol/sygma-core/chains/evm/transactor"
+)
+
+// Deposit is a fungible transfer deposited on the source chain
+type Deposit struct {
+	Source       uint8
+	Destination  uint8
+	DepositNonce uint64
+	ResourceID   [32]byte
+	Amount       *big.Int
+	Recipient    []byte
+}
+
+// Block contains deposits made in the same block
+type Block struct {
+	Number    *big.Int
+	Timestamp time.Time
+	Deposits  []*Deposit
+}
+
+type depositKey struct {
+	source       uint8
+	depositNonce uint64
+}
+
+// Execution is a proposal executed on the destination chain bridge
+type Execution struct {
+	Source       uint8
+	DepositNonce uint64
+	ResourceID   [32]byte
+	Data         []byte
+}
+
+// Chain is an in-memory chain that mines a block for each deposit and
+// executes proposals like the bridge contract
+type Chain struct {
+	domainID uint8
+	signer   ethCommon.Address
+
+	lock        sync.Mutex
+	blocks      []*Block
+	nonces      map[uint8]uint64
+	executions  map[depositKey]*Execution
+	results     map[ethCommon.Hash][]*bridge.ProposalExecutionResult
+	submissions int
+	duplicates  int
+}
+
+// NewChain creates an empty chain with the bridge that accepts proposals signed by the signer
+func NewChain(domainID uint8, signer ethCommon.Address) *Chain {
+	return &Chain{
+		domainID:   domainID,
+		signer:     signer,
+		blocks:     make([]*Block, 0),
+		nonces:     make(map[uint8]uint64),
+		executions: make(map[depositKey]*Execution),
+		results:    make(map[ethCommon.Hash][]*bridge.ProposalExecutionResult),
+	}
+}
+
+func (c *Chain) DomainID() uint8 {
+	return c.domainID
+}
+
+// Deposit mines a new block with a deposit to the destination domain
+func (c *Chain) Deposit(destination uint8, amount *big.Int, recipient []byte) *Deposit {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.nonces[destination]++
+	deposit := &Deposit{
+		Source:       c.domainID,
+		Destination:  destination,
+		DepositNonce: c.nonces[destination],
+		ResourceID:   [32]byte{c.domainID},
+		Amount:       amount,
+		Recipient:    recipient,
+	}
+	c.blocks = append(c.blocks, &Block{
+		Number:    big.NewInt(int64(len(c.blocks))),
+		Timestamp: time.Now(),
+		Deposits:  []*Deposit{deposit},
+	})
+	return deposit
+}
+
+// LatestBlock returns the number of the latest mined block
+func (c *Chain) LatestBlock() *big.Int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return big.NewInt(int64(len(c.blocks) - 1))
+}
+
+// Block returns the mined block with the provided number
+func (c *Chain) Block(number *big.Int) (*Block, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if !number.IsInt64() || number.Int64() < 0 || number.Int64() >= int64(len(c.blocks)) {
+		return nil, fmt.Errorf("block %s not found", number)
+	}
+	return c.blocks[number.Int64()], nil
+}
+
+// Deposits returns all deposits made on the chain
+func (c *Chain) Deposits() []*Deposit {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	deposits := make([]*Deposit, 0)
+	for _, block := range c.blocks {
+		deposits = append(deposits, block.Deposits...)
+	}
+	return deposits
+}
+
+// Execution returns the execution of the deposit from the source domain
+// or nil if the deposit was not executed
+func (c *Chain) Execution(source uint8, depositNonce uint64) *Execution {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.executions[depositKey{source: source, depositNonce: depositNonce}]
+}
+
+// Executions returns all proposals executed on the chain
+func (c *Chain) Executions() []*Execution {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	executions := make([]*Execution, 0, len(c.executions))
+	for _, execution := range c.executions {
+		executions = append(executions, execution)
+	}
+	return executions
+}
+
+// Submissions returns the number of accepted execute proposals transactions
+func (c *Chain) Submissions() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.submissions
+}
+
+// Duplicates returns the number of submitted proposals that were already executed
+func (c *Chain) Duplicates() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.duplicates
+}
+
+func (c *Chain) IsProposalExecuted(p *transfer.TransferProposal) (bool, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	_, ok := c.executions[depositKey{source: p.Source, depositNonce: p.Data.DepositNonce}]
+	return ok, nil
+}
+
+func (c *Chain) AreProposalsExecuted(proposals []*transfer.TransferProposal) ([]bool, error) {
+	executed := make([]bool, len(proposals))
+	for i, p := range proposals {
+		executed[i], _ = c.IsProposalExecuted(p)
+	}
+	return executed, nil
+}
+
+// ExecuteProposals verifies the MPC signature and executes proposals that were not executed yet.
+// As on the bridge contract, already executed proposals are skipped instead of reverting.
+func (c *Chain) ExecuteProposals(
+	proposals []*transfer.TransferProposal,
+	signature []byte,
+	opts transactor.TransactOptions,
+) (*ethCommon.Hash, error) {
+	hash, err := c.ProposalsHash(proposals)
+	if err != nil {
+		return nil, err
+	}
+	if len(signature) != crypto.SignatureLength {
+		return nil, fmt.Errorf("invalid signature length %d", len(signature))
+	}
+	sig := make([]byte, len(signature))
+	copy(sig, signature)
+	sig[crypto.RecoveryIDOffset] -= 27
+	pubKey, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		return nil, err
+	}
+	if crypto.PubkeyToAddress(*pubKey) != c.signer {
+		return nil, fmt.Errorf("invalid proposals signer %s", crypto.PubkeyToAddress(*pubKey))
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.submissions++
+	results := make([]*bridge.ProposalExecutionResult, 0)
+	for _, p := range proposals {
+		key := depositKey{source: p.Source, depositNonce: p.Data.DepositNonce}
+		if _, ok := c.executions[key]; ok {
+			c.duplicates++
+			continue
+		}
+
+		c.executions[key] = &Execution{
+			Source:       p.Source,
+			DepositNonce: p.Data.DepositNonce,
+			ResourceID:   p.Data.ResourceId,
+			Data:         p.Data.Data,
+		}
+		results = append(results, &bridge.ProposalExecutionResult{
+			OriginDomainID: p.Source,
+			DepositNonce:   p.Data.DepositNonce,
+			Executed:       true,
+		})
+	}
+
+	txHash := ethCommon.BytesToHash(crypto.Keccak256(hash, signature))
+	c.results[txHash] = results
+	return &txHash, nil
+}
+
+// SimulateProposals always succeeds as simulated handlers never fail
+func (c *Chain) SimulateProposals(proposals []*transfer.TransferProposal, blockNumber *big.Int) ([]*bridge.RevertError, error) {
+	return make([]*bridge.RevertError, len(proposals)), nil
+}
+
+// ProposalExecutionResults returns results of proposals executed in the transaction
+func (c *Chain) ProposalExecutionResults(hash ethCommon.Hash) ([]*bridge.ProposalExecutionResult, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	results, ok := c.results[hash]
+	if !ok {
+		return nil, fmt.Errorf("transaction %s not found", hash)
+	}
+	return results, nil
+}
+
+// ProposalsHash hashes proposals with the destination domain so signatures
+// can not be reused on other chains
+func (c *Chain) ProposalsHash(proposals []*transfer.TransferProposal) ([]byte, error) {
+	data := []byte{c.domainID}
+	for _, p := range proposals {
+		if p.Destination != c.domainID {
+			return nil, fmt.Errorf("proposal destination %d does not match domain %d", p.Destination, c.domainID)
+		}
+
+		data = append(data, p.Source)
+		data = binary.BigEndian.AppendUint64(data, p.Data.DepositNonce)
+		data = append(data, p.Data.ResourceId[:]...)
+		data = append(data, crypto.Keccak256(p.Data.Data)...)
+	}
+	return crypto.Keccak256(data), nil
+}
@@ -0,0 +1,87 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ChainSafe/sygma-relayer/relayer/transfer"
+	"github.com/sygmaprotocol/sygma-core/relayer/message"
+)
+
+// Listener polls the in-memory chain and sends deposits as transfer messages
+// with the same message IDs as the deposit event handler
+type Listener struct {
+	chain         *Chain
+	msgChan       chan []*message.Message
+	blockInterval time.Duration
+}
+
+func NewListener(chain *Chain, msgChan chan []*message.Message, blockInterval time.Duration) *Listener {
+	return &Listener{
+		chain:         chain,
+		msgChan:       msgChan,
+		blockInterval: blockInterval,
+	}
+}
+
+// ListenToEvents polls new blocks starting from the start block until the context is cancelled
+func (l *Listener) ListenToEvents(ctx context.Context, startBlock *big.Int) {
+	ticker := time.NewTicker(l.blockInterval)
+	defer ticker.Stop()
+
+	block := new(big.Int).Set(startBlock)
+	for {
+		select {
+		case <-ticker.C:
+			{
+				for block.Cmp(l.chain.LatestBlock()) <= 0 {
+					b, err := l.chain.Block(block)
+					if err != nil {
+						break
+					}
+
+					for _, msgs := range l.messages(b) {
+						select {
+						case l.msgChan <- msgs:
+						case <-ctx.Done():
+							return
+						}
+					}
+					block.Add(block, big.NewInt(1))
+				}
+			}
+		case <-ctx.Done():
+			{
+				return
+			}
+		}
+	}
+}
+
+// messages groups block deposits by destination domain
+func (l *Listener) messages(block *Block) map[uint8][]*message.Message {
+	domainMsgs := make(map[uint8][]*message.Message)
+	for _, d := range block.Deposits {
+		messageID := fmt.Sprintf("%d-%d-%d-%d", d.Source, d.Destination, block.Number, block.Number)
+		domainMsgs[d.Destination] = append(domainMsgs[d.Destination], message.NewMessage(
+			d.Source,
+			d.Destination,
+			transfer.TransferMessageData{
+				DepositNonce: d.DepositNonce,
+				ResourceId:   d.ResourceID,
+				Metadata:     make(map[string]interface{}),
+				Payload:      []interface{}{d.Amount.Bytes(), d.Recipient},
+				Type:         transfer.FungibleTransfer,
+			},
+			messageID,
+			transfer.TransferMessageType,
+			block.Timestamp,
+		))
+	}
+	return domainMsgs
+}
@@ -0,0 +1,123 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package simulation
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/rs/zerolog/log"
+)
+
+// Fault is injected into messages sent by relayers
+type Fault struct {
+	// DropRate is the probability in range [0, 1] that the message is dropped
+	DropRate float64
+	// Delay is the time the message is held before it is sent
+	Delay time.Duration
+	// MessageTypes limits the fault to messages of provided types, all messages are affected if empty
+	MessageTypes []comm.MessageType
+}
+
+func (f Fault) applies(msgType comm.MessageType) bool {
+	if len(f.MessageTypes) == 0 {
+		return true
+	}
+	for _, t := range f.MessageTypes {
+		if t == msgType {
+			return true
+		}
+	}
+	return false
+}
+
+// Network injects faults into messages sent between relayers.
+// Random drops are seeded so a failing simulation can be reproduced.
+type Network struct {
+	lock   sync.Mutex
+	rand   *rand.Rand
+	faults map[peer.ID]Fault
+}
+
+func NewNetwork(seed int64) *Network {
+	return &Network{
+		rand:   rand.New(rand.NewSource(seed)),
+		faults: make(map[peer.ID]Fault),
+	}
+}
+
+// SetFault injects the fault into messages sent by the peer
+func (n *Network) SetFault(p peer.ID, fault Fault) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.faults[p] = fault
+}
+
+// ClearFaults removes all injected faults
+func (n *Network) ClearFaults() {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.faults = make(map[peer.ID]Fault)
+}
+
+// Communication wraps communication of the peer so faults are injected into broadcasted messages
+func (n *Network) Communication(self peer.ID, communication comm.Communication) comm.Communication {
+	return &FaultyCommunication{
+		Communication: communication,
+		self:          self,
+		network:       n,
+	}
+}
+
+// fault returns if the message should be dropped and the delay before sending it
+func (n *Network) fault(from peer.ID, msgType comm.MessageType) (bool, time.Duration) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	fault, ok := n.faults[from]
+	if !ok || !fault.applies(msgType) {
+		return false, 0
+	}
+	return n.rand.Float64() < fault.DropRate, fault.Delay
+}
+
+// FaultyCommunication drops and delays messages sent to each peer
+// independently according to faults injected into the network
+type FaultyCommunication struct {
+	comm.Communication
+	self    peer.ID
+	network *Network
+}
+
+func (c *FaultyCommunication) Broadcast(peers peer.IDSlice, msg []byte, msgType comm.MessageType, sessionID string) error {
+	var wg sync.WaitGroup
+	errChn := make(chan error, len(peers))
+	for _, p := range peers {
+		drop, delay := c.network.fault(c.self, msgType)
+		if drop {
+			log.Debug().Str("SessionID", sessionID).Msgf("Dropped %s message to %s", msgType, p)
+			continue
+		}
+
+		wg.Add(1)
+		go func(p peer.ID, delay time.Duration) {
+			defer wg.Done()
+
+			time.Sleep(delay)
+			err := c.Communication.Broadcast(peer.IDSlice{p}, msg, msgType, sessionID)
+			if err != nil {
+				errChn <- err
+			}
+		}(p, delay)
+	}
+	wg.Wait()
+	close(errChn)
+
+	return <-errChn
+}
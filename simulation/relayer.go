@@ -0,0 +1,157 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package simulation
+
+import (
+	"context"
+	"math/big"
+	"sync"
+
+	"github.com/ChainSafe/sygma-relayer/chains/evm/executor"
+	"github.com/ChainSafe/sygma-relayer/comm/elector"
+	"github.com/ChainSafe/sygma-relayer/comm/p2p"
+	"github.com/ChainSafe/sygma-relayer/keyshare"
+	"github.com/ChainSafe/sygma-relayer/relayer/transfer"
+	"github.com/ChainSafe/sygma-relayer/store"
+	"github.com/ChainSafe/sygma-relayer/topology"
+	"github.com/ChainSafe/sygma-relayer/tss"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/rs/zerolog/log"
+	coreEvm "github.com/sygmaprotocol/sygma-core/chains/evm"
+	"github.com/sygmaprotocol/sygma-core/relayer"
+	"github.com/sygmaprotocol/sygma-core/relayer/message"
+)
+
+const (
+	transactionMaxGas = 10_000_000
+	transferGasCost   = 200_000
+)
+
+// Relayer runs the complete relayer pipeline from listeners to executors
+// against in-memory chains
+type Relayer struct {
+	Host host.Host
+
+	gate     *p2p.ConnectionGate
+	topology *topology.NetworkTopology
+	relayer  *relayer.Relayer
+	msgChan  chan []*message.Message
+
+	lock    sync.Mutex
+	cancel  context.CancelFunc
+	crashed bool
+}
+
+func newRelayer(
+	h host.Host,
+	gate *p2p.ConnectionGate,
+	networkTopology *topology.NetworkTopology,
+	network *Network,
+	keysharePath string,
+	chains []*Chain,
+	config Config,
+) *Relayer {
+	commConfig := p2p.CommunicationConfig{RequireSignedMessages: true}
+	communication := network.Communication(h.ID(), p2p.NewCommunication(h, "p2p/sygma", commConfig))
+	electorFactory := elector.NewCoordinatorElectorFactory(h, config.BullyConfig, nil, commConfig)
+	coordinator := tss.NewCoordinator(h, communication, electorFactory)
+	coordinator.CoordinatorTimeout = config.CoordinatorTimeout
+	coordinator.TssTimeout = config.TssTimeout
+	coordinator.InitiatePeriod = config.InitiatePeriod
+	scheduler := tss.NewScheduler(coordinator, 10, nil)
+	keyshareStore := keyshare.NewECDSAKeyshareStore(keysharePath)
+	exitLock := &sync.RWMutex{}
+	propStore := store.NewPropStore(newMemoryDB())
+
+	msgChan := make(chan []*message.Message)
+	domains := make(map[uint8]relayer.RelayedChain)
+	for _, c := range chains {
+		mh := message.NewMessageHandler()
+		mh.RegisterMessageHandler(transfer.TransferMessageType, &executor.TransferMessageHandler{})
+		evmExecutor := executor.NewExecutor(c.DomainID(), h, communication, scheduler, c, nil, propStore, keyshareStore, exitLock, transactionMaxGas, transferGasCost, config.SubmissionBackOff, nil)
+		listener := NewListener(c, msgChan, config.BlockInterval)
+		domains[c.DomainID()] = coreEvm.NewEVMChain(listener, mh, evmExecutor, c.DomainID(), big.NewInt(0))
+	}
+
+	return &Relayer{
+		Host:     h,
+		gate:     gate,
+		topology: networkTopology,
+		relayer:  relayer.NewRelayer(domains, &messageTracker{peerID: h.ID()}),
+		msgChan:  msgChan,
+	}
+}
+
+// Start starts listening to chains and relaying deposits
+func (r *Relayer) Start(ctx context.Context) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	ctx, r.cancel = context.WithCancel(ctx)
+	go r.relayer.Start(ctx, r.msgChan)
+}
+
+// Crash stops the relayer and closes its host so other relayers can not reach it
+func (r *Relayer) Crash() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.crashed {
+		return
+	}
+	r.crashed = true
+	if r.cancel != nil {
+		r.cancel()
+	}
+	err := r.Host.Close()
+	if err != nil {
+		log.Err(err).Msgf("Failed closing host of relayer %s", r.Host.ID())
+	}
+}
+
+func (r *Relayer) Crashed() bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.crashed
+}
+
+// isolate allows connections only to provided peers and closes connections to other peers
+func (r *Relayer) isolate(peers peer.IDSlice) {
+	allowed := &topology.NetworkTopology{
+		Peers:     make([]*peer.AddrInfo, 0),
+		Threshold: r.topology.Threshold,
+	}
+	for _, p := range r.topology.Peers {
+		for _, allowedPeer := range peers {
+			if p.ID == allowedPeer {
+				allowed.Peers = append(allowed.Peers, p)
+			}
+		}
+	}
+	r.gate.SetTopology(allowed)
+
+	for _, p := range r.topology.Peers {
+		if p.ID == r.Host.ID() || allowed.IsAllowedPeer(p.ID) {
+			continue
+		}
+		_ = r.Host.Network().ClosePeer(p.ID)
+	}
+}
+
+// heal allows connections to all topology peers
+func (r *Relayer) heal() {
+	r.gate.SetTopology(r.topology)
+}
+
+type messageTracker struct {
+	peerID peer.ID
+}
+
+func (t *messageTracker) TrackMessages(msgs []*message.Message, status message.MessageStatus) {
+	for _, msg := range msgs {
+		log.Debug().Str("messageID", msg.ID).Msgf("Relayer %s message status %s", t.peerID, status)
+	}
+}
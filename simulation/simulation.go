@@ -0,0 +1,276 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+/*
+Package simulation runs multiple complete relayers in a single process against
+in-memory chains. Relayers communicate over real libp2p hosts on localhost while
+the network injects message drops, delays, partitions and crashes so the
+relayer behaviour can be verified under faults.
+*/
+package simulation
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/ChainSafe/sygma-relayer/chains/evm/executor"
+	"github.com/ChainSafe/sygma-relayer/comm/p2p"
+	"github.com/ChainSafe/sygma-relayer/config/relayer"
+	"github.com/ChainSafe/sygma-relayer/keyshare"
+	"github.com/ChainSafe/sygma-relayer/relayer/transfer"
+	"github.com/ChainSafe/sygma-relayer/topology"
+	"github.com/ChainSafe/sygma-relayer/tss/util"
+	"github.com/ethereum/go-ethereum/crypto"
+	libp2pCrypto "github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/sygmaprotocol/sygma-core/relayer/message"
+)
+
+type Config struct {
+	// KeysharePath is the ECDSA keyshare path format of the relayer index
+	KeysharePath string
+	// PrivateKeyPath is the libp2p private key path format of the relayer index
+	PrivateKeyPath string
+	// Relayers is the number of simulated relayers each with its own keyshare
+	Relayers int
+	// Port is the libp2p port of the first relayer, other relayers use consecutive ports
+	Port int
+	// Domains are domain IDs of simulated chains
+	Domains []uint8
+	// Seed seeds random message drops
+	Seed int64
+
+	BlockInterval      time.Duration
+	SubmissionBackOff  time.Duration
+	CoordinatorTimeout time.Duration
+	TssTimeout         time.Duration
+	InitiatePeriod     time.Duration
+	BullyConfig        relayer.BullyConfig
+}
+
+// Simulation wires relayers together with in-memory chains
+type Simulation struct {
+	Network  *Network
+	Relayers []*Relayer
+
+	chains map[uint8]*Chain
+	cancel context.CancelFunc
+}
+
+// NewSimulation creates relayers and chains with a bridge that accepts
+// proposals signed by the MPC key of the relayer keyshares
+func NewSimulation(config Config) (*Simulation, error) {
+	keyshareStore := keyshare.NewECDSAKeyshareStore(fmt.Sprintf(config.KeysharePath, 0))
+	key, err := keyshareStore.GetKeyshare()
+	if err != nil {
+		return nil, err
+	}
+	signer := crypto.PubkeyToAddress(*key.Key.ECDSAPub.ToBtcecPubKey().ToECDSA())
+
+	chains := make(map[uint8]*Chain)
+	chainList := make([]*Chain, 0)
+	for _, domainID := range config.Domains {
+		chain := NewChain(domainID, signer)
+		chains[domainID] = chain
+		chainList = append(chainList, chain)
+	}
+
+	privKeys := make([]libp2pCrypto.PrivKey, config.Relayers)
+	networkTopology := &topology.NetworkTopology{
+		Peers:     make([]*peer.AddrInfo, config.Relayers),
+		Threshold: key.Threshold,
+	}
+	for i := 0; i < config.Relayers; i++ {
+		privBytes, err := os.ReadFile(fmt.Sprintf(config.PrivateKeyPath, i))
+		if err != nil {
+			return nil, err
+		}
+		privKeys[i], err = libp2pCrypto.UnmarshalPrivateKey(privBytes)
+		if err != nil {
+			return nil, err
+		}
+		peerID, err := peer.IDFromPrivateKey(privKeys[i])
+		if err != nil {
+			return nil, err
+		}
+		networkTopology.Peers[i], err = peer.AddrInfoFromString(
+			fmt.Sprintf("/ip4/127.0.0.1/tcp/%d/p2p/%s", config.Port+i, peerID.Pretty()),
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	network := NewNetwork(config.Seed)
+	relayers := make([]*Relayer, config.Relayers)
+	for i := 0; i < config.Relayers; i++ {
+		gate := p2p.NewConnectionGate(networkTopology)
+		h, err := p2p.NewHost(privKeys[i], networkTopology, gate, uint16(config.Port+i))
+		if err != nil {
+			return nil, err
+		}
+		relayers[i] = newRelayer(h, gate, networkTopology, network, fmt.Sprintf(config.KeysharePath, i), chainList, config)
+	}
+
+	return &Simulation{
+		Network:  network,
+		Relayers: relayers,
+		chains:   chains,
+	}, nil
+}
+
+// Start starts all relayers
+func (s *Simulation) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	for _, r := range s.Relayers {
+		r.Start(ctx)
+	}
+}
+
+// Stop stops all relayers and closes their hosts
+func (s *Simulation) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	for _, r := range s.Relayers {
+		r.Crash()
+	}
+}
+
+// Chain returns the chain of the domain
+func (s *Simulation) Chain(domainID uint8) *Chain {
+	return s.chains[domainID]
+}
+
+// Peers returns peer IDs of all relayers
+func (s *Simulation) Peers() peer.IDSlice {
+	peers := make(peer.IDSlice, len(s.Relayers))
+	for i, r := range s.Relayers {
+		peers[i] = r.Host.ID()
+	}
+	return peers
+}
+
+// Crash crashes the relayer with the index
+func (s *Simulation) Crash(i int) {
+	s.Relayers[i].Crash()
+}
+
+// Coordinator returns the index of the relayer statically elected as
+// the coordinator of the first signing session of the deposit
+func (s *Simulation) Coordinator(deposit *Deposit) int {
+	block := s.depositBlock(deposit)
+	sessionID := fmt.Sprintf("%d-%d-%d-%d-%d", deposit.Source, deposit.Destination, block, block, 0)
+	coordinator := util.SortPeersForSession(s.Peers(), sessionID)[0].ID
+	for i, p := range s.Peers() {
+		if p == coordinator {
+			return i
+		}
+	}
+	return -1
+}
+
+// Partition splits relayers into groups of relayer indexes where relayers
+// can connect only to relayers in the same group
+func (s *Simulation) Partition(groups ...[]int) {
+	peers := s.Peers()
+	for _, group := range groups {
+		groupPeers := make(peer.IDSlice, 0)
+		for _, i := range group {
+			groupPeers = append(groupPeers, peers[i])
+		}
+		for _, i := range group {
+			s.Relayers[i].isolate(groupPeers)
+		}
+	}
+}
+
+// Heal removes network partitions
+func (s *Simulation) Heal() {
+	for _, r := range s.Relayers {
+		r.heal()
+	}
+}
+
+// WaitForExecution waits until all deposits are executed and verifies that
+// every deposit was executed exactly once with the deposited transfer
+func (s *Simulation) WaitForExecution(timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		err := s.verifyExecutions()
+		if err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return err
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func (s *Simulation) verifyExecutions() error {
+	expected := make(map[uint8]int)
+	for _, c := range s.chains {
+		for _, d := range c.Deposits() {
+			destination, ok := s.chains[d.Destination]
+			if !ok {
+				return fmt.Errorf("destination domain %d of deposit %d-%d not simulated", d.Destination, d.Source, d.DepositNonce)
+			}
+
+			execution := destination.Execution(d.Source, d.DepositNonce)
+			if execution == nil {
+				return fmt.Errorf("deposit %d-%d not executed on domain %d", d.Source, d.DepositNonce, d.Destination)
+			}
+			data, err := depositData(d)
+			if err != nil {
+				return err
+			}
+			if execution.ResourceID != d.ResourceID || !bytes.Equal(execution.Data, data) {
+				return fmt.Errorf("deposit %d-%d executed with invalid data %x", d.Source, d.DepositNonce, execution.Data)
+			}
+			expected[d.Destination]++
+		}
+	}
+
+	for domainID, c := range s.chains {
+		executions := len(c.Executions())
+		if executions != expected[domainID] {
+			return fmt.Errorf("domain %d executed %d proposals for %d deposits", domainID, executions, expected[domainID])
+		}
+	}
+	return nil
+}
+
+func (s *Simulation) depositBlock(deposit *Deposit) *big.Int {
+	c := s.chains[deposit.Source]
+	for i := big.NewInt(0); i.Cmp(c.LatestBlock()) <= 0; i.Add(i, big.NewInt(1)) {
+		block, _ := c.Block(i)
+		for _, d := range block.Deposits {
+			if d == deposit {
+				return new(big.Int).Set(i)
+			}
+		}
+	}
+	return nil
+}
+
+// depositData returns proposal data the relayer should execute for the deposit
+func depositData(d *Deposit) ([]byte, error) {
+	msg := message.NewMessage(d.Source, d.Destination, transfer.TransferMessageData{
+		DepositNonce: d.DepositNonce,
+		ResourceId:   d.ResourceID,
+		Payload:      []interface{}{d.Amount.Bytes(), d.Recipient},
+		Type:         transfer.FungibleTransfer,
+	}, "", transfer.TransferMessageType, time.Time{})
+	prop, err := (&executor.TransferMessageHandler{}).HandleMessage(msg)
+	if err != nil {
+		return nil, err
+	}
+	return prop.Data.(transfer.TransferProposalData).Data, nil
+}
@@ -0,0 +1,128 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package simulation_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/ChainSafe/sygma-relayer/config/relayer"
+	"github.com/ChainSafe/sygma-relayer/simulation"
+	"github.com/stretchr/testify/suite"
+)
+
+const executionTimeout = 2 * time.Minute
+
+type SimulationTestSuite struct {
+	suite.Suite
+	simulation *simulation.Simulation
+}
+
+func TestRunSimulationTestSuite(t *testing.T) {
+	suite.Run(t, new(SimulationTestSuite))
+}
+
+func (s *SimulationTestSuite) TearDownTest() {
+	if s.simulation != nil {
+		s.simulation.Stop()
+	}
+}
+
+func (s *SimulationTestSuite) setupSimulation(port int) *simulation.Simulation {
+	sim, err := simulation.NewSimulation(simulation.Config{
+		KeysharePath:       "../tss/test/keyshares/%d.keyshare",
+		PrivateKeyPath:     "../tss/test/pks/%d.pk",
+		Relayers:           3,
+		Port:               port,
+		Domains:            []uint8{1, 2},
+		Seed:               1,
+		BlockInterval:      100 * time.Millisecond,
+		SubmissionBackOff:  5 * time.Second,
+		CoordinatorTimeout: 5 * time.Second,
+		TssTimeout:         30 * time.Second,
+		InitiatePeriod:     time.Second,
+		BullyConfig: relayer.BullyConfig{
+			PingWaitTime:     1 * time.Second,
+			PingBackOff:      1 * time.Second,
+			PingInterval:     1 * time.Second,
+			ElectionWaitTime: 2 * time.Second,
+			BullyWaitTime:    25 * time.Second,
+		},
+	})
+	s.Nil(err)
+	s.simulation = sim
+	return sim
+}
+
+func (s *SimulationTestSuite) Test_NoFaults_DepositsExecutedOnce() {
+	sim := s.setupSimulation(4600)
+	sim.Start()
+
+	sim.Chain(1).Deposit(2, big.NewInt(100), []byte{1})
+	sim.Chain(1).Deposit(2, big.NewInt(200), []byte{2})
+	sim.Chain(2).Deposit(1, big.NewInt(300), []byte{3})
+
+	err := sim.WaitForExecution(executionTimeout)
+	s.Nil(err)
+}
+
+func (s *SimulationTestSuite) Test_DroppedAndDelayedMessages_DepositsExecutedOnce() {
+	sim := s.setupSimulation(4610)
+	peers := sim.Peers()
+	for _, p := range peers {
+		sim.Network.SetFault(p, simulation.Fault{
+			Delay: 50 * time.Millisecond,
+		})
+	}
+	// initiate messages are rebroadcasted so dropped initiate and ready messages are recovered
+	sim.Network.SetFault(peers[0], simulation.Fault{
+		DropRate:     0.5,
+		Delay:        50 * time.Millisecond,
+		MessageTypes: []comm.MessageType{comm.TssInitiateMsg, comm.TssReadyMsg},
+	})
+	sim.Start()
+
+	sim.Chain(1).Deposit(2, big.NewInt(100), []byte{1})
+	sim.Chain(1).Deposit(2, big.NewInt(200), []byte{2})
+
+	err := sim.WaitForExecution(executionTimeout)
+	s.Nil(err)
+}
+
+func (s *SimulationTestSuite) Test_PartitionedRelayer_DepositsExecutedOnce() {
+	sim := s.setupSimulation(4620)
+	sim.Start()
+
+	deposit := sim.Chain(1).Deposit(2, big.NewInt(100), []byte{1})
+	partitioned := (sim.Coordinator(deposit) + 1) % len(sim.Relayers)
+	others := []int{}
+	for i := range sim.Relayers {
+		if i != partitioned {
+			others = append(others, i)
+		}
+	}
+	sim.Partition([]int{partitioned}, others)
+
+	err := sim.WaitForExecution(executionTimeout)
+	s.Nil(err)
+
+	sim.Heal()
+	sim.Chain(1).Deposit(2, big.NewInt(200), []byte{2})
+
+	err = sim.WaitForExecution(executionTimeout)
+	s.Nil(err)
+}
+
+func (s *SimulationTestSuite) Test_CrashedCoordinator_DepositsExecutedOnce() {
+	sim := s.setupSimulation(4630)
+	sim.Start()
+
+	deposit := sim.Chain(1).Deposit(2, big.NewInt(100), []byte{1})
+	sim.Crash(sim.Coordinator(deposit))
+
+	err := sim.WaitForExecution(executionTimeout)
+	s.Nil(err)
+}
@@ -0,0 +1,41 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package simulation
+
+import (
+	"sync"
+
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// memoryDB is an in-memory key value store used instead of the relayer database
+type memoryDB struct {
+	lock   sync.Mutex
+	values map[string][]byte
+}
+
+func newMemoryDB() *memoryDB {
+	return &memoryDB{
+		values: make(map[string][]byte),
+	}
+}
+
+func (db *memoryDB) GetByKey(key []byte) ([]byte, error) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	v, ok := db.values[string(key)]
+	if !ok {
+		return nil, leveldb.ErrNotFound
+	}
+	return v, nil
+}
+
+func (db *memoryDB) SetByKey(key []byte, value []byte) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	db.values[string(key)] = value
+	return nil
+}
@@ -0,0 +1,99 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/sygmaprotocol/sygma-core/store"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+var (
+	BLOCK_HASH_KEY       = "chain:%d:block:%s:hash"
+	TRACKED_DEPOSITS_KEY = "chain:%d:trackedDeposits"
+)
+
+// TrackedDeposit is a deposit from one of the most recent blocks that is invalidated
+// if its block gets orphaned
+type TrackedDeposit struct {
+	Block       *big.Int `json:"block"`
+	BlockHash   string   `json:"blockHash"`
+	TxHash      string   `json:"txHash"`
+	Destination uint8    `json:"destination"`
+	Nonce       uint64   `json:"nonce"`
+}
+
+// TrackedDeposits are deposits from the most recent blocks of a domain and deposits
+// invalidated because their blocks were orphaned
+type TrackedDeposits struct {
+	Deposits []TrackedDeposit `json:"deposits"`
+	Orphaned []TrackedDeposit `json:"orphaned"`
+}
+
+// BlockHashStore stores hashes of blocks processed by listeners
+// so chain reorganizations can be detected
+type BlockHashStore struct {
+	db store.KeyValueReaderWriter
+}
+
+func NewBlockHashStore(db store.KeyValueReaderWriter) *BlockHashStore {
+	return &BlockHashStore{
+		db: db,
+	}
+}
+
+// StoreBlockHash stores the hash of the processed block
+func (s *BlockHashStore) StoreBlockHash(domainID uint8, block *big.Int, hash []byte) error {
+	key := fmt.Sprintf(BLOCK_HASH_KEY, domainID, block.String())
+	return s.db.SetByKey([]byte(key), hash)
+}
+
+// BlockHash returns the stored hash of the processed block or nil if the block
+// was not processed
+func (s *BlockHashStore) BlockHash(domainID uint8, block *big.Int) ([]byte, error) {
+	key := fmt.Sprintf(BLOCK_HASH_KEY, domainID, block.String())
+	v, err := s.db.GetByKey([]byte(key))
+	if err != nil {
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// StoreTrackedDeposits stores deposits tracked for reorganizations of the domain
+func (s *BlockHashStore) StoreTrackedDeposits(domainID uint8, deposits TrackedDeposits) error {
+	data, err := json.Marshal(deposits)
+	if err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf(TRACKED_DEPOSITS_KEY, domainID)
+	return s.db.SetByKey([]byte(key), data)
+}
+
+// TrackedDeposits returns stored deposits tracked for reorganizations of the domain
+func (s *BlockHashStore) TrackedDeposits(domainID uint8) (TrackedDeposits, error) {
+	key := fmt.Sprintf(TRACKED_DEPOSITS_KEY, domainID)
+	v, err := s.db.GetByKey([]byte(key))
+	if err != nil {
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return TrackedDeposits{}, nil
+		}
+		return TrackedDeposits{}, err
+	}
+
+	var deposits TrackedDeposits
+	err = json.Unmarshal(v, &deposits)
+	if err != nil {
+		return TrackedDeposits{}, err
+	}
+	return deposits, nil
+}
@@ -0,0 +1,107 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package store_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/sygma-relayer/store"
+	"github.com/stretchr/testify/suite"
+	mock_store "github.com/sygmaprotocol/sygma-core/mock"
+	"github.com/syndtr/goleveldb/leveldb"
+	"go.uber.org/mock/gomock"
+)
+
+type BlockHashStoreTestSuite struct {
+	suite.Suite
+	blockHashStore       *store.BlockHashStore
+	keyValueReaderWriter *mock_store.MockKeyValueReaderWriter
+}
+
+func TestRunBlockHashStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(BlockHashStoreTestSuite))
+}
+
+func (s *BlockHashStoreTestSuite) SetupTest() {
+	gomockController := gomock.NewController(s.T())
+	s.keyValueReaderWriter = mock_store.NewMockKeyValueReaderWriter(gomockController)
+	s.blockHashStore = store.NewBlockHashStore(s.keyValueReaderWriter)
+}
+
+func (s *BlockHashStoreTestSuite) Test_StoreBlockHash() {
+	s.keyValueReaderWriter.EXPECT().SetByKey([]byte("chain:1:block:100:hash"), []byte{1}).Return(nil)
+
+	err := s.blockHashStore.StoreBlockHash(1, big.NewInt(100), []byte{1})
+
+	s.Nil(err)
+}
+
+func (s *BlockHashStoreTestSuite) Test_BlockHash_NotFound() {
+	s.keyValueReaderWriter.EXPECT().GetByKey([]byte("chain:1:block:100:hash")).Return(nil, leveldb.ErrNotFound)
+
+	hash, err := s.blockHashStore.BlockHash(1, big.NewInt(100))
+
+	s.Nil(err)
+	s.Nil(hash)
+}
+
+func (s *BlockHashStoreTestSuite) Test_BlockHash_FailedFetch() {
+	s.keyValueReaderWriter.EXPECT().GetByKey([]byte("chain:1:block:100:hash")).Return(nil, errors.New("error"))
+
+	_, err := s.blockHashStore.BlockHash(1, big.NewInt(100))
+
+	s.NotNil(err)
+}
+
+func (s *BlockHashStoreTestSuite) Test_BlockHash_SuccessfulFetch() {
+	s.keyValueReaderWriter.EXPECT().GetByKey([]byte("chain:1:block:100:hash")).Return([]byte{1}, nil)
+
+	hash, err := s.blockHashStore.BlockHash(1, big.NewInt(100))
+
+	s.Nil(err)
+	s.Equal([]byte{1}, hash)
+}
+
+func (s *BlockHashStoreTestSuite) Test_TrackedDeposits_NotFound() {
+	s.keyValueReaderWriter.EXPECT().GetByKey([]byte("chain:1:trackedDeposits")).Return(nil, leveldb.ErrNotFound)
+
+	deposits, err := s.blockHashStore.TrackedDeposits(1)
+
+	s.Nil(err)
+	s.Equal(store.TrackedDeposits{}, deposits)
+}
+
+func (s *BlockHashStoreTestSuite) Test_TrackedDeposits_StoredDepositsFetched() {
+	deposits := store.TrackedDeposits{
+		Deposits: []store.TrackedDeposit{{
+			Block:       big.NewInt(100),
+			BlockHash:   "0xa",
+			TxHash:      "tx1",
+			Destination: 2,
+			Nonce:       3,
+		}},
+		Orphaned: []store.TrackedDeposit{{
+			Block:       big.NewInt(99),
+			BlockHash:   "0xb",
+			TxHash:      "tx2",
+			Destination: 2,
+			Nonce:       4,
+		}},
+	}
+	var stored []byte
+	s.keyValueReaderWriter.EXPECT().SetByKey([]byte("chain:1:trackedDeposits"), gomock.Any()).DoAndReturn(func(key []byte, value []byte) error {
+		stored = value
+		return nil
+	})
+	err := s.blockHashStore.StoreTrackedDeposits(1, deposits)
+	s.Nil(err)
+	s.keyValueReaderWriter.EXPECT().GetByKey([]byte("chain:1:trackedDeposits")).Return(stored, nil)
+
+	fetchedDeposits, err := s.blockHashStore.TrackedDeposits(1)
+
+	s.Nil(err)
+	s.Equal(deposits, fetchedDeposits)
+}
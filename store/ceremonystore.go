@@ -0,0 +1,107 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"sync"
+
+	"github.com/sygmaprotocol/sygma-core/store"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+type CeremonyType string
+type CeremonyStatus string
+
+var (
+	ceremoniesKey = "tss:ceremonies"
+
+	ECDSAKeygenCeremony    CeremonyType = "ecdsa-keygen"
+	FrostKeygenCeremony    CeremonyType = "frost-keygen"
+	ECDSAResharingCeremony CeremonyType = "ecdsa-resharing"
+
+	PendingCeremony    CeremonyStatus = "pending"
+	FailedCeremony     CeremonyStatus = "failed"
+	CompletedCeremony  CeremonyStatus = "completed"
+	SupersededCeremony CeremonyStatus = "superseded"
+)
+
+// Ceremony holds the progress of a keygen or resharing tss process
+// that is required to re-run it with the same session ID
+type Ceremony struct {
+	SessionID string
+	Type      CeremonyType
+	DomainID  uint8
+	Block     *big.Int
+	// Threshold is the threshold of the topology the ceremony was started with
+	Threshold int
+	Status    CeremonyStatus
+	// Attempts is the number of times the ceremony was recovered
+	Attempts int
+}
+
+type CeremonyStore struct {
+	mu sync.Mutex
+	db store.KeyValueReaderWriter
+}
+
+func NewCeremonyStore(db store.KeyValueReaderWriter) *CeremonyStore {
+	return &CeremonyStore{
+		db: db,
+	}
+}
+
+// StoreCeremony stores or overwrites ceremony by its session ID
+func (cs *CeremonyStore) StoreCeremony(ceremony Ceremony) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	ceremonies, err := cs.ceremonies()
+	if err != nil {
+		return err
+	}
+	ceremonies[ceremony.SessionID] = ceremony
+
+	cb, err := json.Marshal(ceremonies)
+	if err != nil {
+		return err
+	}
+	return cs.db.SetByKey([]byte(ceremoniesKey), cb)
+}
+
+// Ceremonies returns all stored ceremonies
+func (cs *CeremonyStore) Ceremonies() ([]Ceremony, error) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	ceremonies, err := cs.ceremonies()
+	if err != nil {
+		return nil, err
+	}
+
+	ceremonyList := make([]Ceremony, 0, len(ceremonies))
+	for _, ceremony := range ceremonies {
+		ceremonyList = append(ceremonyList, ceremony)
+	}
+	return ceremonyList, nil
+}
+
+func (cs *CeremonyStore) ceremonies() (map[string]Ceremony, error) {
+	ceremonies := make(map[string]Ceremony)
+	cb, err := cs.db.GetByKey([]byte(ceremoniesKey))
+	if err != nil {
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return ceremonies, nil
+		}
+		return nil, err
+	}
+
+	err = json.Unmarshal(cb, &ceremonies)
+	if err != nil {
+		return nil, err
+	}
+	return ceremonies, nil
+}
@@ -0,0 +1,97 @@
+package store_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/sygma-relayer/store"
+	"github.com/stretchr/testify/suite"
+	mock_store "github.com/sygmaprotocol/sygma-core/mock"
+	"github.com/syndtr/goleveldb/leveldb"
+	"go.uber.org/mock/gomock"
+)
+
+type CeremonyStoreTestSuite struct {
+	suite.Suite
+	ceremonyStore        *store.CeremonyStore
+	keyValueReaderWriter *mock_store.MockKeyValueReaderWriter
+}
+
+func TestRunCeremonyStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(CeremonyStoreTestSuite))
+}
+
+func (s *CeremonyStoreTestSuite) SetupTest() {
+	gomockController := gomock.NewController(s.T())
+	s.keyValueReaderWriter = mock_store.NewMockKeyValueReaderWriter(gomockController)
+	s.ceremonyStore = store.NewCeremonyStore(s.keyValueReaderWriter)
+}
+
+func (s *CeremonyStoreTestSuite) Test_StoreCeremony_FailedFetch() {
+	s.keyValueReaderWriter.EXPECT().GetByKey([]byte("tss:ceremonies")).Return(nil, errors.New("error"))
+
+	err := s.ceremonyStore.StoreCeremony(store.Ceremony{SessionID: "keygen"})
+
+	s.NotNil(err)
+}
+
+func (s *CeremonyStoreTestSuite) Test_StoreCeremony_FirstCeremony() {
+	ceremony := store.Ceremony{
+		SessionID: "keygen",
+		Type:      store.ECDSAKeygenCeremony,
+		Block:     big.NewInt(100),
+		Status:    store.PendingCeremony,
+	}
+	expectedBytes, _ := json.Marshal(map[string]store.Ceremony{"keygen": ceremony})
+	s.keyValueReaderWriter.EXPECT().GetByKey([]byte("tss:ceremonies")).Return(nil, leveldb.ErrNotFound)
+	s.keyValueReaderWriter.EXPECT().SetByKey([]byte("tss:ceremonies"), expectedBytes).Return(nil)
+
+	err := s.ceremonyStore.StoreCeremony(ceremony)
+
+	s.Nil(err)
+}
+
+func (s *CeremonyStoreTestSuite) Test_StoreCeremony_UpdatesExistingCeremony() {
+	ceremony := store.Ceremony{
+		SessionID: "resharing-100",
+		Type:      store.ECDSAResharingCeremony,
+		Block:     big.NewInt(100),
+		Status:    store.PendingCeremony,
+	}
+	storedBytes, _ := json.Marshal(map[string]store.Ceremony{"resharing-100": ceremony})
+	ceremony.Status = store.CompletedCeremony
+	expectedBytes, _ := json.Marshal(map[string]store.Ceremony{"resharing-100": ceremony})
+	s.keyValueReaderWriter.EXPECT().GetByKey([]byte("tss:ceremonies")).Return(storedBytes, nil)
+	s.keyValueReaderWriter.EXPECT().SetByKey([]byte("tss:ceremonies"), expectedBytes).Return(nil)
+
+	err := s.ceremonyStore.StoreCeremony(ceremony)
+
+	s.Nil(err)
+}
+
+func (s *CeremonyStoreTestSuite) Test_Ceremonies_NoCeremonies() {
+	s.keyValueReaderWriter.EXPECT().GetByKey([]byte("tss:ceremonies")).Return(nil, leveldb.ErrNotFound)
+
+	ceremonies, err := s.ceremonyStore.Ceremonies()
+
+	s.Nil(err)
+	s.Equal(len(ceremonies), 0)
+}
+
+func (s *CeremonyStoreTestSuite) Test_Ceremonies_SuccessfulFetch() {
+	ceremony := store.Ceremony{
+		SessionID: "frost-keygen",
+		Type:      store.FrostKeygenCeremony,
+		Block:     big.NewInt(100),
+		Status:    store.FailedCeremony,
+	}
+	storedBytes, _ := json.Marshal(map[string]store.Ceremony{"frost-keygen": ceremony})
+	s.keyValueReaderWriter.EXPECT().GetByKey([]byte("tss:ceremonies")).Return(storedBytes, nil)
+
+	ceremonies, err := s.ceremonyStore.Ceremonies()
+
+	s.Nil(err)
+	s.Equal(ceremonies, []store.Ceremony{ceremony})
+}
@@ -16,10 +16,13 @@ type PropStatus string
 
 var (
 	KEY                     = "source:%d:destination:%d:depositNonce:%d"
+	REASON_KEY              = "source:%d:destination:%d:depositNonce:%d:reason"
+	ORPHAN_KEY              = "source:%d:destination:%d:depositNonce:%d:orphanedBlock"
 	MissingProp  PropStatus = "missing"
 	PendingProp  PropStatus = "pending"
 	FailedProp   PropStatus = "failed"
 	ExecutedProp PropStatus = "executed"
+	InvalidProp  PropStatus = "invalid"
 )
 
 type PropStore struct {
@@ -63,3 +66,71 @@ func (ns *PropStore) PropStatus(source, destination uint8, depositNonce uint64)
 	status := PropStatus(string(v))
 	return status, nil
 }
+
+// StorePropFailure marks the proposal as failed and stores the reason of the failure
+func (ns *PropStore) StorePropFailure(source, destination uint8, depositNonce uint64, reason string) error {
+	key := bytes.Buffer{}
+	keyS := fmt.Sprintf(REASON_KEY, source, destination, depositNonce)
+	key.WriteString(keyS)
+
+	err := ns.db.SetByKey(key.Bytes(), []byte(reason))
+	if err != nil {
+		return err
+	}
+
+	return ns.StorePropStatus(source, destination, depositNonce, FailedProp)
+}
+
+// PropFailureReason returns the reason of the last proposal failure
+func (ns *PropStore) PropFailureReason(source, destination uint8, depositNonce uint64) (string, error) {
+	key := bytes.Buffer{}
+	keyS := fmt.Sprintf(REASON_KEY, source, destination, depositNonce)
+	key.WriteString(keyS)
+
+	v, err := ns.db.GetByKey(key.Bytes())
+	if err != nil {
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return string(v), nil
+}
+
+// InvalidateProp marks the proposal of the deposit from the orphaned block as invalid
+func (ns *PropStore) InvalidateProp(source, destination uint8, depositNonce uint64, blockHash string) error {
+	key := bytes.Buffer{}
+	keyS := fmt.Sprintf(ORPHAN_KEY, source, destination, depositNonce)
+	key.WriteString(keyS)
+
+	err := ns.db.SetByKey(key.Bytes(), []byte(blockHash))
+	if err != nil {
+		return err
+	}
+
+	return ns.StorePropStatus(source, destination, depositNonce, InvalidProp)
+}
+
+// IsPropInvalid returns true if the proposal was invalidated because the deposit
+// block with the given hash was orphaned
+func (ns *PropStore) IsPropInvalid(source, destination uint8, depositNonce uint64, blockHash string) (bool, error) {
+	status, err := ns.PropStatus(source, destination, depositNonce)
+	if err != nil || status != InvalidProp {
+		return false, err
+	}
+
+	key := bytes.Buffer{}
+	keyS := fmt.Sprintf(ORPHAN_KEY, source, destination, depositNonce)
+	key.WriteString(keyS)
+
+	v, err := ns.db.GetByKey(key.Bytes())
+	if err != nil {
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return string(v) == blockHash, nil
+}
@@ -73,3 +73,70 @@ func (s *PropStoreTestSuite) TestGetNonce_SuccessfulFetch() {
 	s.Nil(err)
 	s.Equal(status, store.ExecutedProp)
 }
+
+func (s *PropStoreTestSuite) Test_StorePropFailure_StoresReasonAndStatus() {
+	s.keyValueReaderWriter.EXPECT().SetByKey([]byte("source:1:destination:2:depositNonce:3:reason"), []byte("reason")).Return(nil)
+	s.keyValueReaderWriter.EXPECT().SetByKey([]byte("source:1:destination:2:depositNonce:3"), []byte(store.FailedProp)).Return(nil)
+
+	err := s.nonceStore.StorePropFailure(1, 2, 3, "reason")
+
+	s.Nil(err)
+}
+
+func (s *PropStoreTestSuite) Test_PropFailureReason_ReasonNotFound() {
+	key := "source:1:destination:2:depositNonce:3:reason"
+	s.keyValueReaderWriter.EXPECT().GetByKey([]byte(key)).Return(nil, leveldb.ErrNotFound)
+
+	reason, err := s.nonceStore.PropFailureReason(1, 2, 3)
+
+	s.Nil(err)
+	s.Equal(reason, "")
+}
+
+func (s *PropStoreTestSuite) Test_PropFailureReason_SuccessfulFetch() {
+	key := "source:1:destination:2:depositNonce:3:reason"
+	s.keyValueReaderWriter.EXPECT().GetByKey([]byte(key)).Return([]byte("reason"), nil)
+
+	reason, err := s.nonceStore.PropFailureReason(1, 2, 3)
+
+	s.Nil(err)
+	s.Equal(reason, "reason")
+}
+
+func (s *PropStoreTestSuite) Test_InvalidateProp_StoresOrphanedBlockAndStatus() {
+	s.keyValueReaderWriter.EXPECT().SetByKey([]byte("source:1:destination:2:depositNonce:3:orphanedBlock"), []byte("0x01")).Return(nil)
+	s.keyValueReaderWriter.EXPECT().SetByKey([]byte("source:1:destination:2:depositNonce:3"), []byte(store.InvalidProp)).Return(nil)
+
+	err := s.nonceStore.InvalidateProp(1, 2, 3, "0x01")
+
+	s.Nil(err)
+}
+
+func (s *PropStoreTestSuite) Test_IsPropInvalid_NotInvalidated() {
+	s.keyValueReaderWriter.EXPECT().GetByKey([]byte("source:1:destination:2:depositNonce:3")).Return([]byte(store.PendingProp), nil)
+
+	invalid, err := s.nonceStore.IsPropInvalid(1, 2, 3, "0x01")
+
+	s.Nil(err)
+	s.False(invalid)
+}
+
+func (s *PropStoreTestSuite) Test_IsPropInvalid_DifferentBlock() {
+	s.keyValueReaderWriter.EXPECT().GetByKey([]byte("source:1:destination:2:depositNonce:3")).Return([]byte(store.InvalidProp), nil)
+	s.keyValueReaderWriter.EXPECT().GetByKey([]byte("source:1:destination:2:depositNonce:3:orphanedBlock")).Return([]byte("0x01"), nil)
+
+	invalid, err := s.nonceStore.IsPropInvalid(1, 2, 3, "0x02")
+
+	s.Nil(err)
+	s.False(invalid)
+}
+
+func (s *PropStoreTestSuite) Test_IsPropInvalid_OrphanedBlock() {
+	s.keyValueReaderWriter.EXPECT().GetByKey([]byte("source:1:destination:2:depositNonce:3")).Return([]byte(store.InvalidProp), nil)
+	s.keyValueReaderWriter.EXPECT().GetByKey([]byte("source:1:destination:2:depositNonce:3:orphanedBlock")).Return([]byte("0x01"), nil)
+
+	invalid, err := s.nonceStore.IsPropInvalid(1, 2, 3, "0x01")
+
+	s.Nil(err)
+	s.True(invalid)
+}
@@ -8,8 +8,138 @@ import (
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	envelopeMagic        = "SYGT"
+	EnvelopeVersion byte = 1
+
+	saltSize = 16
+	keySize  = 32
+
+	argon2Time    = 1
+	argon2Memory  = 64 * 1024
+	argon2Threads = 4
+)
+
+var (
+	ErrCiphertextTooShort = errors.New("topology ciphertext too short")
+	ErrUnsupportedVersion = errors.New("unsupported topology envelope version")
+	ErrDecryptionFailed   = errors.New("topology decryption failed")
+	ErrLegacyKey          = errors.New("legacy topology encryption key has to be 16, 24 or 32 bytes long")
+	ErrLegacyNotAllowed   = errors.New("legacy topology encryption not allowed, migrate the topology with the migrate command")
 )
 
+// TopologyEncryption encrypts the topology into a versioned envelope
+//
+//	magic (4 bytes) | version (1 byte) | salt (16 bytes) | nonce (12 bytes) | AES-256-GCM ciphertext
+//
+// The encryption key is derived from the password and salt with argon2id and the
+// envelope header is authenticated as additional data. Legacy AES-CTR ciphertexts
+// without the envelope, which use the password as the key, are decrypted only if
+// allowed because they are not authenticated.
+type TopologyEncryption struct {
+	password    []byte
+	allowLegacy bool
+	legacy      *AESEncryption
+}
+
+func NewTopologyEncryption(password []byte, allowLegacy bool) (*TopologyEncryption, error) {
+	if len(password) == 0 {
+		return nil, fmt.Errorf("topology encryption password not provided")
+	}
+
+	// legacy ciphertexts can be decrypted only if the password is a valid AES key
+	legacy, _ := NewAESEncryption(password)
+	return &TopologyEncryption{
+		password:    password,
+		allowLegacy: allowLegacy,
+		legacy:      legacy,
+	}, nil
+}
+
+// IsLegacyCiphertext checks if the ciphertext is encrypted with the legacy AES-CTR encryption
+func IsLegacyCiphertext(ct []byte) bool {
+	return !bytes.HasPrefix(ct, []byte(envelopeMagic))
+}
+
+// Encrypt encrypts data into the latest envelope version
+func (e *TopologyEncryption) Encrypt(data []byte) ([]byte, error) {
+	salt := make([]byte, saltSize)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := e.aead(salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	header := append(append([]byte(envelopeMagic), EnvelopeVersion), salt...)
+	envelope := bytes.NewBuffer(header)
+	envelope.Write(nonce)
+	envelope.Write(aead.Seal(nil, nonce, data, header))
+	return envelope.Bytes(), nil
+}
+
+// Decrypt decrypts the envelope or the legacy ciphertext if it is allowed
+func (e *TopologyEncryption) Decrypt(ct []byte) ([]byte, error) {
+	if IsLegacyCiphertext(ct) {
+		if !e.allowLegacy {
+			return nil, ErrLegacyNotAllowed
+		}
+		if e.legacy == nil {
+			return nil, ErrLegacyKey
+		}
+
+		log.Warn().Msgf("Decrypting topology with legacy unauthenticated encryption")
+		return e.legacy.Decrypt(ct)
+	}
+
+	headerSize := len(envelopeMagic) + 1 + saltSize
+	if len(ct) < headerSize {
+		return nil, ErrCiphertextTooShort
+	}
+	if version := ct[len(envelopeMagic)]; version != EnvelopeVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, version)
+	}
+
+	header := ct[:headerSize]
+	aead, err := e.aead(header[len(envelopeMagic)+1:])
+	if err != nil {
+		return nil, err
+	}
+	if len(ct) < headerSize+aead.NonceSize()+aead.Overhead() {
+		return nil, ErrCiphertextTooShort
+	}
+	nonce := ct[headerSize : headerSize+aead.NonceSize()]
+	data, err := aead.Open(nil, nonce, ct[headerSize+aead.NonceSize():], header)
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+	return data, nil
+}
+
+func (e *TopologyEncryption) aead(salt []byte) (cipher.AEAD, error) {
+	key := argon2.IDKey(e.password, salt, argon2Time, argon2Memory, argon2Threads, keySize)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// AESEncryption is the legacy unauthenticated topology encryption
 type AESEncryption struct {
 	block cipher.Block
 }
@@ -25,12 +155,16 @@ func NewAESEncryption(key []byte) (*AESEncryption, error) {
 	}, nil
 }
 
-func (ae *AESEncryption) Decrypt(ct []byte) []byte {
+func (ae *AESEncryption) Decrypt(ct []byte) ([]byte, error) {
+	if len(ct) < aes.BlockSize {
+		return nil, ErrCiphertextTooShort
+	}
+
 	iv := ct[:aes.BlockSize]
 	stream := cipher.NewCTR(ae.block, iv)
 	dst := make([]byte, len(ct[aes.BlockSize:]))
 	stream.XORKeyStream(dst, ct[aes.BlockSize:])
-	return dst
+	return dst, nil
 }
 
 // Encrypt is a function that encrypts provided bytes with AES in CTR mode
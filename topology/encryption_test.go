@@ -42,7 +42,8 @@ func (s *AESEncryptionTestSuite) Test_EncrDecr() {
 	ct, err := s.aesEncryption.Encrypt(pt)
 	s.Nil(err)
 
-	resultingPt := s.aesEncryption.Decrypt(ct)
+	resultingPt, err := s.aesEncryption.Decrypt(ct)
+	s.Nil(err)
 
 	decryptedTopology := topology.RawTopology{}
 
@@ -51,3 +52,103 @@ func (s *AESEncryptionTestSuite) Test_EncrDecr() {
 
 	s.Equal(expectedTopology, decryptedTopology)
 }
+
+func (s *AESEncryptionTestSuite) Test_Decrypt_CiphertextTooShort() {
+	_, err := s.aesEncryption.Decrypt([]byte{1, 2, 3})
+
+	s.ErrorIs(err, topology.ErrCiphertextTooShort)
+}
+
+type TopologyEncryptionTestSuite struct {
+	suite.Suite
+	encryption *topology.TopologyEncryption
+}
+
+func TestRunTopologyEncryptionTestSuite(t *testing.T) {
+	suite.Run(t, new(TopologyEncryptionTestSuite))
+}
+
+func (s *TopologyEncryptionTestSuite) SetupTest() {
+	s.encryption, _ = topology.NewTopologyEncryption([]byte("v8y/B?E(H+MbQeTh"), true)
+}
+
+func (s *TopologyEncryptionTestSuite) Test_EmptyPassword() {
+	_, err := topology.NewTopologyEncryption([]byte{}, false)
+
+	s.NotNil(err)
+}
+
+func (s *TopologyEncryptionTestSuite) Test_EncrDecr() {
+	ct, err := s.encryption.Encrypt([]byte("topology"))
+	s.Nil(err)
+
+	pt, err := s.encryption.Decrypt(ct)
+
+	s.Nil(err)
+	s.Equal([]byte("topology"), pt)
+	s.False(topology.IsLegacyCiphertext(ct))
+}
+
+func (s *TopologyEncryptionTestSuite) Test_Decrypt_TamperedCiphertext() {
+	ct, _ := s.encryption.Encrypt([]byte("topology"))
+	ct[len(ct)-1] ^= 0xff
+
+	_, err := s.encryption.Decrypt(ct)
+
+	s.ErrorIs(err, topology.ErrDecryptionFailed)
+}
+
+func (s *TopologyEncryptionTestSuite) Test_Decrypt_InvalidPassword() {
+	ct, _ := s.encryption.Encrypt([]byte("topology"))
+	encryption, _ := topology.NewTopologyEncryption([]byte("invalid"), false)
+
+	_, err := encryption.Decrypt(ct)
+
+	s.ErrorIs(err, topology.ErrDecryptionFailed)
+}
+
+func (s *TopologyEncryptionTestSuite) Test_Decrypt_UnsupportedVersion() {
+	ct, _ := s.encryption.Encrypt([]byte("topology"))
+	ct[4] = topology.EnvelopeVersion + 1
+
+	_, err := s.encryption.Decrypt(ct)
+
+	s.ErrorIs(err, topology.ErrUnsupportedVersion)
+}
+
+func (s *TopologyEncryptionTestSuite) Test_Decrypt_TruncatedEnvelope() {
+	ct, _ := s.encryption.Encrypt([]byte("topology"))
+
+	_, err := s.encryption.Decrypt(ct[:30])
+
+	s.ErrorIs(err, topology.ErrCiphertextTooShort)
+}
+
+func (s *TopologyEncryptionTestSuite) Test_Decrypt_LegacyCiphertext() {
+	aesEncryption, _ := topology.NewAESEncryption([]byte("v8y/B?E(H+MbQeTh"))
+	ct, _ := aesEncryption.Encrypt([]byte("topology"))
+
+	pt, err := s.encryption.Decrypt(ct)
+
+	s.Nil(err)
+	s.Equal([]byte("topology"), pt)
+	s.True(topology.IsLegacyCiphertext(ct))
+}
+
+func (s *TopologyEncryptionTestSuite) Test_Decrypt_LegacyCiphertextInvalidKey() {
+	encryption, _ := topology.NewTopologyEncryption([]byte("password"), true)
+
+	_, err := encryption.Decrypt(make([]byte, 32))
+
+	s.ErrorIs(err, topology.ErrLegacyKey)
+}
+
+func (s *TopologyEncryptionTestSuite) Test_Decrypt_LegacyCiphertextNotAllowed() {
+	encryption, _ := topology.NewTopologyEncryption([]byte("v8y/B?E(H+MbQeTh"), false)
+	aesEncryption, _ := topology.NewAESEncryption([]byte("v8y/B?E(H+MbQeTh"))
+	ct, _ := aesEncryption.Encrypt([]byte("topology"))
+
+	_, err := encryption.Decrypt(ct)
+
+	s.ErrorIs(err, topology.ErrLegacyNotAllowed)
+}
@@ -0,0 +1,87 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package topology
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// MultiSourceTopologyProvider reads the topology from multiple providers and accepts
+// it only if at least quorum of them return the same topology, so a single
+// compromised or unavailable source can not change or block the topology.
+type MultiSourceTopologyProvider struct {
+	providers []*TopologyProvider
+	quorum    int
+}
+
+func NewMultiSourceTopologyProvider(providers []*TopologyProvider, quorum int) (*MultiSourceTopologyProvider, error) {
+	if quorum < 1 || quorum > len(providers) {
+		return nil, fmt.Errorf("topology quorum %d invalid for %d sources", quorum, len(providers))
+	}
+
+	return &MultiSourceTopologyProvider{
+		providers: providers,
+		quorum:    quorum,
+	}, nil
+}
+
+// NetworkTopology fetches the topology from all providers and returns the topology
+// returned by at least quorum providers. Encryption of the same topology differs
+// between uploads so providers are compared by decrypted topologies and the expected
+// hash only has to match the encrypted topology of one of the agreeing sources.
+// The topology version is persisted only after the quorum agrees on the topology.
+func (p *MultiSourceTopologyProvider) NetworkTopology(hash string) (*NetworkTopology, error) {
+	votes := make(map[string]int)
+	verified := make(map[string]bool)
+	for _, provider := range p.providers {
+		ct, eh, err := provider.encryptedTopology()
+		if err != nil {
+			log.Warn().Err(err).Msgf("Failed reading topology from source")
+			continue
+		}
+		topology, version, err := provider.decryptTopology(ct, false)
+		if err != nil {
+			log.Warn().Err(err).Msgf("Failed decrypting topology from source")
+			continue
+		}
+
+		key := fmt.Sprintf("%d/%s", version, topologyKey(topology))
+		votes[key]++
+		if hash == "" || eh == hash {
+			verified[key] = true
+		}
+		if verified[key] && votes[key] >= p.quorum {
+			if provider.verifier != nil {
+				err = provider.verifier.acceptVersion(version)
+				if err != nil {
+					return nil, err
+				}
+			}
+			return topology, nil
+		}
+	}
+
+	if hash != "" && len(verified) == 0 {
+		return nil, fmt.Errorf("no topology source matching expected hash %s", hash)
+	}
+	return nil, fmt.Errorf("less than %d of %d topology sources agree on the topology", p.quorum, len(p.providers))
+}
+
+func topologyKey(topology *NetworkTopology) string {
+	peers := make([]string, len(topology.Peers))
+	for i, p := range topology.Peers {
+		addrs := make([]string, len(p.Addrs))
+		for j, addr := range p.Addrs {
+			addrs[j] = addr.String()
+		}
+		sort.Strings(addrs)
+		peers[i] = fmt.Sprintf("%s:%s", p.ID, strings.Join(addrs, ","))
+	}
+	sort.Strings(peers)
+	return fmt.Sprintf("%d/%s", topology.Threshold, strings.Join(peers, "/"))
+}
@@ -0,0 +1,175 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package topology
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// SignedMessage returns the message governance keys sign. The message is the JSON
+// encoded topology without signatures.
+func SignedMessage(rawTopology *RawTopology) ([]byte, error) {
+	return json.Marshal(&RawTopology{
+		Peers:     rawTopology.Peers,
+		Threshold: rawTopology.Threshold,
+		Version:   rawTopology.Version,
+	})
+}
+
+// SignTopology signs the topology with the governance key as an Ethereum signed
+// message and appends the signature to topology signatures
+func SignTopology(rawTopology *RawTopology, key *ecdsa.PrivateKey) error {
+	msg, err := SignedMessage(rawTopology)
+	if err != nil {
+		return err
+	}
+	sig, err := crypto.Sign(accounts.TextHash(msg), key)
+	if err != nil {
+		return err
+	}
+	sig[crypto.RecoveryIDOffset] += 27
+
+	rawTopology.Signatures = append(rawTopology.Signatures, hexutil.Encode(sig))
+	return nil
+}
+
+// SignatureVerifier verifies the topology is signed by at least threshold governance keys.
+// The highest accepted topology version is persisted to the version file, if provided,
+// and signed topologies with lower versions are rejected.
+type SignatureVerifier struct {
+	signers     map[common.Address]bool
+	threshold   int
+	versionPath string
+
+	lock    sync.Mutex
+	version uint64
+}
+
+func NewSignatureVerifier(signers []common.Address, threshold int, versionPath string) (*SignatureVerifier, error) {
+	if threshold < 1 || threshold > len(signers) {
+		return nil, fmt.Errorf("topology signature threshold %d invalid for %d governance keys", threshold, len(signers))
+	}
+
+	signerMap := make(map[common.Address]bool)
+	for _, signer := range signers {
+		signerMap[signer] = true
+	}
+	return &SignatureVerifier{
+		signers:     signerMap,
+		threshold:   threshold,
+		versionPath: versionPath,
+	}, nil
+}
+
+// Verify checks topology signatures and version and persists the topology version
+func (v *SignatureVerifier) Verify(rawTopology *RawTopology) error {
+	err := v.VerifySignatures(rawTopology)
+	if err != nil {
+		return err
+	}
+	return v.acceptVersion(rawTopology.Version)
+}
+
+// VerifySignatures checks topology signatures and that the topology version is not lower than
+// the highest accepted version without persisting the version
+func (v *SignatureVerifier) VerifySignatures(rawTopology *RawTopology) error {
+	msg, err := SignedMessage(rawTopology)
+	if err != nil {
+		return err
+	}
+	hash := accounts.TextHash(msg)
+
+	signed := make(map[common.Address]bool)
+	for _, rawSig := range rawTopology.Signatures {
+		sig, err := hexutil.Decode(rawSig)
+		if err != nil || len(sig) != crypto.SignatureLength {
+			return fmt.Errorf("invalid topology signature %s", rawSig)
+		}
+		if sig[crypto.RecoveryIDOffset] >= 27 {
+			sig[crypto.RecoveryIDOffset] -= 27
+		}
+
+		pubKey, err := crypto.SigToPub(hash, sig)
+		if err != nil {
+			return fmt.Errorf("invalid topology signature %s: %w", rawSig, err)
+		}
+		signer := crypto.PubkeyToAddress(*pubKey)
+		if v.signers[signer] {
+			signed[signer] = true
+		}
+	}
+
+	if len(signed) < v.threshold {
+		return fmt.Errorf("topology signed by %d governance keys, %d required", len(signed), v.threshold)
+	}
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	highest, err := v.highestVersion()
+	if err != nil {
+		return err
+	}
+	if rawTopology.Version < highest {
+		return fmt.Errorf("topology version %d lower than accepted version %d", rawTopology.Version, highest)
+	}
+	return nil
+}
+
+// acceptVersion rejects versions lower than the highest accepted version and persists higher versions
+func (v *SignatureVerifier) acceptVersion(version uint64) error {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	highest, err := v.highestVersion()
+	if err != nil {
+		return err
+	}
+	if version < highest {
+		return fmt.Errorf("topology version %d lower than accepted version %d", version, highest)
+	}
+	if version == highest {
+		return nil
+	}
+
+	if v.versionPath != "" {
+		err = os.WriteFile(v.versionPath, []byte(strconv.FormatUint(version, 10)), 0600)
+		if err != nil {
+			return err
+		}
+	}
+	v.version = version
+	return nil
+}
+
+// highestVersion returns the highest accepted version from the version file or memory
+func (v *SignatureVerifier) highestVersion() (uint64, error) {
+	if v.versionPath == "" {
+		return v.version, nil
+	}
+
+	b, err := os.ReadFile(v.versionPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return v.version, nil
+		}
+		return 0, err
+	}
+	version, err := strconv.ParseUint(strings.TrimSpace(string(b)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid topology version file %s: %w", v.versionPath, err)
+	}
+	return version, nil
+}
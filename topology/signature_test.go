@@ -0,0 +1,165 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package topology_test
+
+import (
+	"crypto/ecdsa"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ChainSafe/sygma-relayer/topology"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/suite"
+)
+
+type SignatureVerifierTestSuite struct {
+	suite.Suite
+	keys     []*ecdsa.PrivateKey
+	verifier *topology.SignatureVerifier
+}
+
+func TestRunSignatureVerifierTestSuite(t *testing.T) {
+	suite.Run(t, new(SignatureVerifierTestSuite))
+}
+
+func (s *SignatureVerifierTestSuite) SetupTest() {
+	s.keys = []*ecdsa.PrivateKey{}
+	signers := []common.Address{}
+	for i := 0; i < 3; i++ {
+		key, _ := crypto.GenerateKey()
+		s.keys = append(s.keys, key)
+		signers = append(signers, crypto.PubkeyToAddress(key.PublicKey))
+	}
+
+	var err error
+	s.verifier, err = topology.NewSignatureVerifier(signers, 2, filepath.Join(s.T().TempDir(), "topology.version"))
+	s.Nil(err)
+}
+
+func (s *SignatureVerifierTestSuite) signedTopology(version uint64) *topology.RawTopology {
+	rawTopology := s.rawTopology()
+	rawTopology.Version = version
+	_ = topology.SignTopology(rawTopology, s.keys[0])
+	_ = topology.SignTopology(rawTopology, s.keys[1])
+	return rawTopology
+}
+
+func (s *SignatureVerifierTestSuite) rawTopology() *topology.RawTopology {
+	return &topology.RawTopology{
+		Peers: []topology.RawPeer{
+			{PeerAddress: "/dns4/relayer1/tcp/9000/p2p/QmcvEg7jGvuxdsUFRUiE4VdrL2P1Yeju5L83BsJvvXz7zX"},
+			{PeerAddress: "/dns4/relayer2/tcp/9001/p2p/QmeTuMtdpPB7zKDgmobEwSvxodrf5aFVSmBXX3SQJVjJaT"},
+		},
+		Threshold: "1",
+	}
+}
+
+func (s *SignatureVerifierTestSuite) Test_InvalidThreshold() {
+	_, err := topology.NewSignatureVerifier([]common.Address{{}}, 2, "")
+
+	s.NotNil(err)
+}
+
+func (s *SignatureVerifierTestSuite) Test_Verify_ThresholdSigned() {
+	rawTopology := s.rawTopology()
+	_ = topology.SignTopology(rawTopology, s.keys[0])
+	_ = topology.SignTopology(rawTopology, s.keys[2])
+
+	err := s.verifier.Verify(rawTopology)
+
+	s.Nil(err)
+}
+
+func (s *SignatureVerifierTestSuite) Test_Verify_NotEnoughSignatures() {
+	rawTopology := s.rawTopology()
+	_ = topology.SignTopology(rawTopology, s.keys[0])
+
+	err := s.verifier.Verify(rawTopology)
+
+	s.NotNil(err)
+}
+
+func (s *SignatureVerifierTestSuite) Test_Verify_DuplicateSignatures() {
+	rawTopology := s.rawTopology()
+	_ = topology.SignTopology(rawTopology, s.keys[0])
+	rawTopology.Signatures = append(rawTopology.Signatures, rawTopology.Signatures[0])
+
+	err := s.verifier.Verify(rawTopology)
+
+	s.NotNil(err)
+}
+
+func (s *SignatureVerifierTestSuite) Test_Verify_UnknownSigner() {
+	rawTopology := s.rawTopology()
+	key, _ := crypto.GenerateKey()
+	_ = topology.SignTopology(rawTopology, s.keys[0])
+	_ = topology.SignTopology(rawTopology, key)
+
+	err := s.verifier.Verify(rawTopology)
+
+	s.NotNil(err)
+}
+
+func (s *SignatureVerifierTestSuite) Test_Verify_ModifiedTopology() {
+	rawTopology := s.rawTopology()
+	_ = topology.SignTopology(rawTopology, s.keys[0])
+	_ = topology.SignTopology(rawTopology, s.keys[1])
+	rawTopology.Peers = append(rawTopology.Peers, topology.RawPeer{
+		PeerAddress: "/dns4/relayer3/tcp/9002/p2p/QmYAYuLUPNwYEBYJaKHcE7NKjUhiUV8txx2xDXHvcYa1xK",
+	})
+
+	err := s.verifier.Verify(rawTopology)
+
+	s.NotNil(err)
+}
+
+func (s *SignatureVerifierTestSuite) Test_Verify_InvalidSignature() {
+	rawTopology := s.rawTopology()
+	rawTopology.Signatures = []string{"0x01"}
+
+	err := s.verifier.Verify(rawTopology)
+
+	s.NotNil(err)
+}
+
+func (s *SignatureVerifierTestSuite) Test_Verify_ModifiedVersion() {
+	rawTopology := s.signedTopology(2)
+	rawTopology.Version = 3
+
+	err := s.verifier.Verify(rawTopology)
+
+	s.NotNil(err)
+}
+
+func (s *SignatureVerifierTestSuite) Test_Verify_LowerVersionRejected() {
+	err := s.verifier.Verify(s.signedTopology(2))
+	s.Nil(err)
+
+	err = s.verifier.Verify(s.signedTopology(2))
+	s.Nil(err)
+	err = s.verifier.Verify(s.signedTopology(1))
+	s.NotNil(err)
+	err = s.verifier.Verify(s.signedTopology(3))
+	s.Nil(err)
+}
+
+func (s *SignatureVerifierTestSuite) Test_Verify_AcceptedVersionPersisted() {
+	versionPath := filepath.Join(s.T().TempDir(), "topology.version")
+	signers := []common.Address{
+		crypto.PubkeyToAddress(s.keys[0].PublicKey),
+		crypto.PubkeyToAddress(s.keys[1].PublicKey),
+	}
+	verifier, _ := topology.NewSignatureVerifier(signers, 2, versionPath)
+	err := verifier.Verify(s.signedTopology(2))
+	s.Nil(err)
+
+	restartedVerifier, _ := topology.NewSignatureVerifier(signers, 2, versionPath)
+	err = restartedVerifier.Verify(s.signedTopology(1))
+
+	s.NotNil(err)
+	version, _ := os.ReadFile(versionPath)
+	s.Equal("2", string(version))
+}
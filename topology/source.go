@@ -0,0 +1,208 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package topology
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ChainSafe/sygma-relayer/config/relayer"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ipfs/go-cid"
+)
+
+const (
+	URLSourceType      = "url"
+	IPFSSourceType     = "ipfs"
+	FileSourceType     = "file"
+	ContractSourceType = "contract"
+)
+
+// TopologyRegistryABI is the ABI of the registry contract storing the encrypted topology
+const TopologyRegistryABI = `[{"inputs":[],"name":"topology","outputs":[{"internalType":"bytes","name":"","type":"bytes"}],"stateMutability":"view","type":"function"}]`
+
+// Source fetches the encrypted topology
+type Source interface {
+	// EncryptedTopology returns the encrypted topology
+	EncryptedTopology() ([]byte, error)
+	String() string
+}
+
+// NewSource creates the topology source from the source configuration
+func NewSource(config relayer.TopologySource, fetcher Fetcher) (Source, error) {
+	switch config.Type {
+	case URLSourceType, "":
+		return NewURLSource(config.Url, fetcher), nil
+	case IPFSSourceType:
+		return NewIPFSSource(config.Url, config.Cid, fetcher)
+	case FileSourceType:
+		return NewFileSource(config.Path), nil
+	case ContractSourceType:
+		if !common.IsHexAddress(config.Address) {
+			return nil, fmt.Errorf("invalid topology registry address %s", config.Address)
+		}
+		client, err := ethclient.Dial(config.Url)
+		if err != nil {
+			return nil, err
+		}
+		return NewContractSource(client, common.HexToAddress(config.Address)), nil
+	default:
+		return nil, fmt.Errorf("unknown topology source type %s", config.Type)
+	}
+}
+
+// URLSource reads the hex encoded encrypted topology from an URL
+type URLSource struct {
+	url     string
+	fetcher Fetcher
+}
+
+func NewURLSource(url string, fetcher Fetcher) *URLSource {
+	return &URLSource{
+		url:     url,
+		fetcher: fetcher,
+	}
+}
+
+func (s *URLSource) EncryptedTopology() ([]byte, error) {
+	body, err := fetch(s.fetcher, s.url)
+	if err != nil {
+		return nil, err
+	}
+	return decodeHex(body)
+}
+
+func (s *URLSource) String() string {
+	return fmt.Sprintf("URL %s", s.url)
+}
+
+// IPFSSource reads the hex encoded encrypted topology pinned under the CID
+// from an IPFS gateway. Content is verified against the CID so the gateway can not
+// tamper with the topology. Gateways return files of other codecs reassembled from
+// the DAG, which can't be verified, so only CIDs with the raw codec are accepted.
+type IPFSSource struct {
+	gateway string
+	cid     cid.Cid
+	fetcher Fetcher
+}
+
+func NewIPFSSource(gateway string, rawCID string, fetcher Fetcher) (*IPFSSource, error) {
+	c, err := cid.Decode(rawCID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid topology CID %s: %w", rawCID, err)
+	}
+	if c.Prefix().Codec != cid.Raw {
+		return nil, fmt.Errorf("topology CID %s does not use the raw codec and can't be verified", rawCID)
+	}
+
+	return &IPFSSource{
+		gateway: strings.TrimSuffix(gateway, "/"),
+		cid:     c,
+		fetcher: fetcher,
+	}, nil
+}
+
+func (s *IPFSSource) EncryptedTopology() ([]byte, error) {
+	body, err := fetch(s.fetcher, fmt.Sprintf("%s/ipfs/%s", s.gateway, s.cid))
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := s.cid.Prefix().Sum(body)
+	if err != nil {
+		return nil, err
+	}
+	if !c.Equals(s.cid) {
+		return nil, fmt.Errorf("topology content CID %s not matching expected CID %s", c, s.cid)
+	}
+	return decodeHex(body)
+}
+
+func (s *IPFSSource) String() string {
+	return fmt.Sprintf("IPFS %s", s.cid)
+}
+
+// FileSource reads the hex encoded encrypted topology from a local file
+type FileSource struct {
+	path string
+}
+
+func NewFileSource(path string) *FileSource {
+	return &FileSource{
+		path: path,
+	}
+}
+
+func (s *FileSource) EncryptedTopology() ([]byte, error) {
+	body, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+	return decodeHex(body)
+}
+
+func (s *FileSource) String() string {
+	return fmt.Sprintf("file %s", s.path)
+}
+
+// ContractSource reads the encrypted topology from the registry contract
+type ContractSource struct {
+	caller  ethereum.ContractCaller
+	address common.Address
+	abi     abi.ABI
+}
+
+func NewContractSource(caller ethereum.ContractCaller, address common.Address) *ContractSource {
+	a, _ := abi.JSON(strings.NewReader(TopologyRegistryABI))
+	return &ContractSource{
+		caller:  caller,
+		address: address,
+		abi:     a,
+	}
+}
+
+func (s *ContractSource) EncryptedTopology() ([]byte, error) {
+	input, err := s.abi.Pack("topology")
+	if err != nil {
+		return nil, err
+	}
+	output, err := s.caller.CallContract(context.Background(), ethereum.CallMsg{
+		To:   &s.address,
+		Data: input,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.abi.Unpack("topology", output)
+	if err != nil {
+		return nil, err
+	}
+	return *abi.ConvertType(res[0], new([]byte)).(*[]byte), nil
+}
+
+func (s *ContractSource) String() string {
+	return fmt.Sprintf("contract %s", s.address)
+}
+
+func fetch(fetcher Fetcher, url string) ([]byte, error) {
+	resp, err := fetcher.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return io.ReadAll(resp.Body)
+}
+
+func decodeHex(body []byte) ([]byte, error) {
+	return hex.DecodeString(strings.TrimSpace(string(body)))
+}
@@ -0,0 +1,315 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package topology_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ChainSafe/sygma-relayer/config/relayer"
+	"github.com/ChainSafe/sygma-relayer/topology"
+	mock_topology "github.com/ChainSafe/sygma-relayer/topology/mock"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/golang/mock/gomock"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/suite"
+)
+
+const sha256Code = 0x12
+
+type testContractCaller struct {
+	output []byte
+	err    error
+}
+
+func (c *testContractCaller) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return c.output, c.err
+}
+
+type SourceTestSuite struct {
+	suite.Suite
+	fetcher *mock_topology.MockFetcher
+}
+
+func TestRunSourceTestSuite(t *testing.T) {
+	suite.Run(t, new(SourceTestSuite))
+}
+
+func (s *SourceTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.fetcher = mock_topology.NewMockFetcher(ctrl)
+}
+
+func (s *SourceTestSuite) Test_FileSource_ValidFile() {
+	path := filepath.Join(s.T().TempDir(), "topology")
+	_ = os.WriteFile(path, []byte("0102\n"), 0600)
+
+	ct, err := topology.NewFileSource(path).EncryptedTopology()
+
+	s.Nil(err)
+	s.Equal([]byte{1, 2}, ct)
+}
+
+func (s *SourceTestSuite) Test_FileSource_MissingFile() {
+	_, err := topology.NewFileSource(filepath.Join(s.T().TempDir(), "topology")).EncryptedTopology()
+
+	s.NotNil(err)
+}
+
+func (s *SourceTestSuite) Test_IPFSSource_InvalidCID() {
+	_, err := topology.NewIPFSSource("https://ipfs.io", "invalid", s.fetcher)
+
+	s.NotNil(err)
+}
+
+func (s *SourceTestSuite) Test_IPFSSource_ValidContent() {
+	content := []byte("0102")
+	c, _ := cid.Prefix{Version: 1, Codec: cid.Raw, MhType: sha256Code, MhLength: -1}.Sum(content)
+	s.fetcher.EXPECT().Get(fmt.Sprintf("https://ipfs.io/ipfs/%s", c)).Return(&http.Response{
+		Body: io.NopCloser(strings.NewReader(string(content))),
+	}, nil)
+	source, err := topology.NewIPFSSource("https://ipfs.io/", c.String(), s.fetcher)
+	s.Nil(err)
+
+	ct, err := source.EncryptedTopology()
+
+	s.Nil(err)
+	s.Equal([]byte{1, 2}, ct)
+}
+
+func (s *SourceTestSuite) Test_IPFSSource_TamperedContent() {
+	c, _ := cid.Prefix{Version: 1, Codec: cid.Raw, MhType: sha256Code, MhLength: -1}.Sum([]byte("0102"))
+	s.fetcher.EXPECT().Get(fmt.Sprintf("https://ipfs.io/ipfs/%s", c)).Return(&http.Response{
+		Body: io.NopCloser(strings.NewReader("0103")),
+	}, nil)
+	source, err := topology.NewIPFSSource("https://ipfs.io", c.String(), s.fetcher)
+	s.Nil(err)
+
+	_, err = source.EncryptedTopology()
+
+	s.NotNil(err)
+}
+
+func (s *SourceTestSuite) Test_IPFSSource_UnverifiableCodec() {
+	c, _ := cid.Prefix{Version: 1, Codec: cid.DagProtobuf, MhType: sha256Code, MhLength: -1}.Sum([]byte("0102"))
+
+	_, err := topology.NewIPFSSource("https://ipfs.io", c.String(), s.fetcher)
+
+	s.NotNil(err)
+}
+
+func (s *SourceTestSuite) Test_ContractSource_CallFails() {
+	source := topology.NewContractSource(&testContractCaller{err: fmt.Errorf("error")}, common.Address{})
+
+	_, err := source.EncryptedTopology()
+
+	s.NotNil(err)
+}
+
+func (s *SourceTestSuite) Test_ContractSource_ValidTopology() {
+	a, _ := abi.JSON(strings.NewReader(topology.TopologyRegistryABI))
+	output, _ := a.Methods["topology"].Outputs.Pack([]byte{1, 2})
+	source := topology.NewContractSource(&testContractCaller{output: output}, common.Address{})
+
+	ct, err := source.EncryptedTopology()
+
+	s.Nil(err)
+	s.Equal([]byte{1, 2}, ct)
+}
+
+func (s *SourceTestSuite) Test_NewSource_InvalidContractAddress() {
+	_, err := topology.NewSource(relayer.TopologySource{
+		Type:    topology.ContractSourceType,
+		Url:     "http://localhost:8545",
+		Address: "invalid",
+	}, s.fetcher)
+
+	s.NotNil(err)
+}
+
+type MultiSourceTopologyProviderTestSuite struct {
+	suite.Suite
+	encryption *topology.TopologyEncryption
+}
+
+func TestRunMultiSourceTopologyProviderTestSuite(t *testing.T) {
+	suite.Run(t, new(MultiSourceTopologyProviderTestSuite))
+}
+
+func (s *MultiSourceTopologyProviderTestSuite) SetupTest() {
+	s.encryption, _ = topology.NewTopologyEncryption([]byte("qwertyuiopasdfgh"), false)
+}
+
+func (s *MultiSourceTopologyProviderTestSuite) provider(rawTopology *topology.RawTopology) *topology.TopologyProvider {
+	provider, _ := s.providerWithHash(rawTopology)
+	return provider
+}
+
+// providerWithHash creates the provider with the newly encrypted topology and returns the encrypted topology hash
+func (s *MultiSourceTopologyProviderTestSuite) providerWithHash(rawTopology *topology.RawTopology) (*topology.TopologyProvider, string) {
+	path := filepath.Join(s.T().TempDir(), "topology")
+	hash := ""
+	if rawTopology != nil {
+		data, _ := json.Marshal(rawTopology)
+		ct, _ := s.encryption.Encrypt(data)
+		_ = os.WriteFile(path, []byte(hex.EncodeToString(ct)), 0600)
+		h := sha256.Sum256(ct)
+		hash = hex.EncodeToString(h[:])
+	}
+	return topology.NewTopologyProvider(topology.NewFileSource(path), s.encryption, nil), hash
+}
+
+// verifiedProvider creates the provider with the newly encrypted topology that verifies signatures with the verifier
+func (s *MultiSourceTopologyProviderTestSuite) verifiedProvider(rawTopology *topology.RawTopology, verifier *topology.SignatureVerifier) *topology.TopologyProvider {
+	path := filepath.Join(s.T().TempDir(), "topology")
+	data, _ := json.Marshal(rawTopology)
+	ct, _ := s.encryption.Encrypt(data)
+	_ = os.WriteFile(path, []byte(hex.EncodeToString(ct)), 0600)
+	return topology.NewTopologyProvider(topology.NewFileSource(path), s.encryption, verifier)
+}
+
+func (s *MultiSourceTopologyProviderTestSuite) rawTopology(threshold string) *topology.RawTopology {
+	return &topology.RawTopology{
+		Peers: []topology.RawPeer{
+			{PeerAddress: "/dns4/relayer1/tcp/9000/p2p/QmcvEg7jGvuxdsUFRUiE4VdrL2P1Yeju5L83BsJvvXz7zX"},
+			{PeerAddress: "/dns4/relayer2/tcp/9001/p2p/QmeTuMtdpPB7zKDgmobEwSvxodrf5aFVSmBXX3SQJVjJaT"},
+		},
+		Threshold: threshold,
+	}
+}
+
+func (s *MultiSourceTopologyProviderTestSuite) Test_InvalidQuorum() {
+	_, err := topology.NewMultiSourceTopologyProvider([]*topology.TopologyProvider{s.provider(nil)}, 2)
+
+	s.NotNil(err)
+}
+
+func (s *MultiSourceTopologyProviderTestSuite) Test_QuorumAgrees() {
+	provider, err := topology.NewMultiSourceTopologyProvider([]*topology.TopologyProvider{
+		s.provider(s.rawTopology("1")),
+		s.provider(nil),
+		s.provider(s.rawTopology("2")),
+		s.provider(s.rawTopology("1")),
+	}, 2)
+	s.Nil(err)
+
+	tp, err := provider.NetworkTopology("")
+
+	expectedTopology, _ := topology.ProcessRawTopology(s.rawTopology("1"))
+	s.Nil(err)
+	s.Equal(expectedTopology, tp)
+}
+
+func (s *MultiSourceTopologyProviderTestSuite) Test_QuorumNotReached() {
+	provider, err := topology.NewMultiSourceTopologyProvider([]*topology.TopologyProvider{
+		s.provider(s.rawTopology("1")),
+		s.provider(nil),
+		s.provider(s.rawTopology("2")),
+	}, 2)
+	s.Nil(err)
+
+	_, err = provider.NetworkTopology("")
+
+	s.NotNil(err)
+}
+
+func (s *MultiSourceTopologyProviderTestSuite) Test_HashMatchesOneOfAgreeingSources() {
+	verifiedProvider, hash := s.providerWithHash(s.rawTopology("1"))
+	provider, err := topology.NewMultiSourceTopologyProvider([]*topology.TopologyProvider{
+		s.provider(s.rawTopology("1")),
+		s.provider(s.rawTopology("2")),
+		verifiedProvider,
+	}, 2)
+	s.Nil(err)
+
+	tp, err := provider.NetworkTopology(hash)
+
+	expectedTopology, _ := topology.ProcessRawTopology(s.rawTopology("1"))
+	s.Nil(err)
+	s.Equal(expectedTopology, tp)
+}
+
+func (s *MultiSourceTopologyProviderTestSuite) Test_QuorumWithoutHashMatch() {
+	_, hash := s.providerWithHash(s.rawTopology("1"))
+	provider, err := topology.NewMultiSourceTopologyProvider([]*topology.TopologyProvider{
+		s.provider(s.rawTopology("1")),
+		s.provider(s.rawTopology("1")),
+	}, 2)
+	s.Nil(err)
+
+	_, err = provider.NetworkTopology(hash)
+
+	s.NotNil(err)
+}
+
+func (s *MultiSourceTopologyProviderTestSuite) Test_HashMatchingSourceWithoutQuorum() {
+	verifiedProvider, hash := s.providerWithHash(s.rawTopology("2"))
+	provider, err := topology.NewMultiSourceTopologyProvider([]*topology.TopologyProvider{
+		s.provider(s.rawTopology("1")),
+		s.provider(s.rawTopology("1")),
+		verifiedProvider,
+	}, 2)
+	s.Nil(err)
+
+	_, err = provider.NetworkTopology(hash)
+
+	s.NotNil(err)
+}
+
+func (s *MultiSourceTopologyProviderTestSuite) Test_VersionNotPersistedWithoutQuorum() {
+	key, _ := crypto.GenerateKey()
+	versionPath := filepath.Join(s.T().TempDir(), "topology.version")
+	verifier, _ := topology.NewSignatureVerifier([]common.Address{crypto.PubkeyToAddress(key.PublicKey)}, 1, versionPath)
+	newTopology := s.rawTopology("1")
+	newTopology.Version = 2
+	_ = topology.SignTopology(newTopology, key)
+	oldTopology := s.rawTopology("1")
+	oldTopology.Version = 1
+	_ = topology.SignTopology(oldTopology, key)
+	provider, err := topology.NewMultiSourceTopologyProvider([]*topology.TopologyProvider{
+		s.verifiedProvider(newTopology, verifier),
+		s.verifiedProvider(oldTopology, verifier),
+	}, 2)
+	s.Nil(err)
+
+	_, err = provider.NetworkTopology("")
+
+	s.NotNil(err)
+	_, err = os.Stat(versionPath)
+	s.True(os.IsNotExist(err))
+}
+
+func (s *MultiSourceTopologyProviderTestSuite) Test_VersionPersistedAfterQuorum() {
+	key, _ := crypto.GenerateKey()
+	versionPath := filepath.Join(s.T().TempDir(), "topology.version")
+	verifier, _ := topology.NewSignatureVerifier([]common.Address{crypto.PubkeyToAddress(key.PublicKey)}, 1, versionPath)
+	rawTopology := s.rawTopology("1")
+	rawTopology.Version = 2
+	_ = topology.SignTopology(rawTopology, key)
+	provider, err := topology.NewMultiSourceTopologyProvider([]*topology.TopologyProvider{
+		s.verifiedProvider(rawTopology, verifier),
+		s.verifiedProvider(rawTopology, verifier),
+	}, 2)
+	s.Nil(err)
+
+	_, err = provider.NetworkTopology("")
+
+	s.Nil(err)
+	version, err := os.ReadFile(versionPath)
+	s.Nil(err)
+	s.Equal("2", strings.TrimSpace(string(version)))
+}
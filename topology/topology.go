@@ -8,13 +8,13 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"strconv"
-	"strings"
 
 	"github.com/ChainSafe/sygma-relayer/config/relayer"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/libp2p/go-libp2p/core/peer"
+	ma "github.com/multiformats/go-multiaddr"
 	"github.com/rs/zerolog/log"
 )
 
@@ -33,9 +33,60 @@ func (nt NetworkTopology) IsAllowedPeer(peer peer.ID) bool {
 	return false
 }
 
+// IsAllowedRelay checks if the peer is used as a circuit relay in any of the topology addresses
+func (nt NetworkTopology) IsAllowedRelay(peerID peer.ID) bool {
+	for _, p := range nt.Peers {
+		for _, relay := range relays(p) {
+			if relay.ID == peerID {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Relays returns circuit relays from relayed addresses of the peer. Peers that are
+// not publicly reachable advertise relayed addresses in the form of
+// /ip4/<relay ip>/tcp/<relay port>/p2p/<relay ID>/p2p-circuit/p2p/<peer ID>.
+func (nt NetworkTopology) Relays(peerID peer.ID) []peer.AddrInfo {
+	relayInfos := make([]peer.AddrInfo, 0)
+	for _, p := range nt.Peers {
+		if p.ID != peerID {
+			continue
+		}
+		relayInfos = append(relayInfos, relays(p)...)
+	}
+
+	return relayInfos
+}
+
+func relays(p *peer.AddrInfo) []peer.AddrInfo {
+	relayInfos := make([]peer.AddrInfo, 0)
+	for _, addr := range p.Addrs {
+		relayAddr, _ := ma.SplitFunc(addr, func(c ma.Component) bool {
+			return c.Protocol().Code == ma.P_CIRCUIT
+		})
+		if relayAddr == nil || relayAddr.Equal(addr) {
+			continue
+		}
+
+		relayInfo, err := peer.AddrInfoFromP2pAddr(relayAddr)
+		if err != nil {
+			continue
+		}
+		relayInfos = append(relayInfos, *relayInfo)
+	}
+
+	return relayInfos
+}
+
 type RawTopology struct {
 	Peers     []RawPeer `mapstructure:"Peers" json:"peers"`
 	Threshold string    `mapstructure:"Threshold" json:"threshold"`
+	// Version is increased with each signed topology so older signed topologies can't be replayed
+	Version    uint64   `mapstructure:"Version" json:"version,omitempty"`
+	Signatures []string `mapstructure:"Signatures" json:"signatures,omitempty"`
 }
 
 type RawPeer struct {
@@ -46,7 +97,7 @@ type Fetcher interface {
 }
 
 type Decrypter interface {
-	Decrypt(data []byte) []byte
+	Decrypt(data []byte) ([]byte, error)
 }
 
 type NetworkTopologyProvider interface {
@@ -55,56 +106,129 @@ type NetworkTopologyProvider interface {
 	NetworkTopology(hash string) (*NetworkTopology, error)
 }
 
+// NewNetworkTopologyProvider creates the topology provider from the configured sources.
+// Topology is read from the configuration URL if no sources are configured.
 func NewNetworkTopologyProvider(config relayer.TopologyConfiguration, fetcher Fetcher) (NetworkTopologyProvider, error) {
-	decrypter, err := NewAESEncryption([]byte(config.EncryptionKey))
+	decrypter, err := NewTopologyEncryption([]byte(config.EncryptionKey), config.AllowLegacyEncryption)
 	if err != nil {
 		return nil, err
 	}
 
-	return &TopologyProvider{
-		decrypter: decrypter,
-		url:       config.Url,
-		fetcher:   fetcher,
-	}, nil
+	var verifier *SignatureVerifier
+	if len(config.GovernanceKeys) > 0 {
+		signers := make([]common.Address, len(config.GovernanceKeys))
+		for i, key := range config.GovernanceKeys {
+			if !common.IsHexAddress(key) {
+				return nil, fmt.Errorf("invalid governance key %s", key)
+			}
+			signers[i] = common.HexToAddress(key)
+		}
+		threshold := config.GovernanceThreshold
+		if threshold == 0 {
+			threshold = len(signers)
+		}
+		versionPath := ""
+		if config.Path != "" {
+			versionPath = config.Path + ".version"
+		}
+		verifier, err = NewSignatureVerifier(signers, threshold, versionPath)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		log.Warn().Msgf("Topology governance keys not configured, topology signatures are not verified")
+	}
+
+	if len(config.Sources) == 0 {
+		return NewTopologyProvider(NewURLSource(config.Url, fetcher), decrypter, verifier), nil
+	}
+
+	providers := make([]*TopologyProvider, len(config.Sources))
+	for i, sourceConfig := range config.Sources {
+		source, err := NewSource(sourceConfig, fetcher)
+		if err != nil {
+			return nil, err
+		}
+		providers[i] = NewTopologyProvider(source, decrypter, verifier)
+	}
+	if len(providers) == 1 {
+		return providers[0], nil
+	}
+
+	quorum := config.Quorum
+	if quorum == 0 {
+		quorum = len(providers)/2 + 1
+	}
+	return NewMultiSourceTopologyProvider(providers, quorum)
 }
 
 type TopologyProvider struct {
-	url       string
+	source    Source
 	decrypter Decrypter
-	fetcher   Fetcher
+	verifier  *SignatureVerifier
 }
 
-func (t *TopologyProvider) NetworkTopology(hash string) (*NetworkTopology, error) {
-	log.Info().Msgf("Reading topology from URL: %s", t.url)
+// NewTopologyProvider creates the topology provider that verifies topology
+// signatures with the verifier if it is provided
+func NewTopologyProvider(source Source, decrypter Decrypter, verifier *SignatureVerifier) *TopologyProvider {
+	return &TopologyProvider{
+		source:    source,
+		decrypter: decrypter,
+		verifier:  verifier,
+	}
+}
 
-	resp, err := t.fetcher.Get(t.url)
+func (t *TopologyProvider) NetworkTopology(hash string) (*NetworkTopology, error) {
+	ct, eh, err := t.encryptedTopology()
 	if err != nil {
 		return nil, err
 	}
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
+	if hash != "" && eh != hash {
+		return nil, fmt.Errorf("topology hash %s not matching expected hash %s", string(eh), hash)
 	}
-	response := strings.TrimSuffix(string(body), "\n")
-	ct, err := hex.DecodeString(response)
+
+	topology, _, err := t.decryptTopology(ct, true)
+	return topology, err
+}
+
+// encryptedTopology reads the encrypted topology from the source and returns it with its hash
+func (t *TopologyProvider) encryptedTopology() ([]byte, string, error) {
+	log.Info().Msgf("Reading topology from %s", t.source)
+
+	ct, err := t.source.EncryptedTopology()
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	h := sha256.New()
 	h.Write(ct)
-	eh := hex.EncodeToString(h.Sum(nil))
-	if hash != "" && eh != hash {
-		return nil, fmt.Errorf("topology hash %s not matching expected hash %s", string(eh), hash)
-	}
+	return ct, hex.EncodeToString(h.Sum(nil)), nil
+}
 
-	unecryptedBody := t.decrypter.Decrypt(ct)
+// decryptTopology decrypts and verifies the topology and returns it with its version.
+// The topology version is persisted only if accept is set.
+func (t *TopologyProvider) decryptTopology(ct []byte, accept bool) (*NetworkTopology, uint64, error) {
+	unecryptedBody, err := t.decrypter.Decrypt(ct)
+	if err != nil {
+		return nil, 0, err
+	}
 	rawTopology := &RawTopology{}
 	err = json.Unmarshal(unecryptedBody, rawTopology)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+	if t.verifier != nil {
+		if accept {
+			err = t.verifier.Verify(rawTopology)
+		} else {
+			err = t.verifier.VerifySignatures(rawTopology)
+		}
+		if err != nil {
+			return nil, 0, err
+		}
 	}
 
-	return ProcessRawTopology(rawTopology)
+	topology, err := ProcessRawTopology(rawTopology)
+	return topology, rawTopology.Version, err
 }
 
 func ProcessRawTopology(rawTopology *RawTopology) (*NetworkTopology, error) {
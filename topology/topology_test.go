@@ -125,6 +125,27 @@ func (s *NetworkTopologyTestSuite) Test_IsAllowedPeer_InvalidPeer() {
 	s.Equal(isAllowed, false)
 }
 
+func (s *NetworkTopologyTestSuite) Test_Relays_ReturnsRelaysFromRelayedAddresses() {
+	p1RawAddress := "/ip4/127.0.0.1/tcp/4000/p2p/QmcW3oMdSqoEcjbyd51auqC23vhKX6BqfcZcY2HJ3sKAZR"
+	p2RawAddress := "/ip4/127.0.0.1/tcp/4002/p2p/QmeWhpY8tknHS29gzf9TAsNEwfejTCNJ7vFpmkV6rNUgyq"
+	p2RelayedAddress := "/ip4/127.0.0.1/tcp/4000/p2p/QmcW3oMdSqoEcjbyd51auqC23vhKX6BqfcZcY2HJ3sKAZR/p2p-circuit/p2p/QmeWhpY8tknHS29gzf9TAsNEwfejTCNJ7vFpmkV6rNUgyq"
+	p1, _ := peer.AddrInfoFromString(p1RawAddress)
+	p2, _ := peer.AddrInfoFromString(p2RawAddress)
+	p2Relayed, err := peer.AddrInfoFromString(p2RelayedAddress)
+	s.Nil(err)
+	topology := topology.NetworkTopology{
+		Peers: []*peer.AddrInfo{
+			p1, p2, p2Relayed,
+		},
+		Threshold: 2,
+	}
+
+	s.Equal(topology.Relays(p2.ID), []peer.AddrInfo{*p1})
+	s.Equal(topology.Relays(p1.ID), []peer.AddrInfo{})
+	s.Equal(topology.IsAllowedRelay(p1.ID), true)
+	s.Equal(topology.IsAllowedRelay(p2.ID), false)
+}
+
 type TopologyProviderTestSuite struct {
 	suite.Suite
 	fetcher *mock_topology.MockFetcher
@@ -143,8 +164,9 @@ func (s *TopologyProviderTestSuite) SetupTest() {
 func (s *TopologyProviderTestSuite) Test_FetchingTopologyFails() {
 	s.fetcher.EXPECT().Get("test.url").Return(&http.Response{}, fmt.Errorf("error"))
 	topologyConfiguration := relayer.TopologyConfiguration{
-		Url:           "test.url",
-		EncryptionKey: "qwertyuiopasdfgh",
+		Url:                   "test.url",
+		EncryptionKey:         "qwertyuiopasdfgh",
+		AllowLegacyEncryption: true,
 	}
 	topologyProvider, _ := topology.NewNetworkTopologyProvider(topologyConfiguration, s.fetcher)
 
@@ -153,13 +175,32 @@ func (s *TopologyProviderTestSuite) Test_FetchingTopologyFails() {
 	s.NotNil(err)
 }
 
+func (s *TopologyProviderTestSuite) Test_UnsignedTopology() {
+	resp := &http.Response{}
+	resp.Body = io.NopCloser(strings.NewReader("f533758136cd1f62c3c7fd96b41d439ce3c899b0e705ecebd567275e4447683f80c21d9cf6287d3ac504f116c18308d34fd1f79cda675983dc01231cdb13db39f271f37bbc4ed9f89b87b04ed74cb4de382e43809a2e690c7a0872c1c2eec631455628621291803d34c73965917b52b44e713d927db805bbc145a2fe51c7352ab8b34f216a57c19e2e3dca27a1cf2013a9e6ece2989fd90bff45ad614520419bc132bd07d4aa89f1afb4016ba16b8de0b8921071ab99d86f4c15672c08ad98a55c0b179cff340dc128c3f8a56876d9a75aec735924fcba5f21ae6e64cf875f23cc1fdef4ae5c3d0f43e421d75161fd44d3a7a4cbab3c6ff84e7ff3b83582944c93627c75ad93262d057889e53d48263749dab0355adc8f949b946f3da3e9a4a104728a4f56214bb177bd5d59a257cf55befb53b6bff1b293f883bd60b7c1aa13c75e8ffd394b130ab6d867e60bfef67c78432663775093023c66bbad812bdda890de43b5491dd27a75ae27b79d85afc0ff390b531743642066c200ea5a405ef746041fa5fbf75c23c4dd35a1cc9854b01f1aaeec4265b4c46145a99e6b02eba82408903117fa34917368d5012420a2f985d2eac929c758d487e93f7779ae8ba6ff0f7f1eca1997abbc3ff0efdf"))
+	s.fetcher.EXPECT().Get("test.url").Return(resp, nil)
+	topologyConfiguration := relayer.TopologyConfiguration{
+		Url:                   "test.url",
+		EncryptionKey:         "qwertyuiopasdfgh",
+		GovernanceKeys:        []string{"0x5C1F5961696BaD2e73f73417f07EF55C62a2dC5b"},
+		AllowLegacyEncryption: true,
+	}
+	topologyProvider, err := topology.NewNetworkTopologyProvider(topologyConfiguration, s.fetcher)
+	s.Nil(err)
+
+	_, err = topologyProvider.NetworkTopology("")
+
+	s.NotNil(err)
+}
+
 func (s *TopologyProviderTestSuite) Test_ValidTopology() {
 	resp := &http.Response{}
 	resp.Body = io.NopCloser(strings.NewReader("f533758136cd1f62c3c7fd96b41d439ce3c899b0e705ecebd567275e4447683f80c21d9cf6287d3ac504f116c18308d34fd1f79cda675983dc01231cdb13db39f271f37bbc4ed9f89b87b04ed74cb4de382e43809a2e690c7a0872c1c2eec631455628621291803d34c73965917b52b44e713d927db805bbc145a2fe51c7352ab8b34f216a57c19e2e3dca27a1cf2013a9e6ece2989fd90bff45ad614520419bc132bd07d4aa89f1afb4016ba16b8de0b8921071ab99d86f4c15672c08ad98a55c0b179cff340dc128c3f8a56876d9a75aec735924fcba5f21ae6e64cf875f23cc1fdef4ae5c3d0f43e421d75161fd44d3a7a4cbab3c6ff84e7ff3b83582944c93627c75ad93262d057889e53d48263749dab0355adc8f949b946f3da3e9a4a104728a4f56214bb177bd5d59a257cf55befb53b6bff1b293f883bd60b7c1aa13c75e8ffd394b130ab6d867e60bfef67c78432663775093023c66bbad812bdda890de43b5491dd27a75ae27b79d85afc0ff390b531743642066c200ea5a405ef746041fa5fbf75c23c4dd35a1cc9854b01f1aaeec4265b4c46145a99e6b02eba82408903117fa34917368d5012420a2f985d2eac929c758d487e93f7779ae8ba6ff0f7f1eca1997abbc3ff0efdf"))
 	s.fetcher.EXPECT().Get("test.url").Return(resp, nil)
 	topologyConfiguration := relayer.TopologyConfiguration{
-		Url:           "test.url",
-		EncryptionKey: "qwertyuiopasdfgh",
+		Url:                   "test.url",
+		EncryptionKey:         "qwertyuiopasdfgh",
+		AllowLegacyEncryption: true,
 	}
 	topologyProvider, _ := topology.NewNetworkTopologyProvider(topologyConfiguration, s.fetcher)
 
@@ -183,8 +224,9 @@ func (s *TopologyProviderTestSuite) Test_InvalidHash() {
 	resp.Body = io.NopCloser(strings.NewReader("f533758136cd1f62c3c7fd96b41d439ce3c899b0e705ecebd567275e4447683f80c21d9cf6287d3ac504f116c18308d34fd1f79cda675983dc01231cdb13db39f271f37bbc4ed9f89b87b04ed74cb4de382e43809a2e690c7a0872c1c2eec631455628621291803d34c73965917b52b44e713d927db805bbc145a2fe51c7352ab8b34f216a57c19e2e3dca27a1cf2013a9e6ece2989fd90bff45ad614520419bc132bd07d4aa89f1afb4016ba16b8de0b8921071ab99d86f4c15672c08ad98a55c0b179cff340dc128c3f8a56876d9a75aec735924fcba5f21ae6e64cf875f23cc1fdef4ae5c3d0f43e421d75161fd44d3a7a4cbab3c6ff84e7ff3b83582944c93627c75ad93262d057889e53d48263749dab0355adc8f949b946f3da3e9a4a104728a4f56214bb177bd5d59a257cf55befb53b6bff1b293f883bd60b7c1aa13c75e8ffd394b130ab6d867e60bfef67c78432663775093023c66bbad812bdda890de43b5491dd27a75ae27b79d85afc0ff390b531743642066c200ea5a405ef746041fa5fbf75c23c4dd35a1cc9854b01f1aaeec4265b4c46145a99e6b02eba82408903117fa34917368d5012420a2f985d2eac929c758d487e93f7779ae8ba6ff0f7f1eca1997abbc3ff0efdf"))
 	s.fetcher.EXPECT().Get("test.url").Return(resp, nil)
 	topologyConfiguration := relayer.TopologyConfiguration{
-		Url:           "test.url",
-		EncryptionKey: "qwertyuiopasdfgh",
+		Url:                   "test.url",
+		EncryptionKey:         "qwertyuiopasdfgh",
+		AllowLegacyEncryption: true,
 	}
 	topologyProvider, _ := topology.NewNetworkTopologyProvider(topologyConfiguration, s.fetcher)
 
@@ -198,8 +240,9 @@ func (s *TopologyProviderTestSuite) Test_ValidHash() {
 	resp.Body = io.NopCloser(strings.NewReader("f533758136cd1f62c3c7fd96b41d439ce3c899b0e705ecebd567275e4447683f80c21d9cf6287d3ac504f116c18308d34fd1f79cda675983dc01231cdb13db39f271f37bbc4ed9f89b87b04ed74cb4de382e43809a2e690c7a0872c1c2eec631455628621291803d34c73965917b52b44e713d927db805bbc145a2fe51c7352ab8b34f216a57c19e2e3dca27a1cf2013a9e6ece2989fd90bff45ad614520419bc132bd07d4aa89f1afb4016ba16b8de0b8921071ab99d86f4c15672c08ad98a55c0b179cff340dc128c3f8a56876d9a75aec735924fcba5f21ae6e64cf875f23cc1fdef4ae5c3d0f43e421d75161fd44d3a7a4cbab3c6ff84e7ff3b83582944c93627c75ad93262d057889e53d48263749dab0355adc8f949b946f3da3e9a4a104728a4f56214bb177bd5d59a257cf55befb53b6bff1b293f883bd60b7c1aa13c75e8ffd394b130ab6d867e60bfef67c78432663775093023c66bbad812bdda890de43b5491dd27a75ae27b79d85afc0ff390b531743642066c200ea5a405ef746041fa5fbf75c23c4dd35a1cc9854b01f1aaeec4265b4c46145a99e6b02eba82408903117fa34917368d5012420a2f985d2eac929c758d487e93f7779ae8ba6ff0f7f1eca1997abbc3ff0efdf"))
 	s.fetcher.EXPECT().Get("test.url").Return(resp, nil)
 	topologyConfiguration := relayer.TopologyConfiguration{
-		Url:           "test.url",
-		EncryptionKey: "qwertyuiopasdfgh",
+		Url:                   "test.url",
+		EncryptionKey:         "qwertyuiopasdfgh",
+		AllowLegacyEncryption: true,
 	}
 	topologyProvider, _ := topology.NewNetworkTopologyProvider(topologyConfiguration, s.fetcher)
 
@@ -48,6 +48,9 @@ type Coordinator struct {
 	CoordinatorTimeout time.Duration
 	TssTimeout         time.Duration
 	InitiatePeriod     time.Duration
+	// ElectorType is the coordinator election strategy used on the first process run,
+	// retries always use bully coordinator election
+	ElectorType elector.CoordinatorElectorType
 }
 
 func NewCoordinator(
@@ -65,6 +68,7 @@ func NewCoordinator(
 		CoordinatorTimeout: coordinatorTimeout,
 		TssTimeout:         tssTimeout,
 		InitiatePeriod:     initiatePeriod,
+		ElectorType:        elector.Static,
 	}
 }
 
@@ -73,13 +77,12 @@ func NewCoordinator(
 // the result of all of them is needed. The processes should have an unique session ID for each one.
 func (c *Coordinator) Execute(ctx context.Context, tssProcesses []TssProcess, resultChn chan interface{}) error {
 	sessionID := tssProcesses[0].SessionID()
-	value, ok := c.pendingProcesses[sessionID]
-	if ok && value {
+	c.processLock.Lock()
+	if c.pendingProcesses[sessionID] {
+		c.processLock.Unlock()
 		log.Warn().Str("SessionID", sessionID).Msgf("Process already pending")
 		return fmt.Errorf("process already pending")
 	}
-
-	c.processLock.Lock()
 	c.pendingProcesses[sessionID] = true
 	c.processLock.Unlock()
 
@@ -96,7 +99,7 @@ func (c *Coordinator) Execute(ctx context.Context, tssProcesses []TssProcess, re
 		}
 	}()
 
-	coordinatorElector := c.electorFactory.CoordinatorElector(sessionID, elector.Static)
+	coordinatorElector := c.electorFactory.CoordinatorElector(sessionID, c.ElectorType)
 	coordinator, _ := coordinatorElector.Coordinator(ctx, tssProcesses[0].ValidCoordinators())
 
 	log.Info().Str("SessionID", sessionID).Msgf("Starting process with coordinator %s", coordinator.Pretty())
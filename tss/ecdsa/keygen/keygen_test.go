@@ -10,6 +10,7 @@ import (
 
 	"github.com/ChainSafe/sygma-relayer/comm"
 	"github.com/ChainSafe/sygma-relayer/comm/elector"
+	"github.com/ChainSafe/sygma-relayer/comm/p2p"
 	"github.com/ChainSafe/sygma-relayer/tss"
 	"github.com/ChainSafe/sygma-relayer/tss/ecdsa/keygen"
 	tsstest "github.com/ChainSafe/sygma-relayer/tss/test"
@@ -39,7 +40,7 @@ func (s *KeygenTestSuite) Test_ValidKeygenProcess() {
 		}
 		communicationMap[host.ID()] = &communication
 		keygen := keygen.NewKeygen("keygen", s.Threshold, host, &communication, s.MockECDSAStorer)
-		electorFactory := elector.NewCoordinatorElectorFactory(host, s.BullyConfig)
+		electorFactory := elector.NewCoordinatorElectorFactory(host, s.BullyConfig, nil, p2p.CommunicationConfig{})
 		coordinators = append(coordinators, tss.NewCoordinator(host, &communication, electorFactory))
 		processes = append(processes, keygen)
 	}
@@ -57,6 +58,33 @@ func (s *KeygenTestSuite) Test_ValidKeygenProcess() {
 	s.Nil(err)
 }
 
+func (s *KeygenTestSuite) Test_ValidKeygenProcess_TreeBroadcastCommunication() {
+	communications := tsstest.NewTreeBroadcastCommunications(s.CoordinatorTestSuite.Hosts, "p2p/tree-broadcast-test", 1)
+	coordinators := []*tss.Coordinator{}
+	processes := []tss.TssProcess{}
+
+	for _, host := range s.CoordinatorTestSuite.Hosts {
+		communication := communications[host.ID()]
+		keygen := keygen.NewKeygen("keygen-tree-broadcast", s.Threshold, host, communication, s.MockECDSAStorer)
+		electorFactory := elector.NewCoordinatorElectorFactory(host, s.BullyConfig, nil, p2p.CommunicationConfig{})
+		coordinators = append(coordinators, tss.NewCoordinator(host, communication, electorFactory))
+		processes = append(processes, keygen)
+	}
+
+	s.MockECDSAStorer.EXPECT().LockKeyshare().Times(3)
+	s.MockECDSAStorer.EXPECT().UnlockKeyshare().Times(3)
+	s.MockECDSAStorer.EXPECT().StoreKeyshare(gomock.Any()).Times(3)
+	pool := pool.New().WithContext(context.Background()).WithCancelOnError()
+	for i, coordinator := range coordinators {
+		i := i
+		coordinator := coordinator
+		pool.Go(func(ctx context.Context) error { return coordinator.Execute(ctx, []tss.TssProcess{processes[i]}, nil) })
+	}
+
+	err := pool.Wait()
+	s.Nil(err)
+}
+
 func (s *KeygenTestSuite) Test_KeygenTimeout() {
 	communicationMap := make(map[peer.ID]*tsstest.TestCommunication)
 	coordinators := []*tss.Coordinator{}
@@ -68,7 +96,7 @@ func (s *KeygenTestSuite) Test_KeygenTimeout() {
 		}
 		communicationMap[host.ID()] = &communication
 		keygen := keygen.NewKeygen("keygen2", s.Threshold, host, &communication, s.MockECDSAStorer)
-		electorFactory := elector.NewCoordinatorElectorFactory(host, s.BullyConfig)
+		electorFactory := elector.NewCoordinatorElectorFactory(host, s.BullyConfig, nil, p2p.CommunicationConfig{})
 		coordinator := tss.NewCoordinator(host, &communication, electorFactory)
 		coordinator.TssTimeout = time.Millisecond
 		coordinators = append(coordinators, coordinator)
@@ -35,7 +35,6 @@ type SaveDataFetcher interface {
 
 type Signing struct {
 	common.BaseTss
-	coordinator    bool
 	key            keyshare.ECDSAKeyshare
 	msg            *big.Int
 	resultChn      chan interface{}
@@ -81,7 +80,6 @@ func (s *Signing) Run(
 	resultChn chan interface{},
 	params []byte,
 ) error {
-	s.coordinator = coordinator
 	s.resultChn = resultChn
 	ctx, s.Cancel = context.WithCancel(ctx)
 
@@ -186,7 +184,8 @@ func (s *Signing) unmarshallStartParams(paramBytes []byte) ([]peer.ID, error) {
 	return peerSubset, nil
 }
 
-// processEndMessage routes signature to result channel.
+// processEndMessage routes signature to result channel of every participant so
+// participants can submit the signature if the designated submitter fails.
 func (s *Signing) processEndMessage(ctx context.Context, endChn chan tssCommon.SignatureData) error {
 	defer s.Cancel()
 	for {
@@ -196,11 +195,7 @@ func (s *Signing) processEndMessage(ctx context.Context, endChn chan tssCommon.S
 			{
 				s.Log.Info().Msg("Successfully generated signature")
 
-				if s.coordinator {
-					s.resultChn <- &sig
-				} else {
-					s.resultChn <- nil
-				}
+				s.resultChn <- &sig
 
 				return nil
 			}
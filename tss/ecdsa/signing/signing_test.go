@@ -12,6 +12,7 @@ import (
 
 	"github.com/ChainSafe/sygma-relayer/comm"
 	"github.com/ChainSafe/sygma-relayer/comm/elector"
+	"github.com/ChainSafe/sygma-relayer/comm/p2p"
 	"github.com/ChainSafe/sygma-relayer/keyshare"
 	"github.com/ChainSafe/sygma-relayer/tss"
 	"github.com/ChainSafe/sygma-relayer/tss/ecdsa/keygen"
@@ -50,7 +51,7 @@ func (s *SigningTestSuite) Test_ValidSigningProcess() {
 		if err != nil {
 			panic(err)
 		}
-		electorFactory := elector.NewCoordinatorElectorFactory(host, s.BullyConfig)
+		electorFactory := elector.NewCoordinatorElectorFactory(host, s.BullyConfig, nil, p2p.CommunicationConfig{})
 		coordinators = append(coordinators, tss.NewCoordinator(host, &communication, electorFactory))
 		processes = append(processes, signing)
 	}
@@ -69,10 +70,8 @@ func (s *SigningTestSuite) Test_ValidSigningProcess() {
 
 	sig1 := <-resultChn
 	sig2 := <-resultChn
-	s.NotEqual(sig1, sig2)
-	if sig1 == nil && sig2 == nil {
-		s.Fail("signature is nil")
-	}
+	s.NotNil(sig1)
+	s.Equal(sig1, sig2)
 
 	time.Sleep(time.Millisecond * 100)
 	cancel()
@@ -100,7 +99,7 @@ func (s *SigningTestSuite) Test_SigningTimeout() {
 		if err != nil {
 			panic(err)
 		}
-		electorFactory := elector.NewCoordinatorElectorFactory(host, s.BullyConfig)
+		electorFactory := elector.NewCoordinatorElectorFactory(host, s.BullyConfig, nil, p2p.CommunicationConfig{})
 		coordinator := tss.NewCoordinator(host, &communication, electorFactory)
 		coordinator.TssTimeout = time.Nanosecond
 		coordinators = append(coordinators, coordinator)
@@ -132,7 +131,7 @@ func (s *SigningTestSuite) Test_PendingProcessExists() {
 		}
 		communicationMap[host.ID()] = &communication
 		keygen := keygen.NewKeygen("keygen3", s.Threshold, host, &communication, s.MockECDSAStorer)
-		electorFactory := elector.NewCoordinatorElectorFactory(host, s.BullyConfig)
+		electorFactory := elector.NewCoordinatorElectorFactory(host, s.BullyConfig, nil, p2p.CommunicationConfig{})
 		coordinators = append(coordinators, tss.NewCoordinator(host, &communication, electorFactory))
 		processes = append(processes, keygen)
 	}
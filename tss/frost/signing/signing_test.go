@@ -12,6 +12,7 @@ import (
 
 	"github.com/ChainSafe/sygma-relayer/comm"
 	"github.com/ChainSafe/sygma-relayer/comm/elector"
+	"github.com/ChainSafe/sygma-relayer/comm/p2p"
 	"github.com/ChainSafe/sygma-relayer/keyshare"
 	"github.com/ChainSafe/sygma-relayer/tss"
 	"github.com/ChainSafe/sygma-relayer/tss/frost/signing"
@@ -61,7 +62,7 @@ func (s *SigningTestSuite) Test_ValidSigningProcess() {
 		if err != nil {
 			panic(err)
 		}
-		electorFactory := elector.NewCoordinatorElectorFactory(host, s.BullyConfig)
+		electorFactory := elector.NewCoordinatorElectorFactory(host, s.BullyConfig, nil, p2p.CommunicationConfig{})
 		coordinators = append(coordinators, tss.NewCoordinator(host, &communication, electorFactory))
 		processes = append(processes, signing)
 	}
@@ -122,7 +123,7 @@ func (s *SigningTestSuite) Test_MultipleProcesses() {
 		if err != nil {
 			panic(err)
 		}
-		electorFactory := elector.NewCoordinatorElectorFactory(host, s.BullyConfig)
+		electorFactory := elector.NewCoordinatorElectorFactory(host, s.BullyConfig, nil, p2p.CommunicationConfig{})
 		coordinator := tss.NewCoordinator(host, &communication, electorFactory)
 		coordinators = append(coordinators, coordinator)
 		processes = append(processes, []tss.TssProcess{signing1, signing2, signing3})
@@ -180,7 +181,7 @@ func (s *SigningTestSuite) Test_ProcessTimeout() {
 		if err != nil {
 			panic(err)
 		}
-		electorFactory := elector.NewCoordinatorElectorFactory(host, s.BullyConfig)
+		electorFactory := elector.NewCoordinatorElectorFactory(host, s.BullyConfig, nil, p2p.CommunicationConfig{})
 		coordinator := tss.NewCoordinator(host, &communication, electorFactory)
 		coordinator.TssTimeout = time.Nanosecond
 		coordinators = append(coordinators, coordinator)
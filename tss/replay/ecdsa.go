@@ -0,0 +1,126 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package replay
+
+import (
+	"fmt"
+	"math/big"
+
+	tssCommon "github.com/binance-chain/tss-lib/common"
+	"github.com/binance-chain/tss-lib/ecdsa/signing"
+	"github.com/binance-chain/tss-lib/tss"
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/ChainSafe/sygma-relayer/comm/recorder"
+	"github.com/ChainSafe/sygma-relayer/keyshare"
+	"github.com/ChainSafe/sygma-relayer/tss/ecdsa/common"
+	"github.com/ChainSafe/sygma-relayer/tss/message"
+)
+
+// ECDSASigning replays recorded ECDSA signing session attempts with the keyshare of the recording party
+func ECDSASigning(session *recorder.Session, key keyshare.ECDSAKeyshare) ([]*Report, error) {
+	return replay(session, func(session *recorder.Session, peers peer.IDSlice, records []*recorder.Record) (*Report, error) {
+		return replayECDSASigning(session, key, peers, records)
+	})
+}
+
+func replayECDSASigning(
+	session *recorder.Session,
+	key keyshare.ECDSAKeyshare,
+	peers peer.IDSlice,
+	records []*recorder.Record,
+) (*Report, error) {
+	parties := common.PartiesFromPeers(peers)
+	partyStore := make(map[string]*tss.PartyID)
+	for _, party := range parties {
+		partyStore[party.Id] = party
+	}
+	params, err := tss.NewParameters(tss.S256(), tss.NewPeerContext(parties), partyStore[session.Self.Pretty()], len(parties), key.Threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	outChn := make(chan tss.Message)
+	endChn := make(chan tssCommon.SignatureData)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case <-outChn:
+			case <-endChn:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	sessionID := sessionIDInt(session.ID)
+	// the signed message is used only in the last round which can not be verified offline
+	party, err := signing.NewLocalParty(big.NewInt(1), params, key.Key, big.NewInt(0), outChn, endChn, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	tssErr := party.Start()
+	if tssErr != nil {
+		return nil, tssErr
+	}
+
+	report := &Report{Peers: peers}
+	expandedSessionID := tss.ExpandSessionID(sessionID, len(tss.S256().Params().N.Bytes()))
+	for _, record := range records {
+		from, ok := partyStore[record.From.Pretty()]
+		if !ok {
+			return report.reject(record.From, fmt.Errorf("message from peer outside of the subset")), nil
+		}
+
+		msg, err := message.UnmarshalTssMessage(record.Payload)
+		if err != nil {
+			return report.reject(record.From, err), nil
+		}
+		parsedMsg, err := tss.ParseWireMessage(msg.MsgBytes, from, msg.IsBroadcast, expandedSessionID)
+		if err != nil {
+			return report.reject(record.From, err), nil
+		}
+		if !parsedMsg.ValidateBasic(tss.S256()) {
+			return report.reject(record.From, fmt.Errorf("message failed ValidateBasic: %s", parsedMsg)), nil
+		}
+
+		if _, ok := parsedMsg.Content().(*signing.PreSignRound1Message); !ok {
+			report.Validated++
+			continue
+		}
+
+		report.Verified++
+		ok, tssErr := updateParty(party, parsedMsg)
+		if tssErr != nil && len(tssErr.Culprits()) > 0 {
+			culprits, err := common.PeersFromParties(tssErr.Culprits())
+			if err != nil {
+				return nil, err
+			}
+
+			report.Culprits = culprits
+			report.Err = tssErr
+			return report, nil
+		}
+		if !ok {
+			return report.reject(record.From, tssErr), nil
+		}
+	}
+
+	return report, nil
+}
+
+// updateParty updates the party with the message and recovers from state machine
+// panics caused by malformed messages
+func updateParty(party tss.Party, msg tss.ParsedMessage) (ok bool, tssErr *tss.Error) {
+	defer func() {
+		if r := recover(); r != nil {
+			ok = false
+			tssErr = party.WrapError(fmt.Errorf("state machine panicked: %v", r), msg.GetFrom())
+		}
+	}()
+
+	return party.Update(msg)
+}
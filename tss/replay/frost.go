@@ -0,0 +1,95 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package replay
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost"
+
+	"github.com/ChainSafe/sygma-relayer/comm/recorder"
+	"github.com/ChainSafe/sygma-relayer/keyshare"
+	"github.com/ChainSafe/sygma-relayer/tss/frost/common"
+)
+
+// frostCommitmentRound is the first signing round that receives messages from other parties
+const frostCommitmentRound = 2
+
+// FrostSigning replays recorded FROST signing session attempts with the keyshare of the recording party
+func FrostSigning(session *recorder.Session, key keyshare.FrostKeyshare) ([]*Report, error) {
+	return replay(session, func(session *recorder.Session, peers peer.IDSlice, records []*recorder.Record) (*Report, error) {
+		return replayFrostSigning(session, key, peers, records)
+	})
+}
+
+func replayFrostSigning(
+	session *recorder.Session,
+	key keyshare.FrostKeyshare,
+	peers peer.IDSlice,
+	records []*recorder.Record,
+) (*Report, error) {
+	// the signed message is used only in the last round which can not be verified offline
+	handler, err := protocol.NewMultiHandler(
+		frost.SignTaproot(key.Key, common.PartyIDSFromPeers(peers), make([]byte, 32)),
+		[]byte(session.ID),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{Peers: peers}
+	committed := make(map[peer.ID]bool)
+	for _, record := range records {
+		if !contains(peers, record.From) {
+			return report.reject(record.From, fmt.Errorf("message from peer outside of the subset")), nil
+		}
+
+		msg := &protocol.Message{}
+		err := msg.UnmarshalBinary(record.Payload)
+		if err != nil {
+			return report.reject(record.From, err), nil
+		}
+		if msg.From != party.ID(record.From.String()) {
+			return report.reject(record.From, fmt.Errorf("message sent on behalf of party %s", msg.From)), nil
+		}
+		if msg.RoundNumber == frostCommitmentRound && committed[record.From] {
+			// duplicates are ignored by the state machine
+			continue
+		}
+		if !handler.CanAccept(msg) {
+			return report.reject(record.From, fmt.Errorf("message for round %d not accepted by the protocol", msg.RoundNumber)), nil
+		}
+
+		if msg.RoundNumber > frostCommitmentRound {
+			report.Validated++
+			continue
+		}
+
+		report.Verified++
+		committed[record.From] = true
+		handler.Accept(msg)
+		_, err = handler.Result()
+		protocolErr := protocol.Error{}
+		if errors.As(err, &protocolErr) {
+			culprits := make(peer.IDSlice, 0)
+			for _, culprit := range protocolErr.Culprits {
+				p, err := peer.Decode(string(culprit))
+				if err != nil {
+					return nil, err
+				}
+				culprits = append(culprits, p)
+			}
+
+			report.Culprits = culprits
+			report.Err = protocolErr
+			return report, nil
+		}
+	}
+
+	return report, nil
+}
@@ -0,0 +1,87 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+/*
+Package replay replays recorded tss sessions offline to find parties that sent malformed messages.
+
+Messages are fed to the local party state machine created from the keyshare of the recording
+party. Parties draw fresh randomness on every run, so only messages of the first protocol round,
+which do not depend on the local party randomness, are verified by the state machine. Messages of
+later rounds are validated the same way the state machine validates them before round verification.
+*/
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/ChainSafe/sygma-relayer/comm/recorder"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// Report is the result of replaying a single attempt of the session
+type Report struct {
+	// Peers is the peer subset of the attempt
+	Peers peer.IDSlice
+	// Culprits are peers whose messages were rejected
+	Culprits peer.IDSlice
+	// Err is the reason the messages of culprits were rejected
+	Err error
+	// Verified is the number of messages verified by the state machine
+	Verified int
+	// Validated is the number of later round messages only validated
+	Validated int
+}
+
+func (r *Report) reject(culprit peer.ID, err error) *Report {
+	r.Culprits = peer.IDSlice{culprit}
+	r.Err = err
+	return r
+}
+
+type replayFunc func(session *recorder.Session, peers peer.IDSlice, records []*recorder.Record) (*Report, error)
+
+// replay replays each attempt of the signing session with the peer subset sent by the coordinator
+func replay(session *recorder.Session, replayAttempt replayFunc) ([]*Report, error) {
+	attempts, err := session.Attempts()
+	if err != nil {
+		return nil, err
+	}
+	if len(attempts) == 0 {
+		return nil, fmt.Errorf("no start message recorded in session %s", session.ID)
+	}
+
+	reports := make([]*Report, 0)
+	for i, attempt := range attempts {
+		peers := peer.IDSlice{}
+		err := json.Unmarshal(attempt.Params, &peers)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start params of attempt %d: %w", i, err)
+		}
+		if !contains(peers, session.Self) {
+			continue
+		}
+
+		report, err := replayAttempt(session, peers, attempt.Inbound(comm.TssKeySignMsg))
+		if err != nil {
+			return nil, fmt.Errorf("failed replaying attempt %d: %w", i, err)
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+func sessionIDInt(sessionID string) *big.Int {
+	return new(big.Int).SetBytes([]byte(sessionID))
+}
+
+func contains(peers peer.IDSlice, p peer.ID) bool {
+	for _, peerID := range peers {
+		if peerID == p {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,209 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package replay_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/suite"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+
+	"github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/ChainSafe/sygma-relayer/comm/recorder"
+	"github.com/ChainSafe/sygma-relayer/keyshare"
+	"github.com/ChainSafe/sygma-relayer/tss"
+	ecdsaSigning "github.com/ChainSafe/sygma-relayer/tss/ecdsa/signing"
+	frostSigning "github.com/ChainSafe/sygma-relayer/tss/frost/signing"
+	"github.com/ChainSafe/sygma-relayer/tss/message"
+	"github.com/ChainSafe/sygma-relayer/tss/replay"
+	tsstest "github.com/ChainSafe/sygma-relayer/tss/test"
+)
+
+const sessionID = "1-2-3-3-0"
+
+// tamperingCommunication corrupts tss messages sent by a malicious party
+type tamperingCommunication struct {
+	comm.Communication
+	tamper func(payload []byte) []byte
+}
+
+func (c *tamperingCommunication) Broadcast(peers peer.IDSlice, msg []byte, msgType comm.MessageType, sessionID string) error {
+	if msgType == comm.TssKeySignMsg {
+		msg = c.tamper(msg)
+	}
+	return c.Communication.Broadcast(peers, msg, msgType, sessionID)
+}
+
+func tamperECDSA(payload []byte) []byte {
+	msg, _ := message.UnmarshalTssMessage(payload)
+	msg.MsgBytes[len(msg.MsgBytes)-1] ^= 0xff
+	tampered, _ := message.MarshalTssMessage(msg.MsgBytes, msg.IsBroadcast)
+	return tampered
+}
+
+func tamperFrost(payload []byte) []byte {
+	msg := &protocol.Message{}
+	_ = msg.UnmarshalBinary(payload)
+	if msg.RoundNumber != 2 {
+		return payload
+	}
+	msg.Data = msg.Data[:len(msg.Data)-1]
+	tampered, _ := msg.MarshalBinary()
+	return tampered
+}
+
+type ReplayTestSuite struct {
+	suite.Suite
+	hosts          []host.Host
+	communications []comm.Communication
+	recorder       *recorder.RecordingCommunication
+	recordDir      string
+	peers          peer.IDSlice
+}
+
+func TestRunReplayTestSuite(t *testing.T) {
+	suite.Run(t, new(ReplayTestSuite))
+}
+
+func (s *ReplayTestSuite) SetupTest() {
+	s.recordDir = s.T().TempDir()
+	s.hosts = []host.Host{}
+	s.peers = peer.IDSlice{}
+	communicationMap := make(map[peer.ID]*tsstest.TestCommunication)
+	for i := 0; i < 2; i++ {
+		privBytes, err := os.ReadFile(fmt.Sprintf("../test/pks/%d.pk", i))
+		s.Nil(err)
+		priv, err := crypto.UnmarshalPrivateKey(privBytes)
+		s.Nil(err)
+		h, err := libp2p.New(libp2p.Identity(priv), libp2p.DisableRelay())
+		s.Nil(err)
+
+		s.hosts = append(s.hosts, h)
+		s.peers = append(s.peers, h.ID())
+		communicationMap[h.ID()] = &tsstest.TestCommunication{
+			Host:          h,
+			Subscriptions: make(map[comm.SubscriptionID]chan *comm.WrappedMessage),
+		}
+	}
+	tsstest.SetupCommunication(communicationMap)
+
+	var err error
+	s.recorder, err = recorder.NewRecordingCommunication(communicationMap[s.hosts[0].ID()], s.hosts[0].ID(), s.recordDir)
+	s.Nil(err)
+	s.communications = []comm.Communication{s.recorder, communicationMap[s.hosts[1].ID()]}
+}
+
+func (s *ReplayTestSuite) TearDownTest() {
+	for _, h := range s.hosts {
+		h.Close()
+	}
+}
+
+// run records the start message of the peer subset as the coordinator and runs the processes
+func (s *ReplayTestSuite) run(processes []tss.TssProcess) {
+	params, _ := json.Marshal(s.peers)
+	startMsg, _ := message.MarshalStartMessage(params)
+	err := s.recorder.Broadcast(peer.IDSlice{}, startMsg, comm.TssStartMsg, sessionID)
+	s.Nil(err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	resultChn := make(chan interface{}, len(processes))
+	errChn := make(chan error, len(processes))
+	for i, process := range processes {
+		process := process
+		coordinator := i == 0
+		go func() { errChn <- process.Run(ctx, coordinator, resultChn, params) }()
+	}
+	// the recording party messages are recorded once any of the processes fails
+	for range processes {
+		if err := <-errChn; err != nil {
+			break
+		}
+	}
+	for _, process := range processes {
+		process.Stop()
+	}
+}
+
+func (s *ReplayTestSuite) loadSession() *recorder.Session {
+	session, err := recorder.LoadSession(recorder.SessionPath(s.recordDir, sessionID))
+	s.Nil(err)
+	return session
+}
+
+func (s *ReplayTestSuite) ecdsaProcesses() []tss.TssProcess {
+	processes := []tss.TssProcess{}
+	for i, h := range s.hosts {
+		fetcher := keyshare.NewECDSAKeyshareStore(fmt.Sprintf("../test/keyshares/%d.keyshare", i))
+		process, err := ecdsaSigning.NewSigning(big.NewInt(1), "messageID", sessionID, h, s.communications[i], fetcher)
+		s.Nil(err)
+		processes = append(processes, process)
+	}
+	return processes
+}
+
+func (s *ReplayTestSuite) Test_ECDSASigning_ValidSession() {
+	s.run(s.ecdsaProcesses())
+
+	key, err := keyshare.NewECDSAKeyshareStore("../test/keyshares/0.keyshare").GetKeyshare()
+	s.Nil(err)
+	reports, err := replay.ECDSASigning(s.loadSession(), key)
+
+	s.Nil(err)
+	s.Len(reports, 1)
+	s.Empty(reports[0].Culprits)
+	s.Equal(1, reports[0].Verified)
+	s.NotZero(reports[0].Validated)
+}
+
+func (s *ReplayTestSuite) Test_ECDSASigning_MalformedMessage() {
+	s.communications[1] = &tamperingCommunication{Communication: s.communications[1], tamper: tamperECDSA}
+	s.run(s.ecdsaProcesses())
+
+	key, err := keyshare.NewECDSAKeyshareStore("../test/keyshares/0.keyshare").GetKeyshare()
+	s.Nil(err)
+	reports, err := replay.ECDSASigning(s.loadSession(), key)
+
+	s.Nil(err)
+	s.Len(reports, 1)
+	s.Equal(peer.IDSlice{s.hosts[1].ID()}, reports[0].Culprits)
+	s.NotNil(reports[0].Err)
+	s.Equal(1, reports[0].Verified)
+}
+
+func (s *ReplayTestSuite) Test_FrostSigning_MalformedMessage() {
+	s.communications[1] = &tamperingCommunication{Communication: s.communications[1], tamper: tamperFrost}
+	processes := []tss.TssProcess{}
+	for i, h := range s.hosts {
+		fetcher := keyshare.NewFrostKeyshareStore(fmt.Sprintf("../test/keyshares/%d-frost.keyshare", i))
+		process, err := frostSigning.NewSigning(
+			1, []byte("Message"), "c82aa6ae534bb28aaafeb3660c31d6a52e187d8f05d48bb6bdb9b733a9b42212",
+			"messageID", sessionID, h, s.communications[i], fetcher,
+		)
+		s.Nil(err)
+		processes = append(processes, process)
+	}
+	s.run(processes)
+
+	key, err := keyshare.NewFrostKeyshareStore("../test/keyshares/0-frost.keyshare").GetKeyshare()
+	s.Nil(err)
+	reports, err := replay.FrostSigning(s.loadSession(), key)
+
+	s.Nil(err)
+	s.Len(reports, 1)
+	s.Equal(peer.IDSlice{s.hosts[1].ID()}, reports[0].Culprits)
+	s.NotNil(reports[0].Err)
+	s.Equal(1, reports[0].Verified)
+}
@@ -0,0 +1,249 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package tss
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+type Priority int
+
+// MaxDepositQueueTime is the time after which a queued deposit is started
+// before newer deposits so old deposits are not starved under load
+var MaxDepositQueueTime = time.Minute * 5
+
+const (
+	// DepositPriority is used for signing deposits, deposits with the highest order are executed first
+	DepositPriority Priority = iota
+	// RetryPriority is used for signing retried deposits
+	RetryPriority
+	// CeremonyPriority is used for keygen and resharing processes
+	CeremonyPriority
+)
+
+// SigningPriority returns the priority of signing proposals of retried or new deposits
+func SigningPriority(retried bool) Priority {
+	if retried {
+		return RetryPriority
+	}
+	return DepositPriority
+}
+
+type ProcessCoordinator interface {
+	Execute(ctx context.Context, tssProcesses []TssProcess, resultChn chan interface{}) error
+}
+
+type QueueDepthMeter interface {
+	TrackTssQueueDepth(depth int)
+}
+
+type scheduledProcess struct {
+	priority  Priority
+	order     uint64
+	sessionID string
+	queuedAt  time.Time
+	aged      bool
+	sequence  uint64
+	ready     chan struct{}
+	index     int
+}
+
+// effectivePriority returns retry priority for deposits queued
+// for longer than MaxDepositQueueTime
+func (p *scheduledProcess) effectivePriority() Priority {
+	if p.priority == DepositPriority && p.aged {
+		return RetryPriority
+	}
+	return p.priority
+}
+
+// processQueue is a priority queue of processes waiting for execution
+type processQueue []*scheduledProcess
+
+func (q processQueue) Len() int { return len(q) }
+
+// Less orders processes by priority and then by the order shared between relayers.
+// Deposits are started from the highest order while retries and aged deposits are
+// started from the lowest order. Session ID breaks ties so peers use the same order.
+func (q processQueue) Less(i, j int) bool {
+	pi, pj := q[i].effectivePriority(), q[j].effectivePriority()
+	if pi != pj {
+		return pi > pj
+	}
+	if q[i].order != q[j].order {
+		if pi == DepositPriority {
+			return q[i].order > q[j].order
+		}
+		return q[i].order < q[j].order
+	}
+	if q[i].sessionID != q[j].sessionID {
+		return q[i].sessionID < q[j].sessionID
+	}
+	return q[i].sequence < q[j].sequence
+}
+
+func (q processQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *processQueue) Push(x any) {
+	process := x.(*scheduledProcess)
+	process.index = len(*q)
+	*q = append(*q, process)
+}
+
+func (q *processQueue) Pop() any {
+	old := *q
+	n := len(old)
+	process := old[n-1]
+	old[n-1] = nil
+	process.index = -1
+	*q = old[:n-1]
+	return process
+}
+
+// Scheduler limits the number of tss processes executed concurrently by the coordinator.
+// Processes over the limit are queued and started by priority and by an order derived from
+// chain data, like the deposit nonce, so peers start queued sessions in the same order.
+// Ceremonies are never queued so keygen and resharing don't wait for signings that can run
+// up to the tss timeout.
+//
+// The limit and the queue contents are local to the relayer, so under load peers can start
+// different sessions first. A session started by some peers while it is still queued on others
+// can time out and is then retried like any other failed signing. All relayers should use the
+// same max concurrent processes so peers start sessions in a similar order.
+type Scheduler struct {
+	coordinator   ProcessCoordinator
+	maxConcurrent int
+	metrics       QueueDepthMeter
+
+	lock     sync.Mutex
+	running  int
+	sequence uint64
+	queue    processQueue
+}
+
+func NewScheduler(coordinator ProcessCoordinator, maxConcurrent int, metrics QueueDepthMeter) *Scheduler {
+	return &Scheduler{
+		coordinator:   coordinator,
+		maxConcurrent: maxConcurrent,
+		metrics:       metrics,
+		queue:         make(processQueue, 0),
+	}
+}
+
+// Execute waits until the process can be started and executes it with the coordinator.
+// Order should be derived from chain data, like the highest deposit nonce of the signed proposals.
+// Returns context error if the context is cancelled while the process is queued.
+func (s *Scheduler) Execute(ctx context.Context, priority Priority, order uint64, tssProcesses []TssProcess, resultChn chan interface{}) error {
+	sessionID := tssProcesses[0].SessionID()
+	process := s.enqueue(priority, order, sessionID)
+
+	select {
+	case <-process.ready:
+	case <-ctx.Done():
+		if !s.dequeue(process) {
+			// slot was assigned while the context was cancelled
+			s.release()
+		}
+		log.Debug().Str("SessionID", sessionID).Msgf("Queued tss process cancelled")
+		return ctx.Err()
+	}
+	defer s.release()
+
+	return s.coordinator.Execute(ctx, tssProcesses, resultChn)
+}
+
+// QueueDepth returns the number of processes waiting for execution
+func (s *Scheduler) QueueDepth() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.queue.Len()
+}
+
+func (s *Scheduler) enqueue(priority Priority, order uint64, sessionID string) *scheduledProcess {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.sequence++
+	process := &scheduledProcess{
+		priority:  priority,
+		order:     order,
+		sessionID: sessionID,
+		queuedAt:  time.Now(),
+		sequence:  s.sequence,
+		ready:     make(chan struct{}),
+	}
+	if priority == CeremonyPriority {
+		s.running++
+		process.index = -1
+		close(process.ready)
+		return process
+	}
+
+	heap.Push(&s.queue, process)
+	s.schedule()
+	return process
+}
+
+// dequeue removes the process from the queue and returns false if the process was already started
+func (s *Scheduler) dequeue(process *scheduledProcess) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if process.index < 0 {
+		return false
+	}
+	heap.Remove(&s.queue, process.index)
+	s.trackQueueDepth()
+	return true
+}
+
+func (s *Scheduler) release() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.running--
+	s.schedule()
+}
+
+// schedule starts queued processes while there are free slots, it expects the lock to be held
+func (s *Scheduler) schedule() {
+	if s.running < s.maxConcurrent && s.ageQueue() {
+		heap.Init(&s.queue)
+	}
+	for s.running < s.maxConcurrent && s.queue.Len() > 0 {
+		process := heap.Pop(&s.queue).(*scheduledProcess)
+		s.running++
+		close(process.ready)
+	}
+	s.trackQueueDepth()
+}
+
+// ageQueue marks deposits queued for longer than MaxDepositQueueTime as aged
+// and returns true if any process was marked
+func (s *Scheduler) ageQueue() bool {
+	aged := false
+	for _, process := range s.queue {
+		if !process.aged && time.Since(process.queuedAt) > MaxDepositQueueTime {
+			process.aged = true
+			aged = true
+		}
+	}
+	return aged
+}
+
+func (s *Scheduler) trackQueueDepth() {
+	if s.metrics != nil {
+		s.metrics.TrackTssQueueDepth(s.queue.Len())
+	}
+}
@@ -0,0 +1,218 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package tss_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/sygma-relayer/tss"
+	mock_tss "github.com/ChainSafe/sygma-relayer/tss/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+)
+
+type testCoordinator struct {
+	lock     sync.Mutex
+	executed []string
+	release  chan struct{}
+}
+
+func (c *testCoordinator) Execute(ctx context.Context, tssProcesses []tss.TssProcess, resultChn chan interface{}) error {
+	c.lock.Lock()
+	c.executed = append(c.executed, tssProcesses[0].SessionID())
+	c.lock.Unlock()
+
+	<-c.release
+	return nil
+}
+
+func (c *testCoordinator) executedSessions() []string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return append([]string{}, c.executed...)
+}
+
+type testQueueMeter struct {
+	lock  sync.Mutex
+	depth int
+}
+
+func (m *testQueueMeter) TrackTssQueueDepth(depth int) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.depth = depth
+}
+
+type SchedulerTestSuite struct {
+	suite.Suite
+	mockController *gomock.Controller
+	coordinator    *testCoordinator
+	meter          *testQueueMeter
+	scheduler      *tss.Scheduler
+}
+
+func TestRunSchedulerTestSuite(t *testing.T) {
+	suite.Run(t, new(SchedulerTestSuite))
+}
+
+func (s *SchedulerTestSuite) SetupTest() {
+	s.mockController = gomock.NewController(s.T())
+	s.coordinator = &testCoordinator{
+		release: make(chan struct{}),
+	}
+	s.meter = &testQueueMeter{}
+	s.scheduler = tss.NewScheduler(s.coordinator, 1, s.meter)
+}
+
+func (s *SchedulerTestSuite) process(sessionID string) []tss.TssProcess {
+	process := mock_tss.NewMockTssProcess(s.mockController)
+	process.EXPECT().SessionID().Return(sessionID).AnyTimes()
+	return []tss.TssProcess{process}
+}
+
+func (s *SchedulerTestSuite) execute(wg *sync.WaitGroup, priority tss.Priority, order uint64, sessionID string) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = s.scheduler.Execute(context.Background(), priority, order, s.process(sessionID), nil)
+	}()
+}
+
+func (s *SchedulerTestSuite) waitForQueueDepth(depth int) {
+	s.Eventually(func() bool {
+		return s.scheduler.QueueDepth() == depth
+	}, time.Second, time.Millisecond)
+}
+
+func (s *SchedulerTestSuite) Test_Execute_ProcessesExecutedByPriority() {
+	wg := &sync.WaitGroup{}
+	s.execute(wg, tss.DepositPriority, 1, "running")
+	s.Eventually(func() bool {
+		return len(s.coordinator.executedSessions()) == 1
+	}, time.Second, time.Millisecond)
+
+	s.execute(wg, tss.DepositPriority, 2, "old-deposit")
+	s.waitForQueueDepth(1)
+	s.execute(wg, tss.RetryPriority, 1, "retry")
+	s.waitForQueueDepth(2)
+	s.execute(wg, tss.DepositPriority, 3, "new-deposit")
+	s.waitForQueueDepth(3)
+	s.Equal(s.meter.depth, 3)
+
+	for i := 0; i < 4; i++ {
+		s.coordinator.release <- struct{}{}
+	}
+	wg.Wait()
+
+	s.Equal(s.coordinator.executedSessions(), []string{"running", "retry", "new-deposit", "old-deposit"})
+	s.Equal(s.scheduler.QueueDepth(), 0)
+	s.Equal(s.meter.depth, 0)
+}
+
+func (s *SchedulerTestSuite) Test_Execute_DepositsExecutedByOrder() {
+	wg := &sync.WaitGroup{}
+	s.execute(wg, tss.DepositPriority, 1, "running")
+	s.Eventually(func() bool {
+		return len(s.coordinator.executedSessions()) == 1
+	}, time.Second, time.Millisecond)
+
+	s.execute(wg, tss.DepositPriority, 5, "deposit-5")
+	s.waitForQueueDepth(1)
+	s.execute(wg, tss.DepositPriority, 7, "deposit-7")
+	s.waitForQueueDepth(2)
+	s.execute(wg, tss.DepositPriority, 6, "deposit-6")
+	s.waitForQueueDepth(3)
+
+	for i := 0; i < 4; i++ {
+		s.coordinator.release <- struct{}{}
+	}
+	wg.Wait()
+
+	s.Equal(s.coordinator.executedSessions(), []string{"running", "deposit-7", "deposit-6", "deposit-5"})
+}
+
+func (s *SchedulerTestSuite) Test_Execute_AgedDepositsExecutedFirst() {
+	maxDepositQueueTime := tss.MaxDepositQueueTime
+	defer func() { tss.MaxDepositQueueTime = maxDepositQueueTime }()
+	tss.MaxDepositQueueTime = time.Millisecond * 50
+
+	wg := &sync.WaitGroup{}
+	s.execute(wg, tss.DepositPriority, 1, "running")
+	s.Eventually(func() bool {
+		return len(s.coordinator.executedSessions()) == 1
+	}, time.Second, time.Millisecond)
+
+	s.execute(wg, tss.DepositPriority, 5, "old-deposit")
+	s.waitForQueueDepth(1)
+	time.Sleep(tss.MaxDepositQueueTime * 2)
+	s.execute(wg, tss.DepositPriority, 7, "new-deposit")
+	s.waitForQueueDepth(2)
+
+	for i := 0; i < 3; i++ {
+		s.coordinator.release <- struct{}{}
+	}
+	wg.Wait()
+
+	s.Equal(s.coordinator.executedSessions(), []string{"running", "old-deposit", "new-deposit"})
+}
+
+func (s *SchedulerTestSuite) Test_Execute_CeremonyNotQueued() {
+	wg := &sync.WaitGroup{}
+	s.execute(wg, tss.DepositPriority, 1, "running")
+	s.Eventually(func() bool {
+		return len(s.coordinator.executedSessions()) == 1
+	}, time.Second, time.Millisecond)
+	s.execute(wg, tss.DepositPriority, 2, "deposit")
+	s.waitForQueueDepth(1)
+
+	s.execute(wg, tss.CeremonyPriority, 0, "keygen")
+	s.Eventually(func() bool {
+		return len(s.coordinator.executedSessions()) == 2
+	}, time.Second, time.Millisecond)
+	s.Equal(s.scheduler.QueueDepth(), 1)
+
+	for i := 0; i < 3; i++ {
+		s.coordinator.release <- struct{}{}
+	}
+	wg.Wait()
+
+	s.Equal(s.coordinator.executedSessions(), []string{"running", "keygen", "deposit"})
+	s.Equal(s.scheduler.QueueDepth(), 0)
+}
+
+func (s *SchedulerTestSuite) Test_Execute_CancelledWhileQueued() {
+	wg := &sync.WaitGroup{}
+	s.execute(wg, tss.DepositPriority, 1, "running")
+	s.Eventually(func() bool {
+		return len(s.coordinator.executedSessions()) == 1
+	}, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errChn := make(chan error)
+	go func() {
+		errChn <- s.scheduler.Execute(ctx, tss.DepositPriority, 2, s.process("cancelled"), nil)
+	}()
+	s.waitForQueueDepth(1)
+	cancel()
+
+	s.Equal(<-errChn, context.Canceled)
+	s.Equal(s.scheduler.QueueDepth(), 0)
+
+	s.execute(wg, tss.DepositPriority, 2, "next")
+	s.coordinator.release <- struct{}{}
+	s.coordinator.release <- struct{}{}
+	wg.Wait()
+
+	s.Equal(s.coordinator.executedSessions(), []string{"running", "next"})
+}
+
+func (s *SchedulerTestSuite) Test_SigningPriority() {
+	s.Equal(tss.SigningPriority(false), tss.DepositPriority)
+	s.Equal(tss.SigningPriority(true), tss.RetryPriority)
+}
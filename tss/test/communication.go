@@ -9,8 +9,10 @@ import (
 	"time"
 
 	"github.com/ChainSafe/sygma-relayer/comm"
+	"github.com/ChainSafe/sygma-relayer/comm/p2p"
 	"github.com/libp2p/go-libp2p/core/host"
 	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
 )
 
 type Receiver interface {
@@ -75,3 +77,13 @@ func (ts *TestCommunication) ReceiveMessage(msg *comm.WrappedMessage, topic comm
 }
 
 func (ts *TestCommunication) CloseSession(sessionID string) {}
+
+// NewTreeBroadcastCommunications creates tree broadcast communications between hosts so messages
+// are exchanged over the network instead of directly between test communications
+func NewTreeBroadcastCommunications(hosts []host.Host, protocolID protocol.ID, fanout int) map[peer.ID]comm.Communication {
+	communications := make(map[peer.ID]comm.Communication)
+	for _, h := range hosts {
+		communications[h.ID()] = p2p.NewTreeBroadcastCommunication(h, protocolID, p2p.CommunicationConfig{RequireSignedMessages: true}, fanout)
+	}
+	return communications
+}